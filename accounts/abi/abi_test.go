@@ -1161,3 +1161,52 @@ func TestUnpackRevert(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpackError(t *testing.T) {
+	t.Parallel()
+
+	const def = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},{"type":"error","name":"Unauthorized","inputs":[{"type":"address"}]}]`
+	abi, err := JSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, ok := abi.Errors["InsufficientBalance"]
+	if !ok {
+		t.Fatal("error InsufficientBalance not parsed")
+	}
+	if custom.Sig != "InsufficientBalance(uint256,uint256)" {
+		t.Fatalf("signature mismatch: have %s", custom.Sig)
+	}
+	if want := crypto.SHA3([]byte(custom.Sig)); !bytes.Equal(custom.ID[:], want) {
+		t.Fatalf("id mismatch: have %x, want %x", custom.ID, want)
+	}
+	if name := abi.Errors["Unauthorized"].Inputs[0].Name; name != "arg0" {
+		t.Fatalf("unnamed input not sanitized: have %q", name)
+	}
+	args, err := custom.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := append(custom.ID[:4:4], args...)
+
+	decoded, err := abi.UnpackError(data)
+	if err != nil {
+		t.Fatalf("failed to unpack error: %v", err)
+	}
+	if decoded.Name != "InsufficientBalance" {
+		t.Fatalf("name mismatch: have %s", decoded.Name)
+	}
+	if !reflect.DeepEqual(decoded.Args, []interface{}{big.NewInt(1), big.NewInt(2)}) {
+		t.Fatalf("args mismatch: have %v", decoded.Args)
+	}
+	if decoded.String() != "InsufficientBalance(1, 2)" {
+		t.Fatalf("string mismatch: have %s", decoded)
+	}
+	// Unknown selectors and short data should be rejected
+	if _, err := abi.UnpackError(common.Hex2Bytes("deadbeef")); err == nil {
+		t.Fatal("unpacked unknown error")
+	}
+	if _, err := abi.UnpackError([]byte{0x01}); err == nil {
+		t.Fatal("unpacked short data")
+	}
+}
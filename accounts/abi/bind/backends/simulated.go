@@ -27,6 +27,7 @@ import (
 	c "github.com/core-coin/go-core/v2"
 	"github.com/core-coin/go-core/v2/xcbdb"
 
+	"github.com/core-coin/go-core/v2/consensus"
 	"github.com/core-coin/go-core/v2/consensus/cryptore"
 
 	"github.com/core-coin/go-core/v2/accounts/abi"
@@ -64,6 +65,7 @@ var (
 type SimulatedBackend struct {
 	database   xcbdb.Database   // In memory database to store our testing data
 	blockchain *core.BlockChain // Core blockchain to handle the consensus
+	engine     *storageEngine   // Consensus engine writing the storage slots set directly
 
 	mu           sync.Mutex
 	pendingBlock *types.Block   // Currently pending block that will be imported on request
@@ -71,6 +73,8 @@ type SimulatedBackend struct {
 
 	events *filters.EventSystem // Event system for filtering log events live
 
+	errorABIs []abi.ABI // Contract ABIs used to decode custom errors in reverts
+
 	config *params.ChainConfig
 }
 
@@ -78,15 +82,41 @@ type SimulatedBackend struct {
 // and uses a simulated blockchain for testing purposes.
 // A simulated backend always uses networkID 1.
 func NewSimulatedBackendWithDatabase(database xcbdb.Database, alloc core.GenesisAlloc, energyLimit uint64) *SimulatedBackend {
-	genesis := core.Genesis{Config: params.MainnetChainConfig, EnergyLimit: energyLimit, Alloc: alloc}
+	return newSimulatedBackend(database, alloc, energyLimit, params.MainnetChainConfig)
+}
+
+// NewSimulatedBackendWithConfig creates a new binding backend using a simulated
+// blockchain running with the given chain configuration, allowing to test with
+// alternate network IDs or protocol settings.
+func NewSimulatedBackendWithConfig(alloc core.GenesisAlloc, energyLimit uint64, config *params.ChainConfig) *SimulatedBackend {
+	return newSimulatedBackend(rawdb.NewMemoryDatabase(), alloc, energyLimit, config)
+}
+
+// newSimulatedBackend creates a simulated backend on top of the given database
+// with the given chain configuration.
+func newSimulatedBackend(database xcbdb.Database, alloc core.GenesisAlloc, energyLimit uint64, config *params.ChainConfig) *SimulatedBackend {
+	genesis := core.Genesis{Config: config, EnergyLimit: energyLimit, Alloc: alloc}
 	genesis.MustCommit(database)
-	blockchain, err := core.NewBlockChain(database, nil, genesis.Config, cryptore.NewFaker(), vm.Config{}, nil, nil)
+
+	// Trie key preimages are needed to map the state back to addresses when
+	// exporting it, make sure they are retained.
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit: 256,
+		TrieDirtyLimit: 256,
+		TrieTimeLimit:  5 * time.Minute,
+		SnapshotLimit:  256,
+		SnapshotWait:   true,
+		Preimages:      true,
+	}
+	engine := &storageEngine{Engine: cryptore.NewFaker()}
+	blockchain, err := core.NewBlockChain(database, cacheConfig, genesis.Config, engine, vm.Config{}, nil, nil)
 	if err != nil {
 		panic(err)
 	}
 	backend := &SimulatedBackend{
 		database:   database,
 		blockchain: blockchain,
+		engine:     engine,
 		config:     genesis.Config,
 		events:     filters.NewEventSystem(&filterBackend{database, blockchain}, false),
 	}
@@ -127,8 +157,34 @@ func (b *SimulatedBackend) Rollback() {
 	b.rollback()
 }
 
+// ImportChain validates and appends a batch of pre-built blocks to the simulated
+// chain, discarding any pending transactions. It allows replaying a previously
+// exported block range, e.g. to reproduce a bug in a test.
+func (b *SimulatedBackend) ImportChain(blocks []*types.Block) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n, err := b.blockchain.InsertChain(blocks)
+
+	// Unlike locally committed blocks, the state of imported ones only lives in
+	// the blockchain's trie cache. Flush the head state so the pending block can
+	// be built on top of it.
+	root := b.blockchain.CurrentBlock().Root()
+	if ferr := b.blockchain.StateCache().TrieDB().Commit(root, false, nil); ferr != nil {
+		return ferr
+	}
+	b.rollback()
+
+	if err != nil && n < len(blocks) {
+		return fmt.Errorf("failed to import block #%d: %v", blocks[n].NumberU64(), err)
+	}
+	return err
+}
+
 func (b *SimulatedBackend) rollback() {
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), cryptore.NewFaker(), b.database, 1, func(int, *core.BlockGen) {})
+	b.engine.slots = nil
+
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(int, *core.BlockGen) {})
 	stateDB, err := b.blockchain.State()
 	if err != nil {
 		panic(err)
@@ -210,10 +266,38 @@ func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	receipt, _, _, _ := rawdb.ReadReceipt(b.database, txHash, b.config)
+	receipt, blockHash, _, index := rawdb.ReadReceipt(b.database, txHash, b.config)
+	if receipt != nil && receipt.Status == types.ReceiptStatusFailed {
+		receipt.RevertReason = b.revertReason(blockHash, index)
+	}
 	return receipt, nil
 }
 
+// revertReason re-executes the transaction at the given index of a block on top
+// of its parent state to retrieve the reason it reverted with, or an empty string
+// if it didn't revert with Error(string).
+func (b *SimulatedBackend) revertReason(hash common.Hash, index uint64) string {
+	block := b.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		return ""
+	}
+	parent := b.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return ""
+	}
+	statedb, err := b.blockchain.StateAt(parent.Root())
+	if err != nil {
+		return ""
+	}
+	vmenv := vm.NewCVM(core.NewCVMBlockContext(block.Header(), b.blockchain, nil), vm.TxContext{}, statedb, b.config, vm.Config{})
+	result, err := core.ReplayTransaction(vmenv, statedb, block, index)
+	if err != nil {
+		return ""
+	}
+	reason, _ := abi.UnpackRevert(result.Revert())
+	return reason
+}
+
 // TransactionByHash checks the pool of pending transactions in addition to the
 // blockchain. The isPending return value indicates whether the transaction has been
 // mined yet. Note that the transaction may not be part of the canonical chain even if
@@ -356,23 +440,46 @@ func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Ad
 	return b.pendingState.GetCode(contract), nil
 }
 
-func newRevertError(result *core.ExecutionResult) *revertError {
-	reason, errUnpack := abi.UnpackRevert(result.Revert())
-	err := errors.New("execution reverted")
-	if errUnpack == nil {
-		err = fmt.Errorf("execution reverted: %v", reason)
-	}
-	return &revertError{
-		error:  err,
+// RegisterErrorABI registers a contract ABI whose custom errors should be decoded
+// from the revert data of failed calls and energy estimations.
+func (b *SimulatedBackend) RegisterErrorABI(parsed abi.ABI) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.errorABIs = append(b.errorABIs, parsed)
+}
+
+func newRevertError(result *core.ExecutionResult, abis []abi.ABI) *revertError {
+	rerr := &revertError{
+		error:  errors.New("execution reverted"),
 		reason: hexutil.Encode(result.Revert()),
 	}
+	if reason, err := abi.UnpackRevert(result.Revert()); err == nil {
+		rerr.error = fmt.Errorf("execution reverted: %v", reason)
+		return rerr
+	}
+	for _, parsed := range abis {
+		if custom, err := parsed.UnpackError(result.Revert()); err == nil {
+			rerr.error = fmt.Errorf("execution reverted: %v", custom)
+			rerr.custom = custom
+			break
+		}
+	}
+	return rerr
 }
 
 // revertError is an API error that encompasses an CVM revert with JSON error
 // code and a binary data blob.
 type revertError struct {
 	error
-	reason string // revert reason hex encoded
+	reason string           // revert reason hex encoded
+	custom *abi.CustomError // decoded custom error, if any
+}
+
+// CustomError returns the decoded custom error the contract reverted with, or
+// nil if the revert data did not match any of the registered errors.
+func (e *revertError) CustomError() *abi.CustomError {
+	return e.custom
 }
 
 // ErrorCode returns the JSON error code for a revert.
@@ -404,7 +511,7 @@ func (b *SimulatedBackend) CallContract(ctx context.Context, call c.CallMsg, blo
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if len(res.Revert()) > 0 {
-		return nil, newRevertError(res)
+		return nil, newRevertError(res, b.errorABIs)
 	}
 	return res.Return(), res.Err
 }
@@ -421,7 +528,7 @@ func (b *SimulatedBackend) PendingCallContract(ctx context.Context, call c.CallM
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if len(res.Revert()) > 0 {
-		return nil, newRevertError(res)
+		return nil, newRevertError(res, b.errorABIs)
 	}
 	return res.Return(), res.Err
 }
@@ -449,11 +556,12 @@ func (b *SimulatedBackend) EstimateEnergy(ctx context.Context, call c.CallMsg) (
 
 	// Determine the lowest and highest possible energy limits to binary search in between
 	var (
-		lo  = params.TxEnergy - 1
-		hi  uint64
-		cap uint64
+		txEnergy = b.config.EnergyCosts().TxEnergy
+		lo       = txEnergy - 1
+		hi       uint64
+		cap      uint64
 	)
-	if call.Energy >= params.TxEnergy {
+	if call.Energy >= txEnergy {
 		hi = call.Energy
 	} else {
 		hi = b.pendingBlock.EnergyLimit()
@@ -523,7 +631,7 @@ func (b *SimulatedBackend) EstimateEnergy(ctx context.Context, call c.CallMsg) (
 		if failed {
 			if result != nil && result.Err != vm.ErrOutOfEnergy {
 				if len(result.Revert()) > 0 {
-					return 0, newRevertError(result)
+					return 0, newRevertError(result, b.errorABIs)
 				}
 				return 0, result.Err
 			}
@@ -569,7 +677,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	sender, err := types.Sender(types.NewNucleusSigner(b.config.NetworkID), tx)
+	sender, err := types.Sender(types.MakeBlockSigner(b.config, b.pendingBlock.Number()), tx)
 	if err != nil {
 		panic(fmt.Errorf("invalid transaction: %v", err))
 	}
@@ -578,7 +686,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), cryptore.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
@@ -593,6 +701,30 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	return err
 }
 
+// SetStorageAt sets the value of a storage slot of an account in the pending
+// state, avoiding setup transactions in tests. The slot is written at the end
+// of the pending block, so it is visible to transactions sent after the next
+// Commit, and is discarded by Rollback.
+func (b *SimulatedBackend) SetStorageAt(contract common.Address, key, value common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.engine.setStorage(b.blockchain.CurrentBlock().Hash(), contract, key, value)
+
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
+		for _, tx := range b.pendingBlock.Transactions() {
+			block.AddTxWithChain(b.blockchain, tx)
+		}
+	})
+	stateDB, err := b.blockchain.State()
+	if err != nil {
+		return err
+	}
+	b.pendingBlock = blocks[0]
+	b.pendingState, err = state.New(b.pendingBlock.Root(), stateDB.Database(), nil)
+	return err
+}
+
 // FilterLogs executes a log filter operation, blocking during execution and
 // returning all the results in one batch.
 //
@@ -698,7 +830,7 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 		return errors.New("Could not adjust time on non-empty block")
 	}
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), cryptore.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
 		block.OffsetTime(int64(adjustment.Seconds()))
 	})
 	stateDB, err := b.blockchain.State()
@@ -718,19 +850,66 @@ func (b *SimulatedBackend) Blockchain() *core.BlockChain {
 	return b.blockchain
 }
 
+// ExportGenesis serializes the current committed state of the simulated chain
+// into a genesis specification, allowing the exact same state to be launched on
+// a standalone node. Pending transactions are not included.
+func (b *SimulatedBackend) ExportGenesis() (*core.Genesis, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stateDB, err := b.blockchain.State()
+	if err != nil {
+		return nil, err
+	}
+	alloc := make(core.GenesisAlloc)
+	dump := stateDB.RawDump(false, false, false)
+	for addr, account := range dump.Accounts {
+		if account.SecureKey != nil {
+			return nil, fmt.Errorf("missing preimage for account key %x", account.SecureKey)
+		}
+		balance, ok := new(big.Int).SetString(account.Balance, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance %q for account %s", account.Balance, addr.Hex())
+		}
+		genesisAccount := core.GenesisAccount{
+			Balance: balance,
+			Nonce:   account.Nonce,
+		}
+		if code := common.Hex2Bytes(account.Code); len(code) > 0 {
+			genesisAccount.Code = code
+		}
+		if len(account.Storage) > 0 {
+			genesisAccount.Storage = make(map[common.Hash]common.Hash, len(account.Storage))
+			for key, value := range account.Storage {
+				genesisAccount.Storage[key] = common.HexToHash(value)
+			}
+		}
+		alloc[addr] = genesisAccount
+	}
+	head := b.blockchain.CurrentBlock()
+	return &core.Genesis{
+		Config:      b.config,
+		Timestamp:   head.Time(),
+		EnergyLimit: head.EnergyLimit(),
+		Difficulty:  head.Difficulty(),
+		Alloc:       alloc,
+	}, nil
+}
+
 // callMsg implements core.Message to allow passing it as a transaction simulator.
 type callMsg struct {
 	c.CallMsg
 }
 
-func (m callMsg) From() common.Address  { return m.CallMsg.From }
-func (m callMsg) Nonce() uint64         { return 0 }
-func (m callMsg) CheckNonce() bool      { return false }
-func (m callMsg) To() *common.Address   { return m.CallMsg.To }
-func (m callMsg) EnergyPrice() *big.Int { return m.CallMsg.EnergyPrice }
-func (m callMsg) Energy() uint64        { return m.CallMsg.Energy }
-func (m callMsg) Value() *big.Int       { return m.CallMsg.Value }
-func (m callMsg) Data() []byte          { return m.CallMsg.Data }
+func (m callMsg) From() common.Address         { return m.CallMsg.From }
+func (m callMsg) Nonce() uint64                { return 0 }
+func (m callMsg) CheckNonce() bool             { return false }
+func (m callMsg) To() *common.Address          { return m.CallMsg.To }
+func (m callMsg) EnergyPrice() *big.Int        { return m.CallMsg.EnergyPrice }
+func (m callMsg) Energy() uint64               { return m.CallMsg.Energy }
+func (m callMsg) Value() *big.Int              { return m.CallMsg.Value }
+func (m callMsg) Data() []byte                 { return m.CallMsg.Data }
+func (m callMsg) AccessList() types.AccessList { return nil }
 
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
@@ -809,3 +988,52 @@ func nullSubscription() event.Subscription {
 		return nil
 	})
 }
+
+// storageEngine wraps the consensus engine of the simulated chain, writing the
+// storage slots set by SetStorageAt when finalizing the block they were set in.
+// Both the generation and the import of that block see the same state changes.
+// It is guarded by the lock of the simulated backend.
+type storageEngine struct {
+	consensus.Engine
+
+	parent common.Hash                                    // Parent of the block the slots are written in
+	slots  map[common.Address]map[common.Hash]common.Hash // Storage slots to write
+}
+
+// setStorage schedules a storage slot to be written into the child of parent.
+func (e *storageEngine) setStorage(parent common.Hash, addr common.Address, key, value common.Hash) {
+	if e.slots == nil || e.parent != parent {
+		e.parent, e.slots = parent, make(map[common.Address]map[common.Hash]common.Hash)
+	}
+	if e.slots[addr] == nil {
+		e.slots[addr] = make(map[common.Hash]common.Hash)
+	}
+	e.slots[addr][key] = value
+}
+
+// writeStorage writes the scheduled storage slots if header is the block they
+// were set in.
+func (e *storageEngine) writeStorage(header *types.Header, state *state.StateDB) {
+	if header.ParentHash != e.parent {
+		return
+	}
+	for addr, slots := range e.slots {
+		for key, value := range slots {
+			state.SetState(addr, key, value)
+		}
+	}
+}
+
+// Finalize implements consensus.Engine, writing the scheduled storage slots
+// before the final state root is computed.
+func (e *storageEngine) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
+	e.writeStorage(header, state)
+	e.Engine.Finalize(chain, header, state, txs, uncles)
+}
+
+// FinalizeAndAssemble implements consensus.Engine, writing the scheduled storage
+// slots before the block is assembled.
+func (e *storageEngine) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	e.writeStorage(header, state)
+	return e.Engine.FinalizeAndAssemble(chain, header, state, txs, uncles, receipts)
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	crand "crypto/rand"
+	"encoding/json"
 	"errors"
 	"math/big"
 	"reflect"
@@ -31,8 +32,10 @@ import (
 	"github.com/core-coin/go-core/v2/accounts/abi"
 	"github.com/core-coin/go-core/v2/accounts/abi/bind"
 	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/hexutil"
 	"github.com/core-coin/go-core/v2/core"
 	"github.com/core-coin/go-core/v2/core/types"
+	"github.com/core-coin/go-core/v2/core/vm"
 	"github.com/core-coin/go-core/v2/crypto"
 	"github.com/core-coin/go-core/v2/params"
 )
@@ -134,6 +137,53 @@ func TestNewSimulatedBackend(t *testing.T) {
 	}
 }
 
+func TestNewSimulatedBackendWithConfig(t *testing.T) {
+	config := &params.ChainConfig{NetworkID: big.NewInt(1337), Cryptore: new(params.CryptoreConfig)}
+	sim := NewSimulatedBackendWithConfig(core.GenesisAlloc{testKey.Address(): {Balance: big.NewInt(10000000000)}}, 10000000, config)
+	defer sim.Close()
+
+	if sim.blockchain.Config() != config {
+		t.Errorf("expected sim blockchain config to equal the custom config, got %v", sim.blockchain.Config())
+	}
+	to, _ := crypto.GenerateKey(crand.Reader)
+	ctx := context.Background()
+
+	// Transactions signed for a different network should be rejected (the simulator panics)
+	tx := types.NewTransaction(0, to.Address(), big.NewInt(1000), params.TxEnergy, big.NewInt(1), nil)
+	signed, err := types.SignTx(tx, types.NewNucleusSigner(params.MainnetChainConfig.NetworkID), testKey)
+	if err != nil {
+		t.Fatalf("could not sign tx: %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("transaction of another network accepted")
+			}
+		}()
+		sim.SendTransaction(ctx, signed)
+	}()
+	// Transactions signed for the configured network should go through
+	signed, err = types.SignTx(tx, types.NewNucleusSigner(config.NetworkID), testKey)
+	if err != nil {
+		t.Fatalf("could not sign tx: %v", err)
+	}
+	if err := sim.SendTransaction(ctx, signed); err != nil {
+		t.Fatalf("could not add tx to pending block: %v", err)
+	}
+	sim.Commit()
+
+	receipt, err := sim.TransactionReceipt(ctx, signed.Hash())
+	if err != nil {
+		t.Fatalf("could not get transaction receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("transaction failed")
+	}
+	if bal, _ := sim.BalanceAt(ctx, to.Address(), nil); bal.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want 1000", bal)
+	}
+}
+
 func TestSimulatedBackend_AdjustTime(t *testing.T) {
 	sim := NewSimulatedBackend(
 		core.GenesisAlloc{}, 10000000,
@@ -847,6 +897,58 @@ func TestSimulatedBackend_TransactionReceipt(t *testing.T) {
 	}
 }
 
+func TestSimulatedBackend_RevertReasonReceipt(t *testing.T) {
+	typ, _ := abi.NewType("string", "", nil)
+	reason, err := (abi.Arguments{{Type: typ}}).Pack("insufficient funds")
+	if err != nil {
+		t.Fatalf("could not pack revert reason: %v", err)
+	}
+	var (
+		bgCtx          = context.Background()
+		contractKey, _ = crypto.GenerateKey(crand.Reader)
+		contract       = contractKey.Address()
+		data           = append(crypto.SHA3([]byte("Error(string)"))[:4], reason...)
+	)
+	sim := NewSimulatedBackend(
+		core.GenesisAlloc{
+			testKey.Address(): {Balance: big.NewInt(10000000000)},
+			contract:          {Balance: common.Big0, Code: revertCode(data)},
+		}, 10000000,
+	)
+	defer sim.Close()
+
+	// Send a transaction reverting with a reason and one succeeding
+	signer := types.NewNucleusSigner(sim.config.NetworkID)
+	failing, _ := types.SignTx(types.NewTransaction(0, contract, common.Big0, 100000, big.NewInt(1), nil), signer, testKey)
+	passing, _ := types.SignTx(types.NewTransaction(1, testKey.Address(), common.Big1, params.TxEnergy, big.NewInt(1), nil), signer, testKey)
+	for _, tx := range []*types.Transaction{failing, passing} {
+		if err := sim.SendTransaction(bgCtx, tx); err != nil {
+			t.Fatalf("could not add tx to pending block: %v", err)
+		}
+	}
+	sim.Commit()
+
+	receipt, err := sim.TransactionReceipt(bgCtx, failing.Hash())
+	if err != nil {
+		t.Fatalf("could not get transaction receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed || receipt.RevertReason != "insufficient funds" {
+		t.Fatalf("failed receipt mismatch: have status %d reason %q, want %d %q", receipt.Status, receipt.RevertReason, types.ReceiptStatusFailed, "insufficient funds")
+	}
+	blob, _ := json.Marshal(receipt)
+	if !strings.Contains(string(blob), `"status":"0x0"`) || !strings.Contains(string(blob), `"revertReason":"insufficient funds"`) {
+		t.Errorf("failed receipt JSON mismatch: %s", blob)
+	}
+	receipt, err = sim.TransactionReceipt(bgCtx, passing.Hash())
+	if err != nil {
+		t.Fatalf("could not get transaction receipt: %v", err)
+	}
+	blob, _ = json.Marshal(receipt)
+	if !strings.Contains(string(blob), `"status":"0x1"`) || strings.Contains(string(blob), "revertReason") {
+		t.Errorf("successful receipt JSON mismatch: %s", blob)
+	}
+}
+
 func TestSimulatedBackend_SuggestEnergyPrice(t *testing.T) {
 	sim := NewSimulatedBackend(
 		core.GenesisAlloc{},
@@ -936,6 +1038,65 @@ func TestSimulatedBackend_CodeAt(t *testing.T) {
 	}
 }
 
+func TestSimulatedBackend_SetStorageAt(t *testing.T) {
+	sim := simTestBackend(testKey.Address())
+	defer sim.Close()
+	bgCtx := context.Background()
+
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("could not parse abi: %v", err)
+	}
+	auth, _ := bind.NewKeyedTransactorWithNetworkID(testKey, big.NewInt(1))
+	contractAddr, _, _, err := bind.DeployContract(auth, parsed, common.FromHex(abiBin), sim)
+	if err != nil {
+		t.Fatalf("could not deploy contract: %v", err)
+	}
+	// Set a slot of the contract deployed in the same pending block
+	key, value := common.HexToHash("0x01"), common.HexToHash("0xc0ffee")
+	if err := sim.SetStorageAt(contractAddr, key, value); err != nil {
+		t.Fatalf("could not set storage: %v", err)
+	}
+	stored, err := sim.StorageAt(bgCtx, contractAddr, key, nil)
+	if err != nil {
+		t.Fatalf("could not get storage: %v", err)
+	}
+	if common.BytesToHash(stored) != (common.Hash{}) {
+		t.Errorf("storage set before commit: %x", stored)
+	}
+	sim.Commit()
+
+	code, err := sim.CodeAt(bgCtx, contractAddr, nil)
+	if err != nil {
+		t.Fatalf("could not get code: %v", err)
+	}
+	if !bytes.Equal(code, common.FromHex(deployedCode)) {
+		t.Errorf("pending transaction lost when setting storage")
+	}
+	stored, err = sim.StorageAt(bgCtx, contractAddr, key, nil)
+	if err != nil {
+		t.Fatalf("could not get storage: %v", err)
+	}
+	if common.BytesToHash(stored) != value {
+		t.Errorf("storage mismatch: have %x, want %x", stored, value)
+	}
+	// A rolled back slot must not be committed
+	other := common.HexToHash("0x02")
+	if err := sim.SetStorageAt(contractAddr, other, value); err != nil {
+		t.Fatalf("could not set storage: %v", err)
+	}
+	sim.Rollback()
+	sim.Commit()
+
+	stored, err = sim.StorageAt(bgCtx, contractAddr, other, nil)
+	if err != nil {
+		t.Fatalf("could not get storage: %v", err)
+	}
+	if common.BytesToHash(stored) != (common.Hash{}) {
+		t.Errorf("rolled back storage committed: %x", stored)
+	}
+}
+
 // When receive("X") is called with sender 0x00... and value 1, it produces this tx receipt:
 //
 //	receipt{status=1 cenergy=23949 bloom=00000000004000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000040200000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000 logs=[log: b6818c8064f645cd82d99b59a1a267d6d61117ef [75fd880d39c1daf53b6547ab6cb59451fc6452d27caa90e5b6649dd8293b9eed] 000000000000000000000000376c47978271565f56deb45495afa69e59c16ab200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000000000000000000158 9ae378b6d4409eada347a5dc0c180f186cb62dc68fcc0f043425eb917335aa28 0 95d429d309bb9d753954195fe2d69bd140b4ae731b9b5b605c34323de162cf00 0]}
@@ -1107,3 +1268,244 @@ func TestSimulatedBackend_CallContractRevert(t *testing.T) {
 		sim.Commit()
 	}
 }
+
+// revertCode assembles contract code which unconditionally reverts with the
+// given data.
+func revertCode(data []byte) []byte {
+	var code []byte
+	for offset := 0; offset < len(data); offset += 32 {
+		chunk := make([]byte, 32)
+		copy(chunk, data[offset:])
+
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, chunk...)
+		code = append(code, byte(vm.PUSH1), byte(offset), byte(vm.MSTORE))
+	}
+	return append(code, byte(vm.PUSH1), byte(len(data)), byte(vm.PUSH1), 0, byte(vm.REVERT))
+}
+
+func TestSimulatedBackend_CustomErrorRevert(t *testing.T) {
+	const errorsABI = `[{"inputs":[{"internalType":"uint256","name":"available","type":"uint256"},{"internalType":"uint256","name":"required","type":"uint256"}],"name":"InsufficientBalance","type":"error"},{"inputs":[],"name":"Unauthorized","type":"error"}]`
+
+	parsed, err := abi.JSON(strings.NewReader(errorsABI))
+	if err != nil {
+		t.Fatalf("could not parse abi: %v", err)
+	}
+	custom := parsed.Errors["InsufficientBalance"]
+	args, err := custom.Inputs.Pack(big.NewInt(10), big.NewInt(20))
+	if err != nil {
+		t.Fatalf("could not pack error arguments: %v", err)
+	}
+	var (
+		bgCtx    = context.Background()
+		contract = common.Address{0xcb, 0xff}
+		data     = append(custom.ID[:4:4], args...)
+	)
+	sim := NewSimulatedBackend(
+		core.GenesisAlloc{
+			testKey.Address(): {Balance: big.NewInt(10000000000)},
+			contract:          {Balance: common.Big0, Code: revertCode(data)},
+		}, 10000000,
+	)
+	defer sim.Close()
+
+	call := c.CallMsg{From: testKey.Address(), To: &contract}
+
+	// Without the ABI registered, the revert can't be decoded
+	if _, err := sim.CallContract(bgCtx, call, nil); err == nil || err.Error() != "execution reverted" {
+		t.Fatalf("undecoded error mismatch: have %v, want %v", err, "execution reverted")
+	} else if rerr := err.(*revertError); rerr.CustomError() != nil {
+		t.Fatalf("unexpected custom error: %v", rerr.CustomError())
+	}
+	sim.RegisterErrorABI(parsed)
+
+	calls := map[string]func() error{
+		"CallContract": func() error {
+			_, err := sim.CallContract(bgCtx, call, nil)
+			return err
+		},
+		"PendingCallContract": func() error {
+			_, err := sim.PendingCallContract(bgCtx, call)
+			return err
+		},
+		"EstimateEnergy": func() error {
+			_, err := sim.EstimateEnergy(bgCtx, call)
+			return err
+		},
+	}
+	for name, fn := range calls {
+		err := fn()
+		rerr, ok := err.(*revertError)
+		if !ok {
+			t.Fatalf("%s: expected revert error, got %T: %v", name, err, err)
+		}
+		if want := "execution reverted: InsufficientBalance(10, 20)"; rerr.Error() != want {
+			t.Errorf("%s: error mismatch: have %q, want %q", name, rerr.Error(), want)
+		}
+		if want := hexutil.Encode(data); rerr.ErrorData() != want {
+			t.Errorf("%s: error data mismatch: have %v, want %v", name, rerr.ErrorData(), want)
+		}
+		decoded := rerr.CustomError()
+		if decoded == nil {
+			t.Fatalf("%s: custom error not decoded", name)
+		}
+		if decoded.Name != "InsufficientBalance" {
+			t.Errorf("%s: error name mismatch: have %s, want InsufficientBalance", name, decoded.Name)
+		}
+		want := []interface{}{big.NewInt(10), big.NewInt(20)}
+		if !reflect.DeepEqual(decoded.Args, want) {
+			t.Errorf("%s: error args mismatch: have %v, want %v", name, decoded.Args, want)
+		}
+	}
+}
+
+func TestSimulatedBackend_ImportChain(t *testing.T) {
+	var (
+		bgCtx = context.Background()
+		alloc = core.GenesisAlloc{testKey.Address(): {Balance: big.NewInt(10000000000000000)}}
+	)
+	recipientKey, _ := crypto.GenerateKey(crand.Reader)
+	recipient := recipientKey.Address()
+
+	src := NewSimulatedBackend(alloc, 10000000)
+	defer src.Close()
+
+	// Build a few blocks with some value transfers on the source backend
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(uint64(i), recipient, big.NewInt(1000), params.TxEnergy, big.NewInt(1), nil)
+		signedTx, err := types.SignTx(tx, types.NewNucleusSigner(src.config.NetworkID), testKey)
+		if err != nil {
+			t.Fatalf("could not sign tx: %v", err)
+		}
+		if err := src.SendTransaction(bgCtx, signedTx); err != nil {
+			t.Fatalf("could not add tx to pending block: %v", err)
+		}
+		src.Commit()
+	}
+	// Export the built range and replay it on a fresh backend
+	head := src.blockchain.CurrentBlock().NumberU64()
+
+	var blocks []*types.Block
+	for n := uint64(1); n <= head; n++ {
+		blocks = append(blocks, src.blockchain.GetBlockByNumber(n))
+	}
+	dst := NewSimulatedBackend(alloc, 10000000)
+	defer dst.Close()
+
+	if err := dst.ImportChain(blocks); err != nil {
+		t.Fatalf("could not import chain: %v", err)
+	}
+	if have, want := dst.blockchain.CurrentBlock().Hash(), src.blockchain.CurrentBlock().Hash(); have != want {
+		t.Fatalf("head mismatch: have %x, want %x", have, want)
+	}
+	if have, want := dst.blockchain.CurrentBlock().Root(), src.blockchain.CurrentBlock().Root(); have != want {
+		t.Fatalf("head state mismatch: have %x, want %x", have, want)
+	}
+	balance, err := dst.BalanceAt(bgCtx, recipient, nil)
+	if err != nil {
+		t.Fatalf("could not get balance: %v", err)
+	}
+	if balance.Cmp(big.NewInt(3000)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 3000", balance)
+	}
+	// The pending state should be rebuilt on top of the imported head
+	nonce, err := dst.PendingNonceAt(bgCtx, testKey.Address())
+	if err != nil {
+		t.Fatalf("could not get pending nonce: %v", err)
+	}
+	if nonce != 3 {
+		t.Fatalf("pending nonce mismatch: have %d, want 3", nonce)
+	}
+	// Blocks not connecting to the local chain should be rejected
+	other := NewSimulatedBackend(alloc, 10000000)
+	defer other.Close()
+
+	if err := other.ImportChain(blocks[1:]); err == nil {
+		t.Fatal("imported disconnected block range")
+	}
+	if number := other.blockchain.CurrentBlock().NumberU64(); number != 0 {
+		t.Fatalf("head advanced after failed import: have #%d, want #0", number)
+	}
+}
+
+func TestSimulatedBackend_ExportGenesis(t *testing.T) {
+	var (
+		bgCtx      = context.Background()
+		storageKey = common.HexToHash("0x01")
+		storageVal = common.HexToHash("0xc0ffee")
+	)
+	holderKey, _ := crypto.GenerateKey(crand.Reader)
+	recipientKey, _ := crypto.GenerateKey(crand.Reader)
+	holder, recipient := holderKey.Address(), recipientKey.Address()
+
+	sim := NewSimulatedBackend(
+		core.GenesisAlloc{
+			testKey.Address(): {Balance: big.NewInt(10000000000000000)},
+			holder:            {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{storageKey: storageVal}},
+		}, 10000000,
+	)
+	defer sim.Close()
+
+	// Deploy a contract and transfer some funds to populate the state
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("could not parse abi: %v", err)
+	}
+	auth, _ := bind.NewKeyedTransactorWithNetworkID(testKey, big.NewInt(1))
+	contractAddr, _, _, err := bind.DeployContract(auth, parsed, common.FromHex(abiBin), sim)
+	if err != nil {
+		t.Fatalf("could not deploy contract: %v", err)
+	}
+	tx := types.NewTransaction(1, recipient, big.NewInt(1000), params.TxEnergy, big.NewInt(1), nil)
+	signedTx, err := types.SignTx(tx, types.NewNucleusSigner(sim.config.NetworkID), testKey)
+	if err != nil {
+		t.Fatalf("could not sign tx: %v", err)
+	}
+	if err := sim.SendTransaction(bgCtx, signedTx); err != nil {
+		t.Fatalf("could not add tx to pending block: %v", err)
+	}
+	sim.Commit()
+
+	genesis, err := sim.ExportGenesis()
+	if err != nil {
+		t.Fatalf("could not export genesis: %v", err)
+	}
+	if genesis.EnergyLimit != sim.blockchain.CurrentBlock().EnergyLimit() {
+		t.Errorf("energy limit mismatch: have %d, want %d", genesis.EnergyLimit, sim.blockchain.CurrentBlock().EnergyLimit())
+	}
+	exported := NewSimulatedBackend(genesis.Alloc, genesis.EnergyLimit)
+	defer exported.Close()
+
+	for _, addr := range []common.Address{testKey.Address(), holder, recipient, contractAddr} {
+		want, _ := sim.BalanceAt(bgCtx, addr, nil)
+		have, err := exported.BalanceAt(bgCtx, addr, nil)
+		if err != nil {
+			t.Fatalf("could not get balance of %s: %v", addr.Hex(), err)
+		}
+		if have.Cmp(want) != 0 {
+			t.Errorf("balance mismatch for %s: have %v, want %v", addr.Hex(), have, want)
+		}
+		wantNonce, _ := sim.NonceAt(bgCtx, addr, nil)
+		haveNonce, err := exported.NonceAt(bgCtx, addr, nil)
+		if err != nil {
+			t.Fatalf("could not get nonce of %s: %v", addr.Hex(), err)
+		}
+		if haveNonce != wantNonce {
+			t.Errorf("nonce mismatch for %s: have %d, want %d", addr.Hex(), haveNonce, wantNonce)
+		}
+	}
+	code, err := exported.CodeAt(bgCtx, contractAddr, nil)
+	if err != nil {
+		t.Fatalf("could not get code: %v", err)
+	}
+	if !bytes.Equal(code, common.FromHex(deployedCode)) {
+		t.Errorf("code mismatch: have %x, want %s", code, deployedCode)
+	}
+	val, err := exported.StorageAt(bgCtx, holder, storageKey, nil)
+	if err != nil {
+		t.Fatalf("could not get storage: %v", err)
+	}
+	if !bytes.Equal(val, storageVal[:]) {
+		t.Errorf("storage mismatch: have %x, want %x", val, storageVal)
+	}
+}
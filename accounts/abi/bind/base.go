@@ -21,6 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"strings"
+	"sync"
 
 	core "github.com/core-coin/go-core/v2"
 	"github.com/core-coin/go-core/v2/accounts/abi"
@@ -40,6 +43,7 @@ type CallOpts struct {
 	From        common.Address  // Optional the sender address, otherwise the first account is used
 	BlockNumber *big.Int        // Optional the block number on which the call should be performed
 	Context     context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+	Retry       *RetryPolicy    // Optional policy to retry transient backend failures (nil = no retries)
 }
 
 // TransactOpts is the collection of authorization data required to create a
@@ -54,6 +58,9 @@ type TransactOpts struct {
 	EnergyLimit uint64   // Energy limit to set for the transaction execution (0 = estimate)
 
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+	Retry   *RetryPolicy    // Optional policy to retry transient backend failures (nil = no retries)
+
+	NoSend bool // Do all transact steps but do not send the transaction
 }
 
 // FilterOpts is the collection of options to fine tune filtering for events
@@ -68,10 +75,35 @@ type FilterOpts struct {
 // WatchOpts is the collection of options to fine tune subscribing for events
 // within a bound contract.
 type WatchOpts struct {
-	Start   *uint64         // Start of the queried range (nil = latest)
+	Start   *uint64         // Start of the queried range, replaying the past logs first (nil = latest)
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 }
 
+// MetaData collects all metadata of a generated contract binding, giving access
+// to the parsed ABI without having to parse the JSON definition again.
+type MetaData struct {
+	ABI  string            // JSON definition of the contract ABI
+	Sigs map[string]string // Optional map: 4-byte signature -> string signature
+
+	once sync.Once
+	abi  *abi.ABI
+	err  error
+}
+
+// GetAbi returns the parsed ABI of the contract. The definition is only parsed
+// on the first invocation, subsequent ones return the cached result.
+func (m *MetaData) GetAbi() (*abi.ABI, error) {
+	m.once.Do(func() {
+		parsed, err := abi.JSON(strings.NewReader(m.ABI))
+		if err != nil {
+			m.err = err
+			return
+		}
+		m.abi = &parsed
+	})
+	return m.abi, m.err
+}
+
 // BoundContract is the base wrapper object that reflects a contract on the
 // Core network. It contains a collection of methods that are used by the
 // higher level contract bindings to operate.
@@ -101,6 +133,9 @@ func DeployContract(opts *TransactOpts, abi abi.ABI, bytecode []byte, backend Co
 	// Otherwise try to deploy the contract
 	c := NewBoundContract(common.Address{}, abi, backend, backend, backend)
 
+	if err := checkConstructorArgs(abi.Constructor.Inputs, params); err != nil {
+		return common.Address{}, nil, nil, err
+	}
 	input, err := c.abi.Pack("", params...)
 	if err != nil {
 		return common.Address{}, nil, nil, err
@@ -113,6 +148,27 @@ func DeployContract(opts *TransactOpts, abi abi.ABI, bytecode []byte, backend Co
 	return c.address, tx, c, nil
 }
 
+// checkConstructorArgs checks the constructor arguments one by one against the
+// inputs of the constructor, so that a mismatch names the offending parameter.
+func checkConstructorArgs(inputs abi.Arguments, params []interface{}) error {
+	if len(params) != len(inputs) {
+		return fmt.Errorf("constructor argument count mismatch: got %d for %d", len(params), len(inputs))
+	}
+	for i, input := range inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if v := reflect.ValueOf(params[i]); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return fmt.Errorf("constructor argument %s (%v): nil value", name, input.Type)
+		}
+		if _, err := (abi.Arguments{input}).Pack(params[i]); err != nil {
+			return fmt.Errorf("constructor argument %s (%v): %v", name, input.Type, err)
+		}
+	}
+	return nil
+}
+
 // Call invokes the (constant) contract method with params as input values and
 // sets the output to result. The result type might be a single field for simple
 // returns, a slice of interfaces for anonymous returns and a struct for named
@@ -141,23 +197,37 @@ func (c *BoundContract) Call(opts *CallOpts, results *[]interface{}, method stri
 		if !ok {
 			return ErrNoPendingState
 		}
-		output, err = pb.PendingCallContract(ctx, msg)
+		err = opts.Retry.do(ctx, func() (err error) {
+			output, err = pb.PendingCallContract(ctx, msg)
+			return err
+		})
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
-			if code, err = pb.PendingCodeAt(ctx, c.address); err != nil {
+			err = opts.Retry.do(ctx, func() (err error) {
+				code, err = pb.PendingCodeAt(ctx, c.address)
+				return err
+			})
+			if err != nil {
 				return err
 			} else if len(code) == 0 {
 				return ErrNoCode
 			}
 		}
 	} else {
-		output, err = c.caller.CallContract(ctx, msg, opts.BlockNumber)
+		err = opts.Retry.do(ctx, func() (err error) {
+			output, err = c.caller.CallContract(ctx, msg, opts.BlockNumber)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 		if len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
-			if code, err = c.caller.CodeAt(ctx, c.address, opts.BlockNumber); err != nil {
+			err = opts.Retry.do(ctx, func() (err error) {
+				code, err = c.caller.CodeAt(ctx, c.address, opts.BlockNumber)
+				return err
+			})
+			if err != nil {
 				return err
 			} else if len(code) == 0 {
 				return ErrNoCode
@@ -202,11 +272,58 @@ func (c *BoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error)
 	return c.transact(opts, &c.address, nil)
 }
 
+// EstimateEnergy estimates the energy needed to invoke the (paid) contract method
+// with params as input values, without sending any transaction.
+func (c *BoundContract) EstimateEnergy(opts *TransactOpts, method string, params ...interface{}) (uint64, error) {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return 0, err
+	}
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	return c.estimateEnergy(ensureContext(opts.Context), opts, &c.address, opts.EnergyPrice, value, input)
+}
+
+// estimateEnergy estimates the energy needed by a transaction with the given
+// input, sent to contract or creating a new one if contract is nil.
+func (c *BoundContract) estimateEnergy(ctx context.Context, opts *TransactOpts, contract *common.Address, energyPrice, value *big.Int, input []byte) (uint64, error) {
+	// Energy estimation cannot succeed without code for method invocations
+	if contract != nil {
+		var code []byte
+		err := opts.Retry.do(ctx, func() (err error) {
+			code, err = c.transactor.PendingCodeAt(ctx, c.address)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		} else if len(code) == 0 {
+			return 0, ErrNoCode
+		}
+	}
+	// If the contract surely has code (or code is not needed), estimate the transaction
+	var (
+		msg         = core.CallMsg{From: opts.From, To: contract, EnergyPrice: energyPrice, Value: value, Data: input}
+		energyLimit uint64
+	)
+	err := opts.Retry.do(ctx, func() (err error) {
+		energyLimit, err = c.transactor.EstimateEnergy(ctx, msg)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate energy needed: %v", err)
+	}
+	return energyLimit, nil
+}
+
 // transact executes an actual transaction invocation, first deriving any missing
 // authorization fields, and then scheduling the transaction for execution.
 func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
-	var err error
-
+	var (
+		err error
+		ctx = ensureContext(opts.Context)
+	)
 	// Ensure a valid value field and resolve the account nonce
 	value := opts.Value
 	if value == nil {
@@ -214,7 +331,10 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	}
 	var nonce uint64
 	if opts.Nonce == nil {
-		nonce, err = c.transactor.PendingNonceAt(ensureContext(opts.Context), opts.From)
+		err = opts.Retry.do(ctx, func() (err error) {
+			nonce, err = c.transactor.PendingNonceAt(ctx, opts.From)
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
 		}
@@ -224,26 +344,18 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	// Figure out the energy allowance and energy price values
 	energyPrice := opts.EnergyPrice
 	if energyPrice == nil {
-		energyPrice, err = c.transactor.SuggestEnergyPrice(ensureContext(opts.Context))
+		err = opts.Retry.do(ctx, func() (err error) {
+			energyPrice, err = c.transactor.SuggestEnergyPrice(ctx)
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to suggest energy price: %v", err)
 		}
 	}
 	energyLimit := opts.EnergyLimit
 	if energyLimit == 0 {
-		// Energy estimation cannot succeed without code for method invocations
-		if contract != nil {
-			if code, err := c.transactor.PendingCodeAt(ensureContext(opts.Context), c.address); err != nil {
-				return nil, err
-			} else if len(code) == 0 {
-				return nil, ErrNoCode
-			}
-		}
-		// If the contract surely has code (or code is not needed), estimate the transaction
-		msg := core.CallMsg{From: opts.From, To: contract, EnergyPrice: energyPrice, Value: value, Data: input}
-		energyLimit, err = c.transactor.EstimateEnergy(ensureContext(opts.Context), msg)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate energy needed: %v", err)
+		if energyLimit, err = c.estimateEnergy(ctx, opts, contract, energyPrice, value, input); err != nil {
+			return nil, err
 		}
 	}
 	// Create the transaction, sign it and schedule it for execution
@@ -260,7 +372,10 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	if err != nil {
 		return nil, err
 	}
-	if err := c.transactor.SendTransaction(ensureContext(opts.Context), signedTx); err != nil {
+	if opts.NoSend {
+		return signedTx, nil
+	}
+	if err := opts.Retry.do(ctx, func() error { return c.transactor.SendTransaction(ctx, signedTx) }); err != nil {
 		return nil, err
 	}
 	return signedTx, nil
@@ -329,16 +444,16 @@ func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]inter
 	if err != nil {
 		return nil, nil, err
 	}
-	// Start the background filtering
-	logs := make(chan types.Log, 128)
-
 	config := core.FilterQuery{
 		Addresses: []common.Address{c.address},
 		Topics:    topics,
 	}
 	if opts.Start != nil {
-		config.FromBlock = new(big.Int).SetUint64(*opts.Start)
+		return c.watchLogsFrom(ensureContext(opts.Context), *opts.Start, config)
 	}
+	// Start the background filtering
+	logs := make(chan types.Log, 128)
+
 	sub, err := c.filterer.SubscribeFilterLogs(ensureContext(opts.Context), config, logs)
 	if err != nil {
 		return nil, nil, err
@@ -346,6 +461,98 @@ func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]inter
 	return logs, sub, nil
 }
 
+// maxPendingLogs is the maximum number of live logs buffered by a watcher started
+// from a past block, while its consumer catches up with the stream.
+const maxPendingLogs = 4096
+
+// ErrLogsOverflow is returned by a watcher started from a past block if its
+// consumer falls behind by more than maxPendingLogs live logs.
+var ErrLogsOverflow = errors.New("too many pending logs, consumer too slow")
+
+// watchLogsFrom streams the past logs matching the query starting from the given
+// block, then seamlessly continues with the future ones. The live subscription is
+// set up before retrieving the past logs so that nothing is missed in between,
+// with the logs delivered by both only being streamed once. The live logs are
+// buffered while the consumer catches up, so a slow one never blocks the backend,
+// up to maxPendingLogs, after which the subscription fails with ErrLogsOverflow.
+func (c *BoundContract) watchLogsFrom(ctx context.Context, start uint64, config core.FilterQuery) (chan types.Log, event.Subscription, error) {
+	live := make(chan types.Log, 128)
+	sub, err := c.filterer.SubscribeFilterLogs(ctx, config, live)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.FromBlock = new(big.Int).SetUint64(start)
+	past, err := c.filterer.FilterLogs(ctx, config)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, nil, err
+	}
+	logs := make(chan types.Log, 128)
+
+	return logs, event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+
+		// Replay the past logs, tracking the last one delivered and buffering
+		// the live logs arriving meanwhile
+		var (
+			last    *types.Log
+			pending []types.Log
+		)
+		for i := 0; i < len(past); {
+			select {
+			case logs <- past[i]:
+				last = &past[i]
+				i++
+			case log := <-live:
+				if len(pending) >= maxPendingLogs {
+					return ErrLogsOverflow
+				}
+				pending = append(pending, log)
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+		// Continue with the live logs, skipping the ones already replayed. Once
+		// a log past the replayed ones arrives, the rest are all streamed, the
+		// ones of reorged blocks included.
+		for {
+			for last != nil && len(pending) > 0 {
+				log := pending[0]
+				if log.Removed {
+					break
+				}
+				if log.BlockNumber < last.BlockNumber || (log.BlockNumber == last.BlockNumber && log.Index <= last.Index) {
+					pending = pending[1:]
+					continue
+				}
+				last = nil
+			}
+			var (
+				out  chan types.Log
+				next types.Log
+			)
+			if len(pending) > 0 {
+				out, next = logs, pending[0]
+			}
+			select {
+			case out <- next:
+				pending = pending[1:]
+			case log := <-live:
+				if len(pending) >= maxPendingLogs {
+					return ErrLogsOverflow
+				}
+				pending = append(pending, log)
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // UnpackLog unpacks a retrieved log into the provided output structure.
 func (c *BoundContract) UnpackLog(out interface{}, event string, log types.Log) error {
 	if len(log.Data) > 0 {
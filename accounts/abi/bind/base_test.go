@@ -18,19 +18,24 @@ package bind_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	core "github.com/core-coin/go-core/v2"
 	"github.com/core-coin/go-core/v2/accounts/abi"
 	"github.com/core-coin/go-core/v2/accounts/abi/bind"
+	"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/common/hexutil"
+	gcore "github.com/core-coin/go-core/v2/core"
 	"github.com/core-coin/go-core/v2/core/types"
 	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/event"
 	"github.com/core-coin/go-core/v2/rlp"
 )
 
@@ -265,3 +270,263 @@ func newMockLog(topics []common.Hash, txHash common.Hash) types.Log {
 		Removed:     false,
 	}
 }
+
+var errConnReset = errors.New("connection reset by peer")
+
+// flakyCaller is a contract caller failing a number of times before succeeding.
+type flakyCaller struct {
+	mockCaller
+	failures int   // Number of calls to fail before succeeding
+	err      error // Error to fail the calls with
+	calls    int
+}
+
+func (fc *flakyCaller) CallContract(ctx context.Context, call core.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	fc.calls++
+	if fc.calls <= fc.failures {
+		return nil, fc.err
+	}
+	return common.LeftPadBytes([]byte{42}, 32), nil
+}
+
+func TestCallRetry(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"get","outputs":[{"type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &bind.RetryPolicy{Attempts: 3, Transient: []error{errConnReset}}
+
+	tests := []struct {
+		failures  int
+		err       error
+		policy    *bind.RetryPolicy
+		wantErr   error
+		wantCalls int
+	}{
+		// Transient failures are retried until the call succeeds
+		{failures: 2, err: fmt.Errorf("post failed: %w", errConnReset), policy: policy, wantCalls: 3},
+		// Retries are bounded by the number of attempts
+		{failures: 3, err: errConnReset, policy: policy, wantErr: errConnReset, wantCalls: 3},
+		// Permanent failures are returned as is
+		{failures: 2, err: errors.New("execution reverted"), policy: policy, wantErr: errors.New("execution reverted"), wantCalls: 1},
+		// Without a policy no retries are made
+		{failures: 1, err: errConnReset, wantErr: errConnReset, wantCalls: 1},
+	}
+	for i, tt := range tests {
+		caller := &flakyCaller{failures: tt.failures, err: tt.err}
+		bc := bind.NewBoundContract(addr2, parsed, caller, nil, nil)
+
+		var out []interface{}
+		err := bc.Call(&bind.CallOpts{Retry: tt.policy}, &out, "get")
+		if tt.wantErr != nil {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+				t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.wantErr)
+			}
+		} else if err != nil {
+			t.Errorf("test %d: call failed: %v", i, err)
+		} else if have := out[0].(*big.Int); have.Cmp(big.NewInt(42)) != 0 {
+			t.Errorf("test %d: result mismatch: have %v, want 42", i, have)
+		}
+		if caller.calls != tt.wantCalls {
+			t.Errorf("test %d: call count mismatch: have %d, want %d", i, caller.calls, tt.wantCalls)
+		}
+	}
+}
+
+// Tests that transactions created with NoSend are signed and returned, but not
+// submitted to the backend.
+func TestTransactNoSend(t *testing.T) {
+	backend := backends.NewSimulatedBackend(gcore.GenesisAlloc{
+		testKey.Address(): {Balance: big.NewInt(10000000000)},
+	}, 10000000)
+	defer backend.Close()
+
+	opts, err := bind.NewKeyedTransactorWithNetworkID(testKey, backend.Blockchain().Config().NetworkID)
+	if err != nil {
+		t.Fatalf("failed to create transactor: %v", err)
+	}
+	opts.NoSend = true
+	opts.Value = big.NewInt(1000)
+	opts.EnergyLimit = 21000
+
+	recipient := crypto.CreateAddress(testKey.Address(), 1)
+	contract := bind.NewBoundContract(recipient, abi.ABI{}, backend, backend, backend)
+
+	tx, err := contract.Transfer(opts)
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+	// The transaction should be signed, but unknown to the backend
+	signer := types.NewNucleusSigner(backend.Blockchain().Config().NetworkID)
+	if from, err := types.Sender(signer, tx); err != nil || from != testKey.Address() {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", from, err, testKey.Address())
+	}
+	ctx := context.Background()
+	if _, _, err := backend.TransactionByHash(ctx, tx.Hash()); err != core.NotFound {
+		t.Fatalf("unsent transaction known to backend: %v", err)
+	}
+	if nonce, err := backend.PendingNonceAt(ctx, testKey.Address()); err != nil || nonce != 0 {
+		t.Fatalf("pending nonce mismatch: have %d (%v), want 0", nonce, err)
+	}
+	// Relaying the transaction manually should execute it
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	backend.Commit()
+
+	if balance, err := backend.BalanceAt(ctx, recipient, nil); err != nil || balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("recipient balance mismatch: have %v (%v), want 1000", balance, err)
+	}
+}
+
+// mockFilterer is a contract filterer returning a fixed set of past logs and
+// handing out the channel of the live subscription.
+type mockFilterer struct {
+	past []types.Log
+	live chan chan<- types.Log
+}
+
+func (mf *mockFilterer) FilterLogs(ctx context.Context, query core.FilterQuery) ([]types.Log, error) {
+	return mf.past, nil
+}
+
+func (mf *mockFilterer) SubscribeFilterLogs(ctx context.Context, query core.FilterQuery, ch chan<- types.Log) (core.Subscription, error) {
+	mf.live <- ch
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+// Tests that the live logs arriving while the past ones are replayed to a slow
+// consumer don't block the backend, and are delivered after them only once.
+func TestWatchLogsFromSlowConsumer(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"received","inputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var past []types.Log
+	for i := uint64(0); i < 200; i++ {
+		past = append(past, types.Log{BlockNumber: i, Index: 0})
+	}
+	filterer := &mockFilterer{past: past, live: make(chan chan<- types.Log, 1)}
+	bc := bind.NewBoundContract(addr2, parsed, nil, nil, filterer)
+
+	start := uint64(0)
+	logs, sub, err := bc.WatchLogs(&bind.WatchOpts{Start: &start}, "received")
+	if err != nil {
+		t.Fatalf("failed to watch logs: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// Emit live logs overlapping with the past ones without consuming anything
+	live := <-filterer.live
+	for i := uint64(150); i < 500; i++ {
+		select {
+		case live <- types.Log{BlockNumber: i, Index: 0}:
+		case <-time.After(time.Second):
+			t.Fatalf("live log %d blocked during replay", i)
+		}
+	}
+	for i := uint64(0); i < 500; i++ {
+		select {
+		case log := <-logs:
+			if log.BlockNumber != i {
+				t.Fatalf("log %d: block number mismatch: have %d, want %d", i, log.BlockNumber, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("log %d didn't arrive", i)
+		}
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("duplicate log arrived: %v", log)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Tests that only the live logs overlapping with the replayed ones are skipped,
+// the ones of reorged blocks arriving later are all delivered.
+func TestWatchLogsFromReorg(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"received","inputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := []types.Log{{BlockNumber: 1, Index: 0}, {BlockNumber: 2, Index: 0}, {BlockNumber: 2, Index: 1}}
+	filterer := &mockFilterer{past: past, live: make(chan chan<- types.Log, 1)}
+	bc := bind.NewBoundContract(addr2, parsed, nil, nil, filterer)
+
+	start := uint64(0)
+	logs, sub, err := bc.WatchLogs(&bind.WatchOpts{Start: &start}, "received")
+	if err != nil {
+		t.Fatalf("failed to watch logs: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	live := <-filterer.live
+	for _, log := range []types.Log{
+		{BlockNumber: 2, Index: 0},                // replayed
+		{BlockNumber: 2, Index: 1},                // replayed
+		{BlockNumber: 3, Index: 0},                // new
+		{BlockNumber: 3, Index: 0, Removed: true}, // reorged out
+		{BlockNumber: 2, Index: 0, Removed: true}, // reorged out
+		{BlockNumber: 2, Index: 0},                // new chain
+		{BlockNumber: 3, Index: 0},                // new chain
+	} {
+		live <- log
+	}
+	want := append(past, []types.Log{
+		{BlockNumber: 3, Index: 0},
+		{BlockNumber: 3, Index: 0, Removed: true},
+		{BlockNumber: 2, Index: 0, Removed: true},
+		{BlockNumber: 2, Index: 0},
+		{BlockNumber: 3, Index: 0},
+	}...)
+	for i, want := range want {
+		select {
+		case log := <-logs:
+			if log.BlockNumber != want.BlockNumber || log.Index != want.Index || log.Removed != want.Removed {
+				t.Fatalf("log %d: mismatch: have %d/%d/%v, want %d/%d/%v", i, log.BlockNumber, log.Index, log.Removed, want.BlockNumber, want.Index, want.Removed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("log %d didn't arrive", i)
+		}
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log arrived: %v", log)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Tests that a consumer falling too far behind ends the subscription instead of
+// buffering live logs without bounds.
+func TestWatchLogsFromOverflow(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"received","inputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filterer := &mockFilterer{past: []types.Log{{BlockNumber: 0}}, live: make(chan chan<- types.Log, 1)}
+	bc := bind.NewBoundContract(addr2, parsed, nil, nil, filterer)
+
+	start := uint64(0)
+	_, sub, err := bc.WatchLogs(&bind.WatchOpts{Start: &start}, "received")
+	if err != nil {
+		t.Fatalf("failed to watch logs: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	live := <-filterer.live
+	for i := uint64(1); ; i++ {
+		select {
+		case live <- types.Log{BlockNumber: i}:
+		case err := <-sub.Err():
+			if err != bind.ErrLogsOverflow {
+				t.Fatalf("error mismatch: have %v, want %v", err, bind.ErrLogsOverflow)
+			}
+			return
+		case <-time.After(time.Second):
+			t.Fatalf("live log %d blocked", i)
+		}
+	}
+}
@@ -22,9 +22,14 @@ package bind
 
 import (
 	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"go/format"
+	"go/token"
+	"go/types"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -33,11 +38,65 @@ import (
 	"github.com/core-coin/go-core/v2/log"
 )
 
+// Mode is a target output kind the binder is able to generate.
+type Mode int
+
+const (
+	ModeBinding Mode = iota // Go package wrapping the contract with typed methods
+	ModeCLI                 // Standalone Go main package exposing the contract on the command line
+	ModeMock                // Interfaces and recording mocks complementing the Go package binding
+	modeCaller              // Read-only Go package binding a deployed contract, see BindFromABIAndRuntimeCode
+)
+
+// TypeMapping binds method and constructor parameters of an ABI type to a custom
+// Go type instead of the default one, e.g. uint256 to a decimal type instead of
+// *big.Int. The generated code converts between the two with the adapters.
+// Event and struct fields always use the default binding.
+type TypeMapping struct {
+	Type   string   `json:"type"`   // Go type to bind to, qualified with its package name (e.g. decimal.Big)
+	Import string   `json:"import"` // Import path of the package declaring the type and adapters, if any
+	Pack   string   `json:"pack"`   // Function converting the custom type into the default binding
+	Unpack string   `json:"unpack"` // Function converting the default binding into the custom type
+	Params []string `json:"params"` // Names of the parameters to map, all of the ABI type if empty
+}
+
 // Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
 // to be used as is in client code, but rather as an intermediate struct which
 // enforces compile time type safety and naming convention opposed to having to
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, libs map[string]string, aliases map[string]string) (string, error) {
+	return bind(types, abis, bytecodes, nil, fsigs, pkg, ModeBinding, libs, aliases, nil)
+}
+
+// BindWithMode generates a Go wrapper around a contract ABI like Bind, with the
+// mode selecting the kind of output to generate, see Mode.
+func BindWithMode(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, mode Mode, libs map[string]string, aliases map[string]string) (string, error) {
+	return bind(types, abis, bytecodes, nil, fsigs, pkg, mode, libs, aliases, nil)
+}
+
+// BindWithMappings generates a Go wrapper around a contract ABI like Bind, with
+// the mappings replacing the Go types bound to ABI types (keyed by their
+// canonical ABI name, e.g. uint256), see TypeMapping.
+func BindWithMappings(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, mode Mode, libs map[string]string, aliases map[string]string, mappings map[string]TypeMapping) (string, error) {
+	return bind(types, abis, bytecodes, nil, fsigs, pkg, mode, libs, aliases, mappings)
+}
+
+// BindFromABIAndRuntimeCode generates a read-only Go wrapper around an already
+// deployed contract, given its ABI and the runtime bytecode as returned by
+// CodeAt (as opposed to the creation bytecode used for deployment). As the
+// runtime code cannot deploy new contracts, only the caller side of the binding
+// is generated, along with a Verify function checking whether the code at an
+// address matches the one the binding was generated from.
+func BindFromABIAndRuntimeCode(typ string, contractABI string, code []byte, pkg string, aliases map[string]string) (string, error) {
+	if len(code) == 0 {
+		return "", errors.New("no runtime code to generate the binding from")
+	}
+	return bind([]string{typ}, []string{contractABI}, []string{""}, []string{hex.EncodeToString(code)}, nil, pkg, modeCaller, nil, aliases, nil)
+}
+
+// bind generates the Go wrapper of the given mode around the contract ABIs,
+// optionally embedding the deploy and runtime bytecodes of the contracts.
+func bind(types []string, abis []string, bytecodes []string, runtimecodes []string, fsigs []map[string]string, pkg string, mode Mode, libs map[string]string, aliases map[string]string, mappings map[string]TypeMapping) (string, error) {
 	var (
 		// contracts is the map of each individual contract requested binding
 		contracts = make(map[string]*tmplContract)
@@ -47,7 +106,19 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 
 		// isLib is the map used to flag each encountered library as such
 		isLib = make(map[string]struct{})
+
+		// imports is the set of packages required by the used type mappings
+		imports = make(map[string]bool)
 	)
+	if mode == ModeCLI && len(types) != 1 {
+		return "", fmt.Errorf("command line interface requires exactly one contract, have %d", len(types))
+	}
+	if mode == ModeCLI && len(mappings) > 0 {
+		return "", errors.New("command line interface does not support type mappings")
+	}
+	if err := validateMappings(mappings); err != nil {
+		return "", err
+	}
 	for i := 0; i < len(types); i++ {
 		// Parse the actual ABI to generate the binding for
 		cvmABI, err := abi.JSON(strings.NewReader(abis[i]))
@@ -78,7 +149,21 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			transactIdentifiers = make(map[string]bool)
 			eventIdentifiers    = make(map[string]bool)
 		)
-		for _, original := range cvmABI.Methods {
+		// Walk the methods and events in a fixed order, as the names of the structs
+		// are assigned in the order they are first encountered
+		methodNames := make([]string, 0, len(cvmABI.Methods))
+		for name := range cvmABI.Methods {
+			methodNames = append(methodNames, name)
+		}
+		sort.Strings(methodNames)
+		eventNames := make([]string, 0, len(cvmABI.Events))
+		for name := range cvmABI.Events {
+			eventNames = append(eventNames, name)
+		}
+		sort.Strings(eventNames)
+
+		for _, name := range methodNames {
+			original := cvmABI.Methods[name]
 			// Normalize the method for capital cases and non-anonymous inputs/outputs
 			normalized := original
 			normalizedName := abi.ToCamelCase(alias(aliases, original.Name))
@@ -94,10 +179,8 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			normalized.Name = normalizedName
 			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
 			copy(normalized.Inputs, original.Inputs)
-			for j, input := range normalized.Inputs {
-				if input.Name == "" {
-					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
-				}
+			sanitizeArgNames(normalized.Inputs)
+			for _, input := range normalized.Inputs {
 				if hasStruct(input.Type) {
 					bindStructType(input.Type, structs)
 				}
@@ -119,7 +202,8 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 				transacts[original.Name] = &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original.Outputs)}
 			}
 		}
-		for _, original := range cvmABI.Events {
+		for _, name := range eventNames {
+			original := cvmABI.Events[name]
 			// Skip anonymous events as they don't support explicit filtering
 			if original.Anonymous {
 				continue
@@ -137,10 +221,8 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 
 			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
 			copy(normalized.Inputs, original.Inputs)
-			for j, input := range normalized.Inputs {
-				if input.Name == "" {
-					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
-				}
+			sanitizeArgNames(normalized.Inputs)
+			for _, input := range normalized.Inputs {
 				if hasStruct(input.Type) {
 					bindStructType(input.Type, structs)
 				}
@@ -156,11 +238,17 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			receive = &tmplMethod{Original: cvmABI.Receive}
 		}
 
+		// Sanitize the constructor arguments, which become the parameters of Deploy
+		constructor := cvmABI.Constructor
+		constructor.Inputs = make([]abi.Argument, len(cvmABI.Constructor.Inputs))
+		copy(constructor.Inputs, cvmABI.Constructor.Inputs)
+		sanitizeArgNames(constructor.Inputs)
+
 		contracts[types[i]] = &tmplContract{
 			Type:        capitalise(types[i]),
 			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
 			InputBin:    strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"),
-			Constructor: cvmABI.Constructor,
+			Constructor: constructor,
 			Calls:       calls,
 			Transacts:   transacts,
 			Fallback:    fallback,
@@ -168,11 +256,28 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			Events:      events,
 			Libraries:   make(map[string]string),
 		}
+		// Track the packages of the type mappings used by the contract
+		var args []abi.Argument
+		if mode == ModeBinding {
+			args = append(args, constructor.Inputs...)
+		}
+		for _, method := range cvmABI.Methods {
+			args = append(append(args, method.Inputs...), method.Outputs...)
+		}
+		for _, arg := range args {
+			if m := mapping(mappings, arg); m != nil && m.Import != "" {
+				imports[m.Import] = true
+			}
+		}
 		// Function 4-byte signatures are stored in the same sequence
 		// as types, if available.
 		if len(fsigs) > i {
 			contracts[types[i]].FuncSigs = fsigs[i]
 		}
+		// Runtime bytecodes are only available for deployed contracts.
+		if len(runtimecodes) > i {
+			contracts[types[i]].RuntimeBin = strings.TrimPrefix(strings.TrimSpace(runtimecodes[i]), "0x")
+		}
 		// Parse library references.
 		for pattern, name := range libs {
 			matched, err := regexp.Match("__\\$"+pattern+"\\$__", []byte(contracts[types[i]].InputBin))
@@ -193,6 +298,10 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		_, ok := isLib[types[i]]
 		contracts[types[i]].Library = ok
 	}
+	source, ok := tmplSource[mode]
+	if !ok {
+		return "", fmt.Errorf("unsupported binding mode: %d", mode)
+	}
 	// Generate the contract template data content and render it
 	data := &tmplData{
 		Package:   pkg,
@@ -200,6 +309,14 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		Libraries: libs,
 		Structs:   structs,
 	}
+	for path := range imports {
+		// Skip the packages the template imports anyway
+		if !strings.Contains(source, `"`+path+`"`) {
+			data.Imports = append(data.Imports, path)
+		}
+	}
+	sort.Strings(data.Imports)
+
 	buffer := new(bytes.Buffer)
 
 	funcs := map[string]interface{}{
@@ -207,8 +324,29 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		"bindtopictype": bindTopicType,
 		"capitalise":    capitalise,
 		"decapitalise":  decapitalise,
+		"caller": func(contract *tmplContract, structs map[string]*tmplStruct) *tmplCaller {
+			return &tmplCaller{Contract: contract, Structs: structs}
+		},
+		"bindarg": func(arg abi.Argument, structs map[string]*tmplStruct) string {
+			if m := mapping(mappings, arg); m != nil {
+				return m.Type
+			}
+			return bindType(arg.Type, structs)
+		},
+		"packarg": func(arg abi.Argument) string {
+			if m := mapping(mappings, arg); m != nil {
+				return m.Pack + "(" + arg.Name + ")"
+			}
+			return arg.Name
+		},
+		"unpackarg": func(arg abi.Argument, value string) string {
+			if m := mapping(mappings, arg); m != nil {
+				return m.Unpack + "(" + value + ")"
+			}
+			return value
+		},
 	}
-	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSource))
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(source))
 	if err := tmpl.Execute(buffer, data); err != nil {
 		return "", err
 	}
@@ -249,7 +387,7 @@ func bindBasicTypeGo(kind abi.Type) string {
 func bindType(kind abi.Type, structs map[string]*tmplStruct) string {
 	switch kind.T {
 	case abi.TupleTy:
-		return structs[kind.TupleRawName+kind.String()].Name
+		return structs[structID(kind)].Name
 	case abi.ArrayTy:
 		return fmt.Sprintf("[%d]", kind.Size) + bindType(*kind.Elem, structs)
 	case abi.SliceTy:
@@ -282,13 +420,9 @@ func bindTopicType(kind abi.Type, structs map[string]*tmplStruct) string {
 func bindStructType(kind abi.Type, structs map[string]*tmplStruct) string {
 	switch kind.T {
 	case abi.TupleTy:
-		// We compose a raw struct name and a canonical parameter expression
-		// together here. The reason is before ylem v0.5.11, kind.TupleRawName
-		// is empty, so we use canonical parameter expression to distinguish
-		// different struct definition. From the consideration of backward
-		// compatibility, we concat these two together so that if kind.TupleRawName
-		// is not empty, it can have unique id.
-		id := kind.TupleRawName + kind.String()
+		// Structs are deduplicated by their canonical identifier, so the same
+		// struct referenced by multiple contracts is only declared once.
+		id := structID(kind)
 		if s, exist := structs[id]; exist {
 			return s.Name
 		}
@@ -301,6 +435,7 @@ func bindStructType(kind abi.Type, structs map[string]*tmplStruct) string {
 		if name == "" {
 			name = fmt.Sprintf("Struct%d", len(structs))
 		}
+		name = uniqueStructName(name, structs)
 		structs[id] = &tmplStruct{
 			Name:   name,
 			Fields: fields,
@@ -315,6 +450,85 @@ func bindStructType(kind abi.Type, structs map[string]*tmplStruct) string {
 	}
 }
 
+// uniqueStructName returns the given struct name, suffixed with the first free
+// index if it is already taken by another struct. Definitions sharing the raw
+// name but differing in their fields are thus declared under distinct names.
+func uniqueStructName(name string, structs map[string]*tmplStruct) string {
+	taken := make(map[string]bool, len(structs))
+	for _, s := range structs {
+		taken[s.Name] = true
+	}
+	if !taken[name] {
+		return name
+	}
+	for i := 0; ; i++ {
+		if suffixed := fmt.Sprintf("%s%d", name, i); !taken[suffixed] {
+			return suffixed
+		}
+	}
+}
+
+// structID returns the canonical identifier of a struct type. We compose the raw
+// struct name and a canonical parameter expression, including the field names,
+// together here. The reason is before ylem v0.5.11, kind.TupleRawName is empty,
+// so we use the parameter expression to distinguish different struct definitions.
+// The identifier only depends on the parsed type, not on the textual layout of
+// the ABI it originates from, so a library struct shared by multiple contracts
+// always maps to the same identifier.
+func structID(kind abi.Type) string {
+	switch kind.T {
+	case abi.TupleTy:
+		fields := make([]string, len(kind.TupleElems))
+		for i, elem := range kind.TupleElems {
+			fields[i] = structID(*elem) + " " + kind.TupleRawNames[i]
+		}
+		return kind.TupleRawName + "(" + strings.Join(fields, ",") + ")"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", structID(*kind.Elem), kind.Size)
+	case abi.SliceTy:
+		return structID(*kind.Elem) + "[]"
+	default:
+		return kind.String()
+	}
+}
+
+// validateMappings checks that the type mappings refer to valid ABI types and
+// specify both the Go type and the adapters converting it.
+func validateMappings(mappings map[string]TypeMapping) error {
+	for name, m := range mappings {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			return fmt.Errorf("invalid type mapping %q: %v", name, err)
+		}
+		if typ.T == abi.TupleTy || typ.String() != name {
+			return fmt.Errorf("invalid type mapping %q: not a canonical ABI type", name)
+		}
+		if m.Type == "" || m.Pack == "" || m.Unpack == "" {
+			return fmt.Errorf("invalid type mapping %q: type and adapters required", name)
+		}
+	}
+	return nil
+}
+
+// mapping returns the type mapping of the given argument, or nil if it is bound
+// to its default Go type.
+func mapping(mappings map[string]TypeMapping, arg abi.Argument) *TypeMapping {
+	m, exist := mappings[arg.Type.String()]
+	if !exist {
+		return nil
+	}
+	if len(m.Params) == 0 {
+		return &m
+	}
+	// Parameter names are normalized by the binder, compare the Go forms
+	for _, param := range m.Params {
+		if capitalise(param) == capitalise(arg.Name) {
+			return &m
+		}
+	}
+	return nil
+}
+
 // alias returns an alias of the given string based on the aliasing rules
 // or returns itself if no rule is matched.
 func alias(aliases map[string]string, n string) string {
@@ -337,6 +551,40 @@ func decapitalise(input string) string {
 	return strings.ToLower(goForm[:1]) + goForm[1:]
 }
 
+// reservedIdentifiers are the identifiers used by the generated method bodies,
+// which the arguments of the bound methods must not shadow.
+var reservedIdentifiers = map[string]bool{
+	"opts": true, "out": true, "err": true, "logs": true, "sub": true, "sink": true,
+	"abi": true, "bind": true, "big": true, "common": true, "core": true,
+	"errors": true, "event": true, "strings": true, "types": true,
+	"parsed": true, "bin": true, "address": true, "tx": true, "contract": true,
+	"auth": true, "backend": true, "libraries": true,
+}
+
+// sanitizeArgNames names the anonymous arguments, and renames the ones which
+// can't be used as Go parameters in the generated code (keywords, predeclared
+// identifiers and the identifiers of reservedIdentifiers) by appending
+// underscores. As capitalise drops the underscores, the names of the struct
+// fields derived from the arguments are unchanged.
+func sanitizeArgNames(args []abi.Argument) {
+	taken := make(map[string]bool)
+	for _, arg := range args {
+		taken[arg.Name] = true
+	}
+	for j := range args {
+		name := args[j].Name
+		switch {
+		case name == "":
+			args[j].Name = fmt.Sprintf("arg%d", j)
+		case token.IsKeyword(name) || types.Universe.Lookup(name) != nil || reservedIdentifiers[name]:
+			for name += "_"; taken[name]; name += "_" {
+			}
+			taken[name] = true
+			args[j].Name = name
+		}
+	}
+}
+
 // structured checks whether a list of ABI data types has enough information to
 // operate through a proper Go struct or if flat returns are needed.
 func structured(args abi.Arguments) bool {
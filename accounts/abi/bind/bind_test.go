@@ -22,10 +22,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/core-coin/go-core/v2/accounts/abi"
 	"github.com/core-coin/go-core/v2/common"
 )
 
@@ -70,11 +72,33 @@ var bindTests = []struct {
 		`https://coreblockchain.cc/token`,
 		[]string{`60606040526040516107fd3803806107fd83398101604052805160805160a05160c051929391820192909101600160a060020a0333166000908152600360209081526040822086905581548551838052601f6002600019610100600186161502019093169290920482018390047f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e56390810193919290918801908390106100e857805160ff19168380011785555b506101189291505b8082111561017157600081556001016100b4565b50506002805460ff19168317905550505050610658806101a56000396000f35b828001600101855582156100ac579182015b828111156100ac5782518260005055916020019190600101906100fa565b50508060016000509080519060200190828054600181600116156101000203166002900490600052602060002090601f016020900481019282601f1061017557805160ff19168380011785555b506100c89291506100b4565b5090565b82800160010185558215610165579182015b8281111561016557825182600050559160200191906001019061018756606060405236156100775760e060020a600035046306fdde03811461007f57806323b872dd146100dc578063313ce5671461010e57806370a082311461011a57806395d89b4114610132578063a9059cbb1461018e578063cae9ca51146101bd578063dc3080f21461031c578063dd62ed3e14610341575b610365610002565b61036760008054602060026001831615610100026000190190921691909104601f810182900490910260809081016040526060828152929190828280156104eb5780601f106104c0576101008083540402835291602001916104eb565b6103d5600435602435604435600160a060020a038316600090815260036020526040812054829010156104f357610002565b6103e760025460ff1681565b6103d560043560036020526000908152604090205481565b610367600180546020600282841615610100026000190190921691909104601f810182900490910260809081016040526060828152929190828280156104eb5780601f106104c0576101008083540402835291602001916104eb565b610365600435602435600160a060020a033316600090815260036020526040902054819010156103f157610002565b60806020604435600481810135601f8101849004909302840160405260608381526103d5948235946024803595606494939101919081908382808284375094965050505050505060006000836004600050600033600160a060020a03168152602001908152602001600020600050600087600160a060020a031681526020019081526020016000206000508190555084905080600160a060020a0316638f4ffcb1338630876040518560e060020a0281526004018085600160a060020a0316815260200184815260200183600160a060020a03168152602001806020018281038252838181518152602001915080519060200190808383829060006004602084601f0104600f02600301f150905090810190601f1680156102f25780820380516001836020036101000a031916815260200191505b50955050505050506000604051808303816000876161da5a03f11561000257505050509392505050565b6005602090815260043560009081526040808220909252602435815220546103d59081565b60046020818152903560009081526040808220909252602435815220546103d59081565b005b60405180806020018281038252838181518152602001915080519060200190808383829060006004602084601f0104600f02600301f150905090810190601f1680156103c75780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b60408051918252519081900360200190f35b6060908152602090f35b600160a060020a03821660009081526040902054808201101561041357610002565b806003600050600033600160a060020a03168152602001908152602001600020600082828250540392505081905550806003600050600084600160a060020a0316815260200190815260200160002060008282825054019250508190555081600160a060020a031633600160a060020a03167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef836040518082815260200191505060405180910390a35050565b820191906000526020600020905b8154815290600101906020018083116104ce57829003601f168201915b505050505081565b600160a060020a03831681526040812054808301101561051257610002565b600160a060020a0380851680835260046020908152604080852033949094168086529382528085205492855260058252808520938552929052908220548301111561055c57610002565b816003600050600086600160a060020a03168152602001908152602001600020600082828250540392505081905550816003600050600085600160a060020a03168152602001908152602001600020600082828250540192505081905550816005600050600086600160a060020a03168152602001908152602001600020600050600033600160a060020a0316815260200190815260200160002060008282825054019250508190555082600160a060020a031633600160a060020a03167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef846040518082815260200191505060405180910390a3939250505056`},
 		[]string{`[{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},{"constant":false,"inputs":[{"name":"_from","type":"address"},{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"success","type":"bool"}],"type":"function"},{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":true,"inputs":[{"name":"","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[],"type":"function"},{"constant":false,"inputs":[{"name":"_spender","type":"address"},{"name":"_value","type":"uint256"},{"name":"_extraData","type":"bytes"}],"name":"approveAndCall","outputs":[{"name":"success","type":"bool"}],"type":"function"},{"constant":true,"inputs":[{"name":"","type":"address"},{"name":"","type":"address"}],"name":"spentAllowance","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":true,"inputs":[{"name":"","type":"address"},{"name":"","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"inputs":[{"name":"initialSupply","type":"uint256"},{"name":"tokenName","type":"string"},{"name":"decimalUnits","type":"uint8"},{"name":"tokenSymbol","type":"string"}],"type":"constructor"},{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`},
-		`"github.com/core-coin/go-core/v2/common"`,
+		`
+			"fmt"
+			"sort"
+
+			"github.com/core-coin/go-core/v2/common"
+		`,
 		`
 			if b, err := NewToken(common.Address{}, nil); b == nil || err != nil {
 				t.Fatalf("binding (%v) nil or error (%v) not nil", b, nil)
 			}
+			parsed, err := TokenMetaData.GetAbi()
+			if err != nil {
+				t.Fatalf("failed to retrieve parsed ABI: %v", err)
+			}
+			if again, _ := TokenMetaData.GetAbi(); again != parsed {
+				t.Errorf("parsed ABI not cached")
+			}
+			var methods []string
+			for name := range parsed.Methods {
+				methods = append(methods, name)
+			}
+			sort.Strings(methods)
+
+			want := []string{"allowance", "approveAndCall", "balanceOf", "decimals", "name", "spentAllowance", "symbol", "transfer", "transferFrom"}
+			if fmt.Sprint(methods) != fmt.Sprint(want) {
+				t.Errorf("method list mismatch: have %v, want %v", methods, want)
+			}
 		`,
 		nil,
 		nil,
@@ -134,6 +158,71 @@ var bindTests = []struct {
 		nil,
 		nil,
 	},
+	// Test that inputs named after Go keywords and identifiers used by the
+	// generated code are renamed
+	{
+		`ReservedNames`, ``, []string{``},
+		[]string{`
+			[
+				{"type":"function","name":"keywords","constant":true,"inputs":[{"name":"type","type":"uint256"},{"name":"func","type":"string"},{"name":"range","type":"bool"}],"outputs":[]},
+				{"type":"function","name":"identifiers","constant":false,"inputs":[{"name":"opts","type":"uint256"},{"name":"err","type":"string"},{"name":"len","type":"uint256"},{"name":"len_","type":"uint256"}],"outputs":[]},
+				{"type":"event","name":"keyed","inputs":[{"name":"type","type":"uint256","indexed":true},{"name":"func","type":"string"}]}
+			]
+		`},
+		`
+			"fmt"
+			"math/big"
+
+			"github.com/core-coin/go-core/v2/common"
+		`,
+		`if b, err := NewReservedNames(common.Address{}, nil); b == nil || err != nil {
+			 t.Fatalf("binding (%v) nil or error (%v) not nil", b, nil)
+		 } else if false { // Don't run, just compile and test types
+			 err = b.Keywords(nil, big.NewInt(1), "", true)
+			 _, err = b.Identifiers(nil, big.NewInt(1), "", big.NewInt(2), big.NewInt(3))
+
+			 it, err := b.FilterKeyed(nil, []*big.Int{big.NewInt(1)})
+			 fmt.Println(it.Event.Type, it.Event.Func, err)
+		 }`,
+		nil,
+		nil,
+		nil,
+		nil,
+	},
+	// Test that constructor inputs named after Go keywords and the locals of the
+	// deploy functions are renamed
+	{
+		`ReservedDeployer`, ``,
+		[]string{
+			`6080604052____$0123456789abcdef0123456789abcdef01$____00`,
+			`6080604052`,
+		},
+		[]string{
+			`[{"type":"constructor","inputs":[{"name":"type","type":"uint256"},{"name":"parsed","type":"string"},{"name":"bin","type":"bool"},{"name":"address","type":"address"},{"name":"tx","type":"uint256"},{"name":"contract","type":"uint256"},{"name":"auth","type":"uint256"},{"name":"backend","type":"uint256"},{"name":"libraries","type":"uint256"},{"name":"opts","type":"uint256"}]}]`,
+			`[]`,
+		},
+		`
+			"math/big"
+
+			"github.com/core-coin/go-core/v2/accounts/abi/bind"
+			"github.com/core-coin/go-core/v2/common"
+		`,
+		`if false { // Don't run, just compile and test types
+			 var (
+				 auth    *bind.TransactOpts
+				 backend bind.ContractBackend
+				 n       = big.NewInt(1)
+			 )
+			 _, _, _, _ = DeployReservedDeployer(auth, backend, n, "", true, common.Address{}, n, n, n, n, n, n)
+			 _, _, _, _, _ = DeployReservedDeployerAll(auth, backend, n, "", true, common.Address{}, n, n, n, n, n, n)
+		 }`,
+		nil,
+		map[string]string{
+			"0123456789abcdef0123456789abcdef01": "ReservedLib",
+		},
+		nil,
+		[]string{"ReservedDeployer", "ReservedLib"},
+	},
 	// Test that named and anonymous outputs are handled correctly
 	{
 		`OutputChecker`, ``, []string{``},
@@ -273,8 +362,12 @@ var bindTests = []struct {
 			"math/big"
 			"crypto/rand"
 
+			"strings"
+
+			"github.com/core-coin/go-core/v2/accounts/abi"
 			"github.com/core-coin/go-core/v2/accounts/abi/bind"
 			"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
+			"github.com/core-coin/go-core/v2/common"
 			"github.com/core-coin/go-core/v2/core"
 			"github.com/core-coin/go-core/v2/crypto"
 		`,
@@ -286,6 +379,16 @@ var bindTests = []struct {
 			sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(10000000000)}}, 10000000)
 			defer sim.Close()
 
+			// Deploying with mismatching constructor arguments fails clearly
+			parsed, _ := abi.JSON(strings.NewReader(InteractorABI))
+			_, _, _, err := bind.DeployContract(auth, parsed, common.FromHex(InteractorBin), sim, big.NewInt(1))
+			if err == nil || !strings.HasPrefix(err.Error(), "constructor argument str (string): ") {
+				t.Fatalf("Unexpected error for wrong-typed constructor argument: %v", err)
+			}
+			_, _, _, err = bind.DeployContract(auth, parsed, common.FromHex(InteractorBin), sim)
+			if err == nil || err.Error() != "constructor argument count mismatch: got 0 for 1" {
+				t.Fatalf("Unexpected error for missing constructor argument: %v", err)
+			}
 			// Deploy an interaction tester contract and call a transaction on it
 			_, _, interactor, err := DeployInteractor(auth, sim, "Deploy string")
 			if err != nil {
@@ -577,6 +680,7 @@ var bindTests = []struct {
 		[]string{`608060405234801561001057600080fd5b506102fb806100206000396000f3fe608060405234801561001057600080fd5b50600436106100365760003560e01c80635f10819c1461003b578063944e3cd2146100be575b600080fd5b610043610137565b6040518080602001828103825283818151815260200191508051906020019080838360005b83811015610083578082015181840152602081019050610068565b50505050905090810190601f1680156100b05780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b610135600480360360208110156100d457600080fd5b81019080803590602001906401000000008111156100f157600080fd5b82018360208201111561010357600080fd5b8035906020019184600183028401116401000000008311171561012557600080fd5b90919293919293905050506101d5565b005b60008054600181600116156101000203166002900480601f0160208091040260200160405190810160405280929190818152602001828054600181600116156101000203166002900480156101cd5780601f106101a2576101008083540402835291602001916101cd565b820191906000526020600020905b8154815290600101906020018083116101b057829003601f168201915b505050505081565b620186a05a10156101e557600080fd5b8181600091906101f69291906101fb565b505050565b828054600181600116156101000203166002900490600052602060002090601f016020900481019282601f1061023c57803560ff191683800117855561026a565b8280016001018555821561026a579182015b8281111561026957823582559160200191906001019061024e565b5b509050610277919061027b565b5090565b61029d91905b80821115610299576000816000905550600101610281565b5090565b9056fea26469706673582212201b44f850fb5acb3fc3e391ce7fbae403bc47615e6faf470c82f419047d674cd564736f6c637827302e362e392d646576656c6f702e323032302e372e32312b636f6d6d69742e33633832373333370058`},
 		[]string{`[{"inputs":[{"internalType":"string","name":"value","type":"string"}],"name":"SetField","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"field","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"}]`},
 		`
+			"context"
 			"math/big"
 			"crypto/rand"
 
@@ -600,6 +704,20 @@ var bindTests = []struct {
 			}
 			sim.Commit()
 
+			// Estimate setting the field and check that no state is changed
+			energy, err := limiter.EstimateSetField(auth, "estimated")
+			if err != nil {
+				t.Fatalf("Failed to estimate energy: %v", err)
+			}
+			if energy < 100000 {
+				t.Fatalf("Energy estimate too low: have %d, want at least 100000", energy)
+			}
+			if nonce, _ := sim.PendingNonceAt(context.Background(), auth.From); nonce != 1 {
+				t.Fatalf("Nonce mismatch after estimation: have %d, want 1", nonce)
+			}
+			if field, _ := limiter.Field(nil); field != "" {
+				t.Fatalf("Field changed by estimation: have %v", field)
+			}
 			// Set the field with automatic estimation and check that it succeeds
 			if _, err := limiter.SetField(auth, "automatic"); err != nil {
 				t.Fatalf("Failed to call automatically energyed transaction: %v", err)
@@ -803,6 +921,7 @@ var bindTests = []struct {
 			"time"
 			"crypto/rand"
 
+			"github.com/core-coin/go-core/v2/accounts/abi"
 			"github.com/core-coin/go-core/v2/accounts/abi/bind"
 			"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
 			"github.com/core-coin/go-core/v2/common"
@@ -910,6 +1029,15 @@ var bindTests = []struct {
 			if dit.Event.NonIndexedString != "Hello" || string(dit.Event.NonIndexedBytes) != "World" ||	dit.Event.IndexedString != common.HexToHash("0x8ca66ee6b2fe4bb928a8e3cd2f508de4119c0895f22e011117e22cf9b13de7ef") || dit.Event.IndexedBytes != common.HexToHash("0x9916ad7f05695e7eb02cbd48e1aa93f04b8104a7d8698f6d150669128f4b174a") {
 				t.Errorf("dynamic log content mismatch: have %v, want {'0x06b3dfaec148fb1bb2b066f10ec285e7c9bf402ab32aa78a5d38e34566810cd2, '0xf2208c967df089f60420785795c0a9ba8896b0f6f1867fa7f1f12ad6f79c1a18', 'Hello', 'World'}", dit.Event)
 			}
+			if i, ok := abi.MatchIndexedDynamic(dit.Event.IndexedString, []interface{}{"Hi", "Hello", "Bye"}); !ok || i != 1 {
+				t.Errorf("indexed string match mismatch: have (%d, %v), want (1, true)", i, ok)
+			}
+			if i, ok := abi.MatchIndexedDynamic(dit.Event.IndexedBytes, []interface{}{"World", []byte("Hello"), []byte("World")}); !ok || i != 0 {
+				t.Errorf("indexed bytes match mismatch: have (%d, %v), want (0, true)", i, ok)
+			}
+			if _, ok := abi.MatchIndexedDynamic(dit.Event.IndexedString, []interface{}{"Bye", 42}); ok {
+				t.Errorf("unexpected indexed string match")
+			}
 			if dit.Next() {
 				t.Errorf("unexpected dynamic event found: %+v", dit.Event)
 			}
@@ -977,6 +1105,35 @@ var bindTests = []struct {
 				t.Fatalf("unsubscribed simple event arrived: %v", event)
 			case <-time.After(250 * time.Millisecond):
 			}
+			// Subscribe from an earlier block and ensure the past events arrive first
+			start := uint64(2)
+			sub, err = eventer.WatchSimpleEvent(&bind.WatchOpts{Start: &start}, ch, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to subscribe to past simple events: %v", err)
+			}
+			defer sub.Unsubscribe()
+
+			addr256, _ := common.HexToAddress("cb970000000000000000000000000000000000000256")
+			if _, err := eventer.RaiseSimpleEvent(auth, addr256, [32]byte{255}, true, big.NewInt(256)); err != nil {
+				t.Fatalf("failed to raise subscribed simple event: %v", err)
+			}
+			sim.Commit()
+
+			for i, want := range []uint64{11, 21, 22, 31, 32, 33, 255, 254, 256} {
+				select {
+				case event := <-ch:
+					if event.Value.Uint64() != want {
+						t.Errorf("event %d: simple log content mismatch: have %v, want %d", i, event, want)
+					}
+				case <-time.After(250 * time.Millisecond):
+					t.Fatalf("event %d: replayed simple event didn't arrive", i)
+				}
+			}
+			select {
+			case event := <-ch:
+				t.Fatalf("duplicate simple event arrived: %v", event)
+			case <-time.After(250 * time.Millisecond):
+			}
 		`,
 		nil,
 		nil,
@@ -1278,11 +1435,14 @@ var bindTests = []struct {
 			`[{"inputs":[{"internalType":"uint256","name":"a","type":"uint256"},{"internalType":"uint256","name":"b","type":"uint256"}],"name":"add","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`,
 		},
 		`
+			"bytes"
+			"context"
 			"math/big"
 			"crypto/rand"
 
 			"github.com/core-coin/go-core/v2/accounts/abi/bind"
 			"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
+			"github.com/core-coin/go-core/v2/common"
 			"github.com/core-coin/go-core/v2/core"
 			"github.com/core-coin/go-core/v2/crypto"
 		`,
@@ -1315,6 +1475,48 @@ var bindTests = []struct {
 			if res.Cmp(big.NewInt(3)) != 0 {
 				t.Fatalf("Add did not return the correct result: %d != %d", res, 3)
 			}
+
+			// Deploy the library and the contract again in one go, using
+			// explicit nonces for the consecutive deployments.
+			nonce, err := sim.PendingNonceAt(context.Background(), auth.From)
+			if err != nil {
+				t.Fatalf("Failed to retrieve nonce: %v", err)
+			}
+			auth.Nonce = new(big.Int).SetUint64(nonce)
+
+			addr, libs, _, linked, err := DeployUseLibraryAll(auth, sim)
+			if err != nil {
+				t.Fatalf("Failed to deploy linked contracts: %v", err)
+			}
+			sim.Commit()
+
+			if auth.Nonce.Uint64() != nonce {
+				t.Fatalf("Transaction options modified: nonce %v != %d", auth.Nonce, nonce)
+			}
+			if len(libs) != 1 {
+				t.Fatalf("Deployed library count mismatch: have %d, want 1", len(libs))
+			}
+			for _, lib := range []common.Address{libs["Math"], addr} {
+				if code, err := sim.CodeAt(context.Background(), lib, nil); err != nil || len(code) == 0 {
+					t.Fatalf("Contract %v not deployed: %v", lib, err)
+				}
+			}
+			// The contract must call the library deployed along with it, not
+			// the one linked by the earlier deployment.
+			code, err := sim.CodeAt(context.Background(), addr, nil)
+			if err != nil {
+				t.Fatalf("Failed to retrieve contract code: %v", err)
+			}
+			if !bytes.Contains(code, libs["Math"].Bytes()) {
+				t.Fatalf("Contract not linked to library %v", libs["Math"])
+			}
+			res, err = linked.Add(nil, big.NewInt(4), big.NewInt(5))
+			if err != nil {
+				t.Fatalf("Failed to call linked contract: %v", err)
+			}
+			if res.Cmp(big.NewInt(9)) != 0 {
+				t.Fatalf("Add did not return the correct result: %d != %d", res, 9)
+			}
 		`,
 		nil,
 		map[string]string{
@@ -1729,6 +1931,14 @@ func TestGolangBindings(t *testing.T) {
 		if err = ioutil.WriteFile(filepath.Join(pkg, strings.ToLower(tt.name)+".go"), []byte(bind), 0600); err != nil {
 			t.Fatalf("test %d: failed to write binding: %v", i, err)
 		}
+		// Generate the mocks alongside to ensure they compile for every contract
+		mock, err := BindWithMode(types, tt.abi, tt.bytecode, tt.fsigs, "bindtest", ModeMock, tt.libs, tt.aliases)
+		if err != nil {
+			t.Fatalf("test %d: failed to generate mock: %v", i, err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(pkg, strings.ToLower(tt.name)+"_mock.go"), []byte(mock), 0600); err != nil {
+			t.Fatalf("test %d: failed to write mock: %v", i, err)
+		}
 		// Generate the test file with the injected test code
 		code := fmt.Sprintf(`
 			package bindtest
@@ -1770,3 +1980,461 @@ func TestGolangBindings(t *testing.T) {
 		t.Fatalf("failed to run binding test: %v\n%s", err, out)
 	}
 }
+
+// Tests that the command line interface generated by the binder can be compiled
+// and exposes every contract method as a subcommand.
+func TestCommandLineInterface(t *testing.T) {
+	// Skip the test if no Go command can be found
+	gocmd := runtime.GOROOT() + "/bin/go"
+	if !common.FileExist(gocmd) {
+		t.Skip("go sdk not found for testing")
+	}
+	var (
+		token abi.ABI
+		code  string
+		err   error
+	)
+	for _, tt := range bindTests {
+		if tt.name != "Token" {
+			continue
+		}
+		if token, err = abi.JSON(strings.NewReader(tt.abi[0])); err != nil {
+			t.Fatalf("failed to parse token ABI: %v", err)
+		}
+		if code, err = BindWithMode([]string{tt.name}, tt.abi, tt.bytecode, nil, "main", ModeCLI, nil, nil); err != nil {
+			t.Fatalf("failed to generate command line interface: %v", err)
+		}
+	}
+	// Create a temporary workspace for the command and build it
+	ws, err := ioutil.TempDir("", "binding-cli-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary workspace: %v", err)
+	}
+	defer os.RemoveAll(ws)
+
+	pkg := filepath.Join(ws, "tokencli")
+	if err = os.MkdirAll(pkg, 0700); err != nil {
+		t.Fatalf("failed to create package: %v", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(pkg, "main.go"), []byte(code), 0600); err != nil {
+		t.Fatalf("failed to write command line interface: %v", err)
+	}
+	moder := exec.Command(gocmd, "mod", "init", "tokencli")
+	moder.Dir = pkg
+	if out, err := moder.CombinedOutput(); err != nil {
+		t.Fatalf("failed to convert command line interface to modules: %v\n%s", err, out)
+	}
+	pwd, _ := os.Getwd()
+	replacer := exec.Command(gocmd, "mod", "edit", "-x", "-require", "github.com/core-coin/go-core/v2@v2.0.0", "-replace", "github.com/core-coin/go-core/v2="+filepath.Join(pwd, "..", "..", "..")) // Repo root
+	replacer.Dir = pkg
+	if out, err := replacer.CombinedOutput(); err != nil {
+		t.Fatalf("failed to replace command line interface dependency to current source tree: %v\n%s", err, out)
+	}
+	tidier := exec.Command(gocmd, "mod", "tidy")
+	tidier.Dir = pkg
+	if out, err := tidier.CombinedOutput(); err != nil {
+		t.Fatalf("failed to tidy Go module file: %v\n%s", err, out)
+	}
+	binary := filepath.Join(ws, "tokencli.bin")
+	builder := exec.Command(gocmd, "build", "-o", binary)
+	builder.Dir = pkg
+	if out, err := builder.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build command line interface: %v\n%s", err, out)
+	}
+	// Ensure all the contract methods are listed as subcommands
+	out, err := exec.Command(binary, "help").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run command line interface: %v\n%s", err, out)
+	}
+	for name := range token.Methods {
+		if !strings.Contains(string(out), "\n     "+name+" ") {
+			t.Errorf("method %s missing from subcommands:\n%s", name, out)
+		}
+	}
+	// Ensure the method arguments are exposed as flags
+	out, err = exec.Command(binary, "help", "transferFrom").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run command line interface: %v\n%s", err, out)
+	}
+	for _, flag := range []string{"--from", "--to", "--value"} {
+		if !strings.Contains(string(out), flag) {
+			t.Errorf("flag %s missing from transferFrom:\n%s", flag, out)
+		}
+	}
+}
+
+// Tests that the mocks generated by the binder compile alongside the binding,
+// satisfy the same method set as it and record every invocation.
+func TestMockBindings(t *testing.T) {
+	// Skip the test if no Go command can be found
+	gocmd := runtime.GOROOT() + "/bin/go"
+	if !common.FileExist(gocmd) {
+		t.Skip("go sdk not found for testing")
+	}
+	// Create a temporary workspace and generate the token binding and mock into it
+	ws, err := ioutil.TempDir("", "binding-mock-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary workspace: %v", err)
+	}
+	defer os.RemoveAll(ws)
+
+	pkg := filepath.Join(ws, "bindtest")
+	if err = os.MkdirAll(pkg, 0700); err != nil {
+		t.Fatalf("failed to create package: %v", err)
+	}
+	for _, tt := range bindTests {
+		if tt.name != "Token" {
+			continue
+		}
+		for mode, file := range map[Mode]string{ModeBinding: "token.go", ModeMock: "token_mock.go"} {
+			code, err := BindWithMode([]string{tt.name}, tt.abi, tt.bytecode, nil, "bindtest", mode, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to generate %s: %v", file, err)
+			}
+			if err = ioutil.WriteFile(filepath.Join(pkg, file), []byte(code), 0600); err != nil {
+				t.Fatalf("failed to write %s: %v", file, err)
+			}
+		}
+	}
+	tester := `
+		package bindtest
+
+		import (
+			"math/big"
+			"testing"
+
+			"github.com/core-coin/go-core/v2/accounts/abi/bind"
+			"github.com/core-coin/go-core/v2/common"
+		)
+
+		func TestTokenMock(t *testing.T) {
+			mock := &MockToken{
+				BalanceOfFunc: func(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+					return big.NewInt(42), nil
+				},
+			}
+			var (
+				caller     TokenCallerAPI     = mock
+				transactor TokenTransactorAPI = mock
+			)
+			if balance, err := caller.BalanceOf(nil, common.Address{1}); err != nil || balance.Cmp(big.NewInt(42)) != 0 {
+				t.Fatalf("balance mismatch: have %v (%v), want 42", balance, err)
+			}
+			if name, err := caller.Name(nil); err != nil || name != "" {
+				t.Fatalf("unmocked call returned %q (%v)", name, err)
+			}
+			if tx, err := transactor.Transfer(&bind.TransactOpts{}, common.Address{2}, big.NewInt(1)); tx != nil || err != nil {
+				t.Fatalf("unmocked transaction returned %v (%v)", tx, err)
+			}
+			calls := mock.Recorded()
+			if len(calls) != 3 {
+				t.Fatalf("recorded call count mismatch: have %d, want 3", len(calls))
+			}
+			for i, method := range []string{"BalanceOf", "Name", "Transfer"} {
+				if calls[i].Method != method {
+					t.Errorf("call %d: method mismatch: have %s, want %s", i, calls[i].Method, method)
+				}
+			}
+			if to := calls[2].Args[1].(common.Address); to != (common.Address{2}) {
+				t.Errorf("recorded argument mismatch: have %x, want %x", to, common.Address{2})
+			}
+		}
+	`
+	if err := ioutil.WriteFile(filepath.Join(pkg, "token_test.go"), []byte(tester), 0600); err != nil {
+		t.Fatalf("failed to write tests: %v", err)
+	}
+	// Convert the package to go modules and use the current source for go-core
+	moder := exec.Command(gocmd, "mod", "init", "bindtest")
+	moder.Dir = pkg
+	if out, err := moder.CombinedOutput(); err != nil {
+		t.Fatalf("failed to convert binding test to modules: %v\n%s", err, out)
+	}
+	pwd, _ := os.Getwd()
+	replacer := exec.Command(gocmd, "mod", "edit", "-x", "-require", "github.com/core-coin/go-core/v2@v2.0.0", "-replace", "github.com/core-coin/go-core/v2="+filepath.Join(pwd, "..", "..", "..")) // Repo root
+	replacer.Dir = pkg
+	if out, err := replacer.CombinedOutput(); err != nil {
+		t.Fatalf("failed to replace binding test dependency to current source tree: %v\n%s", err, out)
+	}
+	tidier := exec.Command(gocmd, "mod", "tidy")
+	tidier.Dir = pkg
+	if out, err := tidier.CombinedOutput(); err != nil {
+		t.Fatalf("failed to tidy Go module file: %v\n%s", err, out)
+	}
+	cmd := exec.Command(gocmd, "test", "-v", "-count", "1")
+	cmd.Dir = pkg
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to run mock test: %v\n%s", err, out)
+	}
+}
+
+// Tests that ABI types can be bound to custom Go types, converted to and from
+// the default bindings with the provided adapters.
+func TestTypeMappingBinding(t *testing.T) {
+	tt := bindTests[0]
+	for _, test := range bindTests {
+		if test.name == "Token" {
+			tt = test
+		}
+	}
+	// Invalid mappings must be rejected
+	for name, mapping := range map[string]TypeMapping{
+		"decimal": {Type: "Amount", Pack: "Amount.Big", Unpack: "amountFromBig"},
+		"uint256": {Type: "Amount"},
+	} {
+		if _, err := BindWithMappings([]string{tt.name}, tt.abi, tt.bytecode, nil, "bindtest", ModeBinding, nil, nil, map[string]TypeMapping{name: mapping}); err == nil {
+			t.Errorf("invalid mapping %s %+v accepted", name, mapping)
+		}
+	}
+	// Mappings restricted to named parameters must leave the others alone
+	code, err := BindWithMappings([]string{tt.name}, tt.abi, tt.bytecode, nil, "bindtest", ModeBinding, nil, nil, map[string]TypeMapping{
+		"uint256": {Type: "decimal.Big", Import: "example.org/decimal", Pack: "decimal.ToBig", Unpack: "decimal.FromBig", Params: []string{"_value"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		`"example.org/decimal"`,
+		"Transfer(opts *bind.TransactOpts, _to common.Address, _value decimal.Big) (*types.Transaction, error)",
+		`Transact(opts, "transfer", _to, decimal.ToBig(_value))`,
+		"BalanceOf(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error)",
+		"DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, initialSupply *big.Int,",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding missing %s", want)
+		}
+	}
+	// Skip the test if no Go command can be found
+	gocmd := runtime.GOROOT() + "/bin/go"
+	if !common.FileExist(gocmd) {
+		t.Skip("go sdk not found for testing")
+	}
+	// Create a temporary workspace and generate the mapped token binding and mock into it
+	ws, err := ioutil.TempDir("", "binding-mapping-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary workspace: %v", err)
+	}
+	defer os.RemoveAll(ws)
+
+	pkg := filepath.Join(ws, "bindtest")
+	if err = os.MkdirAll(pkg, 0700); err != nil {
+		t.Fatalf("failed to create package: %v", err)
+	}
+	mappings := map[string]TypeMapping{
+		"uint256": {Type: "Amount", Pack: "Amount.Big", Unpack: "amountFromBig"},
+	}
+	for mode, file := range map[Mode]string{ModeBinding: "token.go", ModeMock: "token_mock.go"} {
+		code, err := BindWithMappings([]string{tt.name}, tt.abi, tt.bytecode, nil, "bindtest", mode, nil, nil, mappings)
+		if err != nil {
+			t.Fatalf("failed to generate %s: %v", file, err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(pkg, file), []byte(code), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+	tester := `
+		package bindtest
+
+		import (
+			"context"
+			"math/big"
+			"testing"
+
+			core "github.com/core-coin/go-core/v2"
+			"github.com/core-coin/go-core/v2/accounts/abi/bind"
+			"github.com/core-coin/go-core/v2/common"
+			"github.com/core-coin/go-core/v2/core/types"
+		)
+
+		// Amount is a decimal representation of a token amount.
+		type Amount string
+
+		func amountFromBig(v *big.Int) Amount { return Amount(v.String()) }
+
+		func (a Amount) Big() *big.Int {
+			v, _ := new(big.Int).SetString(string(a), 10)
+			return v
+		}
+
+		// tokenBackend answers all calls with a fixed output, recording the input.
+		type tokenBackend struct {
+			bind.ContractBackend
+			input  []byte
+			output []byte
+		}
+
+		func (b *tokenBackend) CallContract(ctx context.Context, call core.CallMsg, number *big.Int) ([]byte, error) {
+			b.input = call.Data
+			return b.output, nil
+		}
+
+		func (b *tokenBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+			return []byte{1}, nil
+		}
+
+		func (b *tokenBackend) EstimateEnergy(ctx context.Context, call core.CallMsg) (uint64, error) {
+			b.input = call.Data
+			return 21000, nil
+		}
+
+		// The constructor parameters must be mapped too.
+		var _ func(*bind.TransactOpts, bind.ContractBackend, Amount, string, uint8, string) (common.Address, *types.Transaction, *Token, error) = DeployToken
+
+		func TestTokenTypeMapping(t *testing.T) {
+			parsed, err := TokenMetaData.GetAbi()
+			if err != nil {
+				t.Fatalf("failed to retrieve parsed ABI: %v", err)
+			}
+			output, err := parsed.Methods["balanceOf"].Outputs.Pack(big.NewInt(1234))
+			if err != nil {
+				t.Fatalf("failed to pack balance: %v", err)
+			}
+			backend := &tokenBackend{output: output}
+			token, err := NewToken(common.Address{1}, backend)
+			if err != nil {
+				t.Fatalf("failed to bind token: %v", err)
+			}
+			if balance, err := token.BalanceOf(nil, common.Address{2}); err != nil || balance != "1234" {
+				t.Fatalf("balance mismatch: have %q (%v), want 1234", balance, err)
+			}
+			if _, err := token.EstimateTransfer(&bind.TransactOpts{}, common.Address{3}, Amount("5")); err != nil {
+				t.Fatalf("failed to estimate transfer: %v", err)
+			}
+			method := parsed.Methods["transfer"]
+			args, err := method.Inputs.Unpack(backend.input[len(method.ID):])
+			if err != nil {
+				t.Fatalf("failed to unpack transfer: %v", err)
+			}
+			if value := args[1].(*big.Int); value.Cmp(big.NewInt(5)) != 0 {
+				t.Fatalf("transferred value mismatch: have %v, want 5", value)
+			}
+			var mock TokenTransactorAPI = &MockToken{}
+			if _, err := mock.Transfer(&bind.TransactOpts{}, common.Address{3}, Amount("5")); err != nil {
+				t.Fatalf("mock transfer failed: %v", err)
+			}
+		}
+	`
+	if err := ioutil.WriteFile(filepath.Join(pkg, "token_test.go"), []byte(tester), 0600); err != nil {
+		t.Fatalf("failed to write tests: %v", err)
+	}
+	// Convert the package to go modules and use the current source for go-core
+	moder := exec.Command(gocmd, "mod", "init", "bindtest")
+	moder.Dir = pkg
+	if out, err := moder.CombinedOutput(); err != nil {
+		t.Fatalf("failed to convert binding test to modules: %v\n%s", err, out)
+	}
+	pwd, _ := os.Getwd()
+	replacer := exec.Command(gocmd, "mod", "edit", "-x", "-require", "github.com/core-coin/go-core/v2@v2.0.0", "-replace", "github.com/core-coin/go-core/v2="+filepath.Join(pwd, "..", "..", "..")) // Repo root
+	replacer.Dir = pkg
+	if out, err := replacer.CombinedOutput(); err != nil {
+		t.Fatalf("failed to replace binding test dependency to current source tree: %v\n%s", err, out)
+	}
+	tidier := exec.Command(gocmd, "mod", "tidy")
+	tidier.Dir = pkg
+	if out, err := tidier.CombinedOutput(); err != nil {
+		t.Fatalf("failed to tidy Go module file: %v\n%s", err, out)
+	}
+	cmd := exec.Command(gocmd, "test", "-v", "-count", "1")
+	cmd.Dir = pkg
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to run type mapping test: %v\n%s", err, out)
+	}
+}
+
+// Tests that a library struct referenced by multiple contracts is declared only
+// once, regardless of the textual layout of the individual ABIs.
+func TestSharedStructBinding(t *testing.T) {
+	var (
+		types = []string{"Getter", "Setter", "Mapper"}
+		abis  = []string{
+			`[{"inputs":[],"name":"get","outputs":[{"components":[{"internalType":"int256","name":"f1","type":"int256"},{"internalType":"bytes32","name":"f2","type":"bytes32"}],"internalType":"struct ExternalLib.SharedStruct","name":"","type":"tuple"}],"stateMutability":"pure","type":"function"}]`,
+			`[{"name":"set","type":"function","stateMutability":"nonpayable","outputs":[],"inputs":[{"type":"tuple","name":"s","internalType":"struct ExternalLib.SharedStruct","components":[{"type":"int256","name":"f1","internalType":"int256"},{"type":"bytes32","name":"f2","internalType":"bytes32"}]}]}]`,
+			`[
+				{
+					"type": "function",
+					"name": "map",
+					"stateMutability": "pure",
+					"inputs": [{
+						"name": "s",
+						"type": "tuple[]",
+						"internalType": "struct ExternalLib.SharedStruct[]",
+						"components": [
+							{"name": "f1", "type": "int256", "internalType": "int256"},
+							{"name": "f2", "type": "bytes32", "internalType": "bytes32"}
+						]
+					}],
+					"outputs": [{"name": "", "type": "int256", "internalType": "int256"}]
+				}
+			]`,
+		}
+	)
+	code, err := Bind(types, abis, []string{"", "", ""}, nil, "bindtest", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if n := strings.Count(code, "type ExternalLibSharedStruct struct"); n != 1 {
+		t.Fatalf("shared struct declaration count mismatch: have %d, want 1", n)
+	}
+	if n := regexp.MustCompile(`type \w+ struct \{\s*F1 +\*big\.Int`).FindAllString(code, -1); len(n) != 1 {
+		t.Fatalf("struct declaration mismatch: have %v", n)
+	}
+	for _, want := range []string{
+		"func (_Getter *GetterCaller) Get(opts *bind.CallOpts) (ExternalLibSharedStruct, error)",
+		"func (_Setter *SetterTransactor) Set(opts *bind.TransactOpts, s ExternalLibSharedStruct) (*types.Transaction, error)",
+		"func (_Mapper *MapperCaller) Map(opts *bind.CallOpts, s []ExternalLibSharedStruct) (*big.Int, error)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding missing shared struct usage: %s", want)
+		}
+	}
+}
+
+// Tests that structs sharing a raw name but differing in their field names are
+// declared under distinct names, each used by the contract defining it.
+func TestDistinctStructBinding(t *testing.T) {
+	var (
+		types = []string{"First", "Second"}
+		abis  = []string{
+			`[{"inputs":[],"name":"get","outputs":[{"components":[{"internalType":"int256","name":"f1","type":"int256"}],"internalType":"struct Lib.S","name":"","type":"tuple"}],"stateMutability":"pure","type":"function"}]`,
+			`[{"inputs":[],"name":"get","outputs":[{"components":[{"internalType":"int256","name":"g1","type":"int256"}],"internalType":"struct Lib.S","name":"","type":"tuple"}],"stateMutability":"pure","type":"function"}]`,
+		}
+	)
+	code, err := Bind(types, abis, []string{"", ""}, nil, "bindtest", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		`type LibS struct \{\s*F1 +\*big\.Int`,
+		`type LibS0 struct \{\s*G1 +\*big\.Int`,
+		`func \(_First \*FirstCaller\) Get\(opts \*bind\.CallOpts\) \(LibS, error\)`,
+		`func \(_Second \*SecondCaller\) Get\(opts \*bind\.CallOpts\) \(LibS0, error\)`,
+	} {
+		if n := len(regexp.MustCompile(want).FindAllString(code, -1)); n != 1 {
+			t.Errorf("binding match count mismatch for %s: have %d, want 1", want, n)
+		}
+	}
+}
+
+// Tests that the names of structs sharing a raw name within a single contract
+// are assigned deterministically, independent of the ABI map iteration order.
+func TestDistinctStructBindingDeterministic(t *testing.T) {
+	abis := []string{`[
+		{"inputs":[],"name":"b","outputs":[{"components":[{"internalType":"int256","name":"g1","type":"int256"}],"internalType":"struct Lib.S","name":"","type":"tuple"}],"stateMutability":"pure","type":"function"},
+		{"inputs":[],"name":"a","outputs":[{"components":[{"internalType":"int256","name":"f1","type":"int256"}],"internalType":"struct Lib.S","name":"","type":"tuple"}],"stateMutability":"pure","type":"function"}
+	]`}
+	first, err := Bind([]string{"Test"}, abis, []string{""}, nil, "bindtest", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if !regexp.MustCompile(`type LibS struct \{\s*F1 +\*big\.Int`).MatchString(first) {
+		t.Errorf("struct of the first method not bound as LibS")
+	}
+	for i := 0; i < 20; i++ {
+		code, err := Bind([]string{"Test"}, abis, []string{""}, nil, "bindtest", nil, nil)
+		if err != nil {
+			t.Fatalf("failed to generate binding: %v", err)
+		}
+		if code != first {
+			t.Fatalf("binding output differs between runs")
+		}
+	}
+}
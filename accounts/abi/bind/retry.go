@@ -0,0 +1,64 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy configures how backend requests failing with transient errors,
+// e.g. a connection reset by a flaky RPC endpoint, are retried. Errors not in
+// the transient set, such as execution reverts, are returned immediately.
+//
+// Note, retrying the submission of a transaction after a transient error may
+// result in an error reporting the transaction as already known, if the first
+// attempt actually reached the node.
+type RetryPolicy struct {
+	Attempts  int           // Maximum number of attempts, including the first one
+	Delay     time.Duration // Delay between two consecutive attempts
+	Transient []error       // Errors considered transient, matched via errors.Is
+}
+
+// transient reports whether err is one of the configured transient errors.
+func (p *RetryPolicy) transient(err error) bool {
+	for _, target := range p.Transient {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// do runs fn until it succeeds, fails with a non-transient error, the attempts
+// are exhausted or the context is canceled. A nil policy runs fn once.
+func (p *RetryPolicy) do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if p == nil {
+		return err
+	}
+	for attempt := 1; attempt < p.Attempts && err != nil && p.transient(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.Delay):
+		}
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/accounts/abi"
+	"github.com/core-coin/go-core/v2/accounts/abi/bind"
+	"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core"
+	"github.com/core-coin/go-core/v2/crypto"
+)
+
+const (
+	getterABI = `[{"constant":true,"inputs":[],"name":"getter","outputs":[{"name":"","type":"string"},{"name":"","type":"int256"},{"name":"","type":"bytes32"}],"type":"function"}]`
+	getterBin = `608060405234801561001057600080fd5b5061017a806100206000396000f3fe608060405234801561001057600080fd5b506004361061002b5760003560e01c80632c149b2414610030575b600080fd5b6100386100c1565b6040518080602001848152602001838152602001828103825285818151815260200191508051906020019080838360005b83811015610084578082015181840152602081019050610069565b50505050905090810190601f1680156100b15780820380516001836020036101000a031916815260200191505b5094505050505060405180910390f35b6060600080600160405180600001905060405180910390206040518060400160405280600281526020017f4869000000000000000000000000000000000000000000000000000000000000815250919081915092509250925090919256fea26469706673582212209c2f29b00aec02b8784ec578fae7fbcc31cbb48fc7a131cb9d9ade670f0e4ee164736f6c637827302e362e392d646576656c6f702e323032302e372e32312b636f6d6d69742e33633832373333370058`
+)
+
+// Tests that a read-only binding can be generated from the runtime code of a
+// deployed contract, and that it can call and verify the deployed contract.
+func TestBindFromABIAndRuntimeCode(t *testing.T) {
+	// Skip the test if no Go command can be found
+	gocmd := runtime.GOROOT() + "/bin/go"
+	if !common.FileExist(gocmd) {
+		t.Skip("go sdk not found for testing")
+	}
+	// Deploy the contract and retrieve its runtime code
+	key, _ := crypto.GenerateKey(rand.Reader)
+	auth, _ := bind.NewKeyedTransactorWithNetworkID(key, big.NewInt(1))
+
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(10000000000)}}, 10000000)
+	defer sim.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(getterABI))
+	address, _, _, err := bind.DeployContract(auth, parsed, common.FromHex(getterBin), sim)
+	if err != nil {
+		t.Fatalf("failed to deploy contract: %v", err)
+	}
+	sim.Commit()
+
+	code, err := sim.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve runtime code: %v", err)
+	}
+	if _, err := bind.BindFromABIAndRuntimeCode("Getter", getterABI, nil, "bindtest", nil); err == nil {
+		t.Fatalf("binding generated without runtime code")
+	}
+	binding, err := bind.BindFromABIAndRuntimeCode("Getter", getterABI, code, "bindtest", nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	// Only the caller side of the binding must be generated, around the runtime code
+	if !strings.Contains(binding, fmt.Sprintf(`GetterRuntimeBin = "0x%x"`, code)) {
+		t.Errorf("runtime code missing from binding")
+	}
+	for _, forbidden := range []string{"DeployGetter", "GetterTransactor", "GetterFilterer", "GetterBin "} {
+		if strings.Contains(binding, forbidden) {
+			t.Errorf("caller binding contains %s", forbidden)
+		}
+	}
+	// Create a temporary workspace and ensure the binding works against the chain
+	ws, err := ioutil.TempDir("", "binding-runtime-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary workspace: %v", err)
+	}
+	defer os.RemoveAll(ws)
+
+	pkg := filepath.Join(ws, "bindtest")
+	if err = os.MkdirAll(pkg, 0700); err != nil {
+		t.Fatalf("failed to create package: %v", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(pkg, "getter.go"), []byte(binding), 0600); err != nil {
+		t.Fatalf("failed to write binding: %v", err)
+	}
+	tester := fmt.Sprintf(`
+		package bindtest
+
+		import (
+			"crypto/rand"
+			"math/big"
+			"strings"
+			"testing"
+
+			"github.com/core-coin/go-core/v2/accounts/abi"
+			"github.com/core-coin/go-core/v2/accounts/abi/bind"
+			"github.com/core-coin/go-core/v2/accounts/abi/bind/backends"
+			"github.com/core-coin/go-core/v2/common"
+			"github.com/core-coin/go-core/v2/core"
+			"github.com/core-coin/go-core/v2/crypto"
+		)
+
+		func TestGetterCaller(t *testing.T) {
+			key, _ := crypto.GenerateKey(rand.Reader)
+			auth, _ := bind.NewKeyedTransactorWithNetworkID(key, big.NewInt(1))
+
+			sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(10000000000)}}, 10000000)
+			defer sim.Close()
+
+			parsed, _ := abi.JSON(strings.NewReader(GetterABI))
+			address, _, _, err := bind.DeployContract(auth, parsed, common.FromHex("%s"), sim)
+			if err != nil {
+				t.Fatalf("failed to deploy contract: %%v", err)
+			}
+			sim.Commit()
+
+			getter, err := NewGetterCaller(address, sim)
+			if err != nil {
+				t.Fatalf("failed to bind contract: %%v", err)
+			}
+			if str, num, _, err := getter.Getter(nil); err != nil {
+				t.Fatalf("failed to call contract: %%v", err)
+			} else if str != "Hi" || num.Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("retrieved value mismatch: have %%v/%%v, want %%v/%%v", str, num, "Hi", 1)
+			}
+			if ok, err := VerifyGetter(nil, address, sim); err != nil || !ok {
+				t.Fatalf("deployed code verification failed: %%v (%%v)", ok, err)
+			}
+			if ok, err := VerifyGetter(nil, auth.From, sim); err != nil || ok {
+				t.Fatalf("non-contract account verified: %%v (%%v)", ok, err)
+			}
+		}
+	`, getterBin)
+	if err := ioutil.WriteFile(filepath.Join(pkg, "getter_test.go"), []byte(tester), 0600); err != nil {
+		t.Fatalf("failed to write tests: %v", err)
+	}
+	// Convert the package to go modules and use the current source for go-core
+	moder := exec.Command(gocmd, "mod", "init", "bindtest")
+	moder.Dir = pkg
+	if out, err := moder.CombinedOutput(); err != nil {
+		t.Fatalf("failed to convert binding test to modules: %v\n%s", err, out)
+	}
+	pwd, _ := os.Getwd()
+	replacer := exec.Command(gocmd, "mod", "edit", "-x", "-require", "github.com/core-coin/go-core/v2@v2.0.0", "-replace", "github.com/core-coin/go-core/v2="+filepath.Join(pwd, "..", "..", "..")) // Repo root
+	replacer.Dir = pkg
+	if out, err := replacer.CombinedOutput(); err != nil {
+		t.Fatalf("failed to replace binding test dependency to current source tree: %v\n%s", err, out)
+	}
+	tidier := exec.Command(gocmd, "mod", "tidy")
+	tidier.Dir = pkg
+	if out, err := tidier.CombinedOutput(); err != nil {
+		t.Fatalf("failed to tidy Go module file: %v\n%s", err, out)
+	}
+	cmd := exec.Command(gocmd, "test", "-v", "-count", "1")
+	cmd.Dir = pkg
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to run binding test: %v\n%s", err, out)
+	}
+}
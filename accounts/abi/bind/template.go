@@ -24,6 +24,7 @@ type tmplData struct {
 	Contracts map[string]*tmplContract // List of contracts to generate into this file
 	Libraries map[string]string        // Map the bytecode's link pattern to the library name
 	Structs   map[string]*tmplStruct   // Contract struct type definitions
+	Imports   []string                 // Additional packages required by the type mappings
 }
 
 // tmplContract contains the data needed to generate an individual contract binding.
@@ -31,6 +32,7 @@ type tmplContract struct {
 	Type        string                 // Type name of the main contract binding
 	InputABI    string                 // JSON ABI used as the input to generate the binding from
 	InputBin    string                 // Optional CVM bytecode used to generate deploy code from
+	RuntimeBin  string                 // Optional CVM runtime bytecode of an already deployed contract
 	FuncSigs    map[string]string      // Optional map: string signature -> 4-byte signature
 	Constructor abi.Method             // Contract constructor for deploy parametrization
 	Calls       map[string]*tmplMethod // Contract calls that only read state data
@@ -50,6 +52,13 @@ type tmplMethod struct {
 	Structured bool       // Whether the returns should be accumulated into a struct
 }
 
+// tmplCaller bundles a contract with the struct definitions its read-only call
+// methods refer to, as needed by the shared calls template.
+type tmplCaller struct {
+	Contract *tmplContract          // Contract to generate the call methods of
+	Structs  map[string]*tmplStruct // Struct definitions of the generated file
+}
+
 // tmplEvent is a wrapper around an abi.Event that contains a few preprocessed
 // and cached data fields.
 type tmplEvent struct {
@@ -72,9 +81,18 @@ type tmplStruct struct {
 	Fields []*tmplField // Struct fields definition depends on the binding language.
 }
 
+// tmplSource is the mode to template mapping containing all the supported
+// output kinds the package can generate.
+var tmplSource = map[Mode]string{
+	ModeBinding: tmplSourceGo + tmplSourceGoCalls,
+	ModeCLI:     tmplSourceCLI,
+	ModeMock:    tmplSourceMock,
+	modeCaller:  tmplSourceGoCaller + tmplSourceGoCalls,
+}
+
 // tmplSourceGo is the Go source template that the generated Go contract binding
 // is based on.
-const tmplSource = `
+const tmplSourceGo = `
 // Code generated - DO NOT EDIT.
 // This file is a generated binding and any manual changes will be lost.
 
@@ -90,11 +108,14 @@ import (
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/core/types"
 	"github.com/core-coin/go-core/v2/event"
+{{range .Imports}}
+	"{{.}}"{{end}}
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var (
 	_ = big.NewInt
+	_ = abi.ConvertType
 	_ = strings.NewReader
 	_ = core.NotFound
 	_ = bind.Bind
@@ -104,13 +125,7 @@ var (
 )
 
 {{$structs := .Structs}}
-{{range $structs}}
-	// {{.Name}} is an auto generated low-level Go binding around an user-defined struct.
-	type {{.Name}} struct {
-	{{range $field := .Fields}}
-	{{$field.Name}} {{$field.Type}}{{end}}
-	}
-{{end}}
+{{template "structs" $structs}}
 
 {{range $contract := .Contracts}}
 	// {{.Type}}ABI is the input ABI used to generate the binding from.
@@ -124,26 +139,66 @@ var (
 		}
 	{{end}}
 
+	// {{.Type}}MetaData contains all meta data concerning the {{.Type}} contract.
+	var {{.Type}}MetaData = &bind.MetaData{
+		ABI: {{.Type}}ABI,
+		{{if $contract.FuncSigs}}Sigs: {{.Type}}FuncSigs,{{end}}
+	}
+
 	{{if .InputBin}}
 		// {{.Type}}Bin is the compiled bytecode used for deploying new contracts.
 		var {{.Type}}Bin = "0x{{.InputBin}}"
 
 		// Deploy{{.Type}} deploys a new Core contract, binding an instance of {{.Type}} to it.
-		func Deploy{{.Type}}(auth *bind.TransactOpts, backend bind.ContractBackend {{range .Constructor.Inputs}}, {{.Name}} {{bindtype .Type $structs}}{{end}}) (common.Address, *types.Transaction, *{{.Type}}, error) {
-		  parsed, err := abi.JSON(strings.NewReader({{.Type}}ABI))
+		func Deploy{{.Type}}(auth *bind.TransactOpts, backend bind.ContractBackend {{range .Constructor.Inputs}}, {{.Name}} {{bindarg . $structs}}{{end}}) (common.Address, *types.Transaction, *{{.Type}}, error) {
+		  parsed, err := {{.Type}}MetaData.GetAbi()
 		  if err != nil {
 		    return common.Address{}, nil, nil, err
 		  }
+		  bin := {{.Type}}Bin
 		  {{range $pattern, $name := .Libraries}}
 			{{decapitalise $name}}Addr, _, _, _ := Deploy{{capitalise $name}}(auth, backend)
-			{{$contract.Type}}Bin = strings.Replace({{$contract.Type}}Bin, "____${{$pattern}}$____", {{decapitalise $name}}Addr.String(), -1)
+			bin = strings.Replace(bin, "____${{$pattern}}$____", {{decapitalise $name}}Addr.String(), -1)
 		  {{end}}
-		  address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex({{.Type}}Bin), backend {{range .Constructor.Inputs}}, {{.Name}}{{end}})
+		  address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(bin), backend {{range .Constructor.Inputs}}, {{packarg .}}{{end}})
 		  if err != nil {
 		    return common.Address{}, nil, nil, err
 		  }
 		  return address, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
 		}
+
+		{{if .Libraries}}
+			// Deploy{{.Type}}All deploys the libraries {{.Type}} depends on, links their addresses
+			// into its bytecode and deploys a new Core contract, binding an instance of {{.Type}}
+			// to it. The addresses of the deployed libraries are returned keyed by name. If the
+			// nonce of auth is set, the deployments use consecutive nonces starting from it.
+			func Deploy{{.Type}}All(auth *bind.TransactOpts, backend bind.ContractBackend {{range .Constructor.Inputs}}, {{.Name}} {{bindarg . $structs}}{{end}}) (common.Address, map[string]common.Address, *types.Transaction, *{{.Type}}, error) {
+			  parsed, err := {{.Type}}MetaData.GetAbi()
+			  if err != nil {
+			    return common.Address{}, nil, nil, nil, err
+			  }
+			  opts := *auth
+			  libraries := make(map[string]common.Address)
+
+			  bin := {{.Type}}Bin
+			  {{range $pattern, $name := .Libraries}}
+				{{decapitalise $name}}Addr, _, _, err := Deploy{{capitalise $name}}(&opts, backend)
+				if err != nil {
+				  return common.Address{}, nil, nil, nil, err
+				}
+				if opts.Nonce != nil {
+				  opts.Nonce = new(big.Int).Add(opts.Nonce, big.NewInt(1))
+				}
+				libraries["{{$name}}"] = {{decapitalise $name}}Addr
+				bin = strings.Replace(bin, "____${{$pattern}}$____", {{decapitalise $name}}Addr.String(), -1)
+			  {{end}}
+			  address, tx, contract, err := bind.DeployContract(&opts, *parsed, common.FromHex(bin), backend {{range .Constructor.Inputs}}, {{packarg .}}{{end}})
+			  if err != nil {
+			    return common.Address{}, nil, nil, nil, err
+			  }
+			  return address, libraries, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
+			}
+		{{end}}
 	{{end}}
 
 	// {{.Type}} is an auto generated Go binding around an Core contract.
@@ -243,11 +298,11 @@ var (
 
 	// bind{{.Type}} binds a generic wrapper to an already deployed contract.
 	func bind{{.Type}}(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
-	  parsed, err := abi.JSON(strings.NewReader({{.Type}}ABI))
+	  parsed, err := {{.Type}}MetaData.GetAbi()
 	  if err != nil {
 	    return nil, err
 	  }
-	  return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+	  return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
 	}
 
 	// Call invokes the (constant) contract method with params as input values and
@@ -269,14 +324,6 @@ var (
 		return _{{$contract.Type}}.Contract.{{$contract.Type}}Transactor.contract.Transact(opts, method, params...)
 	}
 
-	// Call invokes the (constant) contract method with params as input values and
-	// sets the output to result. The result type might be a single field for simple
-	// returns, a slice of interfaces for anonymous returns and a struct for named
-	// returns.
-	func (_{{$contract.Type}} *{{$contract.Type}}CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
-		return _{{$contract.Type}}.Contract.contract.Call(opts, result, method, params...)
-	}
-
 	// Transfer initiates a plain transaction to move funds to the contract, calling
 	// its default method if one is available.
 	func (_{{$contract.Type}} *{{$contract.Type}}TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
@@ -288,65 +335,58 @@ var (
 		return _{{$contract.Type}}.Contract.contract.Transact(opts, method, params...)
 	}
 
+	{{template "calls" caller $contract $structs}}
+
 	{{range .Calls}}
 		// {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}}.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}Caller) {{.Normalized.Name}}(opts *bind.CallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}}{{end}} error) {
-			var out []interface{}
-			err := _{{$contract.Type}}.contract.Call(opts, &out, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
-			{{if .Structured}}
-			outstruct := new(struct{ {{range .Normalized.Outputs}} {{.Name}} {{bindtype .Type $structs}}; {{end}} })
-			{{range $i, $t := .Normalized.Outputs}} 
-			outstruct.{{.Name}} = out[{{$i}}].({{bindtype .Type $structs}}){{end}}
-
-			return *outstruct, err
-			{{else}}
-			if err != nil {
-				return {{range $i, $_ := .Normalized.Outputs}}*new({{bindtype .Type $structs}}), {{end}} err
-			}
-			{{range $i, $t := .Normalized.Outputs}}
-			out{{$i}} := *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
-			
-			return {{range $i, $t := .Normalized.Outputs}}out{{$i}}, {{end}} err
-			{{end}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}} {{end}} error) {
+		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
+	{{end}}
 
-		// {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}}.
+	{{range .Transacts}}
+		// {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}}.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}} {{end}} error) {
-		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) {{.Normalized.Name}}(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error) {
+			return _{{$contract.Type}}.contract.Transact(opts, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{packarg .}}{{end}})
 		}
 
-		// {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}}.
+		// {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}}.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}} {{end}} error) {
-		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error) {
+		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
-	{{end}}
 
-	{{range .Transacts}}
 		// {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}}.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) {{.Normalized.Name}}(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) (*types.Transaction, error) {
-			return _{{$contract.Type}}.contract.Transact(opts, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		func (_{{$contract.Type}} *{{$contract.Type}}TransactorSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error) {
+		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
 
-		// {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}}.
+		// Estimate{{.Normalized.Name}} estimates the energy needed by the contract method 0x{{printf "%x" .Original.ID}}, without sending a transaction.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) (*types.Transaction, error) {
-		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
+		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) Estimate{{.Normalized.Name}}(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) (uint64, error) {
+			return _{{$contract.Type}}.contract.EstimateEnergy(opts, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{packarg .}}{{end}})
 		}
 
-		// {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}}.
+		// Estimate{{.Normalized.Name}} estimates the energy needed by the contract method 0x{{printf "%x" .Original.ID}}, without sending a transaction.
 		//
 		// Ylem: {{.Original.String}}
-		func (_{{$contract.Type}} *{{$contract.Type}}TransactorSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) (*types.Transaction, error) {
-		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
+		func (_{{$contract.Type}} *{{$contract.Type}}Session) Estimate{{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) (uint64, error) {
+		  return _{{$contract.Type}}.Contract.Estimate{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+
+		// Estimate{{.Normalized.Name}} estimates the energy needed by the contract method 0x{{printf "%x" .Original.ID}}, without sending a transaction.
+		//
+		// Ylem: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}TransactorSession) Estimate{{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) (uint64, error) {
+		  return _{{$contract.Type}}.Contract.Estimate{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
 	{{end}}
 
@@ -541,3 +581,567 @@ var (
  	{{end}}
 {{end}}
 `
+
+// tmplSourceCLI is the Go source template that the generated standalone command
+// line interface of a contract is based on.
+const tmplSourceCLI = `
+// Code generated - DO NOT EDIT.
+// This file is a generated command line interface and any manual changes will be lost.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/core-coin/go-core/v2/accounts/abi"
+	"github.com/core-coin/go-core/v2/accounts/abi/bind"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/hexutil"
+	"github.com/core-coin/go-core/v2/xcbclient"
+)
+
+var (
+	rpcFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "Endpoint of the Core node to talk to",
+		Value: "http://localhost:8545",
+	}
+	addressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Address of the deployed contract",
+	}
+	keyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Encrypted key file to sign transactions with",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "Passphrase to decrypt the key file with",
+	}
+	valueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Funds in ore to transfer along with transactions",
+	}
+)
+
+{{range $contract := .Contracts}}
+	// {{.Type}}ABI is the input ABI used to generate the command line interface from.
+	const {{.Type}}ABI = "{{.InputABI}}"
+
+	func main() {
+		app := cli.NewApp()
+		app.Name = "{{decapitalise .Type}}"
+		app.Usage = "command line interface to the {{.Type}} contract"
+		app.Flags = []cli.Flag{rpcFlag, addressFlag, keyFileFlag, passwordFlag, valueFlag}
+		app.Commands = []cli.Command{
+		{{range .Calls}}
+			{
+				Name:   "{{.Original.Name}}",
+				Usage:  {{printf "%q" .Original.String}},
+				Flags:  []cli.Flag{ {{range .Normalized.Inputs}}cli.StringFlag{Name: "{{decapitalise .Name}}", Usage: "{{.Type}} argument"}, {{end}} },
+				Action: call("{{.Original.Name}}"{{range .Normalized.Inputs}}, "{{decapitalise .Name}}"{{end}}),
+			},
+		{{end}}
+		{{range .Transacts}}
+			{
+				Name:   "{{.Original.Name}}",
+				Usage:  {{printf "%q" .Original.String}},
+				Flags:  []cli.Flag{ {{range .Normalized.Inputs}}cli.StringFlag{Name: "{{decapitalise .Name}}", Usage: "{{.Type}} argument"}, {{end}} },
+				Action: transact("{{.Original.Name}}"{{range .Normalized.Inputs}}, "{{decapitalise .Name}}"{{end}}),
+			},
+		{{end}}
+		}
+		if err := app.Run(os.Args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	// bindContract dials the requested node and binds the contract to the
+	// requested address.
+	func bindContract(ctx *cli.Context) (*bind.BoundContract, abi.ABI, *xcbclient.Client, error) {
+		parsed, err := abi.JSON(strings.NewReader({{.Type}}ABI))
+		if err != nil {
+			return nil, abi.ABI{}, nil, err
+		}
+		address, err := common.HexToAddress(ctx.GlobalString(addressFlag.Name))
+		if err != nil {
+			return nil, abi.ABI{}, nil, fmt.Errorf("invalid contract address: %v", err)
+		}
+		client, err := xcbclient.Dial(ctx.GlobalString(rpcFlag.Name))
+		if err != nil {
+			return nil, abi.ABI{}, nil, err
+		}
+		return bind.NewBoundContract(address, parsed, client, client, client), parsed, client, nil
+	}
+{{end}}
+
+// call creates a command action invoking the given constant method with the
+// arguments read from the named flags, printing the returned values.
+func call(method string, flags ...string) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		contract, parsed, client, err := bindContract(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		args, err := parseArguments(ctx, parsed.Methods[method].Inputs, flags)
+		if err != nil {
+			return err
+		}
+		var out []interface{}
+		if err := contract.Call(&bind.CallOpts{Context: context.Background()}, &out, method, args...); err != nil {
+			return err
+		}
+		for i, output := range parsed.Methods[method].Outputs {
+			if output.Name != "" {
+				fmt.Printf("%s: %s\n", output.Name, formatResult(out[i]))
+			} else {
+				fmt.Println(formatResult(out[i]))
+			}
+		}
+		return nil
+	}
+}
+
+// transact creates a command action signing and submitting a transaction to
+// the given method with the arguments read from the named flags, printing the
+// hash of the sent transaction.
+func transact(method string, flags ...string) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		contract, parsed, client, err := bindContract(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		args, err := parseArguments(ctx, parsed.Methods[method].Inputs, flags)
+		if err != nil {
+			return err
+		}
+		keyin, err := os.Open(ctx.GlobalString(keyFileFlag.Name))
+		if err != nil {
+			return fmt.Errorf("failed to open key file: %v", err)
+		}
+		defer keyin.Close()
+
+		networkID, err := client.NetworkID(context.Background())
+		if err != nil {
+			return err
+		}
+		opts, err := bind.NewTransactorWithNetworkID(keyin, ctx.GlobalString(passwordFlag.Name), networkID)
+		if err != nil {
+			return err
+		}
+		if value := ctx.GlobalString(valueFlag.Name); value != "" {
+			amount, ok := new(big.Int).SetString(value, 0)
+			if !ok {
+				return fmt.Errorf("invalid value: %q", value)
+			}
+			opts.Value = amount
+		}
+		tx, err := contract.Transact(opts, method, args...)
+		if err != nil {
+			return err
+		}
+		fmt.Println(tx.Hash().Hex())
+		return nil
+	}
+}
+
+// parseArguments converts the values of the named flags into the Go types
+// expected by the method inputs.
+func parseArguments(ctx *cli.Context, inputs abi.Arguments, flags []string) ([]interface{}, error) {
+	args := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		if !ctx.IsSet(flags[i]) {
+			return nil, fmt.Errorf("missing argument --%s", flags[i])
+		}
+		arg, err := parseArgument(input.Type, ctx.String(flags[i]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument --%s: %v", flags[i], err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// parseArgument converts a textual flag value into the Go representation of
+// the given ABI type. Composite types are accepted in their JSON form.
+func parseArgument(kind abi.Type, input string) (interface{}, error) {
+	switch kind.T {
+	case abi.AddressTy:
+		return common.HexToAddress(input)
+	case abi.BoolTy:
+		return strconv.ParseBool(input)
+	case abi.StringTy:
+		return input, nil
+	case abi.BytesTy:
+		return hexutil.Decode(input)
+	case abi.IntTy, abi.UintTy:
+		typ := kind.GetType()
+		if typ.Kind() == reflect.Ptr {
+			n, ok := new(big.Int).SetString(input, 0)
+			if !ok {
+				return nil, fmt.Errorf("invalid integer %q", input)
+			}
+			return n, nil
+		}
+		value := reflect.New(typ).Elem()
+		if kind.T == abi.UintTy {
+			n, err := strconv.ParseUint(input, 0, kind.Size)
+			if err != nil {
+				return nil, err
+			}
+			value.SetUint(n)
+		} else {
+			n, err := strconv.ParseInt(input, 0, kind.Size)
+			if err != nil {
+				return nil, err
+			}
+			value.SetInt(n)
+		}
+		return value.Interface(), nil
+	case abi.FixedBytesTy:
+		blob, err := hexutil.Decode(input)
+		if err != nil {
+			return nil, err
+		}
+		if len(blob) != kind.Size {
+			return nil, fmt.Errorf("invalid length %d, want %d", len(blob), kind.Size)
+		}
+		value := reflect.New(kind.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf(blob))
+		return value.Interface(), nil
+	default:
+		value := reflect.New(kind.GetType())
+		if err := json.Unmarshal([]byte(input), value.Interface()); err != nil {
+			return nil, err
+		}
+		return value.Elem().Interface(), nil
+	}
+}
+
+// formatResult converts a returned value into its printable form, rendering
+// byte blobs as hex strings.
+func formatResult(result interface{}) string {
+	if stringer, ok := result.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	switch value := reflect.ValueOf(result); {
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return hexutil.Encode(value.Bytes())
+	case value.Kind() == reflect.Array && value.Type().Elem().Kind() == reflect.Uint8:
+		blob := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(blob), value)
+		return hexutil.Encode(blob)
+	}
+	return fmt.Sprint(result)
+}
+`
+
+// tmplSourceMock is the Go source template that the generated interfaces and
+// recording mocks of a contract binding are based on. The output is meant to be
+// placed into the same package as the binding itself.
+const tmplSourceMock = `
+// Code generated - DO NOT EDIT.
+// This file is a generated binding mock and any manual changes will be lost.
+
+package {{.Package}}
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/core-coin/go-core/v2/accounts/abi/bind"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/types"
+{{range .Imports}}
+	"{{.}}"{{end}}
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+)
+
+{{$structs := .Structs}}
+{{range $contract := .Contracts}}
+	// {{.Type}}CallerAPI is an auto generated interface around the read-only
+	// methods of a {{.Type}} binding.
+	type {{.Type}}CallerAPI interface {
+		{{range .Calls}}
+			{{.Normalized.Name}}(opts *bind.CallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}}{{end}} error)
+		{{end}}
+	}
+
+	// {{.Type}}TransactorAPI is an auto generated interface around the write-only
+	// methods of a {{.Type}} binding.
+	type {{.Type}}TransactorAPI interface {
+		{{range .Transacts}}
+			{{.Normalized.Name}}(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error)
+		{{end}}
+		{{if .Fallback}}Fallback(opts *bind.TransactOpts, calldata []byte) (*types.Transaction, error){{end}}
+		{{if .Receive}}Receive(opts *bind.TransactOpts) (*types.Transaction, error){{end}}
+	}
+
+	// Ensure the real bindings satisfy the generated interfaces.
+	var (
+		_ {{.Type}}CallerAPI     = (*{{.Type}}Caller)(nil)
+		_ {{.Type}}TransactorAPI = (*{{.Type}}Transactor)(nil)
+		_ {{.Type}}CallerAPI     = (*Mock{{.Type}})(nil)
+		_ {{.Type}}TransactorAPI = (*Mock{{.Type}})(nil)
+	)
+
+	// Mock{{.Type}}Call is a single invocation recorded by Mock{{.Type}}.
+	type Mock{{.Type}}Call struct {
+		Method string        // Name of the invoked binding method
+		Args   []interface{} // Arguments of the invocation, starting with the options
+	}
+
+	// Mock{{.Type}} is an auto generated recording mock of a {{.Type}} binding. Every
+	// invocation is recorded and forwarded to the corresponding function field if
+	// set, otherwise zero values are returned.
+	type Mock{{.Type}} struct {
+		{{range .Calls}}
+			{{.Normalized.Name}}Func func(opts *bind.CallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}}{{end}} error)
+		{{end}}
+		{{range .Transacts}}
+			{{.Normalized.Name}}Func func(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error)
+		{{end}}
+		{{if .Fallback}}FallbackFunc func(opts *bind.TransactOpts, calldata []byte) (*types.Transaction, error){{end}}
+		{{if .Receive}}ReceiveFunc func(opts *bind.TransactOpts) (*types.Transaction, error){{end}}
+
+		calls []Mock{{.Type}}Call
+		lock  sync.Mutex
+	}
+
+	// Recorded returns all the invocations of the mock in their order.
+	func (_m *Mock{{.Type}}) Recorded() []Mock{{.Type}}Call {
+		_m.lock.Lock()
+		defer _m.lock.Unlock()
+
+		return append([]Mock{{.Type}}Call(nil), _m.calls...)
+	}
+
+	// record appends an invocation to the recorded ones.
+	func (_m *Mock{{.Type}}) record(method string, args ...interface{}) {
+		_m.lock.Lock()
+		defer _m.lock.Unlock()
+
+		_m.calls = append(_m.calls, Mock{{.Type}}Call{Method: method, Args: args})
+	}
+
+	{{range .Calls}}
+		// {{.Normalized.Name}} records an invocation of the contract method 0x{{printf "%x" .Original.ID}}.
+		//
+		// Ylem: {{.Original.String}}
+		func (_m *Mock{{$contract.Type}}) {{.Normalized.Name}}(opts *bind.CallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}}{{end}} error) {
+			_m.record("{{.Normalized.Name}}", opts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			if _m.{{.Normalized.Name}}Func != nil {
+				return _m.{{.Normalized.Name}}Func(opts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			}
+			return {{if .Structured}}*new(struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} }),{{else}}{{range .Normalized.Outputs}}*new({{bindarg . $structs}}),{{end}}{{end}} nil
+		}
+	{{end}}
+
+	{{range .Transacts}}
+		// {{.Normalized.Name}} records an invocation of the contract method 0x{{printf "%x" .Original.ID}}.
+		//
+		// Ylem: {{.Original.String}}
+		func (_m *Mock{{$contract.Type}}) {{.Normalized.Name}}(opts *bind.TransactOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) (*types.Transaction, error) {
+			_m.record("{{.Normalized.Name}}", opts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			if _m.{{.Normalized.Name}}Func != nil {
+				return _m.{{.Normalized.Name}}Func(opts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			}
+			return nil, nil
+		}
+	{{end}}
+
+	{{if .Fallback}}
+		// Fallback records an invocation of the contract fallback function.
+		//
+		// Ylem: {{.Fallback.Original.String}}
+		func (_m *Mock{{$contract.Type}}) Fallback(opts *bind.TransactOpts, calldata []byte) (*types.Transaction, error) {
+			_m.record("Fallback", opts, calldata)
+			if _m.FallbackFunc != nil {
+				return _m.FallbackFunc(opts, calldata)
+			}
+			return nil, nil
+		}
+	{{end}}
+
+	{{if .Receive}}
+		// Receive records an invocation of the contract receive function.
+		//
+		// Ylem: {{.Receive.Original.String}}
+		func (_m *Mock{{$contract.Type}}) Receive(opts *bind.TransactOpts) (*types.Transaction, error) {
+			_m.record("Receive", opts)
+			if _m.ReceiveFunc != nil {
+				return _m.ReceiveFunc(opts)
+			}
+			return nil, nil
+		}
+	{{end}}
+{{end}}
+`
+
+// tmplSourceGoCaller is the Go source template that the generated read-only
+// binding of a deployed contract is based on.
+const tmplSourceGoCaller = `
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/core-coin/go-core/v2/accounts/abi"
+	"github.com/core-coin/go-core/v2/accounts/abi/bind"
+	"github.com/core-coin/go-core/v2/common"
+{{range .Imports}}
+	"{{.}}"{{end}}
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = abi.ConvertType
+)
+
+{{$structs := .Structs}}
+{{template "structs" $structs}}
+
+{{range $contract := .Contracts}}
+	// {{.Type}}ABI is the input ABI used to generate the binding from.
+	const {{.Type}}ABI = "{{.InputABI}}"
+
+	// {{.Type}}MetaData contains all meta data concerning the {{.Type}} contract.
+	var {{.Type}}MetaData = &bind.MetaData{
+		ABI: {{.Type}}ABI,
+	}
+
+	// {{.Type}}RuntimeBin is the runtime bytecode of the deployed contract used to
+	// generate the binding from.
+	var {{.Type}}RuntimeBin = "0x{{.RuntimeBin}}"
+
+	// {{.Type}}Caller is an auto generated read-only Go binding around an Core contract.
+	type {{.Type}}Caller struct {
+	  contract *bind.BoundContract // Generic contract wrapper for the low level calls
+	}
+
+	// {{.Type}}CallerSession is an auto generated read-only Go binding around an Core contract,
+	// with pre-set call options.
+	type {{.Type}}CallerSession struct {
+	  Contract *{{.Type}}Caller // Generic contract caller binding to set the session for
+	  CallOpts bind.CallOpts    // Call options to use throughout this session
+	}
+
+	// {{.Type}}CallerRaw is an auto generated low-level read-only Go binding around an Core contract.
+	type {{.Type}}CallerRaw struct {
+		Contract *{{.Type}}Caller // Generic read-only contract binding to access the raw methods on
+	}
+
+	// New{{.Type}}Caller creates a new read-only instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}Caller(address common.Address, caller bind.ContractCaller) (*{{.Type}}Caller, error) {
+	  parsed, err := {{.Type}}MetaData.GetAbi()
+	  if err != nil {
+	    return nil, err
+	  }
+	  return &{{.Type}}Caller{contract: bind.NewBoundContract(address, *parsed, caller, nil, nil)}, nil
+	}
+
+	// Verify{{.Type}} reports whether the code deployed at the given address matches
+	// the runtime bytecode the binding was generated from.
+	func Verify{{.Type}}(opts *bind.CallOpts, address common.Address, caller bind.ContractCaller) (bool, error) {
+	  if opts == nil {
+	    opts = new(bind.CallOpts)
+	  }
+	  ctx := opts.Context
+	  if ctx == nil {
+	    ctx = context.Background()
+	  }
+	  code, err := caller.CodeAt(ctx, address, opts.BlockNumber)
+	  if err != nil {
+	    return false, err
+	  }
+	  return bytes.Equal(code, common.FromHex({{.Type}}RuntimeBin)), nil
+	}
+
+	{{template "calls" caller $contract $structs}}
+{{end}}
+`
+
+// tmplSourceGoCalls is the Go source template of the parts shared by the full and
+// the read-only binding: the struct definitions and the read-only call methods.
+const tmplSourceGoCalls = `
+{{define "structs"}}
+{{range .}}
+	// {{.Name}} is an auto generated low-level Go binding around an user-defined struct.
+	type {{.Name}} struct {
+	{{range $field := .Fields}}
+	{{$field.Name}} {{$field.Type}}{{end}}
+	}
+{{end}}
+{{end}}
+
+{{define "calls"}}
+{{$contract := .Contract}}
+{{$structs := .Structs}}
+	// Call invokes the (constant) contract method with params as input values and
+	// sets the output to result. The result type might be a single field for simple
+	// returns, a slice of interfaces for anonymous returns and a struct for named
+	// returns.
+	func (_{{$contract.Type}} *{{$contract.Type}}CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+		return _{{$contract.Type}}.Contract.contract.Call(opts, result, method, params...)
+	}
+
+	{{range $contract.Calls}}
+		// {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}}.
+		//
+		// Ylem: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) {{.Normalized.Name}}(opts *bind.CallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}}{{end}} error) {
+			var out []interface{}
+			err := _{{$contract.Type}}.contract.Call(opts, &out, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{packarg .}}{{end}})
+			{{if .Structured}}
+			outstruct := new(struct{ {{range .Normalized.Outputs}} {{.Name}} {{bindarg . $structs}}; {{end}} })
+			{{range $i, $t := .Normalized.Outputs}} 
+			outstruct.{{.Name}} = {{unpackarg . (printf "out[%d].(%s)" $i (bindtype .Type $structs))}}{{end}}
+
+			return *outstruct, err
+			{{else}}
+			if err != nil {
+				return {{range $i, $_ := .Normalized.Outputs}}*new({{bindarg . $structs}}), {{end}} err
+			}
+			{{range $i, $t := .Normalized.Outputs}}
+			out{{$i}} := *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
+			
+			return {{range $i, $t := .Normalized.Outputs}}{{unpackarg . (printf "out%d" $i)}}, {{end}} err
+			{{end}}
+		}
+
+		// {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}}.
+		//
+		// Ylem: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindarg . $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindarg . $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindarg . $structs}},{{end}} {{end}} error) {
+		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+	{{end}}
+{{end}}
+`
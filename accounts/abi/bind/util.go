@@ -26,11 +26,44 @@ import (
 	"github.com/core-coin/go-core/v2/log"
 )
 
+// PollConfig configures how often a backend is polled while waiting for a
+// transaction to be mined.
+type PollConfig struct {
+	Interval    time.Duration // Delay between the first two polls
+	MaxInterval time.Duration // Upper bound of the delay when backing off (0 = unbounded)
+	Backoff     float64       // Factor to grow the delay by after each poll (<= 1 = constant)
+}
+
+// DefaultPollConfig polls once every second, without backing off.
+var DefaultPollConfig = PollConfig{Interval: time.Second}
+
+// next returns the delay to use after a poll which was preceded by delay.
+func (c PollConfig) next(delay time.Duration) time.Duration {
+	if c.Backoff <= 1 {
+		return delay
+	}
+	delay = time.Duration(float64(delay) * c.Backoff)
+	if c.MaxInterval > 0 && delay > c.MaxInterval {
+		delay = c.MaxInterval
+	}
+	return delay
+}
+
 // WaitMined waits for tx to be mined on the blockchain.
 // It stops waiting when the context is canceled.
 func WaitMined(ctx context.Context, b DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
-	queryTicker := time.NewTicker(time.Second)
-	defer queryTicker.Stop()
+	return WaitMinedWithConfig(ctx, b, tx, DefaultPollConfig)
+}
+
+// WaitMinedWithConfig waits for tx to be mined on the blockchain, polling the
+// backend as configured. It stops waiting when the context is canceled.
+func WaitMinedWithConfig(ctx context.Context, b DeployBackend, tx *types.Transaction, config PollConfig) (*types.Receipt, error) {
+	delay := config.Interval
+	if delay <= 0 {
+		delay = DefaultPollConfig.Interval
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 
 	logger := log.New("hash", tx.Hash())
 	for {
@@ -47,8 +80,10 @@ func WaitMined(ctx context.Context, b DeployBackend, tx *types.Transaction) (*ty
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-queryTicker.C:
+		case <-timer.C:
 		}
+		delay = config.next(delay)
+		timer.Reset(delay)
 	}
 }
 
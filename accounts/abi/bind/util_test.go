@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -135,3 +136,64 @@ func TestWaitDeployedCornerCases(t *testing.T) {
 	backend.SendTransaction(ctx, tx)
 	cancel()
 }
+
+// countingBackend is a deploy backend counting the receipt retrievals.
+type countingBackend struct {
+	bind.DeployBackend
+	polls int32
+}
+
+func (b *countingBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	atomic.AddInt32(&b.polls, 1)
+	return b.DeployBackend.TransactionReceipt(ctx, hash)
+}
+
+func TestWaitMinedWithConfig(t *testing.T) {
+	sim := backends.NewSimulatedBackend(
+		core.GenesisAlloc{
+			testKey.Address(): {Balance: big.NewInt(10000000000)},
+		},
+		10000000,
+	)
+	defer sim.Close()
+
+	tx := types.NewTransaction(0, addr, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx, _ = types.SignTx(tx, types.NewNucleusSigner(sim.Blockchain().Config().NetworkID), testKey)
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	var (
+		backend = &countingBackend{DeployBackend: sim}
+		config  = bind.PollConfig{Interval: 5 * time.Millisecond, MaxInterval: 40 * time.Millisecond, Backoff: 2}
+		delay   = 300 * time.Millisecond
+		mined   = make(chan *types.Receipt)
+	)
+	go func() {
+		receipt, err := bind.WaitMinedWithConfig(context.Background(), backend, tx, config)
+		if err != nil {
+			t.Errorf("failed to wait for transaction: %v", err)
+		}
+		mined <- receipt
+	}()
+	// Mine the transaction after a while
+	time.Sleep(delay)
+	sim.Commit()
+	committed := time.Now()
+
+	select {
+	case receipt := <-mined:
+		if receipt == nil || receipt.TxHash != tx.Hash() {
+			t.Fatalf("receipt mismatch: %v", receipt)
+		}
+		// The receipt should be picked up at the latest after the maximum interval
+		if elapsed := time.Since(committed); elapsed > config.MaxInterval+200*time.Millisecond {
+			t.Errorf("receipt retrieved too late: %v after mining", elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for transaction")
+	}
+	// Polling at the initial interval would need 60 rounds, backing off only 12
+	if polls := atomic.LoadInt32(&backend.polls); polls > 20 {
+		t.Errorf("too many polls with backoff: %d", polls)
+	}
+}
@@ -98,3 +98,49 @@ func NewEvent(name, rawName string, anonymous bool, inputs Arguments) Event {
 func (e Event) String() string {
 	return e.str
 }
+
+// TopicFilter converts filter values for the indexed arguments of the event into
+// the topic set expected by log filters. Unless the event is anonymous, the event
+// ID is prepended as the first topic. See Arguments.TopicFilter for the accepted
+// filter values.
+func (e Event) TopicFilter(values ...interface{}) ([][]common.Hash, error) {
+	topics, err := e.Inputs.TopicFilter(values...)
+	if err != nil {
+		return nil, err
+	}
+	if e.Anonymous {
+		return topics, nil
+	}
+	return append([][]common.Hash{{e.ID}}, topics...), nil
+}
+
+// UnpackLogAnonymous unpacks the topics and data of a log emitted by the event
+// into the struct pointed to by out.
+//
+// Anonymous events carry no ID topic, so they can't be told apart by the logs
+// themselves and the bindings skip them; the caller supplies the event instead.
+// If the event is not anonymous, the first topic must be its ID.
+func (e Event) UnpackLogAnonymous(out interface{}, topics []common.Hash, data []byte) error {
+	if !e.Anonymous {
+		if len(topics) == 0 || topics[0] != e.ID {
+			return fmt.Errorf("abi: log is not an event %v", e.Sig)
+		}
+		topics = topics[1:]
+	}
+	if len(data) > 0 {
+		unpacked, err := e.Inputs.Unpack(data)
+		if err != nil {
+			return err
+		}
+		if err := e.Inputs.Copy(out, unpacked); err != nil {
+			return err
+		}
+	}
+	var indexed Arguments
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	return ParseTopics(out, indexed, topics)
+}
@@ -392,3 +392,38 @@ func TestEventIndexedWithArrayUnpack(t *testing.T) {
 	require.Equal(t, [2]uint8{0, 0}, rst.Value1)
 	require.Equal(t, stringOut, rst.Value2)
 }
+
+func TestEventUnpackLogAnonymous(t *testing.T) {
+	definition := `[
+		{"name": "anon", "type": "event", "anonymous": true, "inputs": [{"indexed": true, "name":"from", "type":"address"},{"indexed": true, "name":"id", "type":"uint256"},{"indexed": false, "name":"value", "type":"uint8"}]},
+		{"name": "named", "type": "event", "inputs": [{"indexed": true, "name":"id", "type":"uint256"},{"indexed": false, "name":"value", "type":"uint8"}]}
+	]`
+	type anonStruct struct {
+		From  common.Address // indexed
+		Id    *big.Int       // indexed
+		Value uint8
+	}
+	abi, err := JSON(strings.NewReader(definition))
+	require.NoError(t, err)
+
+	from := common.BytesToAddress([]byte{0xca, 0xfe})
+	topics := []common.Hash{common.BytesToHash(from.Bytes()), common.BigToHash(big.NewInt(42))}
+	data := packNum(reflect.ValueOf(uint8(8)))
+
+	var rst anonStruct
+	require.NoError(t, abi.Events["anon"].UnpackLogAnonymous(&rst, topics, data))
+	require.Equal(t, from, rst.From)
+	require.Equal(t, big.NewInt(42), rst.Id)
+	require.Equal(t, uint8(8), rst.Value)
+
+	// Topic count mismatches are rejected
+	require.Error(t, abi.Events["anon"].UnpackLogAnonymous(&rst, topics[:1], data))
+
+	// Non-anonymous events need their ID as the first topic
+	named := abi.Events["named"]
+	require.Error(t, named.UnpackLogAnonymous(&rst, topics[1:], data))
+	rst = anonStruct{}
+	require.NoError(t, named.UnpackLogAnonymous(&rst, []common.Hash{named.ID, topics[1]}, data))
+	require.Equal(t, big.NewInt(42), rst.Id)
+	require.Equal(t, uint8(8), rst.Value)
+}
@@ -771,6 +771,61 @@ var packUnpackTests = []packUnpackTest{
 			"0000000000000000000000000000000000000000000000000000000000000006" + // len(str[1]) = 6
 			"666f6f6261720000000000000000000000000000000000000000000000000000", // str[1]
 	},
+	{
+		def:      `[{"type": "string[]"}]`,
+		unpacked: []string{},
+		packed: "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000000", // len(array) = 0
+	},
+	{
+		def:      `[{"type": "string[]"}]`,
+		unpacked: []string{"key"},
+		packed: "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000001" + // len(array) = 1
+			"0000000000000000000000000000000000000000000000000000000000000020" + // offset 32 to i = 0
+			"0000000000000000000000000000000000000000000000000000000000000003" + // len(str[0]) = 3
+			"6b65790000000000000000000000000000000000000000000000000000000000", // str[0]
+	},
+	{
+		// Layout of the listKeys return value of CIP-150 contracts
+		def:      `[{"type": "string[]"}]`,
+		unpacked: []string{"", "a string that is longer than thirty-two bytes"},
+		packed: "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000002" + // len(array) = 2
+			"0000000000000000000000000000000000000000000000000000000000000040" + // offset 64 to i = 0
+			"0000000000000000000000000000000000000000000000000000000000000060" + // offset 96 to i = 1
+			"0000000000000000000000000000000000000000000000000000000000000000" + // len(str[0]) = 0
+			"000000000000000000000000000000000000000000000000000000000000002d" + // len(str[1]) = 45
+			"6120737472696e672074686174206973206c6f6e676572207468616e20746869" + // str[1][0:32]
+			"7274792d74776f20627974657300000000000000000000000000000000000000", // str[1][32:45]
+	},
+	{
+		def:      `[{"type": "string[][]"}]`,
+		unpacked: [][]string{},
+		packed: "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000000", // len(array) = 0
+	},
+	{
+		def:      `[{"type": "string[][]"}]`,
+		unpacked: [][]string{{"a", "bc"}, {}, {"def"}},
+		packed: "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000003" + // len(array) = 3
+			"0000000000000000000000000000000000000000000000000000000000000060" + // offset 96 to i = 0
+			"0000000000000000000000000000000000000000000000000000000000000140" + // offset 320 to i = 1
+			"0000000000000000000000000000000000000000000000000000000000000160" + // offset 352 to i = 2
+			"0000000000000000000000000000000000000000000000000000000000000002" + // len(array[0]) = 2
+			"0000000000000000000000000000000000000000000000000000000000000040" + // offset 64 to array[0][0]
+			"0000000000000000000000000000000000000000000000000000000000000080" + // offset 128 to array[0][1]
+			"0000000000000000000000000000000000000000000000000000000000000001" + // len(array[0][0]) = 1
+			"6100000000000000000000000000000000000000000000000000000000000000" + // array[0][0]
+			"0000000000000000000000000000000000000000000000000000000000000002" + // len(array[0][1]) = 2
+			"6263000000000000000000000000000000000000000000000000000000000000" + // array[0][1]
+			"0000000000000000000000000000000000000000000000000000000000000000" + // len(array[1]) = 0
+			"0000000000000000000000000000000000000000000000000000000000000001" + // len(array[2]) = 1
+			"0000000000000000000000000000000000000000000000000000000000000020" + // offset 32 to array[2][0]
+			"0000000000000000000000000000000000000000000000000000000000000003" + // len(array[2][0]) = 3
+			"6465660000000000000000000000000000000000000000000000000000000000", // array[2][0]
+	},
 	{
 		def:      `[{"type": "bytes32[][]"}]`,
 		unpacked: [][][32]byte{{{1}, {2}}, {{3}, {4}, {5}}},
@@ -0,0 +1,139 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/crypto"
+)
+
+// Error is a custom error a contract can revert with. Similarly to a method call,
+// the revert data consists of the 4 byte error id followed by the ABI encoded
+// arguments.
+type Error struct {
+	Name   string
+	Inputs Arguments
+	str    string
+	// Sig contains the string signature according to the ABI spec.
+	// e.g.	 error foo(uint32 a, int b) = "foo(uint32,int256)"
+	// Please note that "int" is substitute for its canonical representation "int256"
+	Sig string
+	// ID returns the canonical representation of the error's signature used by the
+	// abi definition to identify errors in the revert data.
+	ID common.Hash
+}
+
+// NewError creates a new Error.
+// It sanitizes the input arguments to remove unnamed arguments.
+// It also precomputes the id, signature and string representation
+// of the error.
+func NewError(name string, inputs Arguments) Error {
+	names := make([]string, len(inputs))
+	types := make([]string, len(inputs))
+	for i, input := range inputs {
+		if input.Name == "" {
+			inputs[i] = Argument{
+				Name:    fmt.Sprintf("arg%d", i),
+				Indexed: input.Indexed,
+				Type:    input.Type,
+			}
+		} else {
+			inputs[i] = input
+		}
+		// string representation
+		names[i] = fmt.Sprintf("%v %v", input.Type, inputs[i].Name)
+		// sig representation
+		types[i] = input.Type.String()
+	}
+
+	str := fmt.Sprintf("error %v(%v)", name, strings.Join(names, ", "))
+	sig := fmt.Sprintf("%v(%v)", name, strings.Join(types, ","))
+	id := common.BytesToHash(crypto.SHA3([]byte(sig)))
+
+	return Error{
+		Name:   name,
+		Inputs: inputs,
+		str:    str,
+		Sig:    sig,
+		ID:     id,
+	}
+}
+
+func (e Error) String() string {
+	return e.str
+}
+
+// Unpack decodes the arguments of the error from the given revert data.
+func (e Error) Unpack(data []byte) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, errors.New("invalid data for unpacking")
+	}
+	if !bytes.Equal(data[:4], e.ID[:4]) {
+		return nil, fmt.Errorf("revert data is not error %s", e.Name)
+	}
+	return e.Inputs.Unpack(data[4:])
+}
+
+// CustomError is a decoded custom error a contract reverted with.
+type CustomError struct {
+	Name string        // Name of the error in the contract ABI
+	Args []interface{} // Decoded arguments of the error
+}
+
+// String returns the error in a Name(arg0, arg1, ...) format.
+func (e *CustomError) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = fmt.Sprintf("%v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// ErrorByID looks up an error by the 4-byte id, returns an error if none found.
+func (abi *ABI) ErrorByID(sigdata [4]byte) (*Error, error) {
+	for _, e := range abi.Errors {
+		if bytes.Equal(e.ID[:4], sigdata[:]) {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("no error with id: %#x", sigdata[:])
+}
+
+// UnpackError decodes the revert data of a call into one of the custom errors
+// defined in the ABI.
+func (abi *ABI) UnpackError(data []byte) (*CustomError, error) {
+	if len(data) < 4 {
+		return nil, errors.New("invalid data for unpacking")
+	}
+	var id [4]byte
+	copy(id[:], data[:4])
+
+	e, err := abi.ErrorByID(id)
+	if err != nil {
+		return nil, err
+	}
+	args, err := e.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomError{Name: e.Name, Args: args}, nil
+}
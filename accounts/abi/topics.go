@@ -98,6 +98,86 @@ func MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
 	return topics, nil
 }
 
+// TopicFilter converts filter values for the indexed arguments into the topic
+// set expected by log filters, excluding the event ID (see Event.TopicFilter).
+//
+// The i-th value filters on the i-th indexed argument and can be nil to match
+// any value, a single value of the argument's Go type, or a slice of such values
+// ([]interface{} or a typed slice) to match any of them. Values of dynamic types
+// (string, bytes) are hashed, as done when emitting the event. A common.Hash is
+// always accepted as a precomputed topic.
+func (arguments Arguments) TopicFilter(values ...interface{}) ([][]common.Hash, error) {
+	var indexed Arguments
+	for _, arg := range arguments {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(values) > len(indexed) {
+		return nil, fmt.Errorf("abi: too many topic filters: have %d, want at most %d", len(values), len(indexed))
+	}
+	topics := make([][]common.Hash, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		arg := indexed[i]
+		typ := arg.Type.GetType()
+
+		// Gather the alternatives of the filter, checking them against the argument
+		var rules []interface{}
+		switch val := reflect.ValueOf(value); {
+		case val.Type() == typ || val.Type() == reflect.TypeOf(common.Hash{}):
+			rules = []interface{}{value}
+		case val.Kind() == reflect.Slice:
+			for j := 0; j < val.Len(); j++ {
+				rules = append(rules, val.Index(j).Interface())
+			}
+		default:
+			rules = []interface{}{value}
+		}
+		for _, rule := range rules {
+			if _, ok := rule.(common.Hash); ok {
+				continue
+			}
+			if rule == nil || reflect.TypeOf(rule) != typ {
+				return nil, fmt.Errorf("abi: cannot use %T as %v in topic filter of argument %q", rule, arg.Type, arg.Name)
+			}
+		}
+		filter, err := MakeTopics(rules)
+		if err != nil {
+			return nil, fmt.Errorf("abi: argument %q: %v", arg.Name, err)
+		}
+		topics[i] = filter[0]
+	}
+	return topics, nil
+}
+
+// MatchIndexedDynamic finds which of the candidate values was used for an
+// indexed dynamic event argument (string or bytes). Such arguments are only
+// stored as their SHA3 hash in the topic, so the original value can't be
+// decoded, but it can be recovered if the set of possible values is known.
+//
+// It returns the index of the first candidate hashing to topic and whether one
+// was found. Candidates that are neither strings nor byte slices never match.
+func MatchIndexedDynamic(topic common.Hash, candidates []interface{}) (int, bool) {
+	for i, candidate := range candidates {
+		var hash common.Hash
+		switch candidate := candidate.(type) {
+		case string:
+			hash = crypto.SHA3Hash([]byte(candidate))
+		case []byte:
+			hash = crypto.SHA3Hash(candidate)
+		default:
+			continue
+		}
+		if hash == topic {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
 func genIntType(rule int64, size uint) []byte {
 	var topic [common.HashLength]byte
 	if rule < 0 {
@@ -19,6 +19,7 @@ package abi
 import (
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/core-coin/go-core/v2/common"
@@ -130,6 +131,117 @@ func TestMakeTopics(t *testing.T) {
 	}
 }
 
+func TestTopicFilter(t *testing.T) {
+	const definition = `[{"type":"event","name":"Log","inputs":[
+		{"name":"addr","type":"address","indexed":true},
+		{"name":"amount","type":"uint256","indexed":false},
+		{"name":"num","type":"int256","indexed":true},
+		{"name":"str","type":"string","indexed":true},
+		{"name":"data","type":"bytes","indexed":true}
+	]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	event := abi.Events["Log"]
+
+	var (
+		addr1 = common.Address{1}
+		addr2 = common.Address{2}
+		hash  = common.HexToHash("0xdeadbeef")
+	)
+	tests := []struct {
+		name    string
+		values  []interface{}
+		want    [][]common.Hash
+		wantErr bool
+	}{
+		{
+			"no filters",
+			nil,
+			[][]common.Hash{},
+			false,
+		},
+		{
+			"indexed address",
+			[]interface{}{addr1},
+			[][]common.Hash{{common.BytesToHash(addr1.Bytes())}},
+			false,
+		},
+		{
+			"alternative addresses",
+			[]interface{}{[]common.Address{addr1, addr2}},
+			[][]common.Hash{{common.BytesToHash(addr1.Bytes()), common.BytesToHash(addr2.Bytes())}},
+			false,
+		},
+		{
+			"big int",
+			[]interface{}{nil, big.NewInt(1000)},
+			[][]common.Hash{nil, {common.HexToHash("0x03e8")}},
+			false,
+		},
+		{
+			"hashed string and bytes",
+			[]interface{}{nil, nil, "hello", []byte{1, 2, 3}},
+			[][]common.Hash{nil, nil, {crypto.SHA3Hash([]byte("hello"))}, {crypto.SHA3Hash([]byte{1, 2, 3})}},
+			false,
+		},
+		{
+			"alternative strings and bytes",
+			[]interface{}{nil, nil, []interface{}{"a", "b"}, [][]byte{{1}, {2}}},
+			[][]common.Hash{nil, nil,
+				{crypto.SHA3Hash([]byte("a")), crypto.SHA3Hash([]byte("b"))},
+				{crypto.SHA3Hash([]byte{1}), crypto.SHA3Hash([]byte{2})},
+			},
+			false,
+		},
+		{
+			"precomputed hash",
+			[]interface{}{nil, nil, hash, []interface{}{hash, []byte{1}}},
+			[][]common.Hash{nil, nil, {hash}, {hash, crypto.SHA3Hash([]byte{1})}},
+			false,
+		},
+		{
+			"mismatching type",
+			[]interface{}{"cb00"},
+			nil,
+			true,
+		},
+		{
+			"mismatching alternative type",
+			[]interface{}{nil, []interface{}{big.NewInt(1), int64(2)}},
+			nil,
+			true,
+		},
+		{
+			"too many filters",
+			[]interface{}{nil, nil, nil, nil, nil},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := event.Inputs.TopicFilter(tt.values...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TopicFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopicFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// The event should prepend its ID to the topics
+	topics, err := event.TopicFilter(addr1)
+	if err != nil {
+		t.Fatalf("failed to create event topics: %v", err)
+	}
+	want := [][]common.Hash{{event.ID}, {common.BytesToHash(addr1.Bytes())}}
+	if !reflect.DeepEqual(topics, want) {
+		t.Errorf("event topics mismatch: have %v, want %v", topics, want)
+	}
+}
+
 type args struct {
 	createObj func() interface{}
 	resultObj func() interface{}
@@ -379,3 +491,23 @@ func TestParseTopicsIntoMap(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchIndexedDynamic(t *testing.T) {
+	candidates := []interface{}{"Hi", []byte("Hello"), 42, "Hello"}
+	tests := []struct {
+		topic common.Hash
+		index int
+		found bool
+	}{
+		{crypto.SHA3Hash([]byte("Hi")), 0, true},
+		{crypto.SHA3Hash([]byte("Hello")), 1, true}, // first match wins
+		{crypto.SHA3Hash([]byte("Bye")), -1, false},
+		{common.BigToHash(big.NewInt(42)), -1, false}, // non-dynamic candidates are skipped
+	}
+	for i, tt := range tests {
+		index, found := MatchIndexedDynamic(tt.topic, candidates)
+		if index != tt.index || found != tt.found {
+			t.Errorf("test %d: have (%d, %v), want (%d, %v)", i, index, found, tt.index, tt.found)
+		}
+	}
+}
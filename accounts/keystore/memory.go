@@ -0,0 +1,313 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	core "github.com/core-coin/go-core/v2"
+	"github.com/core-coin/go-core/v2/accounts"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/types"
+	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/event"
+)
+
+// MemoryKeyStoreScheme is the protocol scheme prefixing account and wallet URLs
+// of the in-memory keystore.
+const MemoryKeyStoreScheme = "memory"
+
+// ErrKeyStoreClosed is returned if an operation is attempted on a closed
+// in-memory keystore.
+var ErrKeyStoreClosed = errors.New("keystore closed")
+
+// MemoryKeyStore manages ephemeral keys which are only ever held in memory. The
+// keys are never written to disk and are zeroed when the keystore is closed, so
+// all accounts are lost with it.
+type MemoryKeyStore struct {
+	keys    map[common.Address]*crypto.PrivateKey // Plaintext private keys of the accounts
+	wallets []accounts.Wallet                     // Wallet wrappers around the individual keys, sorted by URL
+	closed  bool                                  // Whether the keys were already zeroed
+
+	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
+	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
+
+	mu sync.RWMutex
+}
+
+// NewMemoryKeyStore creates an empty in-memory keystore.
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{keys: make(map[common.Address]*crypto.PrivateKey)}
+}
+
+// Wallets implements accounts.Backend, returning all single-key wallets held by
+// the keystore.
+func (ks *MemoryKeyStore) Wallets() []accounts.Wallet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(ks.wallets))
+	copy(cpy, ks.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend, creating an async subscription to
+// receive notifications on the addition or removal of keystore wallets.
+func (ks *MemoryKeyStore) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return ks.updateScope.Track(ks.updateFeed.Subscribe(sink))
+}
+
+// HasAddress reports whether a key with the given address is present.
+func (ks *MemoryKeyStore) HasAddress(addr common.Address) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	_, ok := ks.keys[addr]
+	return ok
+}
+
+// Accounts returns all key accounts held by the keystore, sorted by URL.
+func (ks *MemoryKeyStore) Accounts() []accounts.Account {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	accs := make([]accounts.Account, len(ks.wallets))
+	for i, wallet := range ks.wallets {
+		accs[i] = wallet.(*memoryWallet).account
+	}
+	return accs
+}
+
+// NewAccount generates a new random key and stores it in memory.
+func (ks *MemoryKeyStore) NewAccount() (accounts.Account, error) {
+	priv, err := crypto.GenerateKey(crand.Reader)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return ks.ImportEDDSA(priv)
+}
+
+// ImportEDDSA stores the given key in memory. The keystore takes ownership of
+// the key, zeroing it when the account is deleted or the keystore closed.
+func (ks *MemoryKeyStore) ImportEDDSA(priv *crypto.PrivateKey) (accounts.Account, error) {
+	account := accounts.Account{Address: priv.Address()}
+	account.URL = accounts.URL{Scheme: MemoryKeyStoreScheme, Path: account.Address.Hex()}
+
+	ks.mu.Lock()
+	if ks.closed {
+		ks.mu.Unlock()
+		return accounts.Account{}, ErrKeyStoreClosed
+	}
+	if _, ok := ks.keys[account.Address]; ok {
+		ks.mu.Unlock()
+		return account, ErrAccountAlreadyExists
+	}
+	ks.keys[account.Address] = priv
+
+	wallet := &memoryWallet{account: account, keystore: ks}
+	ks.wallets = append(ks.wallets, wallet)
+	sort.Sort(accounts.WalletsByURL(ks.wallets))
+	ks.mu.Unlock()
+
+	ks.updateFeed.Send(accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+	return account, nil
+}
+
+// Delete zeroes the key of the given account and removes it from the keystore.
+func (ks *MemoryKeyStore) Delete(a accounts.Account) error {
+	ks.mu.Lock()
+	key, ok := ks.keys[a.Address]
+	if !ok {
+		ks.mu.Unlock()
+		return ErrNoMatch
+	}
+	zeroMemoryKey(key)
+	delete(ks.keys, a.Address)
+
+	var dropped accounts.Wallet
+	for i, wallet := range ks.wallets {
+		if wallet.(*memoryWallet).account.Address == a.Address {
+			dropped = wallet
+			ks.wallets = append(ks.wallets[:i:i], ks.wallets[i+1:]...)
+			break
+		}
+	}
+	ks.mu.Unlock()
+
+	ks.updateFeed.Send(accounts.WalletEvent{Wallet: dropped, Kind: accounts.WalletDropped})
+	return nil
+}
+
+// SignHash calculates an EDDSA signature for the given hash.
+func (ks *MemoryKeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[a.Address]
+	if !ok {
+		return nil, ErrNoMatch
+	}
+	return crypto.Sign(hash, key)
+}
+
+// SignTx signs the given transaction with the requested account.
+func (ks *MemoryKeyStore) SignTx(a accounts.Account, tx *types.Transaction, networkID *big.Int) (*types.Transaction, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[a.Address]
+	if !ok {
+		return nil, ErrNoMatch
+	}
+	return types.SignTx(tx, types.NewNucleusSigner(networkID), key)
+}
+
+// Close zeroes all keys held in memory and drops every wallet. The keystore
+// cannot be used anymore afterwards.
+func (ks *MemoryKeyStore) Close() error {
+	ks.mu.Lock()
+	if ks.closed {
+		ks.mu.Unlock()
+		return nil
+	}
+	ks.closed = true
+	for addr, key := range ks.keys {
+		zeroMemoryKey(key)
+		delete(ks.keys, addr)
+	}
+	wallets := ks.wallets
+	ks.wallets = nil
+	ks.mu.Unlock()
+
+	for _, wallet := range wallets {
+		ks.updateFeed.Send(accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletDropped})
+	}
+	ks.updateScope.Close()
+	return nil
+}
+
+// zeroMemoryKey overwrites the secret bytes of a private key in place.
+func zeroMemoryKey(k *crypto.PrivateKey) {
+	b := k.PrivateKey()
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// memoryWallet implements the accounts.Wallet interface for a single key of the
+// in-memory keystore.
+type memoryWallet struct {
+	account  accounts.Account // Single account contained in this wallet
+	keystore *MemoryKeyStore  // Keystore where the account originates from
+}
+
+// URL implements accounts.Wallet, returning the URL of the account within.
+func (w *memoryWallet) URL() accounts.URL {
+	return w.account.URL
+}
+
+// Status implements accounts.Wallet. In-memory keys are always usable until
+// they are deleted.
+func (w *memoryWallet) Status() (string, error) {
+	if !w.keystore.HasAddress(w.account.Address) {
+		return "Deleted", nil
+	}
+	return "Unlocked", nil
+}
+
+// Open implements accounts.Wallet, but is a noop since in-memory keys are never
+// encrypted.
+func (w *memoryWallet) Open(passphrase string) error { return nil }
+
+// Close implements accounts.Wallet, but is a noop since there is no meaningful
+// open operation. Keys are zeroed by closing the keystore instead.
+func (w *memoryWallet) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning an account list consisting of
+// the single account that the wallet contains.
+func (w *memoryWallet) Accounts() []accounts.Account {
+	return []accounts.Account{w.account}
+}
+
+// Contains implements accounts.Wallet, returning whether a particular account is
+// or is not wrapped by this wallet instance.
+func (w *memoryWallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.account.URL)
+}
+
+// Derive implements accounts.Wallet, but is a noop since there is no notion of
+// hierarchical account derivation for single keys.
+func (w *memoryWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop since there is no notion
+// of hierarchical account derivation for single keys.
+func (w *memoryWallet) SelfDerive(bases []accounts.DerivationPath, chain core.ChainStateReader) {
+}
+
+// signHash attempts to sign the given hash with the given account. If the wallet
+// does not wrap this particular account, an error is returned to avoid account
+// leakage.
+func (w *memoryWallet) signHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return w.keystore.SignHash(account, hash)
+}
+
+// SignData signs SHA3(data). The mimetype parameter describes the type of data being signed.
+func (w *memoryWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	return w.signHash(account, crypto.SHA3(data))
+}
+
+// SignDataWithPassphrase signs SHA3(data), ignoring the passphrase since
+// in-memory keys are not encrypted.
+func (w *memoryWallet) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
+	return w.SignData(account, mimeType, data)
+}
+
+// SignText implements accounts.Wallet, attempting to sign the hash of the given
+// text with the given account.
+func (w *memoryWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	return w.signHash(account, accounts.TextHash(text))
+}
+
+// SignTextWithPassphrase implements accounts.Wallet, signing the hash of the
+// given text and ignoring the passphrase since in-memory keys are not encrypted.
+func (w *memoryWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	return w.SignText(account, text)
+}
+
+// SignTx implements accounts.Wallet, attempting to sign the given transaction
+// with the given account.
+func (w *memoryWallet) SignTx(account accounts.Account, tx *types.Transaction, networkID *big.Int) (*types.Transaction, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return w.keystore.SignTx(account, tx, networkID)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, signing the given transaction
+// and ignoring the passphrase since in-memory keys are not encrypted.
+func (w *memoryWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, networkID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, networkID)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/accounts"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/types"
+	"github.com/core-coin/go-core/v2/crypto"
+)
+
+// Tests that the in-memory keystore creates accounts and signs with them,
+// without ever touching the filesystem.
+func TestMemoryKeyStore(t *testing.T) {
+	// Run from within an empty directory to detect any file written
+	dir, err := ioutil.TempDir("", "xcb-memory-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	ks := NewMemoryKeyStore()
+	var _ accounts.Backend = ks
+
+	events := make(chan accounts.WalletEvent, 4)
+	sub := ks.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	a, err := ks.NewAccount()
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if a.URL.Scheme != MemoryKeyStoreScheme {
+		t.Errorf("account URL scheme mismatch: have %s, want %s", a.URL.Scheme, MemoryKeyStoreScheme)
+	}
+	if !ks.HasAddress(a.Address) {
+		t.Errorf("HasAddress(%x) should've returned true", a.Address)
+	}
+	if ev := <-events; ev.Kind != accounts.WalletArrived || !ev.Wallet.Contains(a) {
+		t.Errorf("wallet arrival event mismatch: %+v", ev)
+	}
+	wallets := ks.Wallets()
+	if len(wallets) != 1 || !wallets[0].Contains(a) {
+		t.Fatalf("wallet list mismatch: have %v", wallets)
+	}
+	// Sign a hash and a transaction through the wallet
+	hash := crypto.SHA3([]byte("ephemeral"))
+	sig, err := ks.SignHash(a, hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatalf("failed to recover public key: %v", err)
+	}
+	if addr := crypto.PubkeyToAddress(pub); addr != a.Address {
+		t.Errorf("signer mismatch: have %x, want %x", addr, a.Address)
+	}
+	networkID := big.NewInt(1)
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := wallets[0].SignTx(a, tx, networkID)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if from, err := types.Sender(types.NewNucleusSigner(networkID), signed); err != nil || from != a.Address {
+		t.Errorf("transaction sender mismatch: have %x (%v), want %x", from, err, a.Address)
+	}
+	if _, err := wallets[0].SignTx(accounts.Account{Address: common.Address{1}}, tx, networkID); err != accounts.ErrUnknownAccount {
+		t.Errorf("foreign account signing error mismatch: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+	// Closing the keystore should zero the key and drop the wallet
+	ks.mu.RLock()
+	key := ks.keys[a.Address]
+	ks.mu.RUnlock()
+
+	if err := ks.Close(); err != nil {
+		t.Fatalf("failed to close keystore: %v", err)
+	}
+	if ev := <-events; ev.Kind != accounts.WalletDropped || !ev.Wallet.Contains(a) {
+		t.Errorf("wallet drop event mismatch: %+v", ev)
+	}
+	for _, b := range key.PrivateKey() {
+		if b != 0 {
+			t.Fatalf("private key not zeroed: %x", key.PrivateKey())
+		}
+	}
+	if len(ks.Wallets()) != 0 || ks.HasAddress(a.Address) {
+		t.Errorf("keys retained after close")
+	}
+	if _, err := ks.SignHash(a, hash); err != ErrNoMatch {
+		t.Errorf("signing after close error mismatch: have %v, want %v", err, ErrNoMatch)
+	}
+	if _, err := ks.NewAccount(); err != ErrKeyStoreClosed {
+		t.Errorf("account creation after close error mismatch: have %v, want %v", err, ErrKeyStoreClosed)
+	}
+	// Make sure nothing was persisted
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("keystore wrote %d files to disk", len(files))
+	}
+}
@@ -84,6 +84,18 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. foo=bar",
 	}
+	typeMapFlag = cli.StringFlag{
+		Name:  "typemap",
+		Usage: `Path to a JSON file mapping ABI types to custom Go types, e.g. {"uint256": {"type": "decimal.Big", "import": "...", "pack": "...", "unpack": "..."}}`,
+	}
+	cliFlag = cli.BoolFlag{
+		Name:  "cli",
+		Usage: "Generate a standalone command line interface (main package) instead of a binding",
+	}
+	mockFlag = cli.BoolFlag{
+		Name:  "mock",
+		Usage: "Generate interfaces and recording mocks complementing the binding instead of the binding itself",
+	}
 )
 
 func init() {
@@ -99,6 +111,9 @@ func init() {
 		pkgFlag,
 		outFlag,
 		aliasFlag,
+		typeMapFlag,
+		cliFlag,
+		mockFlag,
 	}
 	app.Action = utils.MigrateFlags(abigen)
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
@@ -106,7 +121,15 @@ func init() {
 
 func abigen(c *cli.Context) error {
 	utils.CheckExclusive(c, abiFlag, jsonFlag, solFlag) // Only one source can be selected.
-	if c.GlobalString(pkgFlag.Name) == "" {
+	utils.CheckExclusive(c, cliFlag, mockFlag)          // Only one output kind can be selected.
+	mode := bind.ModeBinding
+	switch {
+	case c.GlobalBool(cliFlag.Name):
+		mode = bind.ModeCLI
+	case c.GlobalBool(mockFlag.Name):
+		mode = bind.ModeMock
+	}
+	if mode != bind.ModeCLI && c.GlobalString(pkgFlag.Name) == "" {
 		utils.Fatalf("No destination package specified (--pkg)")
 	}
 	// If the entire ylem code was specified, build and bind based on that
@@ -150,6 +173,9 @@ func abigen(c *cli.Context) error {
 		if kind == "" {
 			kind = c.GlobalString(pkgFlag.Name)
 		}
+		if kind == "" {
+			utils.Fatalf("No contract type specified (--type)")
+		}
 		types = append(types, kind)
 	} else {
 		// Generate the list of types to exclude from binding
@@ -207,8 +233,19 @@ func abigen(c *cli.Context) error {
 			aliases[match[1]] = match[2]
 		}
 	}
+	// Load the custom Go type mappings, if any
+	var mappings map[string]bind.TypeMapping
+	if c.GlobalIsSet(typeMapFlag.Name) {
+		blob, err := ioutil.ReadFile(c.GlobalString(typeMapFlag.Name))
+		if err != nil {
+			utils.Fatalf("Failed to read type mappings: %v", err)
+		}
+		if err := json.Unmarshal(blob, &mappings); err != nil {
+			utils.Fatalf("Failed to parse type mappings: %v", err)
+		}
+	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), libs, aliases)
+	code, err := bind.BindWithMappings(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), mode, libs, aliases, mappings)
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
@@ -101,7 +101,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	}
 	var (
 		statedb     = MakePreState(rawdb.NewMemoryDatabase(), pre.Pre)
-		signer      = types.MakeSigner(chainConfig.NetworkID)
+		signer      = types.MakeBlockSigner(chainConfig, new(big.Int).SetUint64(pre.Env.Number))
 		energypool  = new(core.EnergyPool)
 		blockHash   = common.Hash{0x13, 0x37}
 		rejectedTxs []int
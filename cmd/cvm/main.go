@@ -108,6 +108,10 @@ var (
 		Name:  "json",
 		Usage: "output trace logs in machine readable format (json)",
 	}
+	TraceFormatFlag = cli.StringFlag{
+		Name:  "trace.format",
+		Usage: "output the trace in a debug_traceTransaction format (callTracer or structLog)",
+	}
 	SenderFlag = cli.StringFlag{
 		Name:  "sender",
 		Usage: "The transaction origin",
@@ -132,6 +136,10 @@ var (
 		Name:  "noreturndata",
 		Usage: "disable return data output",
 	}
+	DeterministicFlag = cli.BoolFlag{
+		Name:  "deterministic",
+		Usage: "use a fixed block context and omit timings, so identical input yields identical output",
+	}
 	CVMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.cvm",
 		Usage: "External CVM configuration (default = built-in interpreter)",
@@ -181,6 +189,7 @@ func init() {
 		StatDumpFlag,
 		GenesisFlag,
 		MachineFlag,
+		TraceFormatFlag,
 		SenderFlag,
 		ReceiverFlag,
 		DisableMemoryFlag,
@@ -188,6 +197,7 @@ func init() {
 		DisableStorageFlag,
 		DisableReturnDataFlag,
 		CVMInterpreterFlag,
+		DeterministicFlag,
 		utils.NetworkIdFlag,
 	}
 	app.Commands = []cli.Command{
@@ -196,6 +206,7 @@ func init() {
 		runCommand,
 		stateTestCommand,
 		stateTransitionCommand,
+		validateCommand,
 	}
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
 }
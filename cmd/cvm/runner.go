@@ -19,7 +19,9 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -39,8 +41,10 @@ import (
 	"github.com/core-coin/go-core/v2/core/state"
 	"github.com/core-coin/go-core/v2/core/vm"
 	"github.com/core-coin/go-core/v2/core/vm/runtime"
+	"github.com/core-coin/go-core/v2/internal/xcbapi"
 	"github.com/core-coin/go-core/v2/log"
 	"github.com/core-coin/go-core/v2/params"
+	"github.com/core-coin/go-core/v2/xcb/tracers"
 )
 
 var runCommand = cli.Command{
@@ -105,6 +109,93 @@ func timedExec(bench bool, execFunc func() ([]byte, uint64, error)) (output []by
 	return output, energyLeft, stats, err
 }
 
+// newFormattedTracer creates a tracer producing the same output as the given
+// debug_traceTransaction format.
+func newFormattedTracer(format string, logconfig *vm.LogConfig) (vm.Tracer, error) {
+	switch format {
+	case "callTracer":
+		return tracers.New(format)
+	case "structLog":
+		return vm.NewStructLogger(logconfig), nil
+	default:
+		return nil, fmt.Errorf("unknown trace format %q", format)
+	}
+}
+
+// writeFormattedTrace writes the result of a tracer created by newFormattedTracer
+// as JSON, in the shape debug_traceTransaction returns it.
+func writeFormattedTrace(w io.Writer, tracer vm.Tracer, output []byte, energyUsed uint64, err error) error {
+	var result interface{}
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		result = &xcbapi.ExecutionResult{
+			Energy:      energyUsed,
+			Failed:      err != nil,
+			ReturnValue: fmt.Sprintf("%x", output),
+			StructLogs:  xcbapi.FormatLogs(tracer.StructLogs()),
+		}
+	case *tracers.Tracer:
+		res, err := tracer.GetResult()
+		if err != nil {
+			return err
+		}
+		result = res
+	default:
+		return fmt.Errorf("bad tracer type %T", tracer)
+	}
+	return json.NewEncoder(w).Encode(result)
+}
+
+// runnerChainConfig returns the chain configuration to execute with, based on
+// the one of the prestate genesis if any. The network ID set by the --networkid
+// flag is applied to it, and it's an error if it conflicts with the one of the
+// genesis, since transactions signed for one network don't verify on another.
+func runnerChainConfig(ctx *cli.Context, genesisConfig *params.ChainConfig) (*params.ChainConfig, error) {
+	var config params.ChainConfig
+	if genesisConfig != nil {
+		config = *genesisConfig
+	} else {
+		config = *params.MainnetChainConfig
+	}
+	if !ctx.GlobalIsSet(utils.NetworkIdFlag.Name) {
+		if config.NetworkID == nil {
+			return nil, errors.New("genesis does not specify a network ID, use --" + utils.NetworkIdFlag.Name)
+		}
+		return &config, nil
+	}
+	id := new(big.Int).SetUint64(ctx.GlobalUint64(utils.NetworkIdFlag.Name))
+	if genesisConfig != nil && genesisConfig.NetworkID != nil && genesisConfig.NetworkID.Cmp(id) != 0 {
+		return nil, fmt.Errorf("network ID mismatch: genesis has %v, --%s flag has %v", genesisConfig.NetworkID, utils.NetworkIdFlag.Name, id)
+	}
+	config.NetworkID = id
+	return &config, nil
+}
+
+// untimedTracer hides the execution time from the wrapped tracer.
+type untimedTracer struct {
+	vm.Tracer
+}
+
+func (t untimedTracer) CaptureEnd(output []byte, energyUsed uint64, _ time.Duration, err error) error {
+	return t.Tracer.CaptureEnd(output, energyUsed, 0, err)
+}
+
+// makeDeterministic pins the block context of the runtime configuration to
+// fixed values, whatever the prestate says, and hides the execution time from
+// the tracer. The same code and input then always produce the same output and
+// trace, which is needed for differential fuzzing against other VMs.
+func makeDeterministic(cfg *runtime.Config) {
+	cfg.Coinbase = common.Address{}
+	cfg.BlockNumber = new(big.Int)
+	cfg.Time = new(big.Int)
+	cfg.Difficulty = new(big.Int)
+	cfg.GetHashFn = nil // the default derives the hashes from the block numbers
+
+	if cfg.CVMConfig.Tracer != nil {
+		cfg.CVMConfig.Tracer = untimedTracer{cfg.CVMConfig.Tracer}
+	}
+}
+
 func runCmd(ctx *cli.Context) error {
 	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
 	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
@@ -117,10 +208,6 @@ func runCmd(ctx *cli.Context) error {
 		Debug:             ctx.GlobalBool(DebugFlag.Name),
 	}
 
-	if id := ctx.GlobalUint64(utils.NetworkIdFlag.Name); id != 0 {
-		common.DefaultNetworkID = common.NetworkID(id)
-	}
-
 	var (
 		tracer        vm.Tracer
 		debugLogger   *vm.StructLogger
@@ -128,7 +215,13 @@ func runCmd(ctx *cli.Context) error {
 		chainConfig   *params.ChainConfig
 		genesisConfig *core.Genesis
 	)
-	if ctx.GlobalBool(MachineFlag.Name) {
+	traceFormat := ctx.GlobalString(TraceFormatFlag.Name)
+	if traceFormat != "" {
+		var err error
+		if tracer, err = newFormattedTracer(traceFormat, logconfig); err != nil {
+			return err
+		}
+	} else if ctx.GlobalBool(MachineFlag.Name) {
 		tracer = vm.NewJSONLogger(logconfig, os.Stdout)
 	} else if ctx.GlobalBool(DebugFlag.Name) {
 		debugLogger = vm.NewStructLogger(logconfig)
@@ -143,23 +236,29 @@ func runCmd(ctx *cli.Context) error {
 		genesis := gen.ToBlock(db)
 		statedb, _ = state.New(genesis.Root(), state.NewDatabase(db), nil)
 		chainConfig = gen.Config
-		common.DefaultNetworkID = common.NetworkID(chainConfig.NetworkID.Int64())
 	} else {
 		statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
 		genesisConfig = new(core.Genesis)
 	}
+	chainConfig, err := runnerChainConfig(ctx, chainConfig)
+	if err != nil {
+		return err
+	}
+	common.DefaultNetworkID = common.NetworkID(chainConfig.NetworkID.Int64())
 
 	sender := common.BytesToAddress([]byte("sender"))
 	receiver := common.BytesToAddress([]byte("receiver"))
 
-	var err error
 	if ctx.GlobalString(SenderFlag.Name) != "" {
 		sender, err = common.HexToAddress(ctx.GlobalString(SenderFlag.Name))
 		if err != nil {
 			return err
 		}
 	}
-	statedb.CreateAccount(sender)
+	// Keep the prestate of the sender, e.g. its nonce or code if it's also the receiver
+	if !statedb.Exist(sender) {
+		statedb.CreateAccount(sender)
+	}
 
 	if ctx.GlobalString(ReceiverFlag.Name) != "" {
 		receiver, err = common.HexToAddress(ctx.GlobalString(ReceiverFlag.Name))
@@ -228,11 +327,15 @@ func runCmd(ctx *cli.Context) error {
 		BlockNumber: new(big.Int).SetUint64(genesisConfig.Number),
 		CVMConfig: vm.Config{
 			Tracer:         tracer,
-			Debug:          ctx.GlobalBool(DebugFlag.Name) || ctx.GlobalBool(MachineFlag.Name),
+			Debug:          ctx.GlobalBool(DebugFlag.Name) || ctx.GlobalBool(MachineFlag.Name) || traceFormat != "",
 			CVMInterpreter: ctx.GlobalString(CVMInterpreterFlag.Name),
 		},
 	}
 
+	if ctx.GlobalBool(DeterministicFlag.Name) {
+		makeDeterministic(&runtimeConfig)
+	}
+
 	if cpuProfilePath := ctx.GlobalString(CPUProfileFlag.Name); cpuProfilePath != "" {
 		f, err := os.Create(cpuProfilePath)
 		if err != nil {
@@ -246,11 +349,7 @@ func runCmd(ctx *cli.Context) error {
 		defer pprof.StopCPUProfile()
 	}
 
-	if chainConfig != nil {
-		runtimeConfig.ChainConfig = chainConfig
-	} else {
-		runtimeConfig.ChainConfig = params.MainnetChainConfig
-	}
+	runtimeConfig.ChainConfig = chainConfig
 
 	var hexInput []byte
 	if inputFileFlag := ctx.GlobalString(InputFileFlag.Name); inputFileFlag != "" {
@@ -272,8 +371,11 @@ func runCmd(ctx *cli.Context) error {
 			return output, energyLeft, err
 		}
 	} else {
+		// Without inline code, the receiver runs its code from the prestate
 		if len(code) > 0 {
 			statedb.SetCode(receiver, code)
+		} else if statedb.GetCodeSize(receiver) == 0 {
+			log.Warn("Receiver has no code, the call executes nothing", "receiver", receiver)
 		}
 		execFunc = func() ([]byte, uint64, error) {
 			return runtime.Call(receiver, input, &runtimeConfig)
@@ -302,6 +404,12 @@ func runCmd(ctx *cli.Context) error {
 		f.Close()
 	}
 
+	if traceFormat != "" {
+		if err := writeFormattedTrace(os.Stdout, tracer, output, initialEnergy-leftOverEnergy, err); err != nil {
+			return err
+		}
+	}
+
 	if ctx.GlobalBool(DebugFlag.Name) {
 		if debugLogger != nil {
 			fmt.Fprintln(os.Stderr, "#### TRACE ####")
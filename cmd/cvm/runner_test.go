@@ -0,0 +1,275 @@
+// Copyright 2024 by the Authors
+// This file is part of go-core.
+//
+// go-core is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-core is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-core. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/core/state"
+	"github.com/core-coin/go-core/v2/core/vm"
+	"github.com/core-coin/go-core/v2/core/vm/runtime"
+	"github.com/core-coin/go-core/v2/crypto"
+)
+
+// runFormattedTrace executes a contract calling into another one, returning the
+// trace in the requested format.
+func runFormattedTrace(t *testing.T, format string) []byte {
+	var (
+		sender   = common.BytesToAddress([]byte("sender"))
+		outer    = common.BytesToAddress([]byte("outer"))
+		inner    = common.BytesToAddress([]byte("inner"))
+		statedb  *state.StateDB
+		innerRet = []byte{
+			byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+			byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+		}
+	)
+	// The outer contract calls the inner one twice and returns the last result
+	call := []byte{
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00,
+		byte(vm.PUSH22),
+	}
+	call = append(call, inner.Bytes()...)
+	call = append(call, byte(vm.ENERGY), byte(vm.CALL), byte(vm.POP))
+
+	outerCode := append(append([]byte{}, call...), call...)
+	outerCode = append(outerCode, byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN))
+
+	statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(outer, outerCode)
+	statedb.SetCode(inner, innerRet)
+
+	tracer, err := newFormattedTracer(format, &vm.LogConfig{DisableMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create tracer: %v", err)
+	}
+	cfg := &runtime.Config{
+		Origin:      sender,
+		State:       statedb,
+		EnergyLimit: 1000000,
+		CVMConfig:   vm.Config{Debug: true, Tracer: tracer},
+	}
+	output, leftOver, err := runtime.Call(outer, nil, cfg)
+	if err != nil {
+		t.Fatalf("failed to execute contract: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := writeFormattedTrace(buf, tracer, output, cfg.EnergyLimit-leftOver, err); err != nil {
+		t.Fatalf("failed to write trace: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// Tests that the callTracer output of a contract making nested calls matches the
+// format returned by debug_traceTransaction.
+func TestCallTracerFormat(t *testing.T) {
+	var have map[string]interface{}
+	if err := json.Unmarshal(runFormattedTrace(t, "callTracer"), &have); err != nil {
+		t.Fatalf("failed to decode trace: %v", err)
+	}
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "calltracer_nested.json"))
+	if err != nil {
+		t.Fatalf("failed to read golden trace: %v", err)
+	}
+	var want map[string]interface{}
+	if err := json.Unmarshal(golden, &want); err != nil {
+		t.Fatalf("failed to decode golden trace: %v", err)
+	}
+	// The execution time is not deterministic
+	delete(have, "time")
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("trace mismatch:\nhave %v\nwant %v", have, want)
+	}
+}
+
+// Tests that the structLog output is an execution result as returned by
+// debug_traceTransaction, including the steps of the nested calls.
+func TestStructLogFormat(t *testing.T) {
+	var result struct {
+		Energy      uint64 `json:"energy"`
+		Failed      bool   `json:"failed"`
+		ReturnValue string `json:"returnValue"`
+		StructLogs  []struct {
+			Op    string `json:"op"`
+			Depth int    `json:"depth"`
+		} `json:"structLogs"`
+	}
+	if err := json.Unmarshal(runFormattedTrace(t, "structLog"), &result); err != nil {
+		t.Fatalf("failed to decode trace: %v", err)
+	}
+	if result.Failed || result.Energy == 0 {
+		t.Errorf("execution result mismatch: failed %v, energy %d", result.Failed, result.Energy)
+	}
+	if want := "000000000000000000000000000000000000000000000000000000000000002a"; result.ReturnValue != want {
+		t.Errorf("return value mismatch: have %s, want %s", result.ReturnValue, want)
+	}
+	var inner int
+	for _, log := range result.StructLogs {
+		if log.Depth == 2 && log.Op == "RETURN" {
+			inner++
+		}
+	}
+	if inner != 2 {
+		t.Errorf("nested call steps mismatch: have %d returns, want 2", inner)
+	}
+}
+
+// Tests that unknown trace formats are rejected.
+func TestUnknownTraceFormat(t *testing.T) {
+	if _, err := newFormattedTracer("prestateTracer", nil); err == nil {
+		t.Fatal("unknown trace format accepted")
+	}
+}
+
+// Tests that the network ID of the --networkid flag must match the one of the
+// prestate genesis.
+func TestRunnerNetworkID(t *testing.T) {
+	defer func(id common.NetworkID) { common.DefaultNetworkID = id }(common.DefaultNetworkID)
+
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	if err := ioutil.WriteFile(genesis, []byte(`{"config":{"networkId":3},"alloc":{},"energyLimit":"0x100000","difficulty":"0x1"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args []string
+		id   common.NetworkID
+		err  string
+	}{
+		{args: []string{"--prestate", genesis}, id: 3},
+		{args: []string{"--prestate", genesis, "--networkid", "3"}, id: 3},
+		{args: []string{"--prestate", genesis, "--networkid", "1"}, err: "network ID mismatch: genesis has 3, --networkid flag has 1"},
+		{args: []string{"--networkid", "5"}, id: 5},
+	}
+	for i, tt := range tests {
+		common.DefaultNetworkID = 0
+
+		args := append(append([]string{"cvm"}, tt.args...), "--code", "00", "run")
+		err := app.Run(args)
+		switch {
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		case tt.err == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.err == "" && common.DefaultNetworkID != tt.id:
+			t.Errorf("test %d: network ID mismatch: have %d, want %d", i, common.DefaultNetworkID, tt.id)
+		}
+	}
+}
+
+// runCapturingStdout runs the cvm with the given arguments and returns what it
+// printed to stdout.
+func runCapturingStdout(t *testing.T, args ...string) []byte {
+	out, err := ioutil.TempFile(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	err = app.Run(append([]string{"cvm"}, args...))
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	blob, err := ioutil.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blob
+}
+
+// Tests that deterministic runs ignore the block context of the prestate and
+// produce byte-identical traces.
+func TestDeterministicRun(t *testing.T) {
+	defer func(id common.NetworkID) { common.DefaultNetworkID = id }(common.DefaultNetworkID)
+
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	if err := ioutil.WriteFile(genesis, []byte(`{"config":{"networkId":3},"alloc":{},"energyLimit":"0x100000","difficulty":"0x20000","timestamp":"0x5f5e100","number":"0x10"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Return TIMESTAMP + NUMBER + DIFFICULTY
+	code := common.Bytes2Hex([]byte{
+		byte(vm.TIMESTAMP), byte(vm.NUMBER), byte(vm.ADD), byte(vm.DIFFICULTY), byte(vm.ADD),
+		byte(vm.PUSH1), 0x00, byte(vm.MSTORE), byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	})
+	args := []string{"--prestate", genesis, "--code", code, "--json", "--deterministic", "run"}
+
+	first := runCapturingStdout(t, args...)
+	second := runCapturingStdout(t, args...)
+	if !bytes.Equal(first, second) {
+		t.Fatalf("traces differ:\nfirst:  %s\nsecond: %s", first, second)
+	}
+	lines := bytes.Split(bytes.TrimSpace(first), []byte("\n"))
+	var end struct {
+		Output string `json:"output"`
+		Time   int64  `json:"time"`
+	}
+	if err := json.Unmarshal(lines[len(lines)-1], &end); err != nil {
+		t.Fatalf("failed to decode trace end: %v", err)
+	}
+	if want := common.Bytes2Hex(make([]byte, 32)); end.Output != want || end.Time != 0 {
+		t.Errorf("deterministic result mismatch: have output %s time %d, want output %s time 0", end.Output, end.Time, want)
+	}
+	// Without the flag, the block context of the prestate is used
+	plain := runCapturingStdout(t, "--prestate", genesis, "--code", code, "run")
+	if want := "0x" + common.Bytes2Hex(common.LeftPadBytes([]byte{0x05, 0xf7, 0xe1, 0x10}, 32)) + "\n"; string(plain) != want {
+		t.Errorf("prestate result mismatch: have %s, want %s", plain, want)
+	}
+}
+
+// Tests that calling a contract deployed in the prestate runs its code on its
+// storage, even if it's also the sender of the call.
+func TestRunPrestateReceiver(t *testing.T) {
+	defer func(id common.NetworkID) { common.DefaultNetworkID = id }(common.DefaultNetworkID)
+	common.DefaultNetworkID = 3
+
+	key, err := crypto.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := key.Address()
+
+	// Return the value of slot 0
+	code := common.Bytes2Hex([]byte{
+		byte(vm.PUSH1), 0x00, byte(vm.SLOAD),
+		byte(vm.PUSH1), 0x00, byte(vm.MSTORE), byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	})
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	alloc := fmt.Sprintf(`{"%s":{"balance":"0x0","code":"0x%s","storage":{"%s":"%s"}}}`, contract.Hex(), code, common.Hash{}.Hex(), common.HexToHash("0x2a").Hex())
+	if err := ioutil.WriteFile(genesis, []byte(`{"config":{"networkId":3},"alloc":`+alloc+`,"energyLimit":"0x100000","difficulty":"0x1"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := "0x" + common.Bytes2Hex(common.HexToHash("0x2a").Bytes()) + "\n"
+	if have := runCapturingStdout(t, "--prestate", genesis, "--receiver", contract.Hex(), "run"); string(have) != want {
+		t.Errorf("call result mismatch: have %s, want %s", have, want)
+	}
+	if have := runCapturingStdout(t, "--prestate", genesis, "--receiver", contract.Hex(), "--sender", contract.Hex(), "run"); string(have) != want {
+		t.Errorf("self call result mismatch: have %s, want %s", have, want)
+	}
+}
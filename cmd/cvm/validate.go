@@ -0,0 +1,75 @@
+// Copyright 2024 by the Authors
+// This file is part of go-core.
+//
+// go-core is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-core is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-core. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/vm"
+)
+
+var validateCommand = cli.Command{
+	Action:    validateCmd,
+	Name:      "validate",
+	Usage:     "statically validates cvm bytecode without executing it",
+	ArgsUsage: "<file>",
+	Description: `The validate command checks the given hex encoded bytecode for undefined
+opcodes, truncated push data, invalid constant jump destinations and stack
+violations on straight-line code. It exits with an error if any is found.`,
+}
+
+func validateCmd(ctx *cli.Context) error {
+	var in string
+	switch {
+	case len(ctx.Args().First()) > 0:
+		input, err := ioutil.ReadFile(ctx.Args().First())
+		if err != nil {
+			return err
+		}
+		in = string(input)
+	case ctx.GlobalIsSet(CodeFlag.Name):
+		in = ctx.GlobalString(CodeFlag.Name)
+	default:
+		return errors.New("Missing filename or --code value")
+	}
+	return validateCode(os.Stdout, in)
+}
+
+// validateCode reports all validation failures of the hex encoded code to w,
+// returning an error if there were any.
+func validateCode(w io.Writer, hexcode string) error {
+	hexcode = strings.TrimSpace(hexcode)
+	if len(hexcode)%2 != 0 {
+		return fmt.Errorf("invalid input length for hex data (%d)", len(hexcode))
+	}
+	errs := vm.ValidateCode(common.FromHex(hexcode))
+	for _, err := range errs {
+		fmt.Fprintln(w, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("code validation failed with %d errors", len(errs))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 by the Authors
+// This file is part of go-core.
+//
+// go-core is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-core is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-core. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		report []string
+	}{
+		// PUSH1 0x03 JUMP JUMPDEST STOP
+		{code: "0x6003565b00"},
+		// PUSH1 0x04 JUMP JUMPDEST STOP
+		{code: "6004565b00", report: []string{"pc 2: JUMP: invalid jump destination"}},
+		// PUSH1 0x01 PUSH2 0x01
+		{code: "60016101", report: []string{"pc 2: PUSH2: truncated push data"}},
+	}
+	for i, tt := range tests {
+		out := new(bytes.Buffer)
+		err := validateCode(out, tt.code)
+		if (err != nil) != (len(tt.report) > 0) {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, len(tt.report) > 0)
+		}
+		var report []string
+		if out.Len() > 0 {
+			report = strings.Split(strings.TrimSpace(out.String()), "\n")
+		}
+		if strings.Join(report, "\n") != strings.Join(tt.report, "\n") {
+			t.Errorf("test %d: report mismatch: have %q, want %q", i, report, tt.report)
+		}
+	}
+}
@@ -82,6 +82,8 @@ var (
 		utils.TxPoolGlobalSlotsFlag,
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
+		utils.TxPoolAccountLimitFlag,
+		utils.TxPoolDataLimitFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
@@ -115,6 +117,7 @@ var (
 		utils.MinerNotifyFlag,
 		utils.MinerEnergyTargetFlag,
 		utils.MinerEnergyLimitFlag,
+		utils.MinerEnergyUtilizationFlag,
 		utils.MinerEnergyPriceFlag,
 		utils.MinerCorebaseFlag,
 		utils.MinerExtraDataFlag,
@@ -160,6 +163,9 @@ var (
 		utils.GraphQLVirtualHostsFlag,
 		utils.HTTPApiFlag,
 		utils.HTTPPathPrefixFlag,
+		utils.HTTPEnableHTTP2Flag,
+		utils.HTTPTLSCertFlag,
+		utils.HTTPTLSKeyFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
@@ -171,6 +177,8 @@ var (
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalEnergyCapFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCRevertReasonFlag,
+		utils.RPCMethodMetricsFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -86,6 +86,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolGlobalSlotsFlag,
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
+			utils.TxPoolAccountLimitFlag,
+			utils.TxPoolDataLimitFlag,
 			utils.TxPoolLifetimeFlag,
 		},
 	},
@@ -122,6 +124,9 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.HTTPPortFlag,
 			utils.HTTPApiFlag,
 			utils.HTTPPathPrefixFlag,
+			utils.HTTPEnableHTTP2Flag,
+			utils.HTTPTLSCertFlag,
+			utils.HTTPTLSKeyFlag,
 			utils.HTTPCORSDomainFlag,
 			utils.HTTPVirtualHostsFlag,
 			utils.WSEnabledFlag,
@@ -139,6 +144,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.GraphQLVirtualHostsFlag,
 			utils.RPCGlobalEnergyCapFlag,
 			utils.RPCGlobalTxFeeCapFlag,
+			utils.RPCRevertReasonFlag,
+			utils.RPCMethodMetricsFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -171,6 +178,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerEnergyPriceFlag,
 			utils.MinerEnergyTargetFlag,
 			utils.MinerEnergyLimitFlag,
+			utils.MinerEnergyUtilizationFlag,
 			utils.MinerCorebaseFlag,
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
@@ -298,6 +298,16 @@ var (
 		Usage: "Maximum number of non-executable transaction slots for all accounts",
 		Value: xcb.DefaultConfig.TxPool.GlobalQueue,
 	}
+	TxPoolAccountLimitFlag = cli.Uint64Flag{
+		Name:  "txpool.accountlimit",
+		Usage: "Maximum number of pooled transactions permitted per remote account, replacements excepted (0 = unlimited)",
+		Value: xcb.DefaultConfig.TxPool.AccountLimit,
+	}
+	TxPoolDataLimitFlag = cli.Uint64Flag{
+		Name:  "txpool.datalimit",
+		Usage: "Maximum size in bytes of the input data of a single transaction",
+		Value: xcb.DefaultConfig.TxPool.DataLimit,
+	}
 	TxPoolLifetimeFlag = cli.DurationFlag{
 		Name:  "txpool.lifetime",
 		Usage: "Maximum amount of time non-executable transaction are queued",
@@ -371,6 +381,10 @@ var (
 		Usage: "Target energy ceiling for mined blocks",
 		Value: xcb.DefaultConfig.Miner.EnergyCeil,
 	}
+	MinerEnergyUtilizationFlag = cli.Uint64Flag{
+		Name:  "miner.energyutilization",
+		Usage: "Targeted energy usage of parent blocks in percent of their limit, adjusting the energy limit of mined blocks (default = 2/3)",
+	}
 	MinerEnergyPriceFlag = BigFlag{
 		Name:  "miner.energyprice",
 		Usage: "Minimum energy price for mining a transaction",
@@ -428,6 +442,14 @@ var (
 		Usage: "Sets a cap on transaction fee (in core) that can be sent via the RPC APIs (0 = no cap)",
 		Value: xcb.DefaultConfig.RPCTxFeeCap,
 	}
+	RPCRevertReasonFlag = cli.BoolFlag{
+		Name:  "rpc.revertreason",
+		Usage: "Derive the revert reason of failed transactions in receipts by re-executing their block",
+	}
+	RPCMethodMetricsFlag = cli.BoolFlag{
+		Name:  "rpc.methodmetrics",
+		Usage: "Collect per-method call counts, error counts and latencies on the HTTP and WS-RPC servers",
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = cli.StringFlag{
 		Name:  "authrpc.addr",
@@ -504,6 +526,20 @@ var (
 		Usage: "HTTP path path prefix on which JSON-RPC is served. Use '/' to serve on all paths.",
 		Value: "",
 	}
+	HTTPEnableHTTP2Flag = cli.BoolFlag{
+		Name:  "http.http2",
+		Usage: "Serve the HTTP-RPC server over HTTP/2 too, multiplexing concurrent calls (cleartext h2c without TLS)",
+	}
+	HTTPTLSCertFlag = cli.StringFlag{
+		Name:  "http.tlscert",
+		Usage: "TLS certificate file of the HTTP-RPC server (enables TLS along with --http.tlskey)",
+		Value: "",
+	}
+	HTTPTLSKeyFlag = cli.StringFlag{
+		Name:  "http.tlskey",
+		Usage: "TLS private key file of the HTTP-RPC server",
+		Value: "",
+	}
 	GraphQLEnabledFlag = cli.BoolFlag{
 		Name:  "graphql",
 		Usage: "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
@@ -587,7 +623,7 @@ var (
 	}
 	NATFlag = cli.StringFlag{
 		Name:  "nat",
-		Usage: "NAT port mapping mechanism (any|none|upnp|pmp|extip:<IP>)",
+		Usage: "NAT port mapping mechanism (any|none|upnp|pmp|extip:<IP>|stun:<host:port>,...)",
 		Value: "any",
 	}
 	NoDiscoverFlag = cli.BoolFlag{
@@ -886,6 +922,18 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(HTTPPathPrefixFlag.Name) {
 		cfg.HTTPPathPrefix = ctx.GlobalString(HTTPPathPrefixFlag.Name)
 	}
+
+	if ctx.GlobalIsSet(HTTPEnableHTTP2Flag.Name) {
+		cfg.HTTPEnableHTTP2 = ctx.GlobalBool(HTTPEnableHTTP2Flag.Name)
+	}
+
+	if ctx.GlobalIsSet(HTTPTLSCertFlag.Name) {
+		cfg.HTTPTLSCert = ctx.GlobalString(HTTPTLSCertFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(HTTPTLSKeyFlag.Name) {
+		cfg.HTTPTLSKey = ctx.GlobalString(HTTPTLSKeyFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1155,6 +1203,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.Led = ctx.GlobalBool(LedFlag.Name)
 		cfg.LedGPIOPort = ctx.GlobalInt(LedGPIOPortFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCMethodMetricsFlag.Name) {
+		cfg.RPCMethodMetrics = ctx.GlobalBool(RPCMethodMetricsFlag.Name)
+	}
 	if ctx.GlobalIsSet(InsecureUnlockAllowedFlag.Name) {
 		cfg.InsecureUnlockAllowed = ctx.GlobalBool(InsecureUnlockAllowedFlag.Name)
 	}
@@ -1250,6 +1301,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalQueueFlag.Name) {
 		cfg.GlobalQueue = ctx.GlobalUint64(TxPoolGlobalQueueFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolAccountLimitFlag.Name) {
+		cfg.AccountLimit = ctx.GlobalUint64(TxPoolAccountLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolDataLimitFlag.Name) {
+		cfg.DataLimit = ctx.GlobalUint64(TxPoolDataLimitFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1268,6 +1325,14 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerEnergyLimitFlag.Name) {
 		cfg.EnergyCeil = ctx.GlobalUint64(MinerEnergyLimitFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerEnergyUtilizationFlag.Name) {
+		utilization := ctx.GlobalUint64(MinerEnergyUtilizationFlag.Name)
+		policy, err := core.NewUtilizationPolicy(utilization, 100)
+		if err != nil {
+			Fatalf("Invalid energy utilization %d%%, must be between 1 and 100", utilization)
+		}
+		cfg.EnergyPolicy = policy
+	}
 	if ctx.GlobalIsSet(MinerEnergyPriceFlag.Name) {
 		cfg.EnergyPrice = GlobalBig(ctx, MinerEnergyPriceFlag.Name)
 	}
@@ -1444,6 +1509,9 @@ func SetXcbConfig(ctx *cli.Context, stack *node.Node, cfg *xcb.Config) {
 	if ctx.GlobalIsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCRevertReasonFlag.Name) {
+		cfg.RPCRevertReason = ctx.GlobalBool(RPCRevertReasonFlag.Name)
+	}
 	if ctx.GlobalIsSet(NoDiscoverFlag.Name) {
 		cfg.DiscoveryURLs = []string{}
 	} else if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
@@ -50,6 +50,9 @@ var (
 	ErrUint64Range   = &decError{"hex number > 64 bits"}
 	ErrUintRange     = &decError{fmt.Sprintf("hex number > %d bits", uintBits)}
 	ErrBig256Range   = &decError{"hex number > 256 bits"}
+
+	ErrDecimalSyntax   = &decError{"invalid decimal string"}
+	ErrDecimal256Range = &decError{"decimal number > 256 bits"}
 )
 
 type decError struct{ msg string }
@@ -92,6 +95,45 @@ func Encode(b []byte) string {
 	return string(enc)
 }
 
+// LengthError is returned when a hex string decodes to, or a byte slice to be
+// encoded has, an unexpected number of bytes.
+type LengthError struct {
+	Have int // Actual number of bytes
+	Want int // Required number of bytes
+}
+
+func (err *LengthError) Error() string {
+	return fmt.Sprintf("hex data has length %d, want %d", err.Have, err.Want)
+}
+
+// DecodeFixed decodes a hex string with 0x prefix, which must contain exactly
+// n bytes. Otherwise a *LengthError is returned.
+func DecodeFixed(input string, n int) ([]byte, error) {
+	dec, err := Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != n {
+		return nil, &LengthError{Have: len(dec), Want: n}
+	}
+	return dec, nil
+}
+
+// EncodeFixed encodes b as a hex string with 0x prefix, left-padded with zeroes
+// to n bytes. A *LengthError is returned if b is longer than n bytes.
+func EncodeFixed(b []byte, n int) (string, error) {
+	if len(b) > n {
+		return "", &LengthError{Have: len(b), Want: n}
+	}
+	enc := make([]byte, n*2+2)
+	copy(enc, "0x")
+	for i := 2; i < len(enc)-len(b)*2; i++ {
+		enc[i] = '0'
+	}
+	hex.Encode(enc[len(enc)-len(b)*2:], b)
+	return string(enc), nil
+}
+
 // DecodeUint64 decodes a hex string with 0x prefix as a quantity.
 func DecodeUint64(input string) (uint64, error) {
 	raw, err := checkNumber(input)
@@ -201,3 +201,71 @@ func TestDecodeUint64(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeFixed(t *testing.T) {
+	tests := []struct {
+		input   string
+		n       int
+		want    []byte
+		wantErr error
+	}{
+		{"0x0102", 2, []byte{1, 2}, nil},
+		{"0x", 0, []byte{}, nil},
+		{"0x01", 2, nil, &LengthError{Have: 1, Want: 2}},
+		{"0x010203", 2, nil, &LengthError{Have: 3, Want: 2}},
+		{"0x01zz", 2, nil, ErrSyntax},
+		{"0102", 2, nil, ErrMissingPrefix},
+	}
+	for _, test := range tests {
+		dec, err := DecodeFixed(test.input, test.n)
+		if test.wantErr != nil {
+			if err == nil || err.Error() != test.wantErr.Error() {
+				t.Errorf("input %s: error mismatch: got %v, want %v", test.input, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("input %s: unexpected error: %v", test.input, err)
+			continue
+		}
+		if !bytes.Equal(dec, test.want) {
+			t.Errorf("input %s: value mismatch: got %x, want %x", test.input, dec, test.want)
+		}
+	}
+	if _, err := DecodeFixed("0x01", 32); err == nil {
+		t.Fatal("short input accepted")
+	} else if lerr, ok := err.(*LengthError); !ok || lerr.Have != 1 || lerr.Want != 32 {
+		t.Errorf("error mismatch: got %#v", err)
+	}
+}
+
+func TestEncodeFixed(t *testing.T) {
+	tests := []struct {
+		input   []byte
+		n       int
+		want    string
+		wantErr bool
+	}{
+		{[]byte{1, 2}, 2, "0x0102", false},
+		{[]byte{1, 2}, 4, "0x00000102", false},
+		{nil, 2, "0x0000", false},
+		{[]byte{}, 0, "0x", false},
+		{[]byte{1, 2, 3}, 2, "", true},
+	}
+	for _, test := range tests {
+		enc, err := EncodeFixed(test.input, test.n)
+		if test.wantErr {
+			if _, ok := err.(*LengthError); !ok {
+				t.Errorf("input %x: error mismatch: got %v, want length error", test.input, err)
+			}
+			continue
+		}
+		if err != nil || enc != test.want {
+			t.Errorf("input %x: wrong encoding %s (err %v), want %s", test.input, enc, err, test.want)
+			continue
+		}
+		if dec, err := DecodeFixed(enc, test.n); err != nil || len(dec) != test.n {
+			t.Errorf("input %x: round-trip failed: %x, %v", test.input, dec, err)
+		}
+	}
+}
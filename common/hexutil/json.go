@@ -28,6 +28,7 @@ import (
 var (
 	bytesT  = reflect.TypeOf(Bytes(nil))
 	bigT    = reflect.TypeOf((*Big)(nil))
+	decBigT = reflect.TypeOf((*DecimalBig)(nil))
 	uintT   = reflect.TypeOf(Uint(0))
 	uint64T = reflect.TypeOf(Uint64(0))
 )
@@ -225,6 +226,59 @@ func (b *Big) UnmarshalGraphQL(input interface{}) error {
 	return err
 }
 
+// DecimalBig marshals/unmarshals as a JSON string in base 10, without any prefix.
+// It is the human readable counterpart of Big, meant for APIs surfacing amounts to
+// users. The zero value marshals as "0".
+//
+// Negative integers are not supported at this time, they are rejected by Unmarshal
+// but will be marshaled without error. Values larger than 256bits are rejected by
+// Unmarshal but will be marshaled without error.
+type DecimalBig big.Int
+
+// MarshalText implements encoding.TextMarshaler
+func (b DecimalBig) MarshalText() ([]byte, error) {
+	return (*big.Int)(&b).MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *DecimalBig) UnmarshalJSON(input []byte) error {
+	if !isString(input) {
+		return errNonString(decBigT)
+	}
+	return wrapTypeError(b.UnmarshalText(input[1:len(input)-1]), decBigT)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *DecimalBig) UnmarshalText(input []byte) error {
+	if len(input) == 0 {
+		return ErrDecimalSyntax
+	}
+	for _, c := range input {
+		if c < '0' || c > '9' {
+			return ErrDecimalSyntax
+		}
+	}
+	var dec big.Int
+	if _, ok := dec.SetString(string(input), 10); !ok {
+		return ErrDecimalSyntax
+	}
+	if dec.BitLen() > 256 {
+		return ErrDecimal256Range
+	}
+	*b = (DecimalBig)(dec)
+	return nil
+}
+
+// ToInt converts b to a big.Int.
+func (b *DecimalBig) ToInt() *big.Int {
+	return (*big.Int)(b)
+}
+
+// String returns the decimal encoding of b.
+func (b *DecimalBig) String() string {
+	return b.ToInt().String()
+}
+
 // Uint64 marshals/unmarshals as a JSON string with 0x prefix.
 // The zero value marshals as "0x0".
 type Uint64 uint64
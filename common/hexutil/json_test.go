@@ -205,6 +205,88 @@ func TestMarshalBig(t *testing.T) {
 	}
 }
 
+var unmarshalDecimalBigTests = []unmarshalTest{
+	// invalid encoding
+	{input: "", wantErr: errJSONEOF},
+	{input: "null", wantErr: errNonString(decBigT)},
+	{input: "10", wantErr: errNonString(decBigT)},
+	{input: `""`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{input: `"0x10"`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{input: `"-1"`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{input: `"+1"`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{input: `"1 000"`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{input: `"1_000"`, wantErr: wrapTypeError(ErrDecimalSyntax, decBigT)},
+	{
+		input:   `"115792089237316195423570985008687907853269984665640564039457584007913129639936"`,
+		wantErr: wrapTypeError(ErrDecimal256Range, decBigT),
+	},
+
+	// valid encoding
+	{input: `"0"`, want: big.NewInt(0)},
+	{input: `"00"`, want: big.NewInt(0)},
+	{input: `"10"`, want: big.NewInt(10)},
+	{input: `"1234567890"`, want: big.NewInt(1234567890)},
+	{
+		input: `"115792089237316195423570985008687907853269984665640564039457584007913129639935"`,
+		want:  referenceBig("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+	},
+}
+
+func TestUnmarshalDecimalBig(t *testing.T) {
+	for _, test := range unmarshalDecimalBigTests {
+		var v DecimalBig
+		err := json.Unmarshal([]byte(test.input), &v)
+		if !checkError(t, test.input, err, test.wantErr) {
+			continue
+		}
+		if test.want != nil && test.want.(*big.Int).Cmp((*big.Int)(&v)) != 0 {
+			t.Errorf("input %s: value mismatch: got %d, want %d", test.input, (*big.Int)(&v), test.want)
+			continue
+		}
+	}
+}
+
+func TestDecimalBigRoundTrip(t *testing.T) {
+	tests := []struct {
+		input *big.Int
+		want  string
+	}{
+		{big.NewInt(0), "0"},
+		{big.NewInt(18), "18"},
+		{referenceBig("de0b6b3a7640000"), "1000000000000000000"},
+		{referenceBig("112233445566778899aabbccddeeff"), "88962710306127702866241727433142015"},
+		{
+			referenceBig("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		},
+	}
+	for _, test := range tests {
+		type result struct {
+			Value *DecimalBig `json:"value"`
+		}
+		out, err := json.Marshal(result{(*DecimalBig)(test.input)})
+		if err != nil {
+			t.Errorf("%d: %v", test.input, err)
+			continue
+		}
+		if want := `{"value":"` + test.want + `"}`; string(out) != want {
+			t.Errorf("%d: MarshalJSON output mismatch: got %s, want %s", test.input, out, want)
+			continue
+		}
+		if out := (*DecimalBig)(test.input).String(); out != test.want {
+			t.Errorf("%d: String mismatch: got %q, want %q", test.input, out, test.want)
+		}
+		var dec result
+		if err := json.Unmarshal(out, &dec); err != nil {
+			t.Errorf("%d: UnmarshalJSON failed: %v", test.input, err)
+			continue
+		}
+		if dec.Value.ToInt().Cmp(test.input) != 0 {
+			t.Errorf("%d: round trip mismatch: got %d", test.input, dec.Value.ToInt())
+		}
+	}
+}
+
 var unmarshalUint64Tests = []unmarshalTest{
 	// invalid encoding
 	{input: "", wantErr: errJSONEOF},
@@ -0,0 +1,197 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// HashSet is a set of hashes. The zero value is an empty set which has to be
+// initialised with NewHashSet or make before adding elements.
+type HashSet map[Hash]struct{}
+
+// NewHashSet creates a set containing the given hashes.
+func NewHashSet(hashes ...Hash) HashSet {
+	set := make(HashSet, len(hashes))
+	for _, hash := range hashes {
+		set[hash] = struct{}{}
+	}
+	return set
+}
+
+// Add inserts a hash into the set.
+func (s HashSet) Add(hash Hash) {
+	s[hash] = struct{}{}
+}
+
+// Contains reports whether the hash is in the set.
+func (s HashSet) Contains(hash Hash) bool {
+	_, ok := s[hash]
+	return ok
+}
+
+// Remove deletes a hash from the set, if present.
+func (s HashSet) Remove(hash Hash) {
+	delete(s, hash)
+}
+
+// Len returns the number of hashes in the set.
+func (s HashSet) Len() int {
+	return len(s)
+}
+
+// Union returns a new set with the hashes contained in either of the sets.
+func (s HashSet) Union(other HashSet) HashSet {
+	set := make(HashSet, len(s)+len(other))
+	for hash := range s {
+		set[hash] = struct{}{}
+	}
+	for hash := range other {
+		set[hash] = struct{}{}
+	}
+	return set
+}
+
+// Intersect returns a new set with the hashes contained in both of the sets.
+func (s HashSet) Intersect(other HashSet) HashSet {
+	if len(other) < len(s) {
+		s, other = other, s
+	}
+	set := make(HashSet)
+	for hash := range s {
+		if _, ok := other[hash]; ok {
+			set[hash] = struct{}{}
+		}
+	}
+	return set
+}
+
+// List returns the hashes of the set in ascending order.
+func (s HashSet) List() []Hash {
+	list := make([]Hash, 0, len(s))
+	for hash := range s {
+		list = append(list, hash)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i][:], list[j][:]) < 0
+	})
+	return list
+}
+
+// MarshalJSON encodes the set as a sorted JSON array of hashes.
+func (s HashSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.List())
+}
+
+// UnmarshalJSON decodes the set from a JSON array of hashes.
+func (s *HashSet) UnmarshalJSON(input []byte) error {
+	var list []Hash
+	if err := json.Unmarshal(input, &list); err != nil {
+		return err
+	}
+	*s = NewHashSet(list...)
+	return nil
+}
+
+// AddressSet is a set of addresses. The zero value is an empty set which has to
+// be initialised with NewAddressSet or make before adding elements.
+type AddressSet map[Address]struct{}
+
+// NewAddressSet creates a set containing the given addresses.
+func NewAddressSet(addrs ...Address) AddressSet {
+	set := make(AddressSet, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// Add inserts an address into the set.
+func (s AddressSet) Add(addr Address) {
+	s[addr] = struct{}{}
+}
+
+// Contains reports whether the address is in the set.
+func (s AddressSet) Contains(addr Address) bool {
+	_, ok := s[addr]
+	return ok
+}
+
+// Remove deletes an address from the set, if present.
+func (s AddressSet) Remove(addr Address) {
+	delete(s, addr)
+}
+
+// Len returns the number of addresses in the set.
+func (s AddressSet) Len() int {
+	return len(s)
+}
+
+// Union returns a new set with the addresses contained in either of the sets.
+func (s AddressSet) Union(other AddressSet) AddressSet {
+	set := make(AddressSet, len(s)+len(other))
+	for addr := range s {
+		set[addr] = struct{}{}
+	}
+	for addr := range other {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// Intersect returns a new set with the addresses contained in both of the sets.
+func (s AddressSet) Intersect(other AddressSet) AddressSet {
+	if len(other) < len(s) {
+		s, other = other, s
+	}
+	set := make(AddressSet)
+	for addr := range s {
+		if _, ok := other[addr]; ok {
+			set[addr] = struct{}{}
+		}
+	}
+	return set
+}
+
+// List returns the addresses of the set in ascending order.
+func (s AddressSet) List() []Address {
+	list := make([]Address, 0, len(s))
+	for addr := range s {
+		list = append(list, addr)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i][:], list[j][:]) < 0
+	})
+	return list
+}
+
+// MarshalJSON encodes the set as a sorted JSON array of addresses.
+func (s AddressSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.List())
+}
+
+// UnmarshalJSON decodes the set from a JSON array of addresses.
+func (s *AddressSet) UnmarshalJSON(input []byte) error {
+	var list []Address
+	if err := json.Unmarshal(input, &list); err != nil {
+		return err
+	}
+	*s = NewAddressSet(list...)
+	return nil
+}
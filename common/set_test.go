@@ -0,0 +1,112 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHashSet(t *testing.T) {
+	var (
+		h1 = HexToHash("01")
+		h2 = HexToHash("02")
+		h3 = HexToHash("03")
+	)
+	set := NewHashSet(h2, h1)
+	set.Add(h1)
+	if set.Len() != 2 {
+		t.Fatalf("length mismatch: have %d, want 2", set.Len())
+	}
+	if !set.Contains(h1) || !set.Contains(h2) || set.Contains(h3) {
+		t.Fatalf("membership mismatch: %v", set.List())
+	}
+	other := NewHashSet(h2, h3)
+	if have, want := set.Union(other).List(), []Hash{h1, h2, h3}; !reflect.DeepEqual(have, want) {
+		t.Errorf("union mismatch: have %v, want %v", have, want)
+	}
+	if have, want := set.Intersect(other).List(), []Hash{h2}; !reflect.DeepEqual(have, want) {
+		t.Errorf("intersection mismatch: have %v, want %v", have, want)
+	}
+	if set.Len() != 2 || other.Len() != 2 {
+		t.Errorf("set operations modified the operands")
+	}
+	set.Remove(h2)
+	set.Remove(h3)
+	if have, want := set.List(), []Hash{h1}; !reflect.DeepEqual(have, want) {
+		t.Errorf("removal mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestAddressSet(t *testing.T) {
+	set := NewAddressSet(Addr3, Addr1)
+	set.Add(Addr1)
+	if set.Len() != 2 {
+		t.Fatalf("length mismatch: have %d, want 2", set.Len())
+	}
+	if !set.Contains(Addr1) || !set.Contains(Addr3) || set.Contains(Addr2) {
+		t.Fatalf("membership mismatch: %v", set.List())
+	}
+	other := NewAddressSet(Addr2, Addr3)
+	if have, want := set.Union(other).List(), []Address{Addr1, Addr2, Addr3}; !reflect.DeepEqual(have, want) {
+		t.Errorf("union mismatch: have %v, want %v", have, want)
+	}
+	if have, want := set.Intersect(other).List(), []Address{Addr3}; !reflect.DeepEqual(have, want) {
+		t.Errorf("intersection mismatch: have %v, want %v", have, want)
+	}
+	if have := set.Intersect(NewAddressSet()); have.Len() != 0 {
+		t.Errorf("intersection with empty set not empty: %v", have.List())
+	}
+	set.Remove(Addr1)
+	if have, want := set.List(), []Address{Addr3}; !reflect.DeepEqual(have, want) {
+		t.Errorf("removal mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	hashes := NewHashSet(HexToHash("02"), HexToHash("01"))
+	blob, err := json.Marshal(hashes)
+	if err != nil {
+		t.Fatalf("failed to marshal hash set: %v", err)
+	}
+	want := `["0x0000000000000000000000000000000000000000000000000000000000000001","0x0000000000000000000000000000000000000000000000000000000000000002"]`
+	if string(blob) != want {
+		t.Errorf("hash set encoding mismatch: have %s, want %s", blob, want)
+	}
+	var decHashes HashSet
+	if err := json.Unmarshal(blob, &decHashes); err != nil {
+		t.Fatalf("failed to unmarshal hash set: %v", err)
+	}
+	if !reflect.DeepEqual(decHashes, hashes) {
+		t.Errorf("hash set round-trip mismatch: have %v, want %v", decHashes.List(), hashes.List())
+	}
+	addrs := NewAddressSet(Addr1, DevAddress)
+	if blob, err = json.Marshal(addrs); err != nil {
+		t.Fatalf("failed to marshal address set: %v", err)
+	}
+	var decAddrs AddressSet
+	if err := json.Unmarshal(blob, &decAddrs); err != nil {
+		t.Fatalf("failed to unmarshal address set: %v", err)
+	}
+	if !reflect.DeepEqual(decAddrs, addrs) {
+		t.Errorf("address set round-trip mismatch: have %v, want %v", decAddrs.List(), addrs.List())
+	}
+	if err := json.Unmarshal([]byte(`{}`), &decAddrs); err == nil {
+		t.Errorf("non-array input accepted")
+	}
+}
@@ -47,7 +47,27 @@ var (
 )
 
 var invalidPrefix = errors.New("Invalid network id prefix in address")
-var invalidChecksum = errors.New("Invalid checksum in address")
+
+var (
+	// ErrInvalidAddressLength is returned when parsing an address which doesn't
+	// consist of exactly AddressLength bytes.
+	ErrInvalidAddressLength = errors.New("invalid address length")
+
+	// ErrInvalidAddressChecksum is returned when the checksum of an address doesn't
+	// match its contents, e.g. because of a typo.
+	ErrInvalidAddressChecksum = errors.New("Invalid checksum in address")
+)
+
+// AddressNetworkError is returned when parsing an address which belongs to a
+// different network than expected.
+type AddressNetworkError struct {
+	Prefix  string    // Network prefix of the address
+	Network NetworkID // Network the address was expected to belong to
+}
+
+func (e *AddressNetworkError) Error() string {
+	return fmt.Sprintf("address network prefix %s doesn't match network %d (prefix %s)", e.Prefix, e.Network, e.Network)
+}
 
 // Hash represents the 32 byte SHA3 hash of arbitrary data.
 type Hash [HashLength]byte
@@ -282,7 +302,7 @@ func VerifyAddress(addr Address) error {
 	if !bytes.Equal(addr[:1], DefaultNetworkID.Bytes()) {
 		return invalidPrefix
 	} else if Bytes2Hex(addr[1:2]) != CalculateChecksum(addr[2:], addr[:1]) {
-		return invalidChecksum
+		return ErrInvalidAddressChecksum
 	}
 	return nil
 }
@@ -295,6 +315,17 @@ func BytesToAddress(b []byte) Address {
 	return a
 }
 
+// BytesToAddressStrict returns Address with value b. As opposed to BytesToAddress,
+// b must be exactly AddressLength bytes long, so that neither the network prefix
+// nor the checksum can be silently cropped or zero padded, as could happen with
+// 20 byte addresses of other chains.
+func BytesToAddressStrict(b []byte) (Address, error) {
+	if len(b) != AddressLength {
+		return Address{}, ErrInvalidAddressLength
+	}
+	return BytesToAddress(b), nil
+}
+
 // BigToAddress returns Address with byte values of b.
 // If b is larger than len(h), b will be cropped from the left.
 func BigToAddress(b *big.Int) Address { return BytesToAddress(b.Bytes()) }
@@ -314,6 +345,34 @@ func HexToAddress(s string) (Address, error) {
 	return addr, nil
 }
 
+// ParseAddress strictly parses a hex-encoded address of the given network. As
+// opposed to HexToAddress, the input must be exactly AddressLength bytes long,
+// and an address of another network results in an *AddressNetworkError. The
+// network independent precompiled and dev addresses are accepted as is.
+func ParseAddress(s string, network NetworkID) (Address, error) {
+	if has0xPrefix(s) {
+		s = s[2:]
+	}
+	if len(s) != 2*AddressLength {
+		return Address{}, ErrInvalidAddressLength
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Address{}, err
+	}
+	addr := BytesToAddress(b)
+	if isPrecompiledAddress(addr) || isDevAddress(addr) {
+		return addr, nil
+	}
+	if !bytes.Equal(addr[:1], network.Bytes()) {
+		return Address{}, &AddressNetworkError{Prefix: Bytes2Hex(addr[:1]), Network: network}
+	}
+	if Bytes2Hex(addr[1:2]) != CalculateChecksum(addr[2:], addr[:1]) {
+		return Address{}, ErrInvalidAddressChecksum
+	}
+	return addr, nil
+}
+
 // IsHexAddress verifies whether a string can represent a valid hex-encoded
 // Core address or not.
 func IsHexAddress(s string) bool {
@@ -20,11 +20,14 @@ import (
 	"bytes"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/core-coin/go-core/v2/rlp"
 )
 
 func TestBytesConversion(t *testing.T) {
@@ -479,3 +482,116 @@ func TestHash_Format(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAddress(t *testing.T) {
+	// Assemble addresses of the same account on various networks
+	body := Hex2Bytes("885aaeb6053f3e94c9b9a09f33669435e7ef1bea")
+	makeAddr := func(network NetworkID) string {
+		prefix := network.Bytes()
+		return network.String() + CalculateChecksum(body, prefix) + Bytes2Hex(body)
+	}
+	var (
+		mainnet = makeAddr(Mainnet)
+		devin   = makeAddr(Devin)
+		private = makeAddr(NetworkID(1337))
+	)
+	corrupted := []byte(mainnet)
+	if corrupted[3] == '0' {
+		corrupted[3] = '1'
+	} else {
+		corrupted[3] = '0'
+	}
+	tests := []struct {
+		input   string
+		network NetworkID
+		err     error
+		netErr  bool
+	}{
+		{mainnet, Mainnet, nil, false},
+		{"0x" + mainnet, Mainnet, nil, false},
+		{devin, Devin, nil, false},
+		{private, NetworkID(1337), nil, false},
+		{Addr1.Hex(), Devin, nil, false},
+		{mainnet, Devin, nil, true},
+		{devin, Mainnet, nil, true},
+		{private, Mainnet, nil, true},
+		{string(corrupted), Mainnet, ErrInvalidAddressChecksum, false},
+		{mainnet[2:], Mainnet, ErrInvalidAddressLength, false},
+		{mainnet + "00", Mainnet, ErrInvalidAddressLength, false},
+	}
+	for i, tt := range tests {
+		addr, err := ParseAddress(tt.input, tt.network)
+		if tt.netErr {
+			var netErr *AddressNetworkError
+			if !errors.As(err, &netErr) {
+				t.Errorf("test %d: error mismatch: have %v, want network error", i, err)
+			} else if netErr.Network != tt.network {
+				t.Errorf("test %d: network mismatch: have %v, want %v", i, netErr.Network, tt.network)
+			}
+			continue
+		}
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err == nil && !strings.HasSuffix(tt.input, addr.Hex()) {
+			t.Errorf("test %d: address mismatch: have %s, want %s", i, addr.Hex(), tt.input)
+		}
+	}
+	if _, err := ParseAddress(strings.Repeat("zz", AddressLength), Mainnet); err == nil {
+		t.Errorf("invalid hex accepted")
+	}
+}
+
+func TestBytesToAddressStrict(t *testing.T) {
+	full := Hex2Bytes("cb" + "00" + "885aaeb6053f3e94c9b9a09f33669435e7ef1bea")
+	if addr, err := BytesToAddressStrict(full); err != nil || !bytes.Equal(addr[:], full) {
+		t.Errorf("full address mismatch: have %x (%v), want %x", addr, err, full)
+	}
+	for _, b := range [][]byte{nil, full[2:], append(full, 0x00)} {
+		if _, err := BytesToAddressStrict(b); err != ErrInvalidAddressLength {
+			t.Errorf("%d byte address error mismatch: have %v, want %v", len(b), err, ErrInvalidAddressLength)
+		}
+	}
+}
+
+// Tests that addresses survive an RLP round trip in their full 22 byte form,
+// including the network prefix, and that shorter encodings are rejected.
+func TestAddressRLP(t *testing.T) {
+	body := Hex2Bytes("885aaeb6053f3e94c9b9a09f33669435e7ef1bea")
+	prefix := NetworkID(Devin).Bytes()
+	addr := BytesToAddress(append(append(prefix, Hex2Bytes(CalculateChecksum(body, prefix))...), body...))
+
+	type account struct {
+		Owner     Address
+		Recipient *Address `rlp:"nil"`
+	}
+	enc, err := rlp.EncodeToBytes(&account{Owner: addr, Recipient: &addr})
+	if err != nil {
+		t.Fatalf("failed to encode address: %v", err)
+	}
+	// Both addresses should be encoded as 22 byte strings
+	field := append([]byte{0x80 + AddressLength}, addr[:]...)
+	if !bytes.Equal(enc[1:], append(field, field...)) {
+		t.Fatalf("encoding mismatch: have %x, want two %x", enc, field)
+	}
+	var dec account
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode address: %v", err)
+	}
+	if dec.Owner != addr || dec.Recipient == nil || *dec.Recipient != addr {
+		t.Fatalf("decoded address mismatch: have %x/%x, want %x", dec.Owner, dec.Recipient, addr)
+	}
+	if !bytes.Equal(dec.Owner[:1], prefix) || Bytes2Hex(dec.Owner[1:2]) != CalculateChecksum(dec.Owner[2:], prefix) {
+		t.Errorf("network prefix or checksum lost: have %x", dec.Owner)
+	}
+	// Addresses of other lengths, e.g. 20 byte ones, must not be accepted
+	short, _ := rlp.EncodeToBytes(addr[2:])
+	if err := rlp.DecodeBytes(short, new(Address)); err == nil {
+		t.Errorf("20 byte address decoded")
+	}
+	long, _ := rlp.EncodeToBytes(append(addr[:], 0x00))
+	if err := rlp.DecodeBytes(long, new(Address)); err == nil {
+		t.Errorf("23 byte address decoded")
+	}
+}
@@ -17,7 +17,9 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/consensus"
@@ -114,25 +116,85 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 	return nil
 }
 
-// CalcEnergyLimit computes the energy limit of the next block after parent. It aims
-// to keep the baseline energy above the provided floor, and increase it towards the
-// ceil if the blocks are full. If the ceil is exceeded, it will always decrease
-// the energy allowance.
-func CalcEnergyLimit(parent *types.Block, energyFloor, energyCeil uint64) uint64 {
-	// contrib = (parentEnergyUsed * 3 / 2) / 1024
-	contrib := (parent.EnergyUsed() + parent.EnergyUsed()/2) / params.EnergyLimitBoundDivisor
+// EnergyLimitPolicy computes the energy limit of the next block after parent.
+// Implementations must aim to keep the limit between the provided floor and
+// ceil, and may not move it further from the parent limit than the header
+// verification allows.
+type EnergyLimitPolicy interface {
+	EnergyLimit(parent *types.Block, energyFloor, energyCeil uint64) uint64
+}
+
+// DefaultEnergyLimitPolicy is the energy limit policy used by CalcEnergyLimit,
+// targeting a parent energy usage of two thirds of its limit.
+var DefaultEnergyLimitPolicy EnergyLimitPolicy = &UtilizationPolicy{Numerator: 2, Denominator: 3}
+
+// errInvalidUtilization is returned if a utilization policy is created with a
+// target outside of (0, 1].
+var errInvalidUtilization = errors.New("invalid energy utilization target")
+
+// UtilizationPolicy is an energy limit policy targeting a utilization of the
+// parent energy limit. If the parent used more energy than the target, the limit
+// is increased, otherwise lowered. The amount depends on how far away from the
+// target the parent energy usage is.
+type UtilizationPolicy struct {
+	Numerator   uint64 // Numerator of the targeted fraction of the parent energy limit, in (0, Denominator]
+	Denominator uint64 // Denominator of the targeted fraction of the parent energy limit
+}
+
+// NewUtilizationPolicy creates an energy limit policy targeting the given
+// fraction of the parent energy limit, which must be in (0, 1].
+func NewUtilizationPolicy(numerator, denominator uint64) (*UtilizationPolicy, error) {
+	if !validUtilization(numerator, denominator) {
+		return nil, fmt.Errorf("%w: %d/%d", errInvalidUtilization, numerator, denominator)
+	}
+	return &UtilizationPolicy{Numerator: numerator, Denominator: denominator}, nil
+}
+
+// validUtilization returns whether numerator/denominator is in (0, 1].
+func validUtilization(numerator, denominator uint64) bool {
+	return numerator != 0 && numerator <= denominator
+}
+
+// EnergyLimit implements EnergyLimitPolicy. It aims to keep the baseline energy
+// above the provided floor, and increase it towards the ceil if the blocks are
+// used above the target. If the ceil is exceeded, it will always decrease the
+// energy allowance. A policy with a target outside of (0, 1], which
+// NewUtilizationPolicy rejects, uses the default one.
+func (p *UtilizationPolicy) EnergyLimit(parent *types.Block, energyFloor, energyCeil uint64) uint64 {
+	num, den := p.Numerator, p.Denominator
+	if !validUtilization(num, den) {
+		num, den = 2, 3
+	}
+	var (
+		bnum = new(big.Int).SetUint64(num)
+		bden = new(big.Int).SetUint64(den)
+	)
+	// contrib = (parentEnergyUsed / target) / 1024
+	contrib := new(big.Int).SetUint64(parent.EnergyUsed())
+	contrib.Mul(contrib, bden)
+	contrib.Div(contrib, new(big.Int).Mul(bnum, new(big.Int).SetUint64(params.EnergyLimitBoundDivisor)))
+
+	// Whether the target is below the default two thirds: 3*num < 2*den
+	lowTarget := new(big.Int).Mul(bnum, big.NewInt(3)).Cmp(new(big.Int).Mul(bden, big.NewInt(2))) < 0
 
 	// decay = parentEnergyLimit / 1024 -1
 	decay := parent.EnergyLimit()/params.EnergyLimitBoundDivisor - 1
 
 	/*
 		strategy: energyLimit of block-to-mine is set based on parent's
-		energyUsed value.  if parentEnergyUsed > parentEnergyLimit * (2/3) then we
-		increase it, otherwise lower it (or leave it unchanged if it's right
+		energyUsed value.  if parentEnergyUsed > parentEnergyLimit * target then
+		we increase it, otherwise lower it (or leave it unchanged if it's right
 		at that usage) the amount increased/decreased depends on how far away
-		from parentEnergyLimit * (2/3) parentEnergyUsed is.
+		from parentEnergyLimit * target parentEnergyUsed is. Targets below the
+		default two thirds may overshoot the allowed energy limit change of a
+		block, so their increase is capped by it.
 	*/
-	limit := parent.EnergyLimit() - decay + contrib
+	limit := parent.EnergyLimit() - decay
+	if lowTarget && contrib.Cmp(new(big.Int).SetUint64(2*decay)) > 0 {
+		limit += 2 * decay
+	} else {
+		limit += contrib.Uint64()
+	}
 	if limit < params.MinEnergyLimit {
 		limit = params.MinEnergyLimit
 	}
@@ -150,3 +212,11 @@ func CalcEnergyLimit(parent *types.Block, energyFloor, energyCeil uint64) uint64
 	}
 	return limit
 }
+
+// CalcEnergyLimit computes the energy limit of the next block after parent using
+// the default energy limit policy. It aims to keep the baseline energy above the
+// provided floor, and increase it towards the ceil if the blocks are full. If the
+// ceil is exceeded, it will always decrease the energy allowance.
+func CalcEnergyLimit(parent *types.Block, energyFloor, energyCeil uint64) uint64 {
+	return DefaultEnergyLimitPolicy.EnergyLimit(parent, energyFloor, energyCeil)
+}
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"runtime"
 	"testing"
 	"time"
@@ -198,3 +199,91 @@ func testHeaderConcurrentAbortion(t *testing.T, threads int) {
 		t.Errorf("verification count too large: have %d, want below %d", verified, 2*threads)
 	}
 }
+
+// Tests that the energy limit policies move the limit towards the targeted
+// utilization, staying within the bounds accepted by the header verification.
+func TestEnergyLimitPolicy(t *testing.T) {
+	const (
+		limit = 10000000
+		floor = 1000000
+		ceil  = 100000000
+		bound = limit / params.EnergyLimitBoundDivisor
+	)
+	parent := func(used uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{EnergyLimit: limit, EnergyUsed: used})
+	}
+	tests := []struct {
+		policy EnergyLimitPolicy
+		used   uint64
+		want   uint64
+	}{
+		// Full and empty blocks move the limit up and down
+		{DefaultEnergyLimitPolicy, limit, limit + (limit*3/2)/params.EnergyLimitBoundDivisor - bound + 1},
+		{DefaultEnergyLimitPolicy, 0, limit - bound + 1},
+		{&UtilizationPolicy{Numerator: 1, Denominator: 2}, limit, limit + bound - 1},
+		{&UtilizationPolicy{Numerator: 1, Denominator: 2}, 0, limit - bound + 1},
+
+		// Usage at the target keeps the limit (modulo rounding)
+		{DefaultEnergyLimitPolicy, limit * 2 / 3, limit + 1},
+		{&UtilizationPolicy{Numerator: 9, Denominator: 10}, limit * 9 / 10, limit + 1},
+
+		// Low targets are capped by the allowed change
+		{&UtilizationPolicy{Numerator: 1, Denominator: 10}, limit, limit + bound - 1},
+		{&UtilizationPolicy{Numerator: 1, Denominator: 10}, limit / 10, limit + 1},
+
+		// Large terms don't overflow
+		{&UtilizationPolicy{Numerator: 1 << 62, Denominator: 1 << 63}, limit, limit + bound - 1},
+		{&UtilizationPolicy{Numerator: 9 << 59, Denominator: 10 << 59}, limit * 9 / 10, limit + 1},
+	}
+	for i, tt := range tests {
+		if have := tt.policy.EnergyLimit(parent(tt.used), floor, ceil); have != tt.want {
+			t.Errorf("test %d: energy limit mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+	// The default policy is the one used by CalcEnergyLimit
+	for _, used := range []uint64{0, limit / 3, limit} {
+		if have, want := CalcEnergyLimit(parent(used), floor, ceil), DefaultEnergyLimitPolicy.EnergyLimit(parent(used), floor, ceil); have != want {
+			t.Errorf("used %d: energy limit mismatch: have %d, want %d", used, have, want)
+		}
+	}
+	// The default policy, and policies without a valid target, keep the
+	// original adjustment rule, including for small limits
+	for _, parentLimit := range []uint64{params.MinEnergyLimit, 5500, 6144, limit} {
+		for _, used := range []uint64{0, parentLimit / 3, parentLimit} {
+			block := types.NewBlockWithHeader(&types.Header{EnergyLimit: parentLimit, EnergyUsed: used})
+			want := parentLimit - (parentLimit/params.EnergyLimitBoundDivisor - 1) + (used+used/2)/params.EnergyLimitBoundDivisor
+			if want < params.MinEnergyLimit {
+				want = params.MinEnergyLimit
+			}
+			for _, policy := range []EnergyLimitPolicy{DefaultEnergyLimitPolicy, new(UtilizationPolicy), &UtilizationPolicy{Numerator: 3, Denominator: 2}} {
+				if have := policy.EnergyLimit(block, 0, ceil); have != want {
+					t.Errorf("limit %d, used %d: energy limit mismatch: have %d, want %d", parentLimit, used, have, want)
+				}
+			}
+		}
+	}
+}
+
+// Tests that utilization policies can only be created with a valid target.
+func TestNewUtilizationPolicy(t *testing.T) {
+	tests := []struct {
+		numerator, denominator uint64
+		valid                  bool
+	}{
+		{2, 3, true},
+		{1, 100, true},
+		{100, 100, true},
+		{0, 100, false},
+		{0, 0, false},
+		{101, 100, false},
+	}
+	for i, tt := range tests {
+		policy, err := NewUtilizationPolicy(tt.numerator, tt.denominator)
+		if tt.valid && (err != nil || policy == nil) {
+			t.Errorf("test %d: failed to create policy: %v", i, err)
+		}
+		if !tt.valid && !errors.Is(err, errInvalidUtilization) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, errInvalidUtilization)
+		}
+	}
+}
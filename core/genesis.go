@@ -231,6 +231,9 @@ func SetupGenesisBlock(db xcbdb.Database, genesis *Genesis) (*params.ChainConfig
 	if height == nil {
 		return newcfg, stored, fmt.Errorf("missing block number for head header hash")
 	}
+	if err := storedcfg.CheckEnergyCompatible(newcfg, *height); err != nil {
+		return newcfg, stored, err
+	}
 	compatErr := storedcfg.CheckCompatible(newcfg, *height)
 	if compatErr != nil && *height != 0 && compatErr.RewindTo != 0 {
 		return newcfg, stored, compatErr
@@ -52,8 +52,11 @@ func TestSetupGenesis(t *testing.T) {
 			},
 		}
 		oldcustomg = customg
+		energyg    = customg
+		txEnergy   = uint64(30000)
 	)
 	oldcustomg.Config = &params.ChainConfig{NetworkID: big.NewInt(1), EWASMBlock: big.NewInt(2)}
+	energyg.Config = &params.ChainConfig{NetworkID: big.NewInt(1), EWASMBlock: big.NewInt(3), EnergyTable: &params.EnergyTable{TxEnergy: &txEnergy}}
 	tests := []struct {
 		name       string
 		fn         func(xcbdb.Database) (*params.ChainConfig, common.Hash, error)
@@ -147,6 +150,28 @@ func TestSetupGenesis(t *testing.T) {
 				RewindTo:     1,
 			},
 		},
+		{
+			name: "energy table changed in DB",
+			fn: func(db xcbdb.Database) (*params.ChainConfig, common.Hash, error) {
+				// Commit the genesis block and advance to block #4, after
+				// which its energy costs can no longer change.
+				genesis := customg.MustCommit(db)
+				bc, err := NewBlockChain(db, nil, customg.Config, cryptore.NewFullFaker(), vm.Config{}, nil, nil)
+				if err != nil {
+					panic(err)
+				}
+				defer bc.Stop()
+				blocks, _ := GenerateChain(customg.Config, genesis, cryptore.NewFaker(), db, 4, nil)
+				if _, err := bc.InsertChain(blocks); err != nil {
+					panic(err)
+				}
+				// This should be rejected outright.
+				return SetupGenesisBlock(db, &energyg)
+			},
+			wantHash:   customghash,
+			wantConfig: energyg.Config,
+			wantErr:    params.ErrEnergyTableIncompatible,
+		},
 	}
 
 	for _, test := range tests {
@@ -95,3 +95,25 @@ func DeleteTrieNode(db xcbdb.KeyValueWriter, hash common.Hash) {
 		log.Crit("Failed to delete trie node", "err", err)
 	}
 }
+
+// ReadTrieCommitJournal retrieves the serialized trie nodes of a commit that
+// was interrupted before finishing.
+func ReadTrieCommitJournal(db xcbdb.KeyValueReader) []byte {
+	data, _ := db.Get(trieCommitJournalKey)
+	return data
+}
+
+// WriteTrieCommitJournal stores the serialized trie nodes of a commit about to
+// be written, to finish it on restart if it gets interrupted.
+func WriteTrieCommitJournal(db xcbdb.KeyValueWriter, journal []byte) {
+	if err := db.Put(trieCommitJournalKey, journal); err != nil {
+		log.Crit("Failed to store trie commit journal", "err", err)
+	}
+}
+
+// DeleteTrieCommitJournal deletes the serialized trie nodes of a finished commit.
+func DeleteTrieCommitJournal(db xcbdb.KeyValueWriter) {
+	if err := db.Delete(trieCommitJournalKey); err != nil {
+		log.Crit("Failed to remove trie commit journal", "err", err)
+	}
+}
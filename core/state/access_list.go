@@ -0,0 +1,102 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/types"
+)
+
+// accessList is the set of accounts and storage slots accessed by the
+// transaction being executed, seeded with the ones declared by its access list.
+type accessList map[common.Address]map[common.Hash]struct{}
+
+// copy creates an independent copy of the access list.
+func (al accessList) copy() accessList {
+	if al == nil {
+		return nil
+	}
+	cpy := make(accessList, len(al))
+	for addr, slots := range al {
+		cpySlots := make(map[common.Hash]struct{}, len(slots))
+		for slot := range slots {
+			cpySlots[slot] = struct{}{}
+		}
+		cpy[addr] = cpySlots
+	}
+	return cpy
+}
+
+// PrepareAccessList clears the access list of the previous transaction and
+// seeds it for the transaction about to be executed with the sender, the
+// destination (nil for contract creations) and the declared access list.
+func (s *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, list types.AccessList) {
+	s.accessList = make(accessList)
+
+	s.AddAddressToAccessList(sender)
+	if dst != nil {
+		s.AddAddressToAccessList(*dst)
+	}
+	for _, tuple := range list {
+		s.AddAddressToAccessList(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			s.AddSlotToAccessList(tuple.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list. The change
+// is journalled, so it is undone if the accessing call reverts.
+func (s *StateDB) AddAddressToAccessList(addr common.Address) {
+	if s.accessList == nil {
+		s.accessList = make(accessList)
+	}
+	if _, ok := s.accessList[addr]; ok {
+		return
+	}
+	s.accessList[addr] = make(map[common.Hash]struct{})
+	s.journal.append(accessListAddAccountChange{&addr})
+}
+
+// AddSlotToAccessList adds the given storage slot of the address, along with the
+// address itself, to the access list. The changes are journalled.
+func (s *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(addr)
+	slots := s.accessList[addr]
+	if _, ok := slots[slot]; ok {
+		return
+	}
+	slots[slot] = struct{}{}
+	s.journal.append(accessListAddSlotChange{address: &addr, slot: &slot})
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (s *StateDB) AddressInAccessList(addr common.Address) bool {
+	_, ok := s.accessList[addr]
+	return ok
+}
+
+// SlotInAccessList returns whether the given address and the storage slot of
+// it are in the access list.
+func (s *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	slots, addressOk := s.accessList[addr]
+	if !addressOk {
+		return false, false
+	}
+	_, slotOk = slots[slot]
+	return true, slotOk
+}
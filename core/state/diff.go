@@ -0,0 +1,203 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/rlp"
+	"github.com/core-coin/go-core/v2/trie"
+)
+
+// AccountDiff is the difference of a single account between two states.
+//
+// Old is nil if the account was added, New is nil if it was deleted. Otherwise
+// the account was changed, and Storage lists the slots which differ.
+type AccountDiff struct {
+	Hash    common.Hash     // Hash of the address, the key in the account trie
+	Address *common.Address // Address of the account, nil if the preimage is unknown
+	Old     *Account        // Account in the first state, nil if missing
+	New     *Account        // Account in the second state, nil if missing
+	Storage []StorageDiff   // Storage slots which differ, sorted by hash
+}
+
+// StorageDiff is the difference of a single storage slot between two states.
+// Missing slots have the zero value.
+type StorageDiff struct {
+	Hash common.Hash  // Hash of the slot key, the key in the storage trie
+	Key  *common.Hash // Slot key, nil if the preimage is unknown
+	Old  common.Hash  // Value in the first state
+	New  common.Hash  // Value in the second state
+}
+
+// Diff computes the accounts and storage slots which differ between the two
+// committed state roots, sorted by the hash of the addresses. Subtries shared
+// by both states are skipped, so the cost is proportional to the size of the
+// change, not to the size of the state.
+func Diff(db Database, rootA, rootB common.Hash) ([]AccountDiff, error) {
+	trA, err := db.OpenTrie(rootA)
+	if err != nil {
+		return nil, err
+	}
+	trB, err := db.OpenTrie(rootB)
+	if err != nil {
+		return nil, err
+	}
+	oldAccs, newAccs, err := diffLeaves(trA, trB)
+	if err != nil {
+		return nil, err
+	}
+	hashes := mergeLeafKeys(oldAccs, newAccs)
+
+	diffs := make([]AccountDiff, 0, len(hashes))
+	for _, hash := range hashes {
+		diff := AccountDiff{Hash: hash}
+		if preimage := trB.GetKey(hash[:]); preimage != nil {
+			addr := common.BytesToAddress(preimage)
+			diff.Address = &addr
+		} else if preimage := trA.GetKey(hash[:]); preimage != nil {
+			addr := common.BytesToAddress(preimage)
+			diff.Address = &addr
+		}
+		if blob, ok := oldAccs[hash]; ok {
+			if diff.Old, err = decodeDiffAccount(blob); err != nil {
+				return nil, err
+			}
+		}
+		if blob, ok := newAccs[hash]; ok {
+			if diff.New, err = decodeDiffAccount(blob); err != nil {
+				return nil, err
+			}
+		}
+		if diff.Storage, err = diffStorage(db, hash, diff.Old, diff.New); err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// diffStorage computes the storage slots of an account which differ between
+// the two versions of it. Either of them may be nil if the account is missing.
+func diffStorage(db Database, hash common.Hash, old, new *Account) ([]StorageDiff, error) {
+	rootA, rootB := emptyRoot, emptyRoot
+	if old != nil {
+		rootA = old.Root
+	}
+	if new != nil {
+		rootB = new.Root
+	}
+	if rootA == rootB {
+		return nil, nil
+	}
+	trA, err := db.OpenStorageTrie(hash, rootA)
+	if err != nil {
+		return nil, err
+	}
+	trB, err := db.OpenStorageTrie(hash, rootB)
+	if err != nil {
+		return nil, err
+	}
+	oldSlots, newSlots, err := diffLeaves(trA, trB)
+	if err != nil {
+		return nil, err
+	}
+	hashes := mergeLeafKeys(oldSlots, newSlots)
+
+	diffs := make([]StorageDiff, 0, len(hashes))
+	for _, slot := range hashes {
+		diff := StorageDiff{Hash: slot}
+		if preimage := trB.GetKey(slot[:]); preimage != nil {
+			key := common.BytesToHash(preimage)
+			diff.Key = &key
+		} else if preimage := trA.GetKey(slot[:]); preimage != nil {
+			key := common.BytesToHash(preimage)
+			diff.Key = &key
+		}
+		if diff.Old, err = decodeDiffSlot(oldSlots[slot]); err != nil {
+			return nil, err
+		}
+		if diff.New, err = decodeDiffSlot(newSlots[slot]); err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// diffLeaves returns the leaves of trie a which are missing or different in
+// trie b, and the leaves of trie b which are missing or different in trie a.
+func diffLeaves(a, b Trie) (map[common.Hash][]byte, map[common.Hash][]byte, error) {
+	collect := func(from, to Trie) (map[common.Hash][]byte, error) {
+		diff, _ := trie.NewDifferenceIterator(from.NodeIterator(nil), to.NodeIterator(nil))
+		it := trie.NewIterator(diff)
+
+		leaves := make(map[common.Hash][]byte)
+		for it.Next() {
+			leaves[common.BytesToHash(it.Key)] = common.CopyBytes(it.Value)
+		}
+		return leaves, it.Err
+	}
+	onlyA, err := collect(b, a)
+	if err != nil {
+		return nil, nil, err
+	}
+	onlyB, err := collect(a, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return onlyA, onlyB, nil
+}
+
+// mergeLeafKeys returns the sorted union of the keys of the two leaf sets.
+func mergeLeafKeys(a, b map[common.Hash][]byte) []common.Hash {
+	keys := make([]common.Hash, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	return keys
+}
+
+func decodeDiffAccount(blob []byte) (*Account, error) {
+	account := new(Account)
+	if err := rlp.DecodeBytes(blob, account); err != nil {
+		return nil, fmt.Errorf("invalid account: %v", err)
+	}
+	return account, nil
+}
+
+func decodeDiffSlot(blob []byte) (common.Hash, error) {
+	if blob == nil {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid storage slot: %v", err)
+	}
+	return common.BytesToHash(content), nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/crypto"
+)
+
+func TestDiff(t *testing.T) {
+	var (
+		db      = NewDatabase(rawdb.NewMemoryDatabase())
+		changed = toAddr([]byte{0x01})
+		deleted = toAddr([]byte{0x02})
+		same    = toAddr([]byte{0x03})
+		added   = toAddr([]byte{0x04})
+		k1      = common.HexToHash("0x01")
+		k2      = common.HexToHash("0x02")
+		k3      = common.HexToHash("0x03")
+	)
+	// Create and commit the first state
+	state, _ := New(common.Hash{}, db, nil)
+	state.SetBalance(changed, big.NewInt(1))
+	state.SetState(changed, k1, common.HexToHash("0x11"))
+	state.SetState(changed, k2, common.HexToHash("0x22"))
+	state.SetNonce(deleted, 1)
+	state.SetBalance(same, big.NewInt(3))
+	state.SetState(same, k1, common.HexToHash("0x33"))
+	rootA, err := state.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit first state: %v", err)
+	}
+	// Mutate a few accounts and slots on top of it
+	state, _ = New(rootA, db, nil)
+	state.SetBalance(changed, big.NewInt(2))
+	state.SetState(changed, k1, common.HexToHash("0x12"))
+	state.SetState(changed, k2, common.Hash{})
+	state.SetState(changed, k3, common.HexToHash("0x44"))
+	state.Suicide(deleted)
+	state.SetNonce(added, 5)
+	rootB, err := state.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit second state: %v", err)
+	}
+
+	diffs, err := Diff(db, rootA, rootB)
+	if err != nil {
+		t.Fatalf("failed to diff states: %v", err)
+	}
+	want := map[common.Address]func(AccountDiff) bool{
+		changed: func(d AccountDiff) bool {
+			return d.Old != nil && d.New != nil && d.Old.Balance.Int64() == 1 && d.New.Balance.Int64() == 2
+		},
+		deleted: func(d AccountDiff) bool {
+			return d.Old != nil && d.Old.Nonce == 1 && d.New == nil && len(d.Storage) == 0
+		},
+		added: func(d AccountDiff) bool {
+			return d.Old == nil && d.New != nil && d.New.Nonce == 5 && len(d.Storage) == 0
+		},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("account diff count mismatch: have %d, want %d", len(diffs), len(want))
+	}
+	for i, diff := range diffs {
+		if i > 0 && diffs[i-1].Hash.Big().Cmp(diff.Hash.Big()) >= 0 {
+			t.Errorf("diff %d: accounts not sorted by hash", i)
+		}
+		if diff.Address == nil {
+			t.Fatalf("diff %d: missing address preimage", i)
+		}
+		if diff.Hash != crypto.SHA3Hash(diff.Address.Bytes()) {
+			t.Errorf("diff %d: hash mismatch for %v", i, diff.Address)
+		}
+		check, ok := want[*diff.Address]
+		if !ok {
+			t.Fatalf("diff %d: unexpected account %v", i, diff.Address)
+		}
+		if !check(diff) {
+			t.Errorf("diff %d: content mismatch for %v: old %+v, new %+v", i, diff.Address, diff.Old, diff.New)
+		}
+		if *diff.Address != changed {
+			continue
+		}
+		// Check the storage changes of the modified account
+		slots := make(map[common.Hash][2]common.Hash)
+		for _, slot := range diff.Storage {
+			if slot.Key == nil {
+				t.Fatalf("missing slot preimage for %x", slot.Hash)
+			}
+			slots[*slot.Key] = [2]common.Hash{slot.Old, slot.New}
+		}
+		wantSlots := map[common.Hash][2]common.Hash{
+			k1: {common.HexToHash("0x11"), common.HexToHash("0x12")},
+			k2: {common.HexToHash("0x22"), {}},
+			k3: {{}, common.HexToHash("0x44")},
+		}
+		if !reflect.DeepEqual(slots, wantSlots) {
+			t.Errorf("storage diff mismatch: have %v, want %v", slots, wantSlots)
+		}
+	}
+	// Identical roots have no difference
+	if diffs, err := Diff(db, rootB, rootB); err != nil || len(diffs) != 0 {
+		t.Errorf("unexpected diff of identical roots: %v, %v", diffs, err)
+	}
+}
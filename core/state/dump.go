@@ -17,6 +17,7 @@
 package state
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -112,12 +113,31 @@ func (d iterativeDump) OnRoot(root common.Hash) {
 }
 
 func (s *StateDB) DumpToCollector(c DumpCollector, excludeCode, excludeStorage, excludeMissingPreimages bool, start []byte, maxResults int) (nextKey []byte) {
+	return s.dumpToCollector(c, excludeCode, excludeStorage, excludeMissingPreimages, nil, start, maxResults)
+}
+
+// dumpToCollector iterates the accounts of the state into the collector. If a
+// prefix is given, only the accounts whose address starts with it are loaded
+// and collected, accounts with missing preimages are skipped.
+func (s *StateDB) dumpToCollector(c DumpCollector, excludeCode, excludeStorage, excludeMissingPreimages bool, prefix []byte, start []byte, maxResults int) (nextKey []byte) {
 	missingPreimages := 0
 	c.OnRoot(s.trie.Hash())
 
 	var count int
 	it := trie.NewIterator(s.trie.NodeIterator(start))
 	for it.Next() {
+		addrBytes := s.trie.GetKey(it.Key)
+		if addrBytes == nil {
+			// Preimage missing
+			missingPreimages++
+			if excludeMissingPreimages || prefix != nil {
+				continue
+			}
+		}
+		// Filter on the address before loading anything else of the account
+		if prefix != nil && !bytes.HasPrefix(addrBytes, prefix) {
+			continue
+		}
 		var data Account
 		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
 			panic(err)
@@ -128,13 +148,7 @@ func (s *StateDB) DumpToCollector(c DumpCollector, excludeCode, excludeStorage,
 			Root:     common.Bytes2Hex(data.Root[:]),
 			CodeHash: common.Bytes2Hex(data.CodeHash),
 		}
-		addrBytes := s.trie.GetKey(it.Key)
 		if addrBytes == nil {
-			// Preimage missing
-			missingPreimages++
-			if excludeMissingPreimages {
-				continue
-			}
 			account.SecureKey = it.Key
 		}
 		addr := common.BytesToAddress(addrBytes)
@@ -189,6 +203,29 @@ func (s *StateDB) Dump(excludeCode, excludeStorage, excludeMissingPreimages bool
 	return json
 }
 
+// RawDumpPrefix returns the accounts whose address starts with the given prefix
+// as a single large object. Since the state trie is keyed by the hash of the
+// addresses, the iterator can't seek to the prefix and all account keys still
+// need to be walked, but the code and storage are only loaded for the matches.
+func (s *StateDB) RawDumpPrefix(prefix []byte, excludeCode, excludeStorage bool) Dump {
+	dump := &Dump{
+		Accounts: make(map[common.Address]DumpAccount),
+	}
+	s.dumpToCollector(dump, excludeCode, excludeStorage, true, prefix, nil, 0)
+	return *dump
+}
+
+// DumpPrefix returns a JSON string representing the accounts whose address starts
+// with the given prefix as a single json-object
+func (s *StateDB) DumpPrefix(prefix []byte, excludeCode, excludeStorage bool) []byte {
+	dump := s.RawDumpPrefix(prefix, excludeCode, excludeStorage)
+	json, err := json.MarshalIndent(dump, "", "    ")
+	if err != nil {
+		fmt.Println("Dump err", err)
+	}
+	return json
+}
+
 // IterativeDump dumps out accounts as json-objects, delimited by linebreaks on stdout
 func (s *StateDB) IterativeDump(excludeCode, excludeStorage, excludeMissingPreimages bool, output *json.Encoder) {
 	s.DumpToCollector(iterativeDump{output}, excludeCode, excludeStorage, excludeMissingPreimages, nil, 0)
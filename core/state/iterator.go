@@ -152,3 +152,61 @@ func (it *NodeIterator) retrieve() bool {
 	}
 	return true
 }
+
+// StorageIterator is an iterator over the storage slots of a single contract,
+// in the order of their hashed keys.
+type StorageIterator struct {
+	trie Trie           // Storage trie being iterated, used for preimage lookups
+	it   *trie.Iterator // Leaf iterator of the storage trie
+
+	Hash  common.Hash // Hash of the current slot key
+	Key   []byte      // Preimage of the current slot key, nil if unknown
+	Value common.Hash // Value of the current slot
+
+	Error error // Failure set in case of an internal error in the iterator
+}
+
+// StorageIterator creates an iterator over the storage of the given account, as
+// of the account's storage root. Only changes that were flushed into the tries
+// via IntermediateRoot or Commit are visible, pending modifications of the live
+// state are not. For a non-existent account the returned iterator is empty.
+//
+// Slot keys can only be recovered if the database was recording preimages.
+func (s *StateDB) StorageIterator(addr common.Address) (*StorageIterator, error) {
+	so := s.getStateObject(addr)
+	if so == nil {
+		return &StorageIterator{}, nil
+	}
+	tr, err := s.db.OpenStorageTrie(so.addrHash, so.data.Root)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageIterator{
+		trie: tr,
+		it:   trie.NewIterator(tr.NodeIterator(nil)),
+	}, nil
+}
+
+// Next moves the iterator to the next storage slot, returning whether there are
+// any further slots. In case of an internal error this method returns false and
+// sets the Error field to the encountered failure.
+func (it *StorageIterator) Next() bool {
+	it.Hash, it.Key, it.Value = common.Hash{}, nil, common.Hash{}
+
+	if it.it == nil || it.Error != nil {
+		return false
+	}
+	if !it.it.Next() {
+		it.Error = it.it.Err
+		return false
+	}
+	_, content, _, err := rlp.Split(it.it.Value)
+	if err != nil {
+		it.Error = err
+		return false
+	}
+	it.Hash = common.BytesToHash(it.it.Key)
+	it.Key = it.trie.GetKey(it.it.Key)
+	it.Value = common.BytesToHash(content)
+	return true
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/core-coin/go-core/v2/xcbdb"
 
 	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/crypto"
 )
 
 // Tests that the node iterator indeed walks over the entire database contents.
@@ -68,3 +70,60 @@ func TestNodeIteratorCoverage(t *testing.T) {
 	}
 	it.Release()
 }
+
+// Tests that the storage iterator returns all committed slots of a contract,
+// along with their key preimages, while ignoring uncommitted changes.
+func TestStorageIterator(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	addr := common.BytesToAddress([]byte{0x01})
+
+	slots := make(map[common.Hash]common.Hash)
+	for i := byte(1); i <= 16; i++ {
+		key, value := common.BytesToHash([]byte{i}), common.BytesToHash([]byte{i, i})
+		state.SetState(addr, key, value)
+		slots[key] = value
+	}
+	root, _ := state.Commit(false)
+	state, _ = New(root, state.db, nil)
+
+	// Pending changes should not be reflected by the iterator
+	state.SetState(addr, common.BytesToHash([]byte{0xff}), common.HexToHash("ff"))
+	state.SetState(addr, common.BytesToHash([]byte{0x01}), common.Hash{})
+
+	it, err := state.StorageIterator(addr)
+	if err != nil {
+		t.Fatalf("failed to create storage iterator: %v", err)
+	}
+	var prev common.Hash
+	found := make(map[common.Hash]common.Hash)
+	for it.Next() {
+		if bytes.Compare(it.Hash[:], prev[:]) <= 0 {
+			t.Errorf("slot hash %x not above previous %x", it.Hash, prev)
+		}
+		prev = it.Hash
+
+		key := common.BytesToHash(it.Key)
+		if hash := crypto.SHA3Hash(it.Key); hash != it.Hash {
+			t.Errorf("slot %x: preimage hash mismatch: have %x", it.Hash, hash)
+		}
+		found[key] = it.Value
+	}
+	if it.Error != nil {
+		t.Fatalf("iteration failed: %v", it.Error)
+	}
+	if len(found) != len(slots) {
+		t.Errorf("slot count mismatch: have %d, want %d", len(found), len(slots))
+	}
+	for key, want := range slots {
+		if have := found[key]; have != want {
+			t.Errorf("slot %x: value mismatch: have %x, want %x", key, have, want)
+		}
+	}
+	// Iterating a missing account should yield nothing
+	if it, err = state.StorageIterator(common.BytesToAddress([]byte{0x02})); err != nil {
+		t.Fatalf("failed to create storage iterator: %v", err)
+	}
+	if it.Next() {
+		t.Errorf("missing account storage not empty")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/state/snapshot"
+	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/rlp"
+)
+
+// prefetchWorkers is the number of accounts prefetched concurrently. Loading
+// them mostly waits for the disk, so it's not bound to the number of CPUs.
+const prefetchWorkers = 16
+
+// Prefetch loads the given accounts and their code concurrently in the
+// background, warming the caches of the state database (the clean trie node
+// cache, the snapshot and the code cache), so that reading them afterwards
+// doesn't wait for the disk. Accounts already loaded by the StateDB are skipped.
+//
+// The prefetching doesn't modify the StateDB, which can be used meanwhile. The
+// returned channel is closed once all the accounts are loaded.
+func (s *StateDB) Prefetch(addrs []common.Address) <-chan struct{} {
+	done := make(chan struct{})
+
+	jobs := make(chan common.Address, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := s.stateObjects[addr]; !ok {
+			jobs <- addr
+		}
+	}
+	close(jobs)
+
+	workers := prefetchWorkers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		// Tries are not safe for concurrent use, each worker reads its own copy
+		tr := s.db.CopyTrie(s.trie)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for addr := range jobs {
+				prefetchAccount(s.db, s.snap, tr, addr)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// prefetchAccount loads an account and its code, from the snapshot if there's
+// one, or from the account trie otherwise.
+func prefetchAccount(db Database, snap snapshot.Snapshot, tr Trie, addr common.Address) {
+	var (
+		hash     = crypto.SHA3Hash(addr.Bytes())
+		codeHash []byte
+	)
+	if snap != nil {
+		acc, err := snap.Account(hash)
+		switch {
+		case err != nil:
+			// The snapshot is not usable, fall back to the trie like the StateDB
+			snap = nil
+		case acc == nil:
+			return
+		default:
+			codeHash = acc.CodeHash
+		}
+	}
+	if snap == nil {
+		enc, err := tr.TryGet(addr.Bytes())
+		if err != nil || len(enc) == 0 {
+			return
+		}
+		var data Account
+		if err := rlp.DecodeBytes(enc, &data); err != nil {
+			return
+		}
+		codeHash = data.CodeHash
+	}
+	if len(codeHash) != 0 && !bytes.Equal(codeHash, emptyCodeHash) {
+		db.ContractCode(hash, common.BytesToHash(codeHash))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/trie"
+	"github.com/core-coin/go-core/v2/xcbdb"
+)
+
+// makePrefetchState commits a state with the given number of accounts, every
+// tenth of them holding code, and returns its root and addresses.
+func makePrefetchState(tb testing.TB, diskdb xcbdb.Database, accounts int) (common.Hash, []common.Address) {
+	state, _ := New(common.Hash{}, NewDatabase(diskdb), nil)
+	addrs := make([]common.Address, accounts)
+	for i := range addrs {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(i+1))
+		addrs[i] = common.BytesToAddress(buf[:])
+		state.SetBalance(addrs[i], big.NewInt(int64(i+1)))
+		if i%10 == 0 {
+			state.SetCode(addrs[i], append([]byte{0x60}, buf[:]...))
+		}
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		tb.Fatalf("failed to commit state: %v", err)
+	}
+	if err := state.Database().TrieDB().Commit(root, false, nil); err != nil {
+		tb.Fatalf("failed to commit trie: %v", err)
+	}
+	return root, addrs
+}
+
+// Tests that prefetched accounts are served from the caches afterwards, and
+// that the StateDB can be used during the prefetching.
+func TestPrefetch(t *testing.T) {
+	diskdb := rawdb.NewMemoryDatabase()
+	root, addrs := makePrefetchState(t, diskdb, 500)
+
+	db := NewDatabaseWithConfig(diskdb, &trie.Config{Cache: 16})
+	state, _ := New(root, db, nil)
+
+	done := state.Prefetch(addrs)
+	state.SetBalance(common.BytesToAddress([]byte("other")), big.NewInt(1))
+	<-done
+
+	_, misses := db.TrieDB().CleanCacheStats()
+	if misses == 0 {
+		t.Fatal("prefetching loaded no trie nodes")
+	}
+	for i, addr := range addrs {
+		if balance := state.GetBalance(addr); balance.Int64() != int64(i+1) {
+			t.Fatalf("account %d: balance mismatch: have %v, want %d", i, balance, i+1)
+		}
+		if i%10 == 0 && len(state.GetCode(addr)) != 9 {
+			t.Fatalf("account %d: code missing", i)
+		}
+	}
+	if _, after := db.TrieDB().CleanCacheStats(); after != misses {
+		t.Errorf("reads after prefetching hit the disk: %d node loads", after-misses)
+	}
+	// Accounts already loaded are not prefetched again
+	<-state.Prefetch(addrs)
+	if _, after := db.TrieDB().CleanCacheStats(); after != misses {
+		t.Errorf("loaded accounts were prefetched")
+	}
+}
+
+// slowDB is a database which waits before every read, like a disk would.
+type slowDB struct {
+	xcbdb.Database
+	latency time.Duration
+}
+
+func (db *slowDB) Get(key []byte) ([]byte, error) {
+	time.Sleep(db.latency)
+	return db.Database.Get(key)
+}
+
+func BenchmarkPrefetch(b *testing.B) {
+	diskdb := rawdb.NewMemoryDatabase()
+	root, addrs := makePrefetchState(b, diskdb, 2000)
+	slow := &slowDB{Database: diskdb, latency: 50 * time.Microsecond}
+
+	read := func(b *testing.B, prefetch bool) {
+		for i := 0; i < b.N; i++ {
+			state, _ := New(root, NewDatabaseWithConfig(slow, &trie.Config{Cache: 16}), nil)
+			if prefetch {
+				<-state.Prefetch(addrs)
+			}
+			for _, addr := range addrs {
+				state.GetBalance(addr)
+				state.GetCode(addr)
+			}
+		}
+	}
+	b.Run("plain", func(b *testing.B) { read(b, false) })
+	b.Run("prefetch", func(b *testing.B) { read(b, true) })
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/state/snapshot"
+	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/rlp"
+)
+
+// ReadOnlyStateDB is an immutable view of a StateDB, taken at the time it was
+// created. Contrary to StateDB, it is safe for concurrent use, making it suitable
+// to serve many read-only calls (e.g. RPC view calls) from a single state.
+//
+// The view doesn't cache anything: every lookup works on its own copy of the
+// underlying tries, which are backed by the shared, thread safe state database.
+// It has no mutating methods, as any state modification has to go through a
+// regular StateDB.
+type ReadOnlyStateDB struct {
+	db   Database
+	trie Trie // Account trie, never accessed directly, only copied
+	snap snapshot.Snapshot
+
+	// Accounts modified in the source state, frozen at creation time
+	accounts map[common.Address]*readOnlyAccount
+
+	errLock sync.Mutex
+	dbErr   error
+}
+
+// readOnlyAccount is the frozen state of an account which was modified, but not
+// yet committed in the source state.
+type readOnlyAccount struct {
+	addrHash common.Hash
+	data     Account
+	deleted  bool
+
+	code    Code
+	trie    Trie    // Storage trie with the uncommitted changes, nil if untouched
+	storage Storage // Storage slots modified in the source state
+	fake    bool    // Whether storage is the fake storage of the debugging mode
+}
+
+// CopyReadOnly creates a read-only view of the current state, including any
+// modifications which were not yet committed. It's cheaper than Copy, as only
+// the modified accounts need to be duplicated and no journal is maintained.
+// The view is safe for concurrent use and unaffected by later changes to s.
+func (s *StateDB) CopyReadOnly() *ReadOnlyStateDB {
+	view := &ReadOnlyStateDB{
+		db:       s.db,
+		trie:     s.db.CopyTrie(s.trie),
+		snap:     s.snap,
+		accounts: make(map[common.Address]*readOnlyAccount, len(s.journal.dirties)+len(s.stateObjectsPending)),
+	}
+	freeze := func(addr common.Address) {
+		obj, exist := s.stateObjects[addr]
+		if !exist {
+			return
+		}
+		if _, done := view.accounts[addr]; done {
+			return
+		}
+		acc := &readOnlyAccount{
+			addrHash: obj.addrHash,
+			data:     obj.data,
+			deleted:  obj.deleted,
+			code:     obj.code,
+		}
+		acc.data.Balance = new(big.Int).Set(obj.data.Balance)
+		if obj.trie != nil {
+			acc.trie = s.db.CopyTrie(obj.trie)
+		}
+		if obj.fakeStorage != nil {
+			acc.storage, acc.fake = obj.fakeStorage.Copy(), true
+		} else {
+			acc.storage = make(Storage, len(obj.pendingStorage)+len(obj.dirtyStorage))
+			for key, value := range obj.pendingStorage {
+				acc.storage[key] = value
+			}
+			for key, value := range obj.dirtyStorage {
+				acc.storage[key] = value
+			}
+		}
+		view.accounts[addr] = acc
+	}
+	for addr := range s.journal.dirties {
+		freeze(addr)
+	}
+	for addr := range s.stateObjectsPending {
+		freeze(addr)
+	}
+	for addr := range s.stateObjectsDirty {
+		freeze(addr)
+	}
+	return view
+}
+
+// setError remembers the first database error encountered.
+func (v *ReadOnlyStateDB) setError(err error) {
+	v.errLock.Lock()
+	defer v.errLock.Unlock()
+
+	if v.dbErr == nil {
+		v.dbErr = err
+	}
+}
+
+// Error returns the first database error encountered by any of the lookups.
+func (v *ReadOnlyStateDB) Error() error {
+	v.errLock.Lock()
+	defer v.errLock.Unlock()
+
+	return v.dbErr
+}
+
+// account retrieves the frozen or committed account data of the given address,
+// returning nil if the account doesn't exist.
+func (v *ReadOnlyStateDB) account(addr common.Address) *readOnlyAccount {
+	if acc, ok := v.accounts[addr]; ok {
+		if acc.deleted {
+			return nil
+		}
+		return acc
+	}
+	addrHash := crypto.SHA3Hash(addr.Bytes())
+
+	// Attempt to use snapshots, if no live objects are available
+	var (
+		data *Account
+		err  error
+	)
+	if v.snap != nil {
+		var acc *snapshot.Account
+		if acc, err = v.snap.Account(addrHash); err == nil {
+			if acc == nil {
+				return nil
+			}
+			data = &Account{
+				Nonce:    acc.Nonce,
+				Balance:  acc.Balance,
+				CodeHash: acc.CodeHash,
+				Root:     common.BytesToHash(acc.Root),
+			}
+			if len(data.CodeHash) == 0 {
+				data.CodeHash = emptyCodeHash
+			}
+			if data.Root == (common.Hash{}) {
+				data.Root = emptyRoot
+			}
+		}
+	}
+	// If snapshot unavailable or reading from it failed, load from the database
+	if v.snap == nil || err != nil {
+		enc, err := v.db.CopyTrie(v.trie).TryGet(addr.Bytes())
+		if err != nil {
+			v.setError(fmt.Errorf("account (%x) error: %v", addr.Bytes(), err))
+			return nil
+		}
+		if len(enc) == 0 {
+			return nil
+		}
+		data = new(Account)
+		if err := rlp.DecodeBytes(enc, data); err != nil {
+			v.setError(fmt.Errorf("account (%x) decode error: %v", addr.Bytes(), err))
+			return nil
+		}
+	}
+	return &readOnlyAccount{addrHash: addrHash, data: *data}
+}
+
+// Exist reports whether the given account address exists in the state.
+func (v *ReadOnlyStateDB) Exist(addr common.Address) bool {
+	return v.account(addr) != nil
+}
+
+// Empty returns whether the account is either non-existent or empty according
+// to the CIP161 specification (balance = nonce = code = 0).
+func (v *ReadOnlyStateDB) Empty(addr common.Address) bool {
+	acc := v.account(addr)
+	return acc == nil || (acc.data.Nonce == 0 && acc.data.Balance.Sign() == 0 && bytes.Equal(acc.data.CodeHash, emptyCodeHash))
+}
+
+// GetBalance retrieves the balance of the given address or 0 if not found.
+func (v *ReadOnlyStateDB) GetBalance(addr common.Address) *big.Int {
+	if acc := v.account(addr); acc != nil {
+		return new(big.Int).Set(acc.data.Balance)
+	}
+	return common.Big0
+}
+
+// GetNonce retrieves the nonce of the given address or 0 if not found.
+func (v *ReadOnlyStateDB) GetNonce(addr common.Address) uint64 {
+	if acc := v.account(addr); acc != nil {
+		return acc.data.Nonce
+	}
+	return 0
+}
+
+// GetCodeHash retrieves the code hash of the given address, or the zero hash
+// if the account doesn't exist.
+func (v *ReadOnlyStateDB) GetCodeHash(addr common.Address) common.Hash {
+	if acc := v.account(addr); acc != nil {
+		return common.BytesToHash(acc.data.CodeHash)
+	}
+	return common.Hash{}
+}
+
+// GetCode retrieves the contract code of the given address.
+func (v *ReadOnlyStateDB) GetCode(addr common.Address) []byte {
+	acc := v.account(addr)
+	if acc == nil {
+		return nil
+	}
+	if acc.code != nil {
+		return acc.code
+	}
+	if bytes.Equal(acc.data.CodeHash, emptyCodeHash) {
+		return nil
+	}
+	code, err := v.db.ContractCode(acc.addrHash, common.BytesToHash(acc.data.CodeHash))
+	if err != nil {
+		v.setError(fmt.Errorf("can't load code hash %x: %v", acc.data.CodeHash, err))
+	}
+	return code
+}
+
+// GetCodeSize retrieves the size of the contract code of the given address.
+func (v *ReadOnlyStateDB) GetCodeSize(addr common.Address) int {
+	acc := v.account(addr)
+	if acc == nil {
+		return 0
+	}
+	if acc.code != nil {
+		return len(acc.code)
+	}
+	if bytes.Equal(acc.data.CodeHash, emptyCodeHash) {
+		return 0
+	}
+	size, err := v.db.ContractCodeSize(acc.addrHash, common.BytesToHash(acc.data.CodeHash))
+	if err != nil {
+		v.setError(fmt.Errorf("can't load code size %x: %v", acc.data.CodeHash, err))
+	}
+	return size
+}
+
+// GetState retrieves a value from the storage of the given address.
+func (v *ReadOnlyStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	acc := v.account(addr)
+	if acc == nil {
+		return common.Hash{}
+	}
+	if value, ok := acc.storage[key]; ok || acc.fake {
+		return value
+	}
+	// Untouched accounts can be served from the snapshot, if available
+	var (
+		enc []byte
+		err error
+	)
+	_, frozen := v.accounts[addr]
+	if !frozen && v.snap != nil {
+		enc, err = v.snap.Storage(acc.addrHash, crypto.SHA3Hash(key.Bytes()))
+	}
+	if frozen || v.snap == nil || err != nil {
+		var tr Trie
+		if acc.trie != nil {
+			tr = v.db.CopyTrie(acc.trie)
+		} else if tr, err = v.db.OpenStorageTrie(acc.addrHash, acc.data.Root); err != nil {
+			v.setError(err)
+			return common.Hash{}
+		}
+		if enc, err = tr.TryGet(key.Bytes()); err != nil {
+			v.setError(err)
+			return common.Hash{}
+		}
+	}
+	var value common.Hash
+	if len(enc) > 0 {
+		_, content, _, err := rlp.Split(enc)
+		if err != nil {
+			v.setError(err)
+		}
+		value.SetBytes(content)
+	}
+	return value
+}
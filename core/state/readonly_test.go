@@ -0,0 +1,202 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+)
+
+// Tests that a read-only view reflects the state at the time it was created,
+// both committed and in-flight, and isn't affected by subsequent changes.
+func TestCopyReadOnly(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	var (
+		committed = common.BytesToAddress([]byte{0x01})
+		dirty     = common.BytesToAddress([]byte{0x02})
+		suicided  = common.BytesToAddress([]byte{0x03})
+		created   = common.BytesToAddress([]byte{0x04})
+		missing   = common.BytesToAddress([]byte{0x05})
+
+		key1 = common.HexToHash("01")
+		key2 = common.HexToHash("02")
+	)
+	// Commit a few accounts into the database
+	for i, addr := range []common.Address{committed, dirty, suicided} {
+		state.SetBalance(addr, big.NewInt(int64(100*(i+1))))
+		state.SetNonce(addr, uint64(i+1))
+		state.SetCode(addr, []byte{byte(i), 0x60})
+		state.SetState(addr, key1, common.HexToHash("aa"))
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	state, _ = New(root, state.db, nil)
+
+	// Modify the state without committing: both finalised and journalled changes
+	state.AddBalance(dirty, big.NewInt(1))
+	state.SetState(dirty, key1, common.HexToHash("bb"))
+	state.Suicide(suicided)
+	state.Finalise(true)
+
+	state.SetState(dirty, key2, common.HexToHash("cc"))
+	state.SetBalance(created, big.NewInt(42))
+	state.SetCode(created, []byte{0xde, 0xad})
+
+	view := state.CopyReadOnly()
+
+	// Mutate the source state, none of which should be visible in the view
+	state.SetBalance(committed, big.NewInt(1))
+	state.SetState(committed, key1, common.HexToHash("ff"))
+	state.SetState(dirty, key2, common.HexToHash("ff"))
+	state.SetCode(dirty, []byte{0xff})
+	state.Suicide(created)
+	state.SetBalance(missing, big.NewInt(1))
+	if _, err := state.Commit(true); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	check := func() {
+		if have := view.GetBalance(committed); have.Cmp(big.NewInt(100)) != 0 {
+			t.Errorf("committed balance mismatch: have %v, want 100", have)
+		}
+		if have := view.GetState(committed, key1); have != common.HexToHash("aa") {
+			t.Errorf("committed storage mismatch: have %x, want aa", have)
+		}
+		if have := view.GetCode(committed); !bytes.Equal(have, []byte{0x00, 0x60}) {
+			t.Errorf("committed code mismatch: have %x, want 0060", have)
+		}
+		if have := view.GetBalance(dirty); have.Cmp(big.NewInt(201)) != 0 {
+			t.Errorf("dirty balance mismatch: have %v, want 201", have)
+		}
+		if have := view.GetNonce(dirty); have != 2 {
+			t.Errorf("dirty nonce mismatch: have %d, want 2", have)
+		}
+		if have := view.GetState(dirty, key1); have != common.HexToHash("bb") {
+			t.Errorf("dirty storage mismatch: have %x, want bb", have)
+		}
+		if have := view.GetState(dirty, key2); have != common.HexToHash("cc") {
+			t.Errorf("dirty journalled storage mismatch: have %x, want cc", have)
+		}
+		if have := view.GetCodeSize(dirty); have != 2 {
+			t.Errorf("dirty code size mismatch: have %d, want 2", have)
+		}
+		if view.Exist(suicided) {
+			t.Errorf("suicided account exists in view")
+		}
+		if have := view.GetState(suicided, key1); have != (common.Hash{}) {
+			t.Errorf("suicided storage mismatch: have %x, want empty", have)
+		}
+		if have := view.GetBalance(created); have.Cmp(big.NewInt(42)) != 0 {
+			t.Errorf("created balance mismatch: have %v, want 42", have)
+		}
+		if have := view.GetCode(created); !bytes.Equal(have, []byte{0xde, 0xad}) {
+			t.Errorf("created code mismatch: have %x, want dead", have)
+		}
+		if view.Exist(missing) || !view.Empty(missing) {
+			t.Errorf("missing account exists in view")
+		}
+	}
+	check()
+
+	// Ensure the view can be used from multiple goroutines at once
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			check()
+		}()
+	}
+	wg.Wait()
+
+	if err := view.Error(); err != nil {
+		t.Fatalf("view database error: %v", err)
+	}
+}
+
+// benchmarkViewState creates a committed state with a number of contracts and
+// some uncommitted changes on top, emulating a pending block.
+func benchmarkViewState(b *testing.B) (*StateDB, []common.Address) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	addrs := make([]common.Address, 256)
+	for i := range addrs {
+		addrs[i] = common.BytesToAddress([]byte{byte(i), 0xff})
+		state.SetBalance(addrs[i], big.NewInt(int64(i)))
+		state.SetCode(addrs[i], bytes.Repeat([]byte{byte(i)}, 64))
+		for j := 0; j < 16; j++ {
+			state.SetState(addrs[i], common.BytesToHash([]byte{byte(j)}), common.BytesToHash([]byte{byte(i), byte(j)}))
+		}
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		b.Fatalf("failed to commit state: %v", err)
+	}
+	state, _ = New(root, state.db, nil)
+	for i := 0; i < len(addrs); i += 4 {
+		state.AddBalance(addrs[i], big.NewInt(1))
+		state.SetState(addrs[i], common.Hash{}, common.HexToHash("01"))
+	}
+	return state, addrs
+}
+
+// Benchmarks concurrent view calls served from a single shared read-only view.
+func BenchmarkCopyReadOnlyConcurrentReads(b *testing.B) {
+	state, addrs := benchmarkViewState(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			view := state.CopyReadOnly()
+			addr := addrs[i%len(addrs)]
+			view.GetBalance(addr)
+			view.GetCode(addr)
+			view.GetState(addr, common.BytesToHash([]byte{byte(i % 16)}))
+		}
+	})
+}
+
+// Benchmarks concurrent view calls each served from a full copy of the state,
+// which is what callers had to do before read-only views were available.
+func BenchmarkCopyConcurrentReads(b *testing.B) {
+	var (
+		state, addrs = benchmarkViewState(b)
+		lock         sync.Mutex
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			lock.Lock() // Copy itself isn't safe for concurrent use
+			cpy := state.Copy()
+			lock.Unlock()
+
+			addr := addrs[i%len(addrs)]
+			cpy.GetBalance(addr)
+			cpy.GetCode(addr)
+			cpy.GetState(addr, common.BytesToHash([]byte{byte(i % 16)}))
+		}
+	})
+}
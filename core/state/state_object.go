@@ -79,6 +79,8 @@ type stateObject struct {
 	trie Trie // storage trie, which becomes non-nil on first access
 	code Code // contract bytecode, which gets set when code is loaded
 
+	codeSize int // cached size of the contract code, zero if not yet known
+
 	originStorage  Storage // Storage cache of original entries to dedup rewrites, reset for every transaction
 	pendingStorage Storage // Storage entries that need to be flushed to disk, at the end of an entire block
 	dirtyStorage   Storage // Storage entries that have been modified in the current transaction execution
@@ -419,6 +421,7 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 		stateObject.trie = db.db.CopyTrie(s.trie)
 	}
 	stateObject.code = s.code
+	stateObject.codeSize = s.codeSize
 	stateObject.dirtyStorage = s.dirtyStorage.Copy()
 	stateObject.originStorage = s.originStorage.Copy()
 	stateObject.pendingStorage = s.pendingStorage.Copy()
@@ -455,18 +458,26 @@ func (s *stateObject) Code(db Database) []byte {
 
 // CodeSize returns the size of the contract code associated with this object,
 // or zero if none. This method is an almost mirror of Code, but uses a cache
-// inside the database to avoid loading codes seen recently.
+// inside the database to avoid loading codes seen recently. Once retrieved, the
+// size is remembered by the object, so the code is never loaded just to measure
+// it again.
 func (s *stateObject) CodeSize(db Database) int {
 	if s.code != nil {
 		return len(s.code)
 	}
+	// Non-empty code can't have a zero size, so zero means not yet cached
+	if s.codeSize != 0 {
+		return s.codeSize
+	}
 	if bytes.Equal(s.CodeHash(), emptyCodeHash) {
 		return 0
 	}
 	size, err := db.ContractCodeSize(s.addrHash, common.BytesToHash(s.CodeHash()))
 	if err != nil {
 		s.setError(fmt.Errorf("can't load code size %x: %v", s.CodeHash(), err))
+		return size
 	}
+	s.codeSize = size
 	return size
 }
 
@@ -482,6 +493,7 @@ func (s *stateObject) SetCode(codeHash common.Hash, code []byte) {
 
 func (s *stateObject) setCode(codeHash common.Hash, code []byte) {
 	s.code = code
+	s.codeSize = len(code)
 	s.data.CodeHash = codeHash[:]
 	s.dirtyCode = true
 }
@@ -90,6 +90,49 @@ func TestDump(t *testing.T) {
 	}
 }
 
+func TestDumpPrefix(t *testing.T) {
+	s := newStateTest()
+	addr1, _ := common.HexToAddress("cb160000000000000000000000000000000000000102")
+	addr2, _ := common.HexToAddress("cb970000000000000000000000000000000000000002")
+	addr3, _ := common.HexToAddress("ab270000000000000000000000000000000000000001")
+	addr4, _ := common.HexToAddress("cb160000000000000000000000000000000000000103")
+	for i, addr := range []common.Address{addr1, addr2, addr3, addr4} {
+		s.state.AddBalance(addr, big.NewInt(int64(i+1)))
+	}
+	s.state.SetCode(addr4, []byte{3, 3, 3})
+	s.state.Commit(false)
+
+	tests := []struct {
+		prefix []byte
+		want   []common.Address
+	}{
+		{prefix: nil, want: []common.Address{addr1, addr2, addr3, addr4}},
+		{prefix: []byte{0xcb}, want: []common.Address{addr1, addr2, addr4}},
+		{prefix: []byte{0xcb, 0x16}, want: []common.Address{addr1, addr4}},
+		{prefix: addr3.Bytes(), want: []common.Address{addr3}},
+		{prefix: []byte{0xcb, 0x17}, want: nil},
+	}
+	for i, tt := range tests {
+		dump := s.state.RawDumpPrefix(tt.prefix, false, true)
+		if len(dump.Accounts) != len(tt.want) {
+			t.Errorf("test %d: account count mismatch: have %d, want %d", i, len(dump.Accounts), len(tt.want))
+		}
+		for _, addr := range tt.want {
+			if _, ok := dump.Accounts[addr]; !ok {
+				t.Errorf("test %d: account %x missing from dump", i, addr)
+			}
+		}
+	}
+	// The accounts should be dumped the same as by a full dump
+	full, dump := s.state.RawDump(false, true, true), s.state.RawDumpPrefix([]byte{0xcb, 0x16}, false, true)
+	if full.Root != dump.Root {
+		t.Errorf("root mismatch: have %s, want %s", dump.Root, full.Root)
+	}
+	if have, want := dump.Accounts[addr4], full.Accounts[addr4]; have.Code != want.Code || have.Balance != want.Balance {
+		t.Errorf("account mismatch: have %+v, want %+v", have, want)
+	}
+}
+
 func TestNull(t *testing.T) {
 	s := newStateTest()
 	address, err := common.HexToAddress("cb9300000000823140710bf13990e4500136726d8b55")
@@ -254,3 +297,70 @@ func compareStateObjects(so0, so1 *stateObject, t *testing.T) {
 		}
 	}
 }
+
+// codeCountingDB is a state database counting the contract code lookups.
+type codeCountingDB struct {
+	Database
+	codeReads int
+	sizeReads int
+}
+
+func (db *codeCountingDB) ContractCode(addrHash, codeHash common.Hash) ([]byte, error) {
+	db.codeReads++
+	return db.Database.ContractCode(addrHash, codeHash)
+}
+
+func (db *codeCountingDB) ContractCodeSize(addrHash, codeHash common.Hash) (int, error) {
+	db.sizeReads++
+	return db.Database.ContractCodeSize(addrHash, codeHash)
+}
+
+// Tests that the code size is consistent with the code, and that it's cached in
+// the state object instead of being looked up over and over again.
+func TestCodeSizeCaching(t *testing.T) {
+	var (
+		db   = &codeCountingDB{Database: NewDatabase(rawdb.NewMemoryDatabase())}
+		addr = toAddr([]byte{0x01})
+		code = bytes.Repeat([]byte{0x60}, 1024)
+	)
+	state, _ := New(common.Hash{}, db, nil)
+	state.SetCode(addr, code)
+	if have := state.GetCodeSize(addr); have != len(code) {
+		t.Fatalf("dirty code size mismatch: have %d, want %d", have, len(code))
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	// Load the contract in a fresh state and query its size repeatedly
+	state, _ = New(root, db, nil)
+	db.codeReads, db.sizeReads = 0, 0
+
+	for i := 0; i < 3; i++ {
+		if have := state.GetCodeSize(addr); have != len(code) {
+			t.Fatalf("code size mismatch: have %d, want %d", have, len(code))
+		}
+	}
+	if db.sizeReads != 1 {
+		t.Errorf("code size lookups mismatch: have %d, want 1", db.sizeReads)
+	}
+	if db.codeReads != 0 {
+		t.Errorf("code loaded %d times to measure its size", db.codeReads)
+	}
+	if have := len(state.GetCode(addr)); have != state.GetCodeSize(addr) {
+		t.Errorf("code length mismatch: have %d, want %d", have, state.GetCodeSize(addr))
+	}
+	// Replacing and reverting the code should update the cached size
+	snap := state.Snapshot()
+	state.SetCode(addr, []byte{0x01, 0x02})
+	if have := state.GetCodeSize(addr); have != 2 {
+		t.Errorf("updated code size mismatch: have %d, want 2", have)
+	}
+	state.RevertToSnapshot(snap)
+	if have := state.GetCodeSize(addr); have != len(code) {
+		t.Errorf("reverted code size mismatch: have %d, want %d", have, len(code))
+	}
+}
@@ -18,7 +18,6 @@
 package state
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 	"sort"
@@ -93,6 +92,9 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Accounts and storage slots accessed by the transaction being executed
+	accessList accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -316,15 +318,29 @@ func (s *StateDB) GetProof(a common.Address) ([][]byte, error) {
 	return proof, err
 }
 
-// GetStorageProof returns the StorageProof for given key
-func (s *StateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte, error) {
-	var proof proofList
+// GetStorageProof returns the MerkleProof for a given Account along with the
+// StorageProofs for each of the given keys, in the same order. The account proof
+// is against the state root, the storage proofs against the storage root of the
+// account. If the account does not exist, the storage proofs are all empty.
+func (s *StateDB) GetStorageProof(a common.Address, keys []common.Hash) ([][]byte, [][][]byte, error) {
+	accountProof, err := s.GetProof(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	storageProofs := make([][][]byte, len(keys))
+
 	trie := s.StorageTrie(a)
 	if trie == nil {
-		return proof, errors.New("storage trie for requested address does not exist")
+		return accountProof, storageProofs, nil
 	}
-	err := trie.Prove(crypto.SHA3(key.Bytes()), 0, &proof)
-	return proof, err
+	for i, key := range keys {
+		var proof proofList
+		if err := trie.Prove(crypto.SHA3(key.Bytes()), 0, &proof); err != nil {
+			return nil, nil, err
+		}
+		storageProofs[i] = proof
+	}
+	return accountProof, storageProofs, nil
 }
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
@@ -695,6 +711,7 @@ func (s *StateDB) Copy() *StateDB {
 	for hash, preimage := range s.preimages {
 		state.preimages[hash] = preimage
 	}
+	state.accessList = s.accessList.copy()
 	return state
 }
 
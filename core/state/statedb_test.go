@@ -32,6 +32,10 @@ import (
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/core/rawdb"
 	"github.com/core-coin/go-core/v2/core/types"
+	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/rlp"
+	"github.com/core-coin/go-core/v2/trie"
+	"github.com/core-coin/go-core/v2/xcbdb/memorydb"
 )
 
 // Tests that updating a state trie does not leak any database writes prior to
@@ -736,3 +740,83 @@ func TestMissingTrieNodes(t *testing.T) {
 		t.Fatalf("expected error, got root :%x", root)
 	}
 }
+
+// Tests that the account and storage proofs returned for an account can be
+// verified against the state root, both for existing and missing slots.
+func TestGetStorageProof(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	addr := common.BytesToAddress([]byte("account"))
+	state.SetBalance(addr, big.NewInt(42))
+	state.SetNonce(addr, 7)
+	state.SetState(addr, common.HexToHash("0x01"), common.HexToHash("0x0a"))
+	state.SetState(addr, common.HexToHash("0x02"), common.HexToHash("0x0b"))
+
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	state, _ = New(root, state.Database(), nil)
+
+	keys := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")}
+	accountProof, storageProofs, err := state.GetStorageProof(addr, keys)
+	if err != nil {
+		t.Fatalf("failed to retrieve proofs: %v", err)
+	}
+	if len(storageProofs) != len(keys) {
+		t.Fatalf("storage proof count mismatch: have %d, want %d", len(storageProofs), len(keys))
+	}
+	// Verify the account proof against the state root
+	blob, err := trie.VerifyProof(root, crypto.SHA3(addr.Bytes()), proofDB(accountProof))
+	if err != nil {
+		t.Fatalf("failed to verify account proof: %v", err)
+	}
+	var account Account
+	if err := rlp.DecodeBytes(blob, &account); err != nil {
+		t.Fatalf("failed to decode account: %v", err)
+	}
+	if account.Nonce != 7 || account.Balance.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("account mismatch: have nonce %d balance %v, want 7 and 42", account.Nonce, account.Balance)
+	}
+	// Verify the storage proofs against the storage root of the account
+	for i, want := range []common.Hash{common.HexToHash("0x0a"), common.HexToHash("0x0b"), {}} {
+		blob, err := trie.VerifyProof(account.Root, crypto.SHA3(keys[i].Bytes()), proofDB(storageProofs[i]))
+		if err != nil {
+			t.Fatalf("key %d: failed to verify storage proof: %v", i, err)
+		}
+		var have common.Hash
+		if blob != nil {
+			_, content, _, err := rlp.Split(blob)
+			if err != nil {
+				t.Fatalf("key %d: failed to decode slot: %v", i, err)
+			}
+			have.SetBytes(content)
+		}
+		if have != want {
+			t.Errorf("key %d: value mismatch: have %x, want %x", i, have, want)
+		}
+	}
+	// Missing accounts should be proven absent, with empty storage proofs
+	missing := common.BytesToAddress([]byte("missing"))
+	accountProof, storageProofs, err = state.GetStorageProof(missing, keys)
+	if err != nil {
+		t.Fatalf("failed to retrieve proofs of missing account: %v", err)
+	}
+	if blob, err := trie.VerifyProof(root, crypto.SHA3(missing.Bytes()), proofDB(accountProof)); err != nil || blob != nil {
+		t.Fatalf("missing account proof mismatch: have %x (%v), want absence", blob, err)
+	}
+	for i, proof := range storageProofs {
+		if len(proof) != 0 {
+			t.Errorf("key %d: non-empty storage proof for missing account", i)
+		}
+	}
+}
+
+// proofDB converts a list of proof nodes into a database keyed by node hash.
+func proofDB(proof [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.SHA3(node), node)
+	}
+	return db
+}
@@ -17,7 +17,9 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/consensus"
@@ -66,7 +68,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	vmenv := vm.NewCVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
-		msg, err := tx.AsMessage(types.MakeSigner(p.config.NetworkID))
+		msg, err := tx.AsMessage(types.MakeBlockSigner(p.config, header.Number))
 		if err != nil {
 			return nil, nil, 0, err
 		}
@@ -105,6 +107,7 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 	receipt := types.NewReceipt(root, result.Failed(), *usedEnergy)
 	receipt.TxHash = tx.Hash()
 	receipt.EnergyUsed = result.UsedEnergy
+	receipt.EffectiveEnergyPrice = new(big.Int).Set(msg.EnergyPrice())
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(cvm.TxContext.Origin, tx.Nonce())
@@ -124,7 +127,7 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 // for the transaction, energy used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *EnergyPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedEnergy *uint64, cfg vm.Config) (*types.Receipt, error) {
-	msg, err := tx.AsMessage(types.MakeSigner(config.NetworkID))
+	msg, err := tx.AsMessage(types.MakeBlockSigner(config, header.Number))
 	if err != nil {
 		return nil, err
 	}
@@ -133,3 +136,37 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	vmenv := vm.NewCVM(blockContext, vm.TxContext{}, statedb, config, cfg)
 	return applyTransaction(msg, config, bc, author, gp, statedb, header, tx, usedEnergy, vmenv)
 }
+
+// ReplayTransaction re-executes the transactions of the block up to and including
+// the one at the given index with the CVM, whose state must be the parent state of
+// the block, and returns the execution result of the last one. The execution stops
+// early with an error if the CVM gets cancelled.
+func ReplayTransaction(cvm *vm.CVM, statedb *state.StateDB, block *types.Block, index uint64) (*ExecutionResult, error) {
+	txs := block.Transactions()
+	if index >= uint64(len(txs)) {
+		return nil, fmt.Errorf("transaction index %d out of range [0, %d)", index, len(txs))
+	}
+	var (
+		signer = types.MakeBlockSigner(cvm.ChainConfig(), block.Number())
+		result *ExecutionResult
+	)
+	for i, tx := range txs[:index+1] {
+		if cvm.Cancelled() {
+			return nil, errors.New("execution aborted")
+		}
+		if i > 0 {
+			statedb.Finalise(true)
+		}
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			return nil, err
+		}
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		cvm.Reset(NewCVMTxContext(msg), statedb)
+
+		if result, err = ApplyMessage(cvm, msg, new(EnergyPool).AddEnergy(tx.Energy())); err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+	}
+	return result, nil
+}
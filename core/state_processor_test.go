@@ -17,6 +17,8 @@
 package core
 
 import (
+	crand "crypto/rand"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/consensus"
 	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/core/state"
 	"github.com/core-coin/go-core/v2/core/types"
 	"github.com/core-coin/go-core/v2/core/vm"
 	"github.com/core-coin/go-core/v2/crypto"
@@ -158,3 +161,258 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	// Assemble and return the final block for sealing
 	return types.NewBlock(header, txs, nil, receipts, new(trie.Trie))
 }
+
+// Tests that the energy costs of a chain config override the protocol defaults,
+// both for the intrinsic transaction costs and the CVM operations.
+func TestEnergyTableOverride(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey(crand.Reader)
+		dest, _  = crypto.GenerateKey(crand.Reader)
+		contract = crypto.CreateAddress(key.Address(), 0)
+		code     = []byte{byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.POP), byte(vm.STOP)}
+	)
+	run := func(table *params.EnergyTable) (uint64, uint64) {
+		config := *params.MainnetChainConfig
+		config.EnergyTable = table
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetBalance(key.Address(), big.NewInt(1000000000))
+		statedb.SetCode(contract, code)
+
+		var (
+			signer = types.NewNucleusSigner(config.NetworkID)
+			header = &types.Header{Number: big.NewInt(1), EnergyLimit: 10000000, Difficulty: big.NewInt(1)}
+			used   = make([]uint64, 2)
+		)
+		for i, to := range []common.Address{dest.Address(), contract} {
+			tx, err := types.SignTx(types.NewTransaction(uint64(i), to, big.NewInt(1), 100000, big.NewInt(1), nil), signer, key)
+			if err != nil {
+				t.Fatalf("failed to sign transaction: %v", err)
+			}
+			receipt, err := ApplyTransaction(&config, nil, &common.Address{}, new(EnergyPool).AddEnergy(header.EnergyLimit), statedb, header, tx, new(uint64), vm.Config{})
+			if err != nil {
+				t.Fatalf("failed to apply transaction: %v", err)
+			}
+			used[i] = receipt.EnergyUsed
+		}
+		return used[0], used[1]
+	}
+	transfer, call := run(nil)
+	if transfer != params.TxEnergy {
+		t.Errorf("default transfer energy mismatch: have %d, want %d", transfer, params.TxEnergy)
+	}
+	if want := params.TxEnergy + 3 + params.SloadEnergy + 2; call != want {
+		t.Errorf("default call energy mismatch: have %d, want %d", call, want)
+	}
+	// Override some costs, leaving the rest at their defaults
+	txEnergy, sloadEnergy := uint64(30000), uint64(100)
+	transfer, call = run(&params.EnergyTable{TxEnergy: &txEnergy, SloadEnergy: &sloadEnergy})
+	if transfer != 30000 {
+		t.Errorf("overridden transfer energy mismatch: have %d, want %d", transfer, 30000)
+	}
+	if want := uint64(30000 + 3 + 100 + 2); call != want {
+		t.Errorf("overridden call energy mismatch: have %d, want %d", call, want)
+	}
+	// Costs may be overridden to zero, which is distinct from leaving them unset
+	sloadEnergy = 0
+	if _, call = run(&params.EnergyTable{SloadEnergy: &sloadEnergy}); call != params.TxEnergy+3+2 {
+		t.Errorf("zeroed call energy mismatch: have %d, want %d", call, params.TxEnergy+3+2)
+	}
+	// Ensure the overrides didn't leak into the default instruction set
+	if transfer, call = run(nil); transfer != params.TxEnergy || call != params.TxEnergy+3+params.SloadEnergy+2 {
+		t.Errorf("override leaked into defaults: transfer %d, call %d", transfer, call)
+	}
+}
+
+// accessListTestConfig returns a copy of the test chain config with the access
+// list fork active from genesis.
+func accessListTestConfig() *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.AccessListBlock = big.NewInt(0)
+	return &config
+}
+
+// Tests that the access list of a transaction is charged for intrinsically and
+// warms the listed storage slots, and that slots are warmed by their first read
+// from the access list fork on only.
+func TestAccessListEnergy(t *testing.T) {
+	var (
+		config   = accessListTestConfig()
+		signer   = types.NewAccessListSigner(config.NetworkID)
+		key, _   = crypto.GenerateKey(crand.Reader)
+		contract = crypto.CreateAddress(key.Address(), 0)
+		code     = []byte{
+			byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.POP),
+			byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.POP),
+			byte(vm.STOP),
+		}
+		listed = params.TxAccessListAddressEnergy + params.TxAccessListStorageKeyEnergy
+		cold   = 3 + params.SloadEnergy + 2
+		warm   = 3 + params.WarmStorageReadEnergy + 2
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(key.Address(), big.NewInt(1000000000))
+	statedb.SetCode(contract, code)
+
+	header := &types.Header{Number: big.NewInt(1), EnergyLimit: 10000000, Difficulty: big.NewInt(1)}
+	tests := []struct {
+		list types.AccessList
+		want uint64
+	}{
+		// Legacy transaction, no access list, the first read warms the slot
+		{nil, params.TxEnergy + cold + warm},
+		// Listed slot, all reads at the reduced cost
+		{
+			types.AccessList{{Address: contract, StorageKeys: []common.Hash{{}}}},
+			params.TxEnergy + listed + warm + warm,
+		},
+		// Listed account but different slot, the first read at the full cost
+		{
+			types.AccessList{{Address: contract, StorageKeys: []common.Hash{{0x01}}}},
+			params.TxEnergy + listed + cold + warm,
+		},
+		// Legacy transaction after a listed slot, the access list must not linger
+		{
+			types.AccessList{{Address: contract, StorageKeys: []common.Hash{{}}}},
+			params.TxEnergy + listed + warm + warm,
+		},
+		{nil, params.TxEnergy + cold + warm},
+	}
+	for i, tt := range tests {
+		var tx *types.Transaction
+		if tt.list == nil {
+			tx = types.NewTransaction(uint64(i), contract, big.NewInt(0), 100000, big.NewInt(1), nil)
+		} else {
+			tx = types.NewTx(&types.AccessListTx{
+				AccountNonce: uint64(i),
+				Price:        big.NewInt(1),
+				EnergyLimit:  100000,
+				Recipient:    &contract,
+				Amount:       big.NewInt(0),
+				AccessList:   tt.list,
+			})
+		}
+		tx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatalf("test %d: failed to sign transaction: %v", i, err)
+		}
+		receipt, err := ApplyTransaction(config, nil, &common.Address{}, new(EnergyPool).AddEnergy(header.EnergyLimit), statedb, header, tx, new(uint64), vm.Config{})
+		if err != nil {
+			t.Fatalf("test %d: failed to apply transaction: %v", i, err)
+		}
+		if receipt.EnergyUsed != tt.want {
+			t.Errorf("test %d: energy used mismatch: have %d, want %d", i, receipt.EnergyUsed, tt.want)
+		}
+	}
+	// Before the fork, every read is charged the full cost
+	unforked := *config
+	unforked.AccessListBlock = nil
+
+	tx, _ := types.SignTx(types.NewTransaction(uint64(len(tests)), contract, big.NewInt(0), 100000, big.NewInt(1), nil), types.NewNucleusSigner(config.NetworkID), key)
+	receipt, err := ApplyTransaction(&unforked, nil, &common.Address{}, new(EnergyPool).AddEnergy(header.EnergyLimit), statedb, header, tx, new(uint64), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to apply unforked transaction: %v", err)
+	}
+	if want := params.TxEnergy + cold + cold; receipt.EnergyUsed != want {
+		t.Errorf("unforked energy used mismatch: have %d, want %d", receipt.EnergyUsed, want)
+	}
+}
+
+// Tests that the accounts warmed by a reverted call are cold again afterwards.
+func TestAccessListRevert(t *testing.T) {
+	var (
+		config = accessListTestConfig()
+		key, _ = crypto.GenerateKey(crand.Reader)
+		target = crypto.CreateAddress(key.Address(), 10)
+		inner  = crypto.CreateAddress(key.Address(), 11)
+		outer  = crypto.CreateAddress(key.Address(), 12)
+	)
+	balance := func() []byte {
+		return append([]byte{byte(vm.PUSH22)}, append(target.Bytes(), byte(vm.BALANCE), byte(vm.POP))...)
+	}
+	// The inner contract reads the balance of the target, then reverts
+	innerCode := append(balance(), byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.REVERT))
+
+	// The outer contract calls the inner one, then reads the balance of the target
+	outerCode := []byte{
+		byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1),
+		byte(vm.PUSH22),
+	}
+	outerCode = append(outerCode, inner.Bytes()...)
+	outerCode = append(outerCode, byte(vm.PUSH2), 0xff, 0xff, byte(vm.CALL), byte(vm.POP))
+	outerCode = append(append(outerCode, balance()...), byte(vm.STOP))
+
+	run := func(code []byte) uint64 {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetBalance(key.Address(), big.NewInt(1000000000))
+		statedb.SetCode(inner, innerCode)
+		statedb.SetCode(outer, code)
+
+		header := &types.Header{Number: big.NewInt(1), EnergyLimit: 10000000, Difficulty: big.NewInt(1)}
+		tx, _ := types.SignTx(types.NewTransaction(0, outer, big.NewInt(0), 1000000, big.NewInt(1), nil), types.NewAccessListSigner(config.NetworkID), key)
+		receipt, err := ApplyTransaction(config, nil, &common.Address{}, new(EnergyPool).AddEnergy(header.EnergyLimit), statedb, header, tx, new(uint64), vm.Config{})
+		if err != nil {
+			t.Fatalf("failed to apply transaction: %v", err)
+		}
+		return receipt.EnergyUsed
+	}
+	// Compare against the outer contract reading the balance twice without the
+	// call, which must differ by the cost of a cold read minus a warm one
+	reverted := run(outerCode)
+	withoutRead := run(append(outerCode[:len(outerCode)-len(balance())-1], byte(vm.STOP)))
+
+	if have, want := reverted-withoutRead, 3+params.BalanceEnergy+2; have != want {
+		t.Errorf("balance read after reverted call mismatch: have %d, want %d (cold)", have, want)
+	}
+}
+
+// Tests that access list transactions are only accepted in blocks after the
+// access list fork.
+func TestAccessListFork(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey(crand.Reader)
+		forked = &params.ChainConfig{NetworkID: big.NewInt(1), AccessListBlock: big.NewInt(1), Cryptore: new(params.CryptoreConfig)}
+		gspec  = &Genesis{
+			Config: forked,
+			Alloc:  GenesisAlloc{key.Address(): {Balance: big.NewInt(1000000000)}},
+		}
+		db      = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(forked, genesis, cryptore.NewFaker(), db, 1, func(i int, b *BlockGen) {
+		tx, err := types.SignTx(types.NewTx(&types.AccessListTx{
+			Price:       big.NewInt(1),
+			EnergyLimit: params.TxEnergy + params.TxAccessListAddressEnergy,
+			Recipient:   &common.Address{},
+			Amount:      big.NewInt(0),
+			AccessList:  types.AccessList{{Address: key.Address()}},
+		}), types.MakeBlockSigner(forked, b.Number()), key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		b.AddTx(tx)
+	})
+	// Import the block on chains activating the fork at and after it
+	for _, tt := range []struct {
+		fork *big.Int
+		err  error
+	}{
+		{big.NewInt(1), nil},
+		{big.NewInt(2), types.ErrTxTypeNotSupported},
+		{nil, types.ErrTxTypeNotSupported},
+	} {
+		config := *forked
+		config.AccessListBlock = tt.fork
+
+		db := rawdb.NewMemoryDatabase()
+		(&Genesis{Config: &config, Alloc: gspec.Alloc}).MustCommit(db)
+		chain, err := NewBlockChain(db, nil, &config, cryptore.NewFaker(), vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatalf("fork %v: failed to create chain: %v", tt.fork, err)
+		}
+		if _, err := chain.InsertChain(blocks); !errors.Is(err, tt.err) {
+			t.Errorf("fork %v: import error mismatch: have %v, want %v", tt.fork, err, tt.err)
+		}
+		chain.Stop()
+	}
+}
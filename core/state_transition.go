@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/types"
 	"github.com/core-coin/go-core/v2/core/vm"
 	"github.com/core-coin/go-core/v2/params"
 )
@@ -69,6 +70,7 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 }
 
 // ExecutionResult includes all output after executing given cvm
@@ -108,12 +110,21 @@ func (result *ExecutionResult) Revert() []byte {
 
 // IntrinsicEnergy computes the 'intrinsic energy' for a message with the given data.
 func IntrinsicEnergy(data []byte, contractCreation bool) (uint64, error) {
+	return IntrinsicEnergyWithAccessList(data, nil, contractCreation, nil)
+}
+
+// IntrinsicEnergyWithAccessList computes the 'intrinsic energy' for a message with
+// the given data and access list, using the energy costs of the given chain
+// configuration (the protocol defaults if nil).
+func IntrinsicEnergyWithAccessList(data []byte, accessList types.AccessList, contractCreation bool, config *params.ChainConfig) (uint64, error) {
+	costs := config.EnergyCosts()
+
 	// Set the starting energy for the raw transaction
 	var energy uint64
 	if contractCreation {
-		energy = params.TxEnergyContractCreation
+		energy = costs.TxEnergyContractCreation
 	} else {
-		energy = params.TxEnergy
+		energy = costs.TxEnergy
 	}
 	// Bump the required energy by the amount of transactional data
 	if len(data) > 0 {
@@ -125,17 +136,30 @@ func IntrinsicEnergy(data []byte, contractCreation bool) (uint64, error) {
 			}
 		}
 		// Make sure we don't exceed uint64 for all data combinations
-		nonZeroEnergy := params.TxDataNonZeroEnergy
+		nonZeroEnergy := costs.TxDataNonZeroEnergy
 		if (math.MaxUint64-energy)/nonZeroEnergy < nz {
 			return 0, ErrEnergyUintOverflow
 		}
 		energy += nz * nonZeroEnergy
 
 		z := uint64(len(data)) - nz
-		if (math.MaxUint64-energy)/params.TxDataZeroEnergy < z {
+		if (math.MaxUint64-energy)/costs.TxDataZeroEnergy < z {
 			return 0, ErrEnergyUintOverflow
 		}
-		energy += z * params.TxDataZeroEnergy
+		energy += z * costs.TxDataZeroEnergy
+	}
+	// Bump the required energy by the addresses and storage keys of the access list
+	if accessList != nil {
+		addresses, keys := uint64(len(accessList)), uint64(accessList.StorageKeys())
+		if (math.MaxUint64-energy)/params.TxAccessListAddressEnergy < addresses {
+			return 0, ErrEnergyUintOverflow
+		}
+		energy += addresses * params.TxAccessListAddressEnergy
+
+		if (math.MaxUint64-energy)/params.TxAccessListStorageKeyEnergy < keys {
+			return 0, ErrEnergyUintOverflow
+		}
+		energy += keys * params.TxAccessListStorageKeyEnergy
 	}
 	return energy, nil
 }
@@ -235,7 +259,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	contractCreation := msg.To() == nil
 
 	// Check clauses 4-5, subtract intrinsic energy if everything is correct
-	energy, err := IntrinsicEnergy(st.data, contractCreation)
+	energy, err := IntrinsicEnergyWithAccessList(st.data, msg.AccessList(), contractCreation, st.cvm.ChainConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -248,6 +272,11 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if msg.Value().Sign() > 0 && !st.cvm.Context.CanTransfer(st.state, msg.From(), msg.Value()) {
 		return nil, fmt.Errorf("%w: address %v", ErrInsufficientFundsForTransfer, msg.From().Hex())
 	}
+	// Seed the accounts and storage slots accessed by the transaction for pricing
+	if st.cvm.ChainConfig().IsAccessList(st.cvm.Context.BlockNumber) {
+		st.state.PrepareAccessList(msg.From(), msg.To(), msg.AccessList())
+	}
+
 	var (
 		ret   []byte
 		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
@@ -18,6 +18,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"sort"
@@ -44,11 +45,11 @@ const (
 	// O(maxslots), where max slots are 4 currently).
 	txSlotSize = 32 * 1024
 
-	// txMaxSize is the maximum size a single transaction can have. This field has
+	// TxMaxSize is the maximum size a single transaction can have. This field has
 	// non-trivial consequences: larger transactions are significantly harder and
 	// more expensive to propagate; larger transactions also take more resources
 	// to validate whether they fit into the pool or not.
-	txMaxSize = 4 * txSlotSize // 128KB
+	TxMaxSize = 4 * txSlotSize // 128KB
 )
 
 var (
@@ -79,6 +80,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrAccountLimitExceeded is returned if a transaction would exceed the
+	// number of transactions a single account may have in the pool.
+	ErrAccountLimitExceeded = errors.New("account transaction limit exceeded")
 )
 
 var (
@@ -101,10 +106,11 @@ var (
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
 
 	// General tx metrics
-	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
-	validTxMeter       = metrics.NewRegisteredMeter("txpool/valid", nil)
-	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
-	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
+	knownTxMeter        = metrics.NewRegisteredMeter("txpool/known", nil)
+	validTxMeter        = metrics.NewRegisteredMeter("txpool/valid", nil)
+	invalidTxMeter      = metrics.NewRegisteredMeter("txpool/invalid", nil)
+	underpricedTxMeter  = metrics.NewRegisteredMeter("txpool/underpriced", nil)
+	accountLimitTxMeter = metrics.NewRegisteredMeter("txpool/accountlimit", nil)
 
 	pendingGauge = metrics.NewRegisteredGauge("txpool/pending", nil)
 	queuedGauge  = metrics.NewRegisteredGauge("txpool/queued", nil)
@@ -146,6 +152,9 @@ type TxPoolConfig struct {
 	GlobalSlots  uint64 // Maximum number of executable transaction slots for all accounts
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
+	AccountLimit uint64 // Maximum number of pooled transactions of a remote account, replacements excepted (0 = unlimited)
+
+	DataLimit uint64 // Maximum size of the input data of a single transaction
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 }
@@ -164,6 +173,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue: 64,
 	GlobalQueue:  1024,
 
+	DataLimit: TxMaxSize,
+
 	Lifetime: 3 * time.Hour,
 }
 
@@ -199,6 +210,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool global queue", "provided", conf.GlobalQueue, "updated", DefaultTxPoolConfig.GlobalQueue)
 		conf.GlobalQueue = DefaultTxPoolConfig.GlobalQueue
 	}
+	if conf.DataLimit < 1 {
+		log.Warn("Sanitizing invalid txpool data limit", "provided", conf.DataLimit, "updated", DefaultTxPoolConfig.DataLimit)
+		conf.DataLimit = DefaultTxPoolConfig.DataLimit
+	}
 	if conf.Lifetime < 1 {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
@@ -226,6 +241,7 @@ type TxPool struct {
 	currentState     *state.StateDB // Current state in the blockchain head
 	pendingNonces    *txNoncer      // Pending state tracking virtual nonces
 	currentMaxEnergy uint64         // Current energy limit for transaction caps
+	accessList       bool           // Fork indicator whether access list transactions are accepted
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -261,7 +277,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		config:          config,
 		chainconfig:     chainconfig,
 		chain:           chain,
-		signer:          types.NewNucleusSigner(chainconfig.NetworkID),
+		signer:          types.LatestSigner(chainconfig),
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
@@ -515,8 +531,16 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+	// Accept only legacy transactions until the access list fork activates
+	if !pool.accessList && tx.Type() != types.LegacyTxType {
+		return types.ErrTxTypeNotSupported
+	}
+	// Reject transactions with oversized input data before computing anything
+	if size := uint64(len(tx.Data())); size > pool.config.DataLimit {
+		return fmt.Errorf("%w: data size %d, limit %d", ErrOversizedData, size, pool.config.DataLimit)
+	}
 	// Reject transactions over defined size to prevent DOS attacks
-	if uint64(tx.Size()) > txMaxSize {
+	if uint64(tx.Size()) > TxMaxSize {
 		return ErrOversizedData
 	}
 	// Transactions can't be negative. This may never happen using RLP decoded
@@ -548,7 +572,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrInsufficientFunds
 	}
 	// Ensure the transaction has more energy than the basic tx fee.
-	intrEnergy, err := IntrinsicEnergy(tx.Data(), tx.To() == nil)
+	intrEnergy, err := IntrinsicEnergyWithAccessList(tx.Data(), tx.AccessList(), tx.To() == nil, pool.chainconfig)
 	if err != nil {
 		return err
 	}
@@ -579,6 +603,28 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	from, err := types.Sender(pool.signer, tx) // already validated
+	if err != nil {
+		return false, err
+	}
+	// If the sender has too many pooled transactions, only accept replacements
+	if limit := pool.config.AccountLimit; limit > 0 && !local && !pool.locals.contains(from) {
+		pending, queue := pool.pending[from], pool.queue[from]
+
+		var count int
+		if pending != nil {
+			count += pending.Len()
+		}
+		if queue != nil {
+			count += queue.Len()
+		}
+		replacing := (pending != nil && pending.Overlaps(tx)) || (queue != nil && queue.Overlaps(tx))
+		if uint64(count) >= limit && !replacing {
+			log.Trace("Discarding transaction over account limit", "hash", hash, "from", from, "limit", limit)
+			accountLimitTxMeter.Mark(1)
+			return false, ErrAccountLimitExceeded
+		}
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
@@ -596,10 +642,6 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
-	from, err := types.Sender(pool.signer, tx) // already validated
-	if err != nil {
-		return false, err
-	}
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -791,6 +833,9 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 		_, err := types.Sender(pool.signer, tx)
 		if err != nil {
 			errs[i] = ErrInvalidRecipientOrSig
+			if err == types.ErrTxTypeNotSupported {
+				errs[i] = err
+			}
 			invalidTxMeter.Mark(1)
 			continue
 		}
@@ -1167,6 +1212,10 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxEnergy = newHead.EnergyLimit
 
+	// Update the fork indicator for the next pending block
+	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
+	pool.accessList = pool.chainconfig.IsAccessList(next)
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
@@ -328,6 +328,45 @@ func TestTransactionQueue2(t *testing.T) {
 	}
 }
 
+// Tests that access list transactions are only accepted into the pool once
+// the access list fork activates in the next block.
+func TestTransactionAccessListFork(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey(crand.Reader)
+	for _, tt := range []struct {
+		fork *big.Int
+		err  error
+	}{
+		{nil, types.ErrTxTypeNotSupported},
+		{big.NewInt(2), types.ErrTxTypeNotSupported},
+		{big.NewInt(1), nil},
+	} {
+		config := *params.MainnetChainConfig
+		config.AccessListBlock = tt.fork
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddBalance(key.Address(), big.NewInt(1000000000))
+		pool := NewTxPool(testTxPoolConfig, &config, &testBlockChain{statedb, 10000000, new(event.Feed)})
+
+		tx, _ := types.SignTx(types.NewTx(&types.AccessListTx{
+			Price:       big.NewInt(1),
+			EnergyLimit: params.TxEnergy + params.TxAccessListAddressEnergy,
+			Recipient:   &common.Address{},
+			Amount:      big.NewInt(0),
+			AccessList:  types.AccessList{{Address: key.Address()}},
+		}), types.LatestSigner(&config), key)
+		if err := pool.AddRemotesSync([]*types.Transaction{tx})[0]; !errors.Is(err, tt.err) {
+			t.Errorf("fork %v: error mismatch: have %v, want %v", tt.fork, err, tt.err)
+		}
+		// Legacy transactions are accepted regardless of the fork
+		if err := pool.AddRemotesSync([]*types.Transaction{transaction(1, params.TxEnergy, key)})[0]; err != nil {
+			t.Errorf("fork %v: legacy transaction rejected: %v", tt.fork, err)
+		}
+		pool.Stop()
+	}
+}
+
 func TestTransactionNegativeValue(t *testing.T) {
 	t.Parallel()
 
@@ -760,6 +799,62 @@ func TestTransactionQueueAccountLimiting(t *testing.T) {
 	}
 }
 
+// Tests that remote accounts can't pool more transactions than the configured
+// account limit, while replacements are still accepted and other accounts are
+// unaffected.
+func TestTransactionAccountLimit(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.AccountLimit = 4
+
+	pool := NewTxPool(config, params.MainnetChainConfig, blockchain)
+	defer pool.Stop()
+
+	flooder, _ := crypto.GenerateKey(crand.Reader)
+	other, _ := crypto.GenerateKey(crand.Reader)
+	pool.currentState.AddBalance(flooder.Address(), big.NewInt(1000000000))
+	pool.currentState.AddBalance(other.Address(), big.NewInt(1000000000))
+
+	// Fill up the limit with executable and queued transactions
+	for _, nonce := range []uint64{0, 1, 2, 5} {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, flooder)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", nonce, err)
+		}
+	}
+	// Any further transaction should be rejected, whether executable or not
+	for _, nonce := range []uint64{3, 6} {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, flooder)); err != ErrAccountLimitExceeded {
+			t.Errorf("tx %d: error mismatch: have %v, want %v", nonce, err, ErrAccountLimitExceeded)
+		}
+	}
+	// Replacements of pending and queued transactions should still be accepted
+	for _, nonce := range []uint64{1, 5} {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(2), flooder)); err != nil {
+			t.Errorf("tx %d: failed to replace transaction: %v", nonce, err)
+		}
+	}
+	// Other accounts should be unaffected
+	for nonce := uint64(0); nonce < config.AccountLimit; nonce++ {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, other)); err != nil {
+			t.Errorf("tx %d: failed to add transaction of other account: %v", nonce, err)
+		}
+	}
+	pending, queued := pool.Stats()
+	if pending != 7 {
+		t.Errorf("pending transactions mismatch: have %d, want %d", pending, 7)
+	}
+	if queued != 1 {
+		t.Errorf("queued transactions mismatch: have %d, want %d", queued, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that if the transaction count belonging to multiple accounts go above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 //
@@ -1115,7 +1210,7 @@ func TestTransactionAllowedTxSize(t *testing.T) {
 	//   - signature == 65 bytes
 	// All those fields are summed up to at most 213 bytes.
 	baseSize := uint64(213)
-	dataSize := txMaxSize - baseSize
+	dataSize := TxMaxSize - baseSize
 
 	// Try adding a transaction with maximal allowed size
 	tx := pricedDataTransaction(0, pool.currentMaxEnergy, big.NewInt(1), key, dataSize)
@@ -1127,11 +1222,11 @@ func TestTransactionAllowedTxSize(t *testing.T) {
 		t.Fatalf("failed to add transaction of random allowed size: %v", err)
 	}
 	// Try adding a transaction of minimal not allowed size
-	if err := pool.addRemoteSync(pricedDataTransaction(2, pool.currentMaxEnergy, big.NewInt(1), key, txMaxSize)); err == nil {
+	if err := pool.addRemoteSync(pricedDataTransaction(2, pool.currentMaxEnergy, big.NewInt(1), key, TxMaxSize)); err == nil {
 		t.Fatalf("expected rejection on slightly oversize transaction")
 	}
 	// Try adding a transaction of random not allowed size
-	if err := pool.addRemoteSync(pricedDataTransaction(2, pool.currentMaxEnergy, big.NewInt(1), key, dataSize+1+uint64(rand.Intn(10*txMaxSize)))); err == nil {
+	if err := pool.addRemoteSync(pricedDataTransaction(2, pool.currentMaxEnergy, big.NewInt(1), key, dataSize+1+uint64(rand.Intn(10*TxMaxSize)))); err == nil {
 		t.Fatalf("expected rejection on oversize transaction")
 	}
 	// Run some sanity checks on the pool internals
@@ -1147,6 +1242,38 @@ func TestTransactionAllowedTxSize(t *testing.T) {
 	}
 }
 
+// Tests that transactions with input data over the configured limit are rejected,
+// while those right at the limit are accepted.
+func TestTransactionDataLimit(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.DataLimit = 1024
+
+	pool := NewTxPool(config, params.MainnetChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey(crand.Reader)
+	pool.currentState.AddBalance(key.Address(), big.NewInt(1000000000))
+
+	err := pool.addRemoteSync(pricedDataTransaction(0, pool.currentMaxEnergy, big.NewInt(1), key, config.DataLimit+1))
+	if !errors.Is(err, ErrOversizedData) {
+		t.Fatalf("oversized data error mismatch: have %v, want %v", err, ErrOversizedData)
+	}
+	if want := "oversized data: data size 1025, limit 1024"; err.Error() != want {
+		t.Errorf("oversized data error message mismatch: have %q, want %q", err, want)
+	}
+	if err := pool.addRemoteSync(pricedDataTransaction(0, pool.currentMaxEnergy, big.NewInt(1), key, config.DataLimit)); err != nil {
+		t.Fatalf("failed to add transaction at the data limit: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+}
+
 // Tests that if transactions start being capped, transactions are also removed from 'all'
 func TestTransactionCapClearsFromAll(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,196 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/hexutil"
+)
+
+// AccessList is a list of the accounts and storage slots a transaction plans to
+// access during its execution.
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list.
+type AccessTuple struct {
+	Address     common.Address `json:"address"     gencodec:"required"`
+	StorageKeys []common.Hash  `json:"storageKeys" gencodec:"required"`
+}
+
+// StorageKeys returns the total number of storage keys in the access list.
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+	return sum
+}
+
+// AccessListTx is the data of transactions carrying an access list.
+type AccessListTx struct {
+	NetworkID    uint            // network ID the transaction is signed for
+	AccountNonce uint64          // nonce of sender account
+	Price        *big.Int        // ore per energy
+	EnergyLimit  uint64          // energy limit
+	Recipient    *common.Address `rlp:"nil"` // nil means contract creation
+	Amount       *big.Int        // ore amount
+	Payload      []byte          // contract invocation input data
+	AccessList   AccessList      // accounts and storage slots accessed
+	Signature    []byte          // extended ed448 signature
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *AccessListTx) copy() TxData {
+	cpy := &AccessListTx{
+		NetworkID:    tx.NetworkID,
+		AccountNonce: tx.AccountNonce,
+		EnergyLimit:  tx.EnergyLimit,
+		Recipient:    copyAddressPtr(tx.Recipient),
+		Payload:      common.CopyBytes(tx.Payload),
+		Signature:    common.CopyBytes(tx.Signature),
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Price:      new(big.Int),
+		Amount:     new(big.Int),
+	}
+	for i, tuple := range tx.AccessList {
+		cpy.AccessList[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]common.Hash{}, tuple.StorageKeys...),
+		}
+	}
+	if tx.Price != nil {
+		cpy.Price.Set(tx.Price)
+	}
+	if tx.Amount != nil {
+		cpy.Amount.Set(tx.Amount)
+	}
+	if cpy.Signature == nil {
+		cpy.Signature = []byte{}
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *AccessListTx) txType() byte            { return AccessListTxType }
+func (tx *AccessListTx) networkID() uint         { return tx.NetworkID }
+func (tx *AccessListTx) accessList() AccessList  { return tx.AccessList }
+func (tx *AccessListTx) data() []byte            { return tx.Payload }
+func (tx *AccessListTx) energy() uint64          { return tx.EnergyLimit }
+func (tx *AccessListTx) energyPrice() *big.Int   { return tx.Price }
+func (tx *AccessListTx) value() *big.Int         { return tx.Amount }
+func (tx *AccessListTx) nonce() uint64           { return tx.AccountNonce }
+func (tx *AccessListTx) to() *common.Address     { return tx.Recipient }
+func (tx *AccessListTx) signature() []byte       { return tx.Signature }
+func (tx *AccessListTx) setNetworkID(id uint)    { tx.NetworkID = id }
+func (tx *AccessListTx) setSignature(sig []byte) { tx.Signature = sig }
+
+// accessListTxJSON is the web3 RPC representation of an access list transaction.
+type accessListTxJSON struct {
+	Type         hexutil.Uint64  `json:"type"`
+	NetworkID    *hexutil.Uint64 `json:"network_id"`
+	AccountNonce *hexutil.Uint64 `json:"nonce"`
+	Price        *hexutil.Big    `json:"energyPrice"`
+	EnergyLimit  *hexutil.Uint64 `json:"energy"`
+	Recipient    *common.Address `json:"to"`
+	Amount       *hexutil.Big    `json:"value"`
+	Payload      *hexutil.Bytes  `json:"input"`
+	AccessList   *AccessList     `json:"accessList"`
+	Signature    *hexutil.Bytes  `json:"signature"`
+
+	// These are only used when marshaling to JSON.
+	Hash *common.Hash    `json:"hash,omitempty"`
+	From *common.Address `json:"from,omitempty"`
+}
+
+// marshalJSON encodes the transaction data in the web3 RPC format, along with
+// the derived hash and sender if known.
+func (tx *AccessListTx) marshalJSON(hash *common.Hash, from *common.Address) ([]byte, error) {
+	var (
+		networkID = hexutil.Uint64(tx.NetworkID)
+		nonce     = hexutil.Uint64(tx.AccountNonce)
+		energy    = hexutil.Uint64(tx.EnergyLimit)
+		payload   = hexutil.Bytes(tx.Payload)
+		sig       = hexutil.Bytes(tx.Signature)
+		list      = tx.AccessList
+	)
+	if list == nil {
+		list = AccessList{}
+	}
+	return json.Marshal(&accessListTxJSON{
+		Type:         AccessListTxType,
+		NetworkID:    &networkID,
+		AccountNonce: &nonce,
+		Price:        (*hexutil.Big)(tx.Price),
+		EnergyLimit:  &energy,
+		Recipient:    tx.Recipient,
+		Amount:       (*hexutil.Big)(tx.Amount),
+		Payload:      &payload,
+		AccessList:   &list,
+		Signature:    &sig,
+		Hash:         hash,
+		From:         from,
+	})
+}
+
+// unmarshalJSON decodes the transaction data from the web3 RPC format.
+func (tx *AccessListTx) unmarshalJSON(input []byte) error {
+	var dec accessListTxJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.NetworkID == nil {
+		return errors.New("missing required field 'network_id' for AccessListTx")
+	}
+	if dec.AccountNonce == nil {
+		return errors.New("missing required field 'nonce' for AccessListTx")
+	}
+	if dec.Price == nil {
+		return errors.New("missing required field 'energyPrice' for AccessListTx")
+	}
+	if dec.EnergyLimit == nil {
+		return errors.New("missing required field 'energy' for AccessListTx")
+	}
+	if dec.Amount == nil {
+		return errors.New("missing required field 'value' for AccessListTx")
+	}
+	if dec.Payload == nil {
+		return errors.New("missing required field 'input' for AccessListTx")
+	}
+	if dec.AccessList == nil {
+		return errors.New("missing required field 'accessList' for AccessListTx")
+	}
+	if dec.Signature == nil {
+		return errors.New("missing required field 'signature' for AccessListTx")
+	}
+	*tx = AccessListTx{
+		NetworkID:    uint(*dec.NetworkID),
+		AccountNonce: uint64(*dec.AccountNonce),
+		Price:        (*big.Int)(dec.Price),
+		EnergyLimit:  uint64(*dec.EnergyLimit),
+		Recipient:    dec.Recipient,
+		Amount:       (*big.Int)(dec.Amount),
+		Payload:      *dec.Payload,
+		AccessList:   *dec.AccessList,
+		Signature:    *dec.Signature,
+	}
+	return nil
+}
@@ -146,6 +146,18 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	sha := hasherPool.Get().(crypto.SHA3State)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+	sha.Write([]byte{prefix})
+	rlp.Encode(sha, x)
+	sha.Read(h[:])
+	return h
+}
+
 // EmptyBody returns true if there is no additional 'body' to complete the header
 // that is: no transactions and no uncles.
 func (h *Header) EmptyBody() bool {
@@ -279,7 +291,10 @@ func CopyHeader(h *Header) *Header {
 // DecodeRLP decodes the Core
 func (b *Block) DecodeRLP(s *rlp.Stream) error {
 	var eb extblock
-	_, size, _ := s.Kind()
+	_, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
 	if err := s.Decode(&eb); err != nil {
 		return err
 	}
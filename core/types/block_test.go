@@ -158,3 +158,10 @@ func makeBenchBlock() *Block {
 	}
 	return NewBlock(header, txs, uncles, receipts, newHasher())
 }
+
+func TestBlockDecodeTooLarge(t *testing.T) {
+	var block Block
+	if err := block.DecodeRLP(oversizedStream(0xfb, 128*1024*1024, 1024)); err != rlp.ErrValueTooLarge {
+		t.Errorf("error mismatch: have %v, want %v", err, rlp.ErrValueTooLarge)
+	}
+}
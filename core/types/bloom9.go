@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/common/hexutil"
 	"github.com/core-coin/go-core/v2/crypto"
 )
@@ -158,3 +159,52 @@ func bloomValues(data []byte, hashbuf []byte) (uint, byte, uint, byte, uint, byt
 func BloomLookup(bin Bloom, topic bytesBacked) bool {
 	return bin.Test(topic.Bytes())
 }
+
+// BloomLookupAddress checks whether logs emitted by the given address may be
+// present in the bloom filter.
+func BloomLookupAddress(bin Bloom, addr common.Address) bool {
+	return bin.Test(addr.Bytes())
+}
+
+// BloomLookupTopics checks whether all the given topics may be present in the
+// bloom filter. An empty topic list always matches.
+func BloomLookupTopics(bin Bloom, topics []common.Hash) bool {
+	for _, topic := range topics {
+		if !bin.Test(topic[:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// BloomMatches checks whether the bloom filter may contain logs matching the
+// given criteria, using the semantics of log filter queries: at least one of the
+// addresses has to be present, and for every topic position at least one of the
+// listed topics. Empty address or topic lists act as wildcards.
+func BloomMatches(bin Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		var included bool
+		for _, addr := range addresses {
+			if BloomLookupAddress(bin, addr) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, sub := range topics {
+		included := len(sub) == 0 // empty rule set == wildcard
+		for _, topic := range sub {
+			if bin.Test(topic[:]) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
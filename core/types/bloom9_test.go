@@ -76,6 +76,65 @@ func TestBloomExtensively(t *testing.T) {
 	}
 }
 
+func TestBloomLookupHelpers(t *testing.T) {
+	var (
+		addrA  = common.BytesToAddress([]byte{0x0a})
+		addrB  = common.BytesToAddress([]byte{0x0b})
+		addrC  = common.BytesToAddress([]byte{0x0c})
+		topic1 = common.HexToHash("0x01")
+		topic2 = common.HexToHash("0x02")
+		topic3 = common.HexToHash("0x03")
+		topic4 = common.HexToHash("0x04")
+	)
+	bloom := CreateBloom(Receipts{
+		{Logs: []*Log{{Address: addrA, Topics: []common.Hash{topic1, topic2}}}},
+		{Logs: []*Log{{Address: addrB, Topics: []common.Hash{topic3}}}},
+	})
+	for _, addr := range []common.Address{addrA, addrB} {
+		if !BloomLookupAddress(bloom, addr) {
+			t.Errorf("address %x missing from bloom", addr)
+		}
+	}
+	if BloomLookupAddress(bloom, addrC) {
+		t.Errorf("address %x unexpectedly in bloom", addrC)
+	}
+	topicTests := []struct {
+		topics []common.Hash
+		want   bool
+	}{
+		{nil, true},
+		{[]common.Hash{topic1}, true},
+		{[]common.Hash{topic1, topic2, topic3}, true},
+		{[]common.Hash{topic4}, false},
+		{[]common.Hash{topic1, topic4}, false},
+	}
+	for i, test := range topicTests {
+		if have := BloomLookupTopics(bloom, test.topics); have != test.want {
+			t.Errorf("topics test %d: have %v, want %v", i, have, test.want)
+		}
+	}
+	matchTests := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		want      bool
+	}{
+		{nil, nil, true},
+		{[]common.Address{addrA}, nil, true},
+		{[]common.Address{addrC}, nil, false},
+		{[]common.Address{addrC, addrB}, nil, true},
+		{nil, [][]common.Hash{{topic1}, {}, {topic4, topic3}}, true},
+		{nil, [][]common.Hash{{topic4}}, false},
+		{[]common.Address{addrA}, [][]common.Hash{{topic1}, {topic2}}, true},
+		{[]common.Address{addrA}, [][]common.Hash{{topic1}, {topic4}}, false},
+		{[]common.Address{addrC}, [][]common.Hash{{topic1}}, false},
+	}
+	for i, test := range matchTests {
+		if have := BloomMatches(bloom, test.addresses, test.topics); have != test.want {
+			t.Errorf("match test %d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
 func BenchmarkBloom9(b *testing.B) {
 	test := []byte("testestestest")
 	for i := 0; i < b.N; i++ {
@@ -24,6 +24,8 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 		TxHash               common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress      common.Address `json:"contractAddress"`
 		EnergyUsed           hexutil.Uint64 `json:"energyUsed" gencodec:"required"`
+		EffectiveEnergyPrice *hexutil.Big   `json:"effectiveEnergyPrice"`
+		RevertReason         string         `json:"revertReason,omitempty"`
 		BlockHash            common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber          *hexutil.Big   `json:"blockNumber,omitempty"`
 		TransactionIndex     hexutil.Uint   `json:"transactionIndex"`
@@ -37,6 +39,8 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	enc.TxHash = r.TxHash
 	enc.ContractAddress = r.ContractAddress
 	enc.EnergyUsed = hexutil.Uint64(r.EnergyUsed)
+	enc.EffectiveEnergyPrice = (*hexutil.Big)(r.EffectiveEnergyPrice)
+	enc.RevertReason = r.RevertReason
 	enc.BlockHash = r.BlockHash
 	enc.BlockNumber = (*hexutil.Big)(r.BlockNumber)
 	enc.TransactionIndex = hexutil.Uint(r.TransactionIndex)
@@ -54,6 +58,8 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		TxHash               *common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress      *common.Address `json:"contractAddress"`
 		EnergyUsed           *hexutil.Uint64 `json:"energyUsed" gencodec:"required"`
+		EffectiveEnergyPrice *hexutil.Big    `json:"effectiveEnergyPrice"`
+		RevertReason         *string         `json:"revertReason,omitempty"`
 		BlockHash            *common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber          *hexutil.Big    `json:"blockNumber,omitempty"`
 		TransactionIndex     *hexutil.Uint   `json:"transactionIndex"`
@@ -91,6 +97,12 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'energyUsed' for Receipt")
 	}
 	r.EnergyUsed = uint64(*dec.EnergyUsed)
+	if dec.EffectiveEnergyPrice != nil {
+		r.EffectiveEnergyPrice = (*big.Int)(dec.EffectiveEnergyPrice)
+	}
+	if dec.RevertReason != nil {
+		r.RevertReason = *dec.RevertReason
+	}
 	if dec.BlockHash != nil {
 		r.BlockHash = *dec.BlockHash
 	}
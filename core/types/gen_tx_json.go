@@ -11,11 +11,11 @@ import (
 	"github.com/core-coin/go-core/v2/common/hexutil"
 )
 
-var _ = (*txdataMarshaling)(nil)
+var _ = (*legacyTxMarshaling)(nil)
 
 // MarshalJSON marshals as JSON.
-func (t txdata) MarshalJSON() ([]byte, error) {
-	type txdata struct {
+func (t LegacyTx) MarshalJSON() ([]byte, error) {
+	type LegacyTx struct {
 		AccountNonce hexutil.Uint64  `json:"nonce"    gencodec:"required"`
 		Price        *hexutil.Big    `json:"energyPrice" gencodec:"required"`
 		EnergyLimit  hexutil.Uint64  `json:"energy"      gencodec:"required"`
@@ -25,8 +25,9 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		Payload      hexutil.Bytes   `json:"input"    gencodec:"required"`
 		Signature    hexutil.Bytes   `json:"signature"    gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		From         *common.Address `json:"from,omitempty" rlp:"-"`
 	}
-	var enc txdata
+	var enc LegacyTx
 	enc.AccountNonce = hexutil.Uint64(t.AccountNonce)
 	enc.Price = (*hexutil.Big)(t.Price)
 	enc.EnergyLimit = hexutil.Uint64(t.EnergyLimit)
@@ -36,12 +37,13 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.Payload = t.Payload
 	enc.Signature = t.Signature
 	enc.Hash = t.Hash
+	enc.From = t.From
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
-func (t *txdata) UnmarshalJSON(input []byte) error {
-	type txdata struct {
+func (t *LegacyTx) UnmarshalJSON(input []byte) error {
+	type LegacyTx struct {
 		AccountNonce *hexutil.Uint64 `json:"nonce"    gencodec:"required"`
 		Price        *hexutil.Big    `json:"energyPrice" gencodec:"required"`
 		EnergyLimit  *hexutil.Uint64 `json:"energy"      gencodec:"required"`
@@ -51,44 +53,48 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		Payload      *hexutil.Bytes  `json:"input"    gencodec:"required"`
 		Signature    *hexutil.Bytes  `json:"signature"    gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		From         *common.Address `json:"from,omitempty" rlp:"-"`
 	}
-	var dec txdata
+	var dec LegacyTx
 	if err := json.Unmarshal(input, &dec); err != nil {
 		return err
 	}
 	if dec.AccountNonce == nil {
-		return errors.New("missing required field 'nonce' for txdata")
+		return errors.New("missing required field 'nonce' for LegacyTx")
 	}
 	t.AccountNonce = uint64(*dec.AccountNonce)
 	if dec.Price == nil {
-		return errors.New("missing required field 'energyPrice' for txdata")
+		return errors.New("missing required field 'energyPrice' for LegacyTx")
 	}
 	t.Price = (*big.Int)(dec.Price)
 	if dec.EnergyLimit == nil {
-		return errors.New("missing required field 'energy' for txdata")
+		return errors.New("missing required field 'energy' for LegacyTx")
 	}
 	t.EnergyLimit = uint64(*dec.EnergyLimit)
 	if dec.NetworkID == nil {
-		return errors.New("missing required field 'network_id' for txdata")
+		return errors.New("missing required field 'network_id' for LegacyTx")
 	}
 	t.NetworkID = uint(*dec.NetworkID)
 	if dec.Recipient != nil {
 		t.Recipient = dec.Recipient
 	}
 	if dec.Amount == nil {
-		return errors.New("missing required field 'value' for txdata")
+		return errors.New("missing required field 'value' for LegacyTx")
 	}
 	t.Amount = (*big.Int)(dec.Amount)
 	if dec.Payload == nil {
-		return errors.New("missing required field 'input' for txdata")
+		return errors.New("missing required field 'input' for LegacyTx")
 	}
 	t.Payload = *dec.Payload
 	if dec.Signature == nil {
-		return errors.New("missing required field 'signature' for txdata")
+		return errors.New("missing required field 'signature' for LegacyTx")
 	}
 	t.Signature = *dec.Signature
 	if dec.Hash != nil {
 		t.Hash = dec.Hash
 	}
+	if dec.From != nil {
+		t.From = dec.From
+	}
 	return nil
 }
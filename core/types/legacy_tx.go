@@ -0,0 +1,100 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/hexutil"
+)
+
+//go:generate gencodec -type LegacyTx -field-override legacyTxMarshaling -out gen_tx_json.go
+
+// LegacyTx is the transaction data of regular Core transactions.
+type LegacyTx struct {
+	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
+	Price        *big.Int        `json:"energyPrice" gencodec:"required"`
+	EnergyLimit  uint64          `json:"energy"      gencodec:"required"`
+	NetworkID    uint            `json:"network_id" gencodec:"required"`
+	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
+	Amount       *big.Int        `json:"value"    gencodec:"required"`
+	Payload      []byte          `json:"input"    gencodec:"required"`
+	Signature    []byte          `json:"signature"    gencodec:"required"`
+
+	// These are only used when marshaling to JSON.
+	Hash *common.Hash    `json:"hash" rlp:"-"`
+	From *common.Address `json:"from,omitempty" rlp:"-"`
+}
+
+type legacyTxMarshaling struct {
+	AccountNonce hexutil.Uint64
+	Price        *hexutil.Big
+	EnergyLimit  hexutil.Uint64
+	NetworkID    hexutil.Uint64
+	Signature    hexutil.Bytes
+	Amount       *hexutil.Big
+	Payload      hexutil.Bytes
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *LegacyTx) copy() TxData {
+	cpy := &LegacyTx{
+		AccountNonce: tx.AccountNonce,
+		EnergyLimit:  tx.EnergyLimit,
+		NetworkID:    tx.NetworkID,
+		Recipient:    copyAddressPtr(tx.Recipient),
+		Payload:      common.CopyBytes(tx.Payload),
+		Signature:    common.CopyBytes(tx.Signature),
+		// These are initialized below.
+		Price:  new(big.Int),
+		Amount: new(big.Int),
+	}
+	if tx.Price != nil {
+		cpy.Price.Set(tx.Price)
+	}
+	if tx.Amount != nil {
+		cpy.Amount.Set(tx.Amount)
+	}
+	if cpy.Signature == nil {
+		cpy.Signature = []byte{}
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *LegacyTx) txType() byte            { return LegacyTxType }
+func (tx *LegacyTx) networkID() uint         { return tx.NetworkID }
+func (tx *LegacyTx) accessList() AccessList  { return nil }
+func (tx *LegacyTx) data() []byte            { return tx.Payload }
+func (tx *LegacyTx) energy() uint64          { return tx.EnergyLimit }
+func (tx *LegacyTx) energyPrice() *big.Int   { return tx.Price }
+func (tx *LegacyTx) value() *big.Int         { return tx.Amount }
+func (tx *LegacyTx) nonce() uint64           { return tx.AccountNonce }
+func (tx *LegacyTx) to() *common.Address     { return tx.Recipient }
+func (tx *LegacyTx) signature() []byte       { return tx.Signature }
+func (tx *LegacyTx) setNetworkID(id uint)    { tx.NetworkID = id }
+func (tx *LegacyTx) setSignature(sig []byte) { tx.Signature = sig }
+
+// copyAddressPtr copies an address.
+func copyAddressPtr(a *common.Address) *common.Address {
+	if a == nil {
+		return nil
+	}
+	cpy := *a
+	return &cpy
+}
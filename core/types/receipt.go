@@ -57,9 +57,11 @@ type Receipt struct {
 
 	// Implementation fields: These fields are added by gocore when processing a transaction.
 	// They are stored in the chain database.
-	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
-	ContractAddress common.Address `json:"contractAddress"`
-	EnergyUsed      uint64         `json:"energyUsed" gencodec:"required"`
+	TxHash               common.Hash    `json:"transactionHash" gencodec:"required"`
+	ContractAddress      common.Address `json:"contractAddress"`
+	EnergyUsed           uint64         `json:"energyUsed" gencodec:"required"`
+	EffectiveEnergyPrice *big.Int       `json:"effectiveEnergyPrice"`   // Price per unit of energy actually paid, derived from the transaction
+	RevertReason         string         `json:"revertReason,omitempty"` // Reason of a failed transaction which reverted with Error(string), derived on retrieval and not stored
 
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
@@ -73,6 +75,7 @@ type receiptMarshaling struct {
 	Status               hexutil.Uint64
 	CumulativeEnergyUsed hexutil.Uint64
 	EnergyUsed           hexutil.Uint64
+	EffectiveEnergyPrice *hexutil.Big
 	BlockNumber          *hexutil.Big
 	TransactionIndex     hexutil.Uint
 }
@@ -295,7 +298,7 @@ func (r Receipts) GetRlp(i int) []byte {
 // DeriveFields fills the receipts with their computed fields based on consensus
 // data and contextual infos like containing block and transactions.
 func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, number uint64, txs Transactions) error {
-	signer := MakeSigner(config.NetworkID)
+	signer := MakeBlockSigner(config, new(big.Int).SetUint64(number))
 
 	logIndex := uint(0)
 	if len(txs) != len(r) {
@@ -305,6 +308,9 @@ func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, num
 		// The transaction hash can be retrieved from the transaction itself
 		r[i].TxHash = txs[i].Hash()
 
+		// Without a base fee, the whole energy price of the transaction is paid
+		r[i].EffectiveEnergyPrice = txs[i].EnergyPrice()
+
 		// block location fields
 		r[i].BlockHash = hash
 		r[i].BlockNumber = new(big.Int).SetUint64(number)
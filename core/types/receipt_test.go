@@ -19,6 +19,7 @@ package types
 import (
 	"bytes"
 	crand "crypto/rand"
+	"encoding/json"
 	"math"
 	"math/big"
 	"reflect"
@@ -228,6 +229,9 @@ func TestDeriveFields(t *testing.T) {
 		if receipts[i].TransactionIndex != uint(i) {
 			t.Errorf("receipts[%d].TransactionIndex = %d, want %d", i, receipts[i].TransactionIndex, i)
 		}
+		if receipts[i].EffectiveEnergyPrice.Cmp(txs[i].EnergyPrice()) != 0 {
+			t.Errorf("receipts[%d].EffectiveEnergyPrice = %v, want %v", i, receipts[i].EffectiveEnergyPrice, txs[i].EnergyPrice())
+		}
 		if receipts[i].EnergyUsed != txs[i].Energy() {
 			t.Errorf("receipts[%d].EnergyUsed = %d, want %d", i, receipts[i].EnergyUsed, txs[i].Energy())
 		}
@@ -263,6 +267,83 @@ func TestDeriveFields(t *testing.T) {
 	}
 }
 
+// Tests that the effective energy price is derived instead of being encoded, and
+// matches the energy price of legacy transactions.
+func TestReceiptEffectiveEnergyPrice(t *testing.T) {
+	tx := NewTransaction(1, address, big.NewInt(1), 21000, big.NewInt(7), nil)
+	receipt := &Receipt{
+		Status:               ReceiptStatusSuccessful,
+		CumulativeEnergyUsed: 21000,
+		Logs:                 []*Log{},
+		TxHash:               tx.Hash(),
+		EnergyUsed:           21000,
+		EffectiveEnergyPrice: big.NewInt(7),
+	}
+	// The field must not change the consensus or storage encodings
+	plain := *receipt
+	plain.EffectiveEnergyPrice = nil
+	for _, pair := range [][2]interface{}{{receipt, &plain}, {(*ReceiptForStorage)(receipt), (*ReceiptForStorage)(&plain)}} {
+		have, err := rlp.EncodeToBytes(pair[0])
+		if err != nil {
+			t.Fatalf("failed to encode receipt: %v", err)
+		}
+		want, _ := rlp.EncodeToBytes(pair[1])
+		if !bytes.Equal(have, want) {
+			t.Fatalf("encoding changed by effective energy price: have %x, want %x", have, want)
+		}
+	}
+	// Decoding the stored receipt and deriving the fields must restore it
+	enc, _ := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	var dec ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if err := (Receipts{(*Receipt)(&dec)}).DeriveFields(params.MainnetChainConfig, common.Hash{}, 0, Transactions{tx}); err != nil {
+		t.Fatalf("failed to derive fields: %v", err)
+	}
+	if dec.EffectiveEnergyPrice == nil || dec.EffectiveEnergyPrice.Cmp(tx.EnergyPrice()) != 0 {
+		t.Fatalf("effective energy price mismatch: have %v, want %v", dec.EffectiveEnergyPrice, tx.EnergyPrice())
+	}
+	// The JSON representation carries the field
+	blob, err := json.Marshal(receipt)
+	if err != nil {
+		t.Fatalf("failed to marshal receipt: %v", err)
+	}
+	var decJSON Receipt
+	if err := json.Unmarshal(blob, &decJSON); err != nil {
+		t.Fatalf("failed to unmarshal receipt: %v", err)
+	}
+	if decJSON.EffectiveEnergyPrice == nil || decJSON.EffectiveEnergyPrice.Cmp(receipt.EffectiveEnergyPrice) != 0 {
+		t.Fatalf("JSON effective energy price mismatch: have %v, want %v", decJSON.EffectiveEnergyPrice, receipt.EffectiveEnergyPrice)
+	}
+}
+
+func TestReceiptRevertReasonStorage(t *testing.T) {
+	receipt := &Receipt{
+		Status:               ReceiptStatusFailed,
+		CumulativeEnergyUsed: 21000,
+		Logs:                 []*Log{},
+		RevertReason:         "insufficient funds",
+	}
+	// The reason is derived on retrieval, it must not leak into any encoding
+	plain := *receipt
+	plain.RevertReason = ""
+
+	have, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	want, _ := encodeAsStoredReceiptRLP(&plain)
+	if !bytes.Equal(have, want) {
+		t.Fatalf("storage encoding changed by revert reason: have %x, want %x", have, want)
+	}
+	have, _ = rlp.EncodeToBytes(receipt)
+	want, _ = rlp.EncodeToBytes(&plain)
+	if !bytes.Equal(have, want) {
+		t.Fatalf("consensus encoding changed by revert reason: have %x, want %x", have, want)
+	}
+}
+
 func clearComputedFieldsOnReceipts(t *testing.T, receipts Receipts) {
 	t.Helper()
 
@@ -280,6 +361,7 @@ func clearComputedFieldsOnReceipt(t *testing.T, receipt *Receipt) {
 	receipt.TransactionIndex = math.MaxUint32
 	receipt.ContractAddress = common.Address{}
 	receipt.EnergyUsed = 0
+	receipt.EffectiveEnergyPrice = nil
 
 	clearComputedFieldsOnLogs(t, receipt.Logs)
 }
@@ -17,8 +17,11 @@
 package types
 
 import (
+	"bytes"
 	"container/heap"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"sync/atomic"
@@ -26,18 +29,28 @@ import (
 
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/common/hexutil"
+	"github.com/core-coin/go-core/v2/crypto"
 	"github.com/core-coin/go-core/v2/rlp"
 )
 
-//go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
-
 var (
-	ErrInvalidSig = errors.New("invalid transaction values")
+	ErrInvalidSig         = errors.New("invalid transaction values")
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	ErrTxTooLarge         = errors.New("transaction too large")
+	errEmptyTypedTx       = errors.New("empty typed transaction bytes")
+)
+
+// Transaction types.
+const (
+	LegacyTxType = iota
+	AccessListTxType
 )
 
+// Transaction is a Core transaction. The zero value is an empty legacy
+// transaction.
 type Transaction struct {
-	data txdata    // Consensus contents of a transaction
-	time time.Time // Time first seen locally (spam avoidance)
+	inner TxData    // Consensus contents of a transaction
+	time  time.Time // Time first seen locally (spam avoidance)
 
 	// caches
 	hash atomic.Value
@@ -45,28 +58,32 @@ type Transaction struct {
 	from atomic.Value
 }
 
-type txdata struct {
-	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
-	Price        *big.Int        `json:"energyPrice" gencodec:"required"`
-	EnergyLimit  uint64          `json:"energy"      gencodec:"required"`
-	NetworkID    uint            `json:"network_id" gencodec:"required"`
-	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
-	Amount       *big.Int        `json:"value"    gencodec:"required"`
-	Payload      []byte          `json:"input"    gencodec:"required"`
-	Signature    []byte          `json:"signature"    gencodec:"required"`
-
-	// This is only used when marshaling to JSON.
-	Hash *common.Hash `json:"hash" rlp:"-"`
+// NewTx creates a new transaction.
+func NewTx(inner TxData) *Transaction {
+	tx := new(Transaction)
+	tx.setDecoded(inner.copy(), 0)
+	return tx
 }
 
-type txdataMarshaling struct {
-	AccountNonce hexutil.Uint64
-	Price        *hexutil.Big
-	EnergyLimit  hexutil.Uint64
-	NetworkID    hexutil.Uint64
-	Signature    hexutil.Bytes
-	Amount       *hexutil.Big
-	Payload      hexutil.Bytes
+// TxData is the underlying data of a transaction.
+//
+// This is implemented by LegacyTx and AccessListTx.
+type TxData interface {
+	txType() byte // returns the type ID
+	copy() TxData // creates a deep copy and initializes all fields
+
+	networkID() uint
+	accessList() AccessList
+	data() []byte
+	energy() uint64
+	energyPrice() *big.Int
+	value() *big.Int
+	nonce() uint64
+	to() *common.Address
+	signature() []byte
+
+	setNetworkID(networkID uint)
+	setSignature(sig []byte)
 }
 
 func NewTransaction(nonce uint64, to common.Address, amount *big.Int, energyLimit uint64, energyPrice *big.Int, data []byte) *Transaction {
@@ -81,7 +98,7 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, energyLim
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
 	}
-	d := txdata{
+	d := LegacyTx{
 		AccountNonce: nonce,
 		Price:        new(big.Int),
 		EnergyLimit:  energyLimit,
@@ -98,71 +115,268 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, energyLim
 		d.Price.Set(energyPrice)
 	}
 	return &Transaction{
-		data: d,
-		time: time.Now(),
+		inner: &d,
+		time:  time.Now(),
 	}
 }
 
-// EncodeRLP implements rlp.Encoder
+// EncodeRLP implements rlp.Encoder. Legacy transactions are encoded as a plain
+// RLP list, typed ones are wrapped into an RLP string holding the envelope.
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.txdata())
+	}
+	buf := new(bytes.Buffer)
+	if err := tx.encodeTyped(buf); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
+}
+
+// encodeTyped writes the canonical encoding of a typed transaction to w.
+func (tx *Transaction) encodeTyped(w *bytes.Buffer) error {
+	w.WriteByte(tx.Type())
+	return rlp.Encode(w, tx.txdata())
+}
+
+// MarshalBinary returns the canonical encoding of the transaction. For legacy
+// transactions, it returns the RLP encoding. For typed transactions, it returns
+// the type byte followed by the RLP encoding of the payload.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.txdata())
+	}
+	var buf bytes.Buffer
+	err := tx.encodeTyped(&buf)
+	return buf.Bytes(), err
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
-		tx.time = time.Now()
+	kind, _, err := s.Kind()
+	switch {
+	case err != nil:
+		return err
+	case kind == rlp.List:
+		// It's a legacy transaction. Its hash is the one of the raw encoding,
+		// which is retained to avoid encoding it again.
+		raw, err := s.Raw()
+		if err != nil {
+			return err
+		}
+		var inner LegacyTx
+		if err := rlp.DecodeBytes(raw, &inner); err != nil {
+			return err
+		}
+		tx.setDecoded(&inner, len(raw))
+		tx.hash.Store(crypto.SHA3Hash(raw))
+		return nil
+	case kind == rlp.String:
+		// It's a typed transaction envelope.
+		var b []byte
+		if b, err = s.Bytes(); err != nil {
+			return err
+		}
+		inner, err := tx.decodeTyped(b)
+		if err == nil {
+			tx.setDecoded(inner, len(b))
+			tx.hash.Store(crypto.SHA3Hash(b))
+		}
+		return err
+	default:
+		return rlp.ErrExpectedList
+	}
+}
+
+// UnmarshalBinary decodes the canonical encoding of transactions.
+// It supports legacy RLP transactions and typed envelopes.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		// It's a legacy transaction.
+		var data LegacyTx
+		err := rlp.DecodeBytes(b, &data)
+		if err != nil {
+			return err
+		}
+		tx.setDecoded(&data, len(b))
+		tx.hash.Store(crypto.SHA3Hash(b))
+		return nil
+	}
+	// It's a typed transaction envelope.
+	inner, err := tx.decodeTyped(b)
+	if err != nil {
+		return err
+	}
+	tx.setDecoded(inner, len(b))
+	tx.hash.Store(crypto.SHA3Hash(b))
+	return nil
+}
+
+// decodeTyped decodes a typed transaction from the canonical format. The first
+// byte selects the decoder of the payload.
+func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
+	if len(b) == 0 {
+		return nil, errEmptyTypedTx
+	}
+	switch b[0] {
+	case AccessListTxType:
+		var inner AccessListTx
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
+}
+
+// DecodeTransactions decodes an RLP list of transactions from s, as received
+// from untrusted sources. Transactions with an encoding larger than maxSize are
+// rejected with ErrTxTooLarge before their contents are read.
+func DecodeTransactions(s *rlp.Stream, maxSize uint64) ([]*Transaction, error) {
+	if _, err := s.List(); err != nil {
+		return nil, err
+	}
+	var txs []*Transaction
+	for {
+		kind, size, err := s.Kind()
+		if err == rlp.EOL {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		// Legacy transactions are encoded as lists, their size includes the
+		// list header. Typed ones are the contents of a string.
+		if kind == rlp.List {
+			size = rlp.ListSize(size)
+		}
+		if size > maxSize {
+			return nil, fmt.Errorf("%w: transaction %d, size %d > %d", ErrTxTooLarge, len(txs), size, maxSize)
+		}
+		tx := new(Transaction)
+		if err := s.Decode(tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, s.ListEnd()
+}
+
+// setDecoded sets the inner transaction and size after decoding. The sender
+// cached for the previous contents, if any, is invalidated. Decoders set the
+// hash from the encoding they decoded, NewTx leaves it to Hash.
+func (tx *Transaction) setDecoded(inner TxData, size int) {
+	tx.inner = inner
+	tx.time = time.Now()
+	if size > 0 {
+		tx.size.Store(common.StorageSize(size))
+	}
+	tx.from = atomic.Value{}
+}
+
+// txdata returns the consensus contents of the transaction. The zero value
+// transaction has none, it is treated as an empty legacy transaction.
+func (tx *Transaction) txdata() TxData {
+	if tx.inner == nil {
+		return &LegacyTx{Price: new(big.Int), Amount: new(big.Int), Signature: []byte{}}
 	}
-	return err
+	return tx.inner
 }
 
-// MarshalJSON encodes the web3 RPC transaction format.
+// MarshalJSON encodes the web3 RPC transaction format. Besides the consensus
+// fields, the transaction hash is included, along with the sender if it was
+// already derived by a signer.
 func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
-	data := tx.data
-	data.Hash = &hash
-	return data.MarshalJSON()
+
+	var from *common.Address
+	if sc := tx.from.Load(); sc != nil && sc.(sigCache).signer != nil {
+		addr := sc.(sigCache).from
+		from = &addr
+	}
+	switch inner := tx.txdata().(type) {
+	case *LegacyTx:
+		data := *inner
+		data.Hash, data.From = &hash, from
+		return data.MarshalJSON()
+	case *AccessListTx:
+		return inner.marshalJSON(&hash, from)
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
 }
 
-// UnmarshalJSON decodes the web3 RPC transaction format.
+// UnmarshalJSON decodes the web3 RPC transaction format. Derived fields (hash
+// and sender) are ignored, they are always recomputed from the consensus fields.
+// Transactions without a type field are decoded as legacy ones.
 func (tx *Transaction) UnmarshalJSON(input []byte) error {
-	var dec txdata
-	if err := dec.UnmarshalJSON(input); err != nil {
+	var kind struct {
+		Type *hexutil.Uint64 `json:"type"`
+	}
+	if err := json.Unmarshal(input, &kind); err != nil {
 		return err
 	}
+	var inner TxData
+	switch {
+	case kind.Type == nil || *kind.Type == LegacyTxType:
+		var dec LegacyTx
+		if err := dec.UnmarshalJSON(input); err != nil {
+			return err
+		}
+		dec.Hash, dec.From = nil, nil
+		inner = &dec
+	case *kind.Type == AccessListTxType:
+		var dec AccessListTx
+		if err := dec.unmarshalJSON(input); err != nil {
+			return err
+		}
+		inner = &dec
+	default:
+		return ErrTxTypeNotSupported
+	}
 	*tx = Transaction{
-		data: dec,
-		time: time.Now(),
+		inner: inner,
+		time:  time.Now(),
 	}
 	return nil
 }
 
-func (tx *Transaction) Data() []byte          { return common.CopyBytes(tx.data.Payload) }
-func (tx *Transaction) Energy() uint64        { return tx.data.EnergyLimit }
-func (tx *Transaction) EnergyPrice() *big.Int { return new(big.Int).Set(tx.data.Price) }
+// Type returns the transaction type.
+func (tx *Transaction) Type() uint8 { return tx.txdata().txType() }
+
+func (tx *Transaction) Data() []byte          { return common.CopyBytes(tx.txdata().data()) }
+func (tx *Transaction) Energy() uint64        { return tx.txdata().energy() }
+func (tx *Transaction) EnergyPrice() *big.Int { return new(big.Int).Set(tx.txdata().energyPrice()) }
 func (tx *Transaction) EnergyPriceCmp(other *Transaction) int {
-	return tx.data.Price.Cmp(other.data.Price)
+	return tx.txdata().energyPrice().Cmp(other.txdata().energyPrice())
 }
 func (tx *Transaction) EnergyPriceIntCmp(other *big.Int) int {
-	return tx.data.Price.Cmp(other)
+	return tx.txdata().energyPrice().Cmp(other)
+}
+func (tx *Transaction) Value() *big.Int        { return new(big.Int).Set(tx.txdata().value()) }
+func (tx *Transaction) AccessList() AccessList { return tx.txdata().accessList() }
+func (tx *Transaction) NetworkID() uint        { return tx.txdata().networkID() }
+func (tx *Transaction) Nonce() uint64          { return tx.txdata().nonce() }
+func (tx *Transaction) CheckNonce() bool       { return true }
+func (tx *Transaction) Signature() []byte      { return tx.txdata().signature() }
+
+// SetNetworkID changes the network of the transaction. The cached hash, size and
+// sender derive from the previous contents, so they are dropped.
+func (tx *Transaction) SetNetworkID(networkID uint) {
+	if tx.inner == nil {
+		tx.inner = tx.txdata()
+	}
+	tx.inner.setNetworkID(networkID)
+	tx.hash = atomic.Value{}
+	tx.size = atomic.Value{}
+	tx.from = atomic.Value{}
 }
-func (tx *Transaction) Value() *big.Int             { return new(big.Int).Set(tx.data.Amount) }
-func (tx *Transaction) NetworkID() uint             { return tx.data.NetworkID }
-func (tx *Transaction) Nonce() uint64               { return tx.data.AccountNonce }
-func (tx *Transaction) CheckNonce() bool            { return true }
-func (tx *Transaction) Signature() []byte           { return tx.data.Signature }
-func (tx *Transaction) SetNetworkID(networkID uint) { tx.data.NetworkID = networkID }
 
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
-	if tx.data.Recipient == nil {
+	if tx.txdata().to() == nil {
 		return nil
 	}
-	to := *tx.data.Recipient
+	to := *tx.txdata().to()
 	return &to
 }
 
@@ -172,7 +386,12 @@ func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx.txdata())
+	} else {
+		v = prefixedRlpHash(tx.Type(), tx.txdata())
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -184,7 +403,10 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, tx.txdata())
+	if tx.Type() != LegacyTxType {
+		c++ // type byte
+	}
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -196,12 +418,13 @@ func (tx *Transaction) Size() common.StorageSize {
 // XXX Rename message to something less arbitrary?
 func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 	msg := Message{
-		nonce:       tx.data.AccountNonce,
-		energyLimit: tx.data.EnergyLimit,
-		energyPrice: new(big.Int).Set(tx.data.Price),
-		to:          tx.data.Recipient,
-		amount:      tx.data.Amount,
-		data:        tx.data.Payload,
+		nonce:       tx.txdata().nonce(),
+		energyLimit: tx.txdata().energy(),
+		energyPrice: new(big.Int).Set(tx.txdata().energyPrice()),
+		to:          tx.txdata().to(),
+		amount:      tx.txdata().value(),
+		data:        tx.txdata().data(),
+		accessList:  tx.txdata().accessList(),
 		checkNonce:  true,
 	}
 
@@ -212,19 +435,16 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 
 // WithSignature returns a new transaction with the given signature.
 func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, error) {
-	cpy := &Transaction{
-		data: tx.data,
-		time: tx.time,
-	}
-	cpy.data.NetworkID = uint(signer.NetworkID())
-	cpy.data.Signature = sig[:]
-	return cpy, nil
+	cpy := tx.txdata().copy()
+	cpy.setNetworkID(uint(signer.NetworkID()))
+	cpy.setSignature(sig)
+	return &Transaction{inner: cpy, time: tx.time}, nil
 }
 
 // Cost returns amount + energyprice * energylimit.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.EnergyLimit))
-	total.Add(total, tx.data.Amount)
+	total := new(big.Int).Mul(tx.txdata().energyPrice(), new(big.Int).SetUint64(tx.txdata().energy()))
+	total.Add(total, tx.txdata().value())
 	return total
 }
 
@@ -267,7 +487,7 @@ func TxDifference(a, b Transactions) Transactions {
 type TxByNonce Transactions
 
 func (s TxByNonce) Len() int           { return len(s) }
-func (s TxByNonce) Less(i, j int) bool { return s[i].data.AccountNonce < s[j].data.AccountNonce }
+func (s TxByNonce) Less(i, j int) bool { return s[i].Nonce() < s[j].Nonce() }
 func (s TxByNonce) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
 // TxByPriceAndTime implements both the sort and the heap interface, making it useful
@@ -278,7 +498,7 @@ func (s TxByPriceAndTime) Len() int { return len(s) }
 func (s TxByPriceAndTime) Less(i, j int) bool {
 	// If the prices are equal, use the time the transaction was first seen for
 	// deterministic sorting
-	cmp := s[i].data.Price.Cmp(s[j].data.Price)
+	cmp := s[i].txdata().energyPrice().Cmp(s[j].txdata().energyPrice())
 	if cmp == 0 {
 		return s[i].time.Before(s[j].time)
 	}
@@ -371,6 +591,7 @@ type Message struct {
 	energyLimit uint64
 	energyPrice *big.Int
 	data        []byte
+	accessList  AccessList
 	checkNonce  bool
 }
 
@@ -387,11 +608,12 @@ func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *b
 	}
 }
 
-func (m Message) From() common.Address  { return m.from }
-func (m Message) To() *common.Address   { return m.to }
-func (m Message) EnergyPrice() *big.Int { return m.energyPrice }
-func (m Message) Value() *big.Int       { return m.amount }
-func (m Message) Energy() uint64        { return m.energyLimit }
-func (m Message) Nonce() uint64         { return m.nonce }
-func (m Message) Data() []byte          { return m.data }
-func (m Message) CheckNonce() bool      { return m.checkNonce }
+func (m Message) From() common.Address   { return m.from }
+func (m Message) To() *common.Address    { return m.to }
+func (m Message) EnergyPrice() *big.Int  { return m.energyPrice }
+func (m Message) Value() *big.Int        { return m.amount }
+func (m Message) Energy() uint64         { return m.energyLimit }
+func (m Message) Nonce() uint64          { return m.nonce }
+func (m Message) Data() []byte           { return m.data }
+func (m Message) AccessList() AccessList { return m.accessList }
+func (m Message) CheckNonce() bool       { return m.checkNonce }
@@ -18,6 +18,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/core-coin/go-core/v2/common"
@@ -36,15 +37,36 @@ type sigCache struct {
 	from   common.Address
 }
 
-// MakeSigner returns a Signer based on network ID.
+// MakeSigner returns a Signer based on network ID. The signer only accepts
+// legacy transactions, use MakeBlockSigner for the ones valid in a block.
 func MakeSigner(networkID *big.Int) Signer {
 	var signer = NewNucleusSigner(networkID)
 	return signer
 }
 
+// MakeBlockSigner returns a Signer based on the given chain config and block
+// number, accepting the transaction types enabled at that block.
+func MakeBlockSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
+	if config.IsAccessList(blockNumber) {
+		return NewAccessListSigner(config.NetworkID)
+	}
+	return NewNucleusSigner(config.NetworkID)
+}
+
+// LatestSigner returns the most permissive Signer available for the given chain
+// config, accepting the transaction types of all forks scheduled in it. Use it
+// in transaction-handling code where the current block number is unknown, the
+// block validation still applies the Signer of MakeBlockSigner.
+func LatestSigner(config *params.ChainConfig) Signer {
+	if config.AccessListBlock != nil {
+		return NewAccessListSigner(config.NetworkID)
+	}
+	return NewNucleusSigner(config.NetworkID)
+}
+
 // SignTx signs the transaction using the given signer and private key
 func SignTx(tx *Transaction, s Signer, prv *crypto.PrivateKey) (*Transaction, error) {
-	tx.data.NetworkID = uint(s.NetworkID())
+	tx.SetNetworkID(uint(s.NetworkID()))
 	h := s.Hash(tx)
 	sig, err := crypto.Sign(h[:], prv)
 	if err != nil {
@@ -53,6 +75,27 @@ func SignTx(tx *Transaction, s Signer, prv *crypto.PrivateKey) (*Transaction, er
 	return tx.WithSignature(s, sig)
 }
 
+// WithSignatureBytes attaches a signature created outside of the node, e.g. by
+// an offline or hardware signer, to the transaction. The signature has to be
+// made over signer.Hash(tx) and be in the extended form of crypto.Sign, that is
+// the Ed448 signature followed by the public key of the signer.
+//
+// Contrary to WithSignature, the signature is verified. The recovered sender is
+// cached on the returned transaction.
+func (tx *Transaction) WithSignatureBytes(signer Signer, sig []byte) (*Transaction, error) {
+	if len(sig) != crypto.ExtendedSignatureLength {
+		return nil, fmt.Errorf("%w: signature length %d, want %d", ErrInvalidSig, len(sig), crypto.ExtendedSignatureLength)
+	}
+	signed, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Sender(signer, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
 // Sender returns the address derived from the signature (V, R, S) using ed448
 // elliptic curve and an error if it failed deriving or upon an incorrect
 // signature.
@@ -61,12 +104,27 @@ func SignTx(tx *Transaction, s Signer, prv *crypto.PrivateKey) (*Transaction, er
 // signing method. The cache is invalidated if the cached signer does
 // not match the signer used in the current call.
 func Sender(signer Signer, tx *Transaction) (common.Address, error) {
+	return tx.SenderCached(signer)
+}
+
+// SenderCached returns the sender of the transaction as derived by the given
+// signer. The first successful derivation is memoized on the transaction along
+// with the signer identity, so repeated calls from the transaction pool and the
+// block processor don't redo the expensive signature verification. Calling it
+// with a different signer invalidates the cached sender.
+//
+// Signers of the same network share the cached sender regardless of the forks
+// they are configured for, the transaction type is checked on every call.
+func (tx *Transaction) SenderCached(signer Signer) (common.Address, error) {
+	if checker, ok := signer.(txTypeChecker); ok && !checker.supportsType(tx.Type()) {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	if sc := tx.from.Load(); sc != nil {
 		sigCache := sc.(sigCache)
 		// If the signer used to derive from in a previous
 		// call is not the same as used current, invalidate
 		// the cache.
-		if sigCache.signer.Equal(signer) {
+		if sigCache.signer != nil && sigCache.signer.Equal(signer) {
 			return sigCache.from, nil
 		}
 	}
@@ -92,11 +150,19 @@ type Signer interface {
 	NetworkID() int
 }
 
+// txTypeChecker is implemented by signers only accepting some transaction types.
+type txTypeChecker interface {
+	supportsType(txType uint8) bool
+}
+
 // NucleusSigner implements Signer with network id.
 type NucleusSigner struct {
-	networkId *big.Int
+	networkId  *big.Int
+	accessList bool // Whether access list transactions are accepted
 }
 
+// NewNucleusSigner returns a signer accepting legacy transactions only, as valid
+// before the access list transactions fork.
 func NewNucleusSigner(networkId *big.Int) NucleusSigner {
 	if networkId == nil {
 		panic("networkID must be set")
@@ -106,20 +172,38 @@ func NewNucleusSigner(networkId *big.Int) NucleusSigner {
 	}
 }
 
+// NewAccessListSigner returns a signer accepting legacy and access list
+// transactions, as valid from the access list transactions fork on.
+func NewAccessListSigner(networkId *big.Int) NucleusSigner {
+	signer := NewNucleusSigner(networkId)
+	signer.accessList = true
+	return signer
+}
+
+// Equal returns whether the given signer is a NucleusSigner of the same network.
+// The accepted transaction types are not compared, as they don't change the
+// sender derived from a transaction.
 func (s NucleusSigner) Equal(s2 Signer) bool {
 	nucleus, ok := s2.(NucleusSigner)
 	return ok && nucleus.networkId.Cmp(s.networkId) == 0
 }
 
+func (s NucleusSigner) supportsType(txType uint8) bool {
+	return txType == LegacyTxType || (txType == AccessListTxType && s.accessList)
+}
+
 func (s NucleusSigner) Sender(tx *Transaction) (common.Address, error) {
-	if tx.data.Recipient != nil {
-		err := common.VerifyAddress(*tx.data.Recipient)
+	if !s.supportsType(tx.Type()) {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	if to := tx.txdata().to(); to != nil {
+		err := common.VerifyAddress(*to)
 		if err != nil {
 			return common.Address{}, err
 		}
 	}
 
-	if tx.Hash().Hex() != params.ZeroNetworkIDTxHash.Hex() && tx.data.NetworkID != uint(s.networkId.Int64()) {
+	if tx.Hash().Hex() != params.ZeroNetworkIDTxHash.Hex() && tx.txdata().networkID() != uint(s.networkId.Int64()) {
 		return common.Address{}, ErrInvalidNetworkId
 	}
 	return recoverPlain(s, tx)
@@ -132,22 +216,35 @@ func (s NucleusSigner) NetworkID() int {
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s NucleusSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() == AccessListTxType {
+		return prefixedRlpHash(tx.Type(), []interface{}{
+			tx.txdata().networkID(),
+			tx.txdata().nonce(),
+			tx.txdata().energyPrice(),
+			tx.txdata().energy(),
+			tx.txdata().to(),
+			tx.txdata().value(),
+			tx.txdata().data(),
+			tx.txdata().accessList(),
+		})
+	}
 	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.EnergyLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
-		tx.data.NetworkID,
+		tx.txdata().nonce(),
+		tx.txdata().energyPrice(),
+		tx.txdata().energy(),
+		tx.txdata().to(),
+		tx.txdata().value(),
+		tx.txdata().data(),
+		tx.txdata().networkID(),
 	})
 }
 
 func recoverPlain(signer Signer, tx *Transaction) (common.Address, error) {
-	if len(tx.data.Signature) != crypto.ExtendedSignatureLength {
+	sig := tx.txdata().signature()
+	if len(sig) != crypto.ExtendedSignatureLength {
 		return common.Address{}, ErrInvalidSig
 	}
-	pubk, err := crypto.SigToPub(signer.Hash(tx).Bytes(), tx.data.Signature[:])
+	pubk, err := crypto.SigToPub(signer.Hash(tx).Bytes(), sig)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -131,3 +131,144 @@ func TestNetworkId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+// countingSigner is a signer deriving a fixed sender, counting the derivations.
+type countingSigner struct {
+	NucleusSigner
+	from  common.Address
+	calls *int
+}
+
+func (s countingSigner) Sender(tx *Transaction) (common.Address, error) {
+	*s.calls++
+	return s.from, nil
+}
+
+func (s countingSigner) Equal(s2 Signer) bool {
+	other, ok := s2.(countingSigner)
+	return ok && other.calls == s.calls
+}
+
+func TestSenderCached(t *testing.T) {
+	key, _ := defaultTestKey()
+	tx, err := SignTx(NewTransaction(0, key.Address(), new(big.Int), 0, new(big.Int), nil), NewNucleusSigner(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var callsA, callsB int
+	signerA := countingSigner{NucleusSigner: NewNucleusSigner(big.NewInt(1)), from: common.Address{0xa}, calls: &callsA}
+	signerB := countingSigner{NucleusSigner: NewNucleusSigner(big.NewInt(1)), from: common.Address{0xb}, calls: &callsB}
+
+	check := func(signer countingSigner, wantA, wantB int) {
+		t.Helper()
+		from, err := tx.SenderCached(signer)
+		if err != nil {
+			t.Fatalf("failed to derive sender: %v", err)
+		}
+		if from != signer.from {
+			t.Fatalf("sender mismatch: have %x, want %x", from, signer.from)
+		}
+		if callsA != wantA || callsB != wantB {
+			t.Fatalf("derivation count mismatch: have %d/%d, want %d/%d", callsA, callsB, wantA, wantB)
+		}
+	}
+	check(signerA, 1, 0) // first derivation
+	check(signerA, 1, 0) // cached
+	check(signerB, 1, 1) // different signer, cache invalidated
+	check(signerB, 1, 1) // cached
+	check(signerA, 2, 1) // switching back derives again
+
+	// The real signer must still recover the real sender
+	from, err := tx.SenderCached(NewNucleusSigner(big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != key.Address() {
+		t.Fatalf("sender mismatch: have %x, want %x", from, key.Address())
+	}
+}
+
+// Tests that signers of the same network share the cached sender regardless of
+// the accepted transaction types, while still rejecting unsupported types.
+func TestSenderCachedAcrossForks(t *testing.T) {
+	key, _ := defaultTestKey()
+	var (
+		legacySigner = NewNucleusSigner(big.NewInt(1))
+		accessSigner = NewAccessListSigner(big.NewInt(1))
+	)
+	tx, err := SignTx(NewTransaction(0, key.Address(), new(big.Int), 0, new(big.Int), nil), legacySigner, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sender(accessSigner, tx); err != nil {
+		t.Fatalf("failed to derive sender: %v", err)
+	}
+	if from, err := Sender(legacySigner, tx); err != nil || from != key.Address() {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", from, err, key.Address())
+	}
+	if cached := tx.from.Load().(sigCache).signer.(NucleusSigner); !cached.accessList {
+		t.Error("sender derived again by an equal signer")
+	}
+	typed, err := SignTx(NewTx(&AccessListTx{Price: new(big.Int), Amount: new(big.Int)}), accessSigner, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sender(accessSigner, typed); err != nil {
+		t.Fatalf("failed to derive sender: %v", err)
+	}
+	if _, err := Sender(legacySigner, typed); err != ErrTxTypeNotSupported {
+		t.Errorf("cached sender returned for unsupported type: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+}
+
+func BenchmarkSenderRecovery(b *testing.B) {
+	key, _ := defaultTestKey()
+	signer := NewNucleusSigner(big.NewInt(1))
+	tx, _ := SignTx(NewTransaction(0, key.Address(), new(big.Int), 0, new(big.Int), nil), signer, key)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := signer.Sender(tx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := tx.SenderCached(signer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestWithSignatureBytes(t *testing.T) {
+	key, _ := crypto.GenerateKey(crand.Reader)
+	signer := NewNucleusSigner(big.NewInt(18))
+
+	unsigned := NewTransaction(3, key.Address(), big.NewInt(10), 21000, big.NewInt(1), []byte{0xca, 0xfe})
+	signed, err := SignTx(unsigned, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rebuild the transaction from the signature of an external signer
+	sig := common.CopyBytes(signed.Signature())
+	rebuilt, err := unsigned.WithSignatureBytes(signer, sig)
+	if err != nil {
+		t.Fatalf("failed to attach signature: %v", err)
+	}
+	if rebuilt.Hash() != signed.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", rebuilt.Hash(), signed.Hash())
+	}
+	if from, err := Sender(signer, rebuilt); err != nil || from != key.Address() {
+		t.Errorf("sender mismatch: have %v (%v), want %v", from, err, key.Address())
+	}
+	// Malformed and foreign signatures must be rejected
+	if _, err := unsigned.WithSignatureBytes(signer, sig[:crypto.SignatureLength]); err == nil {
+		t.Error("short signature accepted")
+	}
+	other := NewTransaction(4, key.Address(), big.NewInt(10), 21000, big.NewInt(1), nil)
+	if _, err := other.WithSignatureBytes(signer, sig); err == nil {
+		t.Error("signature of another transaction accepted")
+	}
+}
@@ -20,7 +20,10 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"encoding/json"
+	"errors"
+	"io"
 	"math/big"
+	"reflect"
 	"testing"
 	"time"
 
@@ -79,6 +82,192 @@ func TestTransactionEncode(t *testing.T) {
 	}
 }
 
+func TestTransactionEnvelopeLegacy(t *testing.T) {
+	for _, tx := range []*Transaction{emptyTx, rightvrsTx} {
+		if tx.Type() != LegacyTxType {
+			t.Fatalf("wrong transaction type: have %d, want %d", tx.Type(), LegacyTxType)
+		}
+		// Legacy transactions are encoded as plain RLP through the envelope
+		bin, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("binary encode error: %v", err)
+		}
+		enc, _ := rlp.EncodeToBytes(tx)
+		if !bytes.Equal(bin, enc) {
+			t.Fatalf("binary encoding mismatch: have %x, want %x", bin, enc)
+		}
+		var dec Transaction
+		if err := dec.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("binary decode error: %v", err)
+		}
+		if dec.Type() != LegacyTxType {
+			t.Errorf("decoded transaction type mismatch: have %d, want %d", dec.Type(), LegacyTxType)
+		}
+		if dec.Hash() != tx.Hash() {
+			t.Errorf("decoded transaction hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+		}
+		if dec.Size() != common.StorageSize(len(bin)) {
+			t.Errorf("decoded transaction size mismatch: have %v, want %d", dec.Size(), len(bin))
+		}
+		// Rebuilding the transaction from its inner data must be lossless
+		rebuilt := NewTx(tx.inner)
+		if rebuilt.Hash() != tx.Hash() {
+			t.Errorf("rebuilt transaction hash mismatch: have %x, want %x", rebuilt.Hash(), tx.Hash())
+		}
+	}
+}
+
+func TestTransactionZeroValue(t *testing.T) {
+	// The zero value transaction must behave as an empty legacy transaction
+	tx := new(Transaction)
+	if tx.Type() != LegacyTxType {
+		t.Fatalf("wrong transaction type: have %d, want %d", tx.Type(), LegacyTxType)
+	}
+	if tx.EnergyPrice().Sign() != 0 || tx.Value().Sign() != 0 || tx.Cost().Sign() != 0 {
+		t.Errorf("non-zero amounts: price %v, value %v, cost %v", tx.EnergyPrice(), tx.Value(), tx.Cost())
+	}
+	if tx.To() != nil {
+		t.Errorf("recipient mismatch: have %v, want nil", tx.To())
+	}
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("decoded transaction hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	tx.SetNetworkID(1)
+	if tx.NetworkID() != 1 {
+		t.Errorf("network id mismatch: have %d, want 1", tx.NetworkID())
+	}
+}
+
+func TestTransactionEnvelopeUnsupported(t *testing.T) {
+	var tx Transaction
+	if err := tx.UnmarshalBinary(nil); err != errEmptyTypedTx {
+		t.Errorf("empty envelope: have %v, want %v", err, errEmptyTypedTx)
+	}
+	if err := tx.UnmarshalBinary([]byte{0x7f, 0xc0}); err != ErrTxTypeNotSupported {
+		t.Errorf("unknown binary type: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	// Typed envelopes are wrapped into an RLP string when embedded into lists
+	enc, _ := rlp.EncodeToBytes([]byte{0x7f, 0xc0})
+	if err := rlp.DecodeBytes(enc, &tx); err != ErrTxTypeNotSupported {
+		t.Errorf("unknown RLP type: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+}
+
+func TestTransactionEnvelopeAccessList(t *testing.T) {
+	var (
+		signer = NewAccessListSigner(params.MainnetChainConfig.NetworkID)
+		to     = key.Address()
+		list   = AccessList{
+			{Address: to, StorageKeys: []common.Hash{{0x01}, {0x02}}},
+			{Address: address, StorageKeys: []common.Hash{}},
+		}
+	)
+	unsigned := NewTx(&AccessListTx{
+		AccountNonce: 1,
+		Price:        big.NewInt(10),
+		EnergyLimit:  50000,
+		Recipient:    &to,
+		Amount:       big.NewInt(10),
+		Payload:      common.FromHex("1123"),
+		AccessList:   list,
+	})
+	tx, err := SignTx(unsigned, signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != AccessListTxType {
+		t.Fatalf("wrong transaction type: have %d, want %d", tx.Type(), AccessListTxType)
+	}
+	if sender, err := Sender(signer, tx); err != nil {
+		t.Fatalf("could not derive sender: %v", err)
+	} else if sender != key.Address() {
+		t.Fatalf("sender mismatch: have %v, want %v", sender, key.Address())
+	}
+	// Signers of networks without the access list fork must reject it
+	if _, err := NewNucleusSigner(params.MainnetChainConfig.NetworkID).Sender(tx); err != ErrTxTypeNotSupported {
+		t.Fatalf("legacy signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	// The access list is covered by the signature
+	legacy := NewTransaction(1, to, big.NewInt(10), 50000, big.NewInt(10), common.FromHex("1123"))
+	legacy.SetNetworkID(uint(signer.NetworkID()))
+	if signer.Hash(tx) == signer.Hash(legacy) {
+		t.Fatal("access list transaction signing hash equals legacy one")
+	}
+	// Binary and RLP encodings must round trip
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("binary encode error: %v", err)
+	}
+	if bin[0] != AccessListTxType {
+		t.Fatalf("binary encoding type mismatch: have %d, want %d", bin[0], AccessListTxType)
+	}
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("RLP encode error: %v", err)
+	}
+	var fromBin, fromRLP Transaction
+	if err := fromBin.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("binary decode error: %v", err)
+	}
+	if err := rlp.DecodeBytes(enc, &fromRLP); err != nil {
+		t.Fatalf("RLP decode error: %v", err)
+	}
+	for _, dec := range []*Transaction{&fromBin, &fromRLP} {
+		if dec.Hash() != tx.Hash() {
+			t.Errorf("decoded transaction hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+		}
+		if dec.Size() != common.StorageSize(len(bin)) {
+			t.Errorf("decoded transaction size mismatch: have %v, want %d", dec.Size(), len(bin))
+		}
+		if !reflect.DeepEqual(dec.AccessList(), list) {
+			t.Errorf("decoded access list mismatch: have %v, want %v", dec.AccessList(), list)
+		}
+		if sender, err := Sender(signer, dec); err != nil || sender != key.Address() {
+			t.Errorf("decoded sender mismatch: have %v (%v), want %v", sender, err, key.Address())
+		}
+	}
+	// JSON encoding must round trip too
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("JSON encode error: %v", err)
+	}
+	var fromJSON Transaction
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("JSON decode error: %v", err)
+	}
+	if fromJSON.Hash() != tx.Hash() {
+		t.Errorf("JSON decoded transaction hash mismatch: have %x, want %x", fromJSON.Hash(), tx.Hash())
+	}
+	// Messages should carry the access list, legacy ones none
+	if msg, err := tx.AsMessage(signer); err != nil {
+		t.Fatalf("could not convert to message: %v", err)
+	} else if !reflect.DeepEqual(msg.AccessList(), list) {
+		t.Errorf("message access list mismatch: have %v, want %v", msg.AccessList(), list)
+	}
+	if legacy, err = SignTx(legacy, signer, key); err != nil {
+		t.Fatalf("could not sign legacy transaction: %v", err)
+	}
+	if msg, err := legacy.AsMessage(signer); err != nil {
+		t.Fatalf("could not convert legacy transaction to message: %v", err)
+	} else if msg.AccessList() != nil {
+		t.Errorf("legacy message has access list: %v", msg.AccessList())
+	}
+	// Tampering with the access list must invalidate the signature
+	tampered := tx.inner.copy().(*AccessListTx)
+	tampered.AccessList[0].StorageKeys = tampered.AccessList[0].StorageKeys[:1]
+	if sender, err := Sender(signer, NewTx(tampered)); err == nil && sender == key.Address() {
+		t.Error("tampered access list transaction still verifies")
+	}
+}
+
 func decodeTx(data []byte) (*Transaction, error) {
 	var tx Transaction
 	t, err := &tx, rlp.Decode(bytes.NewReader(data), &tx)
@@ -225,6 +414,122 @@ func TestTransactionTimeSort(t *testing.T) {
 	}
 }
 
+// Tests that the JSON encoding of transactions contains the derived hash and
+// sender fields, and that they are ignored when decoding.
+func TestTransactionJSONDerivedFields(t *testing.T) {
+	key, err := crypto.GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signer := NewNucleusSigner(big.NewInt(int64(common.DefaultNetworkID)))
+
+	tx, err := SignTx(NewTransaction(1, key.Address(), common.Big1, 21000, common.Big2, []byte("abcdef")), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	type derived struct {
+		Hash *common.Hash    `json:"hash"`
+		From *common.Address `json:"from"`
+	}
+	// Without a derived sender, only the hash should be included
+	enc, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	var fields derived
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		t.Fatalf("failed to decode derived fields: %v", err)
+	}
+	if fields.Hash == nil || *fields.Hash != tx.Hash() {
+		t.Errorf("hash mismatch: have %v, want %x", fields.Hash, tx.Hash())
+	}
+	if fields.From != nil {
+		t.Errorf("sender included before derivation: %x", *fields.From)
+	}
+	// Once the sender is derived, it should be included too
+	from, err := Sender(signer, tx)
+	if err != nil {
+		t.Fatalf("failed to derive sender: %v", err)
+	}
+	if enc, err = json.Marshal(tx); err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	fields = derived{}
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		t.Fatalf("failed to decode derived fields: %v", err)
+	}
+	if fields.Hash == nil || *fields.Hash != tx.Hash() {
+		t.Errorf("hash mismatch: have %v, want %x", fields.Hash, tx.Hash())
+	}
+	if fields.From == nil || *fields.From != from {
+		t.Errorf("sender mismatch: have %v, want %x", fields.From, from)
+	}
+	// Decoding should ignore the derived fields, even if they are bogus
+	var obj map[string]interface{}
+	if err := json.Unmarshal(enc, &obj); err != nil {
+		t.Fatalf("failed to decode transaction object: %v", err)
+	}
+	obj["hash"] = common.Hash{0x01}
+	obj["from"] = crypto.CreateAddress(from, 0)
+	if enc, err = json.Marshal(obj); err != nil {
+		t.Fatalf("failed to encode transaction object: %v", err)
+	}
+	var parsed Transaction
+	if err := json.Unmarshal(enc, &parsed); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("parsed hash mismatch: have %x, want %x", parsed.Hash(), tx.Hash())
+	}
+	if parsed.from.Load() != nil {
+		t.Errorf("parsed transaction has cached sender")
+	}
+	if sender, err := Sender(signer, &parsed); err != nil || sender != from {
+		t.Errorf("parsed sender mismatch: have %x (%v), want %x", sender, err, from)
+	}
+}
+
+// Tests that decoding into a transaction with a cached sender drops the sender,
+// so it's no longer included in the JSON encoding.
+func TestTransactionJSONRedecoded(t *testing.T) {
+	key, err := crypto.GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signer := NewNucleusSigner(big.NewInt(int64(common.DefaultNetworkID)))
+
+	tx, err := SignTx(NewTransaction(1, key.Address(), common.Big1, 21000, common.Big2, []byte("abcdef")), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if _, err := Sender(signer, tx); err != nil {
+		t.Fatalf("failed to derive sender: %v", err)
+	}
+	blob, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	if err := rlp.DecodeBytes(blob, tx); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.from.Load() != nil {
+		t.Errorf("decoded transaction has cached sender")
+	}
+	enc, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	var fields struct {
+		From *common.Address `json:"from"`
+	}
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		t.Fatalf("failed to decode derived fields: %v", err)
+	}
+	if fields.From != nil {
+		t.Errorf("sender included after decoding: %x", *fields.From)
+	}
+}
+
 // TestTransactionJSON tests serializing/de-serializing to/from JSON.
 func TestTransactionJSON(t *testing.T) {
 	key, err := crypto.GenerateKey(crand.Reader)
@@ -272,3 +577,202 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the hash cached by the decoders matches the one computed from the
+// decoded contents, and that decoding into a used transaction resets its caches.
+func TestTransactionHashCache(t *testing.T) {
+	signer := NewAccessListSigner(params.MainnetChainConfig.NetworkID)
+	to := key.Address()
+	typed, err := SignTx(NewTx(&AccessListTx{
+		AccountNonce: 1,
+		Price:        big.NewInt(10),
+		EnergyLimit:  50000,
+		Recipient:    &to,
+		Amount:       big.NewInt(10),
+		AccessList:   AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	for i, tx := range []*Transaction{rightvrsTx, typed} {
+		enc, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatalf("tx %d: RLP encode error: %v", i, err)
+		}
+		bin, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("tx %d: binary encode error: %v", i, err)
+		}
+		var fromRLP, fromBinary Transaction
+		if err := rlp.DecodeBytes(enc, &fromRLP); err != nil {
+			t.Fatalf("tx %d: RLP decode error: %v", i, err)
+		}
+		if err := fromBinary.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("tx %d: binary decode error: %v", i, err)
+		}
+		for _, decoded := range []*Transaction{&fromRLP, &fromBinary} {
+			if decoded.hash.Load() == nil {
+				t.Fatalf("tx %d: hash not cached by decoding", i)
+			}
+			fresh := rlpHash(decoded.inner)
+			if decoded.Type() != LegacyTxType {
+				fresh = prefixedRlpHash(decoded.Type(), decoded.inner)
+			}
+			if have := decoded.Hash(); have != fresh || have != tx.Hash() {
+				t.Errorf("tx %d: hash mismatch: have %x, fresh %x, original %x", i, have, fresh, tx.Hash())
+			}
+		}
+	}
+	// Decoding another transaction into a used one must not keep stale caches
+	var tx Transaction
+	if err := tx.UnmarshalBinary(common.FromHex("f8cb800a82c3500196cb8238748ee459bc0c1d86eab1d3f6d83bb433cdad9c0a821123b8a8b7ea2c0222ad2cf32dc5671dcff5b6d3190d328b2696cca92b5b17d56b76fb26b6e3e303f3d1c428828b0e86616f783b4fc0dcc9d60157f820d2ce5b6b2709734fcf4188bc1020db6e8b972c63531832d0ee4fd66a1c2cee858540e237ff4351d8b2ed0c08edf15a9851cfd532191c39825e4ad8d405878998a67c949b3f94e3445f1d6e61f69d091be53f4326eb9d9d05627375ef943ae9f1763689984aa377ed84cd8923973ab0")); err != nil {
+		t.Fatalf("binary decode error: %v", err)
+	}
+	Sender(signer, &tx)
+	bin, _ := typed.MarshalBinary()
+	if err := tx.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("binary decode error: %v", err)
+	}
+	if tx.Hash() != typed.Hash() {
+		t.Errorf("stale hash after decoding: have %x, want %x", tx.Hash(), typed.Hash())
+	}
+	if sender, err := Sender(signer, &tx); err != nil || sender != key.Address() {
+		t.Errorf("sender mismatch after decoding: have %v (%v), want %v", sender, err, key.Address())
+	}
+}
+
+// Tests that changing the network of a decoded transaction drops the caches
+// derived from its previous contents.
+func TestTransactionSetNetworkIDCache(t *testing.T) {
+	signer := NewNucleusSigner(big.NewInt(1))
+	signed, err := SignTx(NewTransaction(1, key.Address(), big.NewInt(10), 50000, big.NewInt(10), []byte("abcdef")), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	enc, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		t.Fatalf("RLP encode error: %v", err)
+	}
+	var tx Transaction
+	if err := rlp.DecodeBytes(enc, &tx); err != nil {
+		t.Fatalf("RLP decode error: %v", err)
+	}
+	if _, err := Sender(signer, &tx); err != nil {
+		t.Fatalf("failed to derive sender: %v", err)
+	}
+	oldHash := tx.Hash()
+
+	tx.SetNetworkID(2)
+	if have, want := tx.Hash(), rlpHash(tx.inner); have != want || have == oldHash {
+		t.Errorf("hash mismatch: have %x, want %x (old %x)", have, want, oldHash)
+	}
+	size, _ := rlp.EncodeToBytes(tx.inner)
+	if have := tx.Size(); have != common.StorageSize(len(size)) {
+		t.Errorf("size mismatch: have %v, want %d", have, len(size))
+	}
+	if tx.from.Load() != nil {
+		t.Errorf("sender still cached after changing the network")
+	}
+}
+
+func BenchmarkTransactionHash(b *testing.B) {
+	enc, err := rlp.EncodeToBytes(rightvrsTx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("decoded", func(b *testing.B) {
+		var tx Transaction
+		if err := rlp.DecodeBytes(enc, &tx); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tx.Hash()
+		}
+	})
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewTx(rightvrsTx.inner).Hash()
+		}
+	})
+}
+
+// zeroReader is an endless source of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+// oversizedStream returns a stream limited to the given input length, like the
+// ones p2p messages are decoded from, declaring a value of the given header and
+// size followed by endless content.
+func oversizedStream(header byte, size uint32, limit uint64) *rlp.Stream {
+	prefix := []byte{header, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}
+	return rlp.NewStream(io.MultiReader(bytes.NewReader(prefix), zeroReader{}), limit)
+}
+
+func TestTransactionDecodeTooLarge(t *testing.T) {
+	// Declared lengths past the input limit are rejected before reading the contents
+	for _, header := range []byte{0xfb, 0xbb} { // legacy list and typed envelope
+		var tx Transaction
+		if err := tx.DecodeRLP(oversizedStream(header, 256*1024*1024, 1024)); err != rlp.ErrValueTooLarge {
+			t.Errorf("header %#x: error mismatch: have %v, want %v", header, err, rlp.ErrValueTooLarge)
+		}
+	}
+	// Transactions within the limit are decoded, caching their size
+	var tx Transaction
+	enc, err := rlp.EncodeToBytes(rightvrsTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rlp.DecodeBytes(enc, &tx); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if size := tx.size.Load(); size == nil || size.(common.StorageSize) != common.StorageSize(len(enc)) {
+		t.Errorf("cached size mismatch: have %v, want %d", size, len(enc))
+	}
+}
+
+// Tests that transaction lists from untrusted sources are rejected as soon as a
+// transaction declares an encoding larger than the limit.
+func TestDecodeTransactionsTooLarge(t *testing.T) {
+	enc, err := rlp.EncodeToBytes(rightvrsTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := uint64(len(enc))
+
+	// A valid transaction followed by an oversized one, in an input of unknown
+	// length which the stream can't check declared sizes against
+	for _, header := range []byte{0xfb, 0xbb} { // legacy list and typed envelope
+		prefix := []byte{0xfb, 0x10, 0x00, 0x00, 0x00}
+		prefix = append(prefix, enc...)
+		prefix = append(prefix, header, 0x0f, 0x00, 0x00, 0x00)
+		s := rlp.NewStream(io.MultiReader(bytes.NewReader(prefix), zeroReader{}), 0)
+
+		if _, err := DecodeTransactions(s, limit); !errors.Is(err, ErrTxTooLarge) {
+			t.Errorf("header %#x: error mismatch: have %v, want %v", header, err, ErrTxTooLarge)
+		}
+	}
+	// Transactions within the limit are decoded
+	list, err := rlp.EncodeToBytes([]*Transaction{rightvrsTx, rightvrsTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := DecodeTransactions(rlp.NewStream(bytes.NewReader(list), uint64(len(list))), limit)
+	if err != nil {
+		t.Fatalf("failed to decode transactions: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Hash() != rightvrsTx.Hash() || txs[1].Hash() != rightvrsTx.Hash() {
+		t.Errorf("decoded transactions mismatch: have %v", txs)
+	}
+	if _, err := DecodeTransactions(rlp.NewStream(bytes.NewReader(list), uint64(len(list))), limit-1); !errors.Is(err, ErrTxTooLarge) {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrTxTooLarge)
+	}
+}
@@ -190,6 +190,14 @@ func (cvm *CVM) Interpreter() Interpreter {
 	return cvm.interpreter
 }
 
+// maxCallDepth returns the maximum depth of the call/create stack.
+func (cvm *CVM) maxCallDepth() int {
+	if cvm.vmConfig.MaxCallDepth != 0 {
+		return int(cvm.vmConfig.MaxCallDepth)
+	}
+	return int(params.CallCreateDepth)
+}
+
 // Call executes the contract associated with the addr with the given input as
 // parameters. It also handles any necessary value transfer required and takes
 // the necessary steps to create accounts and reverses the state in case of an
@@ -199,7 +207,7 @@ func (cvm *CVM) Call(caller ContractRef, addr common.Address, input []byte, ener
 		return nil, energy, nil
 	}
 	// Fail if we're trying to execute above the call depth limit
-	if cvm.depth > int(params.CallCreateDepth) {
+	if cvm.depth > cvm.maxCallDepth() {
 		return nil, energy, ErrDepth
 	}
 	// Fail if we're trying to transfer more than the available balance
@@ -274,7 +282,7 @@ func (cvm *CVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		return nil, energy, nil
 	}
 	// Fail if we're trying to execute above the call depth limit
-	if cvm.depth > int(params.CallCreateDepth) {
+	if cvm.depth > cvm.maxCallDepth() {
 		return nil, energy, ErrDepth
 	}
 	// Fail if we're trying to transfer more than the available balance
@@ -317,7 +325,7 @@ func (cvm *CVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		return nil, energy, nil
 	}
 	// Fail if we're trying to execute above the call depth limit
-	if cvm.depth > int(params.CallCreateDepth) {
+	if cvm.depth > cvm.maxCallDepth() {
 		return nil, energy, ErrDepth
 	}
 	var snapshot = cvm.StateDB.Snapshot()
@@ -351,7 +359,7 @@ func (cvm *CVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		return nil, energy, nil
 	}
 	// Fail if we're trying to execute above the call depth limit
-	if cvm.depth > int(params.CallCreateDepth) {
+	if cvm.depth > cvm.maxCallDepth() {
 		return nil, energy, ErrDepth
 	}
 	// We take a snapshot here. This is a bit counter-intuitive, and could probably be skipped.
@@ -407,7 +415,7 @@ func (c *codeAndHash) Hash() common.Hash {
 func (cvm *CVM) create(caller ContractRef, codeAndHash *codeAndHash, energy uint64, value *big.Int, address common.Address) ([]byte, common.Address, uint64, error) {
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
-	if cvm.depth > int(params.CallCreateDepth) {
+	if cvm.depth > cvm.maxCallDepth() {
 		return nil, common.Address{}, energy, ErrDepth
 	}
 	if !cvm.Context.CanTransfer(cvm.StateDB, caller.Address(), value) {
@@ -415,6 +423,11 @@ func (cvm *CVM) create(caller ContractRef, codeAndHash *codeAndHash, energy uint
 	}
 	nonce := cvm.StateDB.GetNonce(caller.Address())
 	cvm.StateDB.SetNonce(caller.Address(), nonce+1)
+	// The created account is accessed, add it to the access list even if the
+	// creation fails
+	if cvm.chainConfig.IsAccessList(cvm.Context.BlockNumber) {
+		cvm.StateDB.AddAddressToAccessList(address)
+	}
 	// Ensure there's no existing contract already at the designated address
 	contractHash := cvm.StateDB.GetCodeHash(address)
 	if cvm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
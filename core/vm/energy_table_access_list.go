@@ -0,0 +1,175 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/math"
+	"github.com/core-coin/go-core/v2/params"
+)
+
+// From the access list fork on, accounts and storage slots are priced by whether
+// the transaction already accessed them. The first (cold) access of an account
+// or slot costs as much as before the fork and adds it to the access list of the
+// transaction, every later (warm) access costs WarmStorageReadEnergy. The access
+// list of the transaction seeds the list, so its entries are warm from the start.
+//
+// The additions are journalled by the state, a reverted call thus also reverts
+// the warming of the accounts and slots it accessed.
+
+// warmCost splits the pre-fork cost of an access into the cost of a warm access
+// and the surcharge added to it for a cold access.
+func warmCost(cost uint64) (warm uint64, coldSurcharge uint64) {
+	if warm = params.WarmStorageReadEnergy; warm > cost {
+		warm = cost
+	}
+	return warm, cost - warm
+}
+
+// makeEnergySLoadAccessList creates the energy function of SLOAD, charging the
+// given cost for reading a cold storage slot.
+func makeEnergySLoadAccessList(sloadEnergy uint64) energyFunc {
+	warm, _ := warmCost(sloadEnergy)
+	return func(cvm *CVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		slot := common.Hash(stack.peek().Bytes32())
+		if _, slotOk := cvm.StateDB.SlotInAccessList(contract.Address(), slot); slotOk {
+			return warm, nil
+		}
+		cvm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+		return sloadEnergy, nil
+	}
+}
+
+// makeEnergySStoreAccessList creates the energy function of SSTORE, following
+// the schedule of energySStore with the reads of the slot priced by whether it
+// is warm, given the cost of SLOAD of a cold slot. The refunds are unchanged.
+func makeEnergySStoreAccessList(sloadEnergy uint64) energyFunc {
+	warm, coldSurcharge := warmCost(sloadEnergy)
+	return func(cvm *CVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		// If we fail the minimum energy availability invariant, fail (0)
+		if contract.Energy <= params.SstoreSentryEnergy {
+			return 0, errors.New("not enough energy for reentrancy sentry")
+		}
+		// Energy sentry honoured, do the actual energy calculation based on the stored value
+		var (
+			y, x    = stack.Back(1), stack.Back(0)
+			slot    = common.Hash(x.Bytes32())
+			current = cvm.StateDB.GetState(contract.Address(), slot)
+			cost    = uint64(0)
+		)
+		// Cold slots are charged the surcharge on top of the warm schedule
+		if _, slotOk := cvm.StateDB.SlotInAccessList(contract.Address(), slot); !slotOk {
+			cost = coldSurcharge
+			cvm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+		}
+		value := common.Hash(y.Bytes32())
+
+		if current == value { // noop (1)
+			return cost + warm, nil
+		}
+		original := cvm.StateDB.GetCommittedState(contract.Address(), slot)
+		if original == current {
+			if original == (common.Hash{}) { // create slot (2.1.1)
+				return cost + params.SstoreInitEnergy - coldSurcharge, nil
+			}
+			if value == (common.Hash{}) { // delete slot (2.1.2b)
+				cvm.StateDB.AddRefund(params.SstoreClearRefund)
+			}
+			return cost + params.SstoreCleanEnergy - coldSurcharge, nil // write existing slot (2.1.2)
+		}
+		if original != (common.Hash{}) {
+			if current == (common.Hash{}) { // recreate slot (2.2.1.1)
+				cvm.StateDB.SubRefund(params.SstoreClearRefund)
+			} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
+				cvm.StateDB.AddRefund(params.SstoreClearRefund)
+			}
+		}
+		if original == value {
+			if original == (common.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+				cvm.StateDB.AddRefund(params.SstoreInitRefund)
+			} else { // reset to original existing slot (2.2.2.2)
+				cvm.StateDB.AddRefund(params.SstoreCleanRefund)
+			}
+		}
+		return cost + warm, nil // dirty update (2.2)
+	}
+}
+
+// makeEnergyAccountAccessList creates the energy function of an operation
+// accessing the account on top of the stack, charging the cold surcharge on top
+// of the constant warm cost of the operation and the given energy function.
+func makeEnergyAccountAccessList(coldSurcharge uint64, energy energyFunc) energyFunc {
+	return func(cvm *CVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		var cost uint64
+		if energy != nil {
+			var err error
+			if cost, err = energy(cvm, contract, stack, mem, memorySize); err != nil {
+				return 0, err
+			}
+		}
+		addr := common.Address(stack.peek().Bytes22())
+		if cvm.StateDB.AddressInAccessList(addr) {
+			return cost, nil
+		}
+		cvm.StateDB.AddAddressToAccessList(addr)
+
+		var overflow bool
+		if cost, overflow = math.SafeAdd(cost, coldSurcharge); overflow {
+			return 0, ErrEnergyUintOverflow
+		}
+		return cost, nil
+	}
+}
+
+// makeEnergyCallAccessList creates the energy function of a CALL variant, charging
+// the cold surcharge for the callee on top of the constant warm cost of the call
+// and the given energy function.
+func makeEnergyCallAccessList(coldSurcharge uint64, energy energyFunc) energyFunc {
+	return func(cvm *CVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.Address(stack.Back(1).Bytes22())
+		if cvm.StateDB.AddressInAccessList(addr) {
+			return energy(cvm, contract, stack, mem, memorySize)
+		}
+		cvm.StateDB.AddAddressToAccessList(addr)
+
+		// The surcharge is deducted before computing the energy passed to the callee,
+		// which is capped to a share of the energy left, and restored afterwards
+		// since the caller deducts the total cost returned.
+		if !contract.UseEnergy(coldSurcharge) {
+			return 0, ErrOutOfEnergy
+		}
+		cost, err := energy(cvm, contract, stack, mem, memorySize)
+		contract.Energy += coldSurcharge
+		if err != nil {
+			return 0, err
+		}
+		var overflow bool
+		if cost, overflow = math.SafeAdd(cost, coldSurcharge); overflow {
+			return 0, ErrEnergyUintOverflow
+		}
+		return cost, nil
+	}
+}
+
+// energySelfdestructAccessList is the energy function of SELFDESTRUCT, adding
+// the beneficiary to the access list. Its cost is unchanged.
+func energySelfdestructAccessList(cvm *CVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	cvm.StateDB.AddAddressToAccessList(common.Address(stack.peek().Bytes22()))
+	return energySelfdestruct(cvm, contract, stack, mem, memorySize)
+}
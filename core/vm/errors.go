@@ -39,6 +39,8 @@ var (
 	ErrEnergyUintOverflow       = errors.New("energy uint64 overflow")
 	ErrInvalidRetsub            = errors.New("invalid retsub")
 	ErrReturnStackExceeded      = errors.New("return stack limit reached")
+	ErrTruncatedPush            = errors.New("truncated push data")
+	ErrMemoryLimit              = errors.New("max memory size exceeded")
 )
 
 // ErrStackUnderflow wraps an cvm error when the items on the stack less
@@ -517,6 +517,9 @@ func opSload(pc *uint64, interpreter *CVMInterpreter, callContext *callCtx) ([]b
 	loc := callContext.stack.peek()
 	hash := common.Hash(loc.Bytes32())
 	val := interpreter.cvm.StateDB.GetState(callContext.contract.Address(), hash)
+	if tracer := interpreter.cfg.StorageTracer; tracer != nil {
+		tracer.CaptureStorageRead(callContext.contract.Address(), hash, val)
+	}
 	loc.SetBytes(val.Bytes())
 	return nil, nil
 }
@@ -524,6 +527,10 @@ func opSload(pc *uint64, interpreter *CVMInterpreter, callContext *callCtx) ([]b
 func opSstore(pc *uint64, interpreter *CVMInterpreter, callContext *callCtx) ([]byte, error) {
 	loc := callContext.stack.pop()
 	val := callContext.stack.pop()
+	if tracer := interpreter.cfg.StorageTracer; tracer != nil {
+		addr, key := callContext.contract.Address(), common.Hash(loc.Bytes32())
+		tracer.CaptureStorageWrite(addr, key, interpreter.cvm.StateDB.GetState(addr, key), val.Bytes32())
+	}
 	interpreter.cvm.StateDB.SetState(callContext.contract.Address(),
 		loc.Bytes32(), val.Bytes32())
 	return nil, nil
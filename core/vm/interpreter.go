@@ -26,10 +26,13 @@ import (
 
 // Config are the configuration options for the Interpreter
 type Config struct {
-	Debug                   bool   // Enables debugging
-	Tracer                  Tracer // Opcode logger
-	NoRecursion             bool   // Disables call, callcode, delegate call and create
-	EnablePreimageRecording bool   // Enables recording of SHA3/keccak preimages
+	Debug                   bool          // Enables debugging
+	Tracer                  Tracer        // Opcode logger
+	StorageTracer           StorageTracer // Storage access logger, independent of Debug
+	NoRecursion             bool          // Disables call, callcode, delegate call and create
+	EnablePreimageRecording bool          // Enables recording of SHA3/keccak preimages
+	MaxCallDepth            uint64        // Maximum depth of the call/create stack, params.CallCreateDepth if zero
+	MaxMemory               uint64        // Maximum memory size in bytes of an execution context, unlimited if zero
 
 	JumpTable [256]*operation // CVM instruction table, automatically populated if unset
 
@@ -94,7 +97,17 @@ func NewCVMInterpreter(cvm *CVM, cfg Config) *CVMInterpreter {
 	// the jump table was initialised. If it was not
 	// we'll set the default jump table.
 	if cfg.JumpTable[STOP] == nil {
-		cfg.JumpTable = InstructionSet
+		accessList := cvm.chainConfig.IsAccessList(cvm.Context.BlockNumber)
+		switch {
+		case cvm.chainConfig.EnergyTable != nil && accessList:
+			cfg.JumpTable = InstructionSet.withEnergyCosts(cvm.chainConfig.EnergyCosts()).withAccessList()
+		case cvm.chainConfig.EnergyTable != nil:
+			cfg.JumpTable = InstructionSet.withEnergyCosts(cvm.chainConfig.EnergyCosts())
+		case accessList:
+			cfg.JumpTable = AccessListInstructionSet
+		default:
+			cfg.JumpTable = InstructionSet
+		}
 	}
 
 	return &CVMInterpreter{
@@ -233,6 +246,9 @@ func (in *CVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			if memorySize, overflow = math.SafeMul(toWordSize(memSize), 32); overflow {
 				return nil, ErrEnergyUintOverflow
 			}
+			if in.cfg.MaxMemory != 0 && memorySize > in.cfg.MaxMemory {
+				return nil, ErrMemoryLimit
+			}
 		}
 		// Dynamic portion of energy
 		// consume the energy and return an error if not enough energy is available.
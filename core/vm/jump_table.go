@@ -49,7 +49,8 @@ type operation struct {
 }
 
 var (
-	InstructionSet = newInstructionSet()
+	InstructionSet           = newInstructionSet()
+	AccessListInstructionSet = InstructionSet.withAccessList()
 )
 
 // JumpTable contains the CVM opcodes supported at a given fork.
@@ -983,3 +984,54 @@ func newInstructionSet() JumpTable {
 		},
 	}
 }
+
+// withEnergyCosts returns a copy of the jump table with the static energy costs
+// of the operations replaced by the ones in the given table.
+func (jt JumpTable) withEnergyCosts(costs params.EnergyCosts) JumpTable {
+	set := func(op OpCode, cost uint64) {
+		cpy := *jt[op]
+		cpy.constantEnergy = cost
+		jt[op] = &cpy
+	}
+	set(SHA3, costs.Sha3Energy)
+	set(BALANCE, costs.BalanceEnergy)
+	set(EXTCODESIZE, costs.ExtcodeSizeEnergy)
+	set(EXTCODECOPY, costs.ExtcodeCopyBase)
+	set(EXTCODEHASH, costs.ExtcodeHashEnergy)
+	set(SLOAD, costs.SloadEnergy)
+	set(JUMPDEST, costs.JumpdestEnergy)
+	set(CREATE, costs.CreateEnergy)
+	set(CREATE2, costs.Create2Energy)
+	for _, op := range []OpCode{CALL, CALLCODE, DELEGATECALL, STATICCALL} {
+		set(op, costs.CallEnergy)
+	}
+	return jt
+}
+
+// withAccessList returns a copy of the jump table pricing the accounts and
+// storage slots accessed by the operations by whether they are in the access
+// list of the transaction, see energy_table_access_list.go. The static costs
+// of the table are charged for cold accesses.
+func (jt JumpTable) withAccessList() JumpTable {
+	sloadEnergy := jt[SLOAD].constantEnergy
+
+	set := func(op OpCode, constant uint64, dynamic energyFunc) {
+		cpy := *jt[op]
+		cpy.constantEnergy, cpy.dynamicEnergy = constant, dynamic
+		jt[op] = &cpy
+	}
+	set(SLOAD, 0, makeEnergySLoadAccessList(sloadEnergy))
+	set(SSTORE, 0, makeEnergySStoreAccessList(sloadEnergy))
+
+	for _, op := range []OpCode{BALANCE, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH} {
+		warm, coldSurcharge := warmCost(jt[op].constantEnergy)
+		set(op, warm, makeEnergyAccountAccessList(coldSurcharge, jt[op].dynamicEnergy))
+	}
+	for _, op := range []OpCode{CALL, CALLCODE, DELEGATECALL, STATICCALL} {
+		warm, coldSurcharge := warmCost(jt[op].constantEnergy)
+		set(op, warm, makeEnergyCallAccessList(coldSurcharge, jt[op].dynamicEnergy))
+	}
+	set(SELFDESTRUCT, jt[SELFDESTRUCT].constantEnergy, energySelfdestructAccessList)
+
+	return jt
+}
@@ -112,6 +112,17 @@ type Tracer interface {
 	CaptureEnd(output []byte, energyUsed uint64, t time.Duration, err error) error
 }
 
+// StorageTracer is notified of every storage slot accessed by the SLOAD and
+// SSTORE instructions. Unlike Tracer, it doesn't require Config.Debug to be set
+// and doesn't add any overhead to other instructions.
+type StorageTracer interface {
+	// CaptureStorageRead is called on SLOAD with the value read.
+	CaptureStorageRead(addr common.Address, key, value common.Hash)
+	// CaptureStorageWrite is called on SSTORE with the value prior to the write
+	// and the new one.
+	CaptureStorageWrite(addr common.Address, key, prev, value common.Hash)
+}
+
 // StructLogger is an CVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
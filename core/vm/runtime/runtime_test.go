@@ -127,6 +127,64 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// storageAccess is a storage slot access captured by storageRecorder.
+type storageAccess struct {
+	write     bool
+	addr      common.Address
+	key       common.Hash
+	prev, val common.Hash
+}
+
+// storageRecorder is a vm.StorageTracer recording all storage accesses.
+type storageRecorder struct {
+	accesses []storageAccess
+}
+
+func (r *storageRecorder) CaptureStorageRead(addr common.Address, key, value common.Hash) {
+	r.accesses = append(r.accesses, storageAccess{addr: addr, key: key, val: value})
+}
+
+func (r *storageRecorder) CaptureStorageWrite(addr common.Address, key, prev, value common.Hash) {
+	r.accesses = append(r.accesses, storageAccess{write: true, addr: addr, key: key, prev: prev, val: value})
+}
+
+// Tests that the storage tracer captures the storage transitions of a contract,
+// even without debug mode enabled.
+func TestStorageTracer(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	address := common.BytesToAddress([]byte("contract"))
+	state.SetCode(address, []byte{
+		byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x00, byte(vm.SSTORE), // slot 0: 0 -> 1
+		byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.POP), // read slot 0
+		byte(vm.PUSH1), 0x02, byte(vm.PUSH1), 0x00, byte(vm.SSTORE), // slot 0: 1 -> 2
+		byte(vm.PUSH1), 0x03, byte(vm.PUSH1), 0x01, byte(vm.SSTORE), // slot 1: 0 -> 3
+	})
+	recorder := new(storageRecorder)
+	if _, _, err := Call(address, nil, &Config{State: state, CVMConfig: vm.Config{StorageTracer: recorder}}); err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	var (
+		zero  = common.Hash{}
+		one   = common.BigToHash(big.NewInt(1))
+		two   = common.BigToHash(big.NewInt(2))
+		three = common.BigToHash(big.NewInt(3))
+	)
+	want := []storageAccess{
+		{write: true, addr: address, key: zero, prev: zero, val: one},
+		{addr: address, key: zero, val: one},
+		{write: true, addr: address, key: zero, prev: one, val: two},
+		{write: true, addr: address, key: one, prev: zero, val: three},
+	}
+	if len(recorder.accesses) != len(want) {
+		t.Fatalf("access count mismatch: have %d, want %d", len(recorder.accesses), len(want))
+	}
+	for i, access := range recorder.accesses {
+		if access != want[i] {
+			t.Errorf("access %d mismatch: have %+v, want %+v", i, access, want[i])
+		}
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
@@ -245,6 +303,45 @@ func (d *dummyChain) GetHeader(h common.Hash, n uint64) *types.Header {
 	return fakeHeader(n, parentHash)
 }
 
+// Tests that a recursive contract stops at the configured call depth, the
+// call exceeding it failing without aborting the outer frames.
+func TestCallDepthLimit(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	address := common.BytesToAddress([]byte("contract"))
+	// Increments slot 0, then calls itself with all the remaining energy
+	state.SetCode(address, []byte{
+		byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.PUSH1), 0x01, byte(vm.ADD),
+		byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00,
+		byte(vm.PUSH1), 0x00, byte(vm.ADDRESS), byte(vm.ENERGY), byte(vm.CALL),
+		byte(vm.POP), byte(vm.STOP),
+	})
+	if _, _, err := Call(address, nil, &Config{State: state, CVMConfig: vm.Config{MaxCallDepth: 10}}); err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	// The frames at depth 0 to 10 have run
+	if have, want := state.GetState(address, common.Hash{}), common.BigToHash(big.NewInt(11)); have != want {
+		t.Errorf("call count mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that expanding the memory above the configured limit fails.
+func TestMemoryLimit(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	address := common.BytesToAddress([]byte("contract"))
+	state.SetCode(address, []byte{
+		byte(vm.PUSH1), 0x01, byte(vm.PUSH2), 0x04, 0x00, byte(vm.MSTORE), // expands to 1056 bytes
+	})
+	cfg := &Config{State: state, CVMConfig: vm.Config{MaxMemory: 1024}}
+	if _, _, err := Call(address, nil, cfg); err != vm.ErrMemoryLimit {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrMemoryLimit)
+	}
+	cfg.CVMConfig.MaxMemory = 2048
+	if _, _, err := Call(address, nil, cfg); err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+}
+
 // TestBlockhash tests the blockhash operation. It's a bit special, since it internally
 // requires access to a chain reader.
 func TestBlockhash(t *testing.T) {
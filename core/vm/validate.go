@@ -0,0 +1,127 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/core-coin/go-core/v2/params"
+)
+
+// CodeError is a static validation failure of the instruction at a given
+// position of the code.
+type CodeError struct {
+	PC  uint64 // Position of the faulty instruction
+	Op  OpCode // Faulty instruction
+	Err error  // Reason of the failure
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("pc %d: %v: %v", e.PC, e.Op, e.Err)
+}
+
+func (e *CodeError) Unwrap() error { return e.Err }
+
+// ValidateCode statically checks the given code without executing it, returning
+// all failures found. Only instructions reachable by sequential execution from
+// the start of the code or from a jump destination are checked:
+//
+//   - every instruction must be defined and its push data must not be truncated
+//   - jumps to constant targets pushed right before must land on a JUMPDEST
+//     (or a BEGINSUB for JUMPSUB)
+//   - the stack must neither under- nor overflow, as far as its height is known,
+//     which is the case for straight-line code from the start of the code
+func ValidateCode(code []byte) []*CodeError {
+	var (
+		errs   []*CodeError
+		bitmap = codeBitmap(code)
+
+		reachable = true // Whether the current instruction can be reached
+		known     = true // Whether the stack height is known
+		height    = 0    // Stack height, if known
+		target    []byte // Data of the previous instruction, if it was a push
+		length    = uint64(len(code))
+	)
+	for pc := uint64(0); pc < length; {
+		op := OpCode(code[pc])
+
+		// Jump destinations start a new reachable block with unknown stack
+		if op == JUMPDEST || op == BEGINSUB {
+			if reachable && op == BEGINSUB {
+				errs = append(errs, &CodeError{PC: pc, Op: op, Err: ErrInvalidSubroutineEntry})
+			}
+			reachable, known = true, false
+		}
+		size := uint64(1)
+		if op >= PUSH1 && op <= PUSH32 {
+			size += uint64(op - PUSH1 + 1)
+		}
+		if !reachable {
+			pc += size
+			continue
+		}
+		operation := InstructionSet[op]
+		if operation == nil {
+			errs = append(errs, &CodeError{PC: pc, Op: op, Err: &ErrInvalidOpCode{opcode: op}})
+			reachable, target = false, nil
+			pc += size
+			continue
+		}
+		if pc+size > length {
+			errs = append(errs, &CodeError{PC: pc, Op: op, Err: ErrTruncatedPush})
+			break
+		}
+		// Track the stack height as long as it's known
+		if known {
+			if height < operation.minStack {
+				errs = append(errs, &CodeError{PC: pc, Op: op, Err: &ErrStackUnderflow{stackLen: height, required: operation.minStack}})
+				known = false
+			} else if height > operation.maxStack {
+				errs = append(errs, &CodeError{PC: pc, Op: op, Err: &ErrStackOverflow{stackLen: height, limit: operation.maxStack}})
+				known = false
+			} else {
+				height += int(params.StackLimit) - operation.maxStack
+			}
+		}
+		// Check constant jump targets
+		if target != nil && (op == JUMP || op == JUMPI || op == JUMPSUB) {
+			want := JUMPDEST
+			if op == JUMPSUB {
+				want = BEGINSUB
+			}
+			dest := new(big.Int).SetBytes(target)
+			if !dest.IsUint64() || dest.Uint64() >= length || !bitmap.codeSegment(dest.Uint64()) || OpCode(code[dest.Uint64()]) != want {
+				errs = append(errs, &CodeError{PC: pc, Op: op, Err: ErrInvalidJump})
+			}
+		}
+		// Update the control flow state for the next instruction
+		switch {
+		case op == JUMPI:
+		case op == JUMPSUB:
+			known = false
+		case operation.halts || operation.jumps || operation.reverts:
+			reachable = false
+		}
+		target = nil
+		if size > 1 {
+			target = code[pc+1 : pc+size]
+		}
+		pc += size
+	}
+	return errs
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateCode(t *testing.T) {
+	var (
+		underflow *ErrStackUnderflow
+		invalidOp *ErrInvalidOpCode
+	)
+	tests := []struct {
+		code []byte
+		pc   uint64      // position of the expected failure
+		err  interface{} // expected failure, nil if the code is valid
+	}{
+		// Valid code: a jump over some dead data to a return
+		{
+			code: []byte{
+				byte(PUSH1), 0x05, byte(JUMP), 0xfe, 0xfe,
+				byte(JUMPDEST), byte(PUSH1), 0x00, byte(DUP1), byte(RETURN),
+			},
+		},
+		// Conditional jump falling through, with push data resembling a JUMPDEST
+		{
+			code: []byte{
+				byte(PUSH1), 0x01, byte(PUSH1), 0x08, byte(JUMPI), byte(PUSH1), byte(JUMPDEST), byte(STOP),
+				byte(JUMPDEST), byte(STOP),
+			},
+		},
+		// Empty code is valid
+		{code: []byte{}},
+		// Jump into push data
+		{
+			code: []byte{byte(PUSH1), 0x03, byte(JUMP), byte(PUSH1), byte(JUMPDEST)},
+			pc:   2,
+			err:  ErrInvalidJump,
+		},
+		// Jump to a non-JUMPDEST instruction
+		{
+			code: []byte{byte(PUSH1), 0x01, byte(PUSH1), 0x05, byte(JUMPI), byte(STOP)},
+			pc:   4,
+			err:  ErrInvalidJump,
+		},
+		// Jump out of the code
+		{
+			code: []byte{byte(PUSH2), 0xff, 0xff, byte(JUMP)},
+			pc:   3,
+			err:  ErrInvalidJump,
+		},
+		// Truncated push data
+		{
+			code: []byte{byte(PUSH1), 0x01, byte(PUSH4), 0x01, 0x02},
+			pc:   2,
+			err:  ErrTruncatedPush,
+		},
+		// Stack underflow on straight-line code
+		{
+			code: []byte{byte(PUSH1), 0x01, byte(ADD)},
+			pc:   2,
+			err:  &underflow,
+		},
+		// Undefined opcode
+		{
+			code: []byte{byte(PUSH1), 0x01, 0xef},
+			pc:   2,
+			err:  &invalidOp,
+		},
+	}
+	for i, tt := range tests {
+		errs := ValidateCode(tt.code)
+		if tt.err == nil {
+			if len(errs) != 0 {
+				t.Errorf("test %d: unexpected failures: %v", i, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Errorf("test %d: failure count mismatch: have %v, want 1", i, errs)
+			continue
+		}
+		if errs[0].PC != tt.pc {
+			t.Errorf("test %d: failure position mismatch: have %d, want %d", i, errs[0].PC, tt.pc)
+		}
+		if err, ok := tt.err.(error); ok {
+			if !errors.Is(errs[0], err) {
+				t.Errorf("test %d: failure mismatch: have %v, want %v", i, errs[0], err)
+			}
+		} else if !errors.As(errs[0], tt.err) {
+			t.Errorf("test %d: failure type mismatch: have %v, want %T", i, errs[0], tt.err)
+		}
+	}
+}
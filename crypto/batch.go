@@ -0,0 +1,88 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// minParallelBatch is the number of signatures below which batches are verified
+// serially, as the goroutine overhead outweighs the gains of parallelism.
+const minParallelBatch = 8
+
+// VerifyBatch checks a set of signatures, where sigs[i] must have been created
+// over messages[i] by the owner of pubkeys[i]. It reports whether all signatures
+// are valid, along with the indices of the failing ones in ascending order.
+//
+// Ed448 has no native batch verification in the underlying libraries, so the
+// individual signatures are verified concurrently across all available CPUs.
+// Entries missing any of the three components are reported as failures.
+func VerifyBatch(pubkeys [][]byte, messages [][]byte, sigs [][]byte) (bool, []int) {
+	n := len(sigs)
+	if len(pubkeys) > n {
+		n = len(pubkeys)
+	}
+	if len(messages) > n {
+		n = len(messages)
+	}
+	verify := func(i int) bool {
+		if i >= len(pubkeys) || i >= len(messages) || i >= len(sigs) {
+			return false
+		}
+		return VerifySignature(pubkeys[i], messages[i], sigs[i])
+	}
+	var failed []int
+	if threads := runtime.GOMAXPROCS(0); n < minParallelBatch || threads == 1 {
+		for i := 0; i < n; i++ {
+			if !verify(i) {
+				failed = append(failed, i)
+			}
+		}
+	} else {
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			next = make(chan int, n)
+		)
+		for i := 0; i < n; i++ {
+			next <- i
+		}
+		close(next)
+
+		if threads > n {
+			threads = n
+		}
+		wg.Add(threads)
+		for t := 0; t < threads; t++ {
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					if !verify(i) {
+						mu.Lock()
+						failed = append(failed, i)
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		sort.Ints(failed)
+	}
+	return len(failed) == 0, failed
+}
@@ -18,6 +18,7 @@ package crypto
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -66,6 +67,15 @@ const PubkeyLength = 57
 const PrivkeyLength = 57
 const ExtendedSignatureLength = SignatureLength + PubkeyLength
 
+var (
+	// ErrInvalidKeyLength is returned when parsing a private key of wrong size.
+	ErrInvalidKeyLength = errors.New("invalid private key length")
+
+	// ErrInvalidHex is returned when a hex encoded private key contains
+	// characters other than hex digits.
+	ErrInvalidHex = errors.New("invalid hex character in private key")
+)
+
 var errInvalidPubkey = errors.New("invalid public key")
 var errInvalidPrivkey = errors.New("invalid private key")
 var errInvalidSignature = errors.New("invalid signature")
@@ -100,6 +110,28 @@ func SHA3Hash(data ...[]byte) (h common.Hash) {
 	return h
 }
 
+// SHA3Writer incrementally computes the SHA3 hash of the data written to it,
+// allowing large inputs to be hashed without holding them fully in memory.
+type SHA3Writer struct {
+	state SHA3State
+}
+
+// NewSHA3Writer creates a writer that hashes everything written to it.
+func NewSHA3Writer() *SHA3Writer {
+	return &SHA3Writer{state: sha3.New256().(SHA3State)}
+}
+
+// Write feeds more data into the running hash. It never returns an error.
+func (w *SHA3Writer) Write(p []byte) (int, error) {
+	return w.state.Write(p)
+}
+
+// Sum returns the SHA3 hash of the data written so far. It does not change the
+// underlying hash state, so more data may be written afterwards.
+func (w *SHA3Writer) Sum() []byte {
+	return w.state.Sum(nil)
+}
+
 // Keccak512 calculates and returns the Keccak512 hash of the input data.
 func Keccak512(data ...[]byte) []byte {
 	d := sha3.NewLegacyKeccak512()
@@ -137,24 +169,52 @@ func PubkeyToAddress(p *PublicKey) common.Address {
 // UnmarshalPrivateKey creates a private key with the given D value.
 func UnmarshalPrivateKey(d []byte) (*PrivateKey, error) {
 	if len(d) != PrivkeyLength {
-		return nil, errInvalidPrivkey
+		return nil, ErrInvalidKeyLength
 	}
 	priv := PrivateKey{}
 	copy(priv.privateKey[:], d[:])
 	return &priv, nil
 }
 
-// UnmarshalPrivateKeyHex parses a private key.
+// UnmarshalPrivateKeyHex parses a private key. The key material is decoded in
+// constant time, so the time taken to parse a key doesn't leak its contents.
 func UnmarshalPrivateKeyHex(hexkey string) (*PrivateKey, error) {
-	b, err := hex.DecodeString(hexkey)
-	if byteErr, ok := err.(hex.InvalidByteError); ok {
-		return nil, fmt.Errorf("invalid hex character %q in private key", byte(byteErr))
-	} else if err != nil {
-		return nil, errors.New("invalid hex data for private key")
+	if len(hexkey) != 2*PrivkeyLength {
+		return nil, ErrInvalidKeyLength
+	}
+	b := make([]byte, PrivkeyLength)
+	defer zeroBytes(b)
+
+	if !decodeHexConstantTime(b, hexkey) {
+		return nil, ErrInvalidHex
 	}
 	return UnmarshalPrivateKey(b)
 }
 
+// decodeHexConstantTime decodes the hex string src into dst, which must be half
+// its length. Unlike hex.Decode, the running time only depends on the length of
+// the input, not on its contents. It reports whether the input was valid hex.
+func decodeHexConstantTime(dst []byte, src string) bool {
+	var invalid uint32
+	for i := 0; i < len(dst); i++ {
+		hi, hiOk := hexNibble(uint32(src[2*i]))
+		lo, loOk := hexNibble(uint32(src[2*i+1]))
+		dst[i] = byte(hi<<4 | lo)
+		invalid |= (hiOk & loOk) ^ 1
+	}
+	return invalid == 0
+}
+
+// hexNibble converts a hex character to its value without branching on it. The
+// second return value is 1 if the character is a valid hex digit, 0 otherwise.
+func hexNibble(c uint32) (uint32, uint32) {
+	num := c ^ '0'
+	numMask := ((num - 10) >> 8) & 0xff // 0xff if c is a decimal digit
+	alpha := (c &^ 32) - 55
+	alphaMask := (((alpha - 10) ^ (alpha - 16)) >> 8) & 0xff // 0xff if c is a letter a-f or A-F
+	return (numMask & num) | (alphaMask & alpha), (numMask | alphaMask) & 1
+}
+
 // MarshalPrivateKey exports a private key into a binary dump.
 func MarshalPrivateKey(priv *PrivateKey) []byte {
 	if priv == nil {
@@ -197,6 +257,11 @@ func LoadEDDSA(file string) (*PrivateKey, error) {
 }
 
 // Ecrecover returns the public key that created the given signature.
+//
+// Ed448 signatures can't be inverted to derive the signer the way secp256k1 ones
+// can. Instead, the extended signatures produced by Sign carry the public key of
+// the signer after the plain signature, which is returned once the signature is
+// verified against it. Plain signatures are rejected.
 func Ecrecover(hash, sig []byte) ([]byte, error) {
 	pubkey, err := SigToPub(hash, sig)
 	if err != nil {
@@ -205,6 +270,44 @@ func Ecrecover(hash, sig []byte) ([]byte, error) {
 	return pubkey[:], nil
 }
 
+// VerifyAndExtract verifies a signature over hash and returns the address of
+// the signer. The signature is either an extended one as produced by Sign, in
+// which case pub may be nil, or a plain signature of SignatureLength bytes along
+// with the public key of the signer. If both an extended signature and a public
+// key are given, they have to match.
+func VerifyAndExtract(pub, hash, sig []byte) (common.Address, error) {
+	switch len(sig) {
+	case SignatureLength:
+		if len(pub) != PubkeyLength {
+			return common.Address{}, errInvalidPubkey
+		}
+		sig = append(common.CopyBytes(sig), pub...)
+	case ExtendedSignatureLength:
+		if pub != nil && !bytes.Equal(pub, sig[SignatureLength:]) {
+			return common.Address{}, errInvalidPubkey
+		}
+	default:
+		return common.Address{}, errInvalidSignature
+	}
+	pubkey, err := SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return PubkeyToAddress(pubkey), nil
+}
+
+// VerifyWithAddress checks that the public key belongs to the given address and
+// created the signature over hash. The signature is either a plain one or an
+// extended one as produced by Sign, whose embedded public key must match. It
+// returns false on any mismatch or malformed input.
+func VerifyWithAddress(addr common.Address, pubkey, hash, sig []byte) bool {
+	if len(pubkey) != PubkeyLength {
+		return false
+	}
+	signer, err := VerifyAndExtract(pubkey, hash, sig)
+	return err == nil && signer == addr
+}
+
 // SaveEDDSA saves a private key to the given file with
 // restrictive permissions. The key data is saved hex-encoded.
 func SaveEDDSA(file string, key *PrivateKey) error {
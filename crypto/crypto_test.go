@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/core-coin/go-core/v2/common"
@@ -39,6 +40,35 @@ func TestSHA3Hash(t *testing.T) {
 	checkhash(t, "Sha3-256-array", func(in []byte) []byte { h := SHA3Hash(in); return h[:] }, msg, exp)
 }
 
+func TestSHA3Writer(t *testing.T) {
+	var (
+		chunks [][]byte
+		blob   []byte
+	)
+	for i := 0; i < 100; i++ {
+		chunk := bytes.Repeat([]byte{byte(i)}, i*7)
+		chunks = append(chunks, chunk)
+		blob = append(blob, chunk...)
+	}
+	w := NewSHA3Writer()
+	if !bytes.Equal(w.Sum(), SHA3()) {
+		t.Fatalf("empty writer hash mismatch: have %x, want %x", w.Sum(), SHA3())
+	}
+	for i, chunk := range chunks {
+		if n, err := w.Write(chunk); n != len(chunk) || err != nil {
+			t.Fatalf("chunk %d: write failed: n=%d err=%v", i, n, err)
+		}
+	}
+	if have, want := w.Sum(), SHA3(blob); !bytes.Equal(have, want) {
+		t.Fatalf("streamed hash mismatch: have %x, want %x", have, want)
+	}
+	// Summing must not disturb the running state
+	w.Write([]byte("abc"))
+	if have, want := w.Sum(), SHA3(blob, []byte("abc")); !bytes.Equal(have, want) {
+		t.Fatalf("continued hash mismatch: have %x, want %x", have, want)
+	}
+}
+
 func TestToEDDSAErrors(t *testing.T) {
 	if _, err := UnmarshalPrivateKeyHex("0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
 		t.Fatal("UnmarshalPrivateKeyHex should've returned error")
@@ -48,6 +78,49 @@ func TestToEDDSAErrors(t *testing.T) {
 	}
 }
 
+func TestUnmarshalPrivateKeyHexErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{input: testPrivHex},
+		{input: strings.ToUpper(testPrivHex)},
+		{input: "", err: ErrInvalidKeyLength},
+		{input: testPrivHex[:len(testPrivHex)-2], err: ErrInvalidKeyLength},
+		{input: testPrivHex[:len(testPrivHex)-1], err: ErrInvalidKeyLength},
+		{input: testPrivHex + "00", err: ErrInvalidKeyLength},
+		{input: "0x" + testPrivHex[2:], err: ErrInvalidHex},
+		{input: testPrivHex[:len(testPrivHex)-1] + "g", err: ErrInvalidHex},
+		{input: "G" + testPrivHex[1:], err: ErrInvalidHex},
+		{input: testPrivHex[:10] + " " + testPrivHex[11:], err: ErrInvalidHex},
+		{input: testPrivHex[:10] + "/:@`" + testPrivHex[14:], err: ErrInvalidHex},
+	}
+	for _, test := range tests {
+		key, err := UnmarshalPrivateKeyHex(test.input)
+		if err != test.err {
+			t.Errorf("input %q: error mismatch: have %v, want %v", test.input, err, test.err)
+			continue
+		}
+		if err == nil {
+			want, _ := hex.DecodeString(test.input)
+			if !bytes.Equal(key.PrivateKey(), want) {
+				t.Errorf("input %q: key mismatch: have %x", test.input, key.PrivateKey())
+			}
+		}
+	}
+	// Cross-check the decoder against the standard library on every character
+	for c := 0; c < 256; c++ {
+		in := strings.Repeat(string([]byte{byte(c)}), 2)
+		dst := make([]byte, 1)
+		want, err := hex.DecodeString(in)
+		if ok := decodeHexConstantTime(dst, in); ok != (err == nil) {
+			t.Fatalf("char %#x: validity mismatch: have %v, want %v", c, ok, err == nil)
+		} else if ok && dst[0] != want[0] {
+			t.Fatalf("char %#x: value mismatch: have %x, want %x", c, dst[0], want[0])
+		}
+	}
+}
+
 func BenchmarkSha3(b *testing.B) {
 	a := []byte("hello world")
 	for i := 0; i < b.N; i++ {
@@ -169,7 +242,7 @@ func TestLoadEDDSA(t *testing.T) {
 		},
 		{
 			input: "69bb68c3a00a0cd9cbf2cab316476228c758329bbfe0b1759e8634694a9497afea05bcbf24e2aa0627eac4240484bb71de646a9296872a3c0X",
-			err:   "invalid hex character 'X' in private key",
+			err:   "invalid hex character in private key",
 		},
 		{
 			input: "69bb68c3a00a0cd9cbf2cab316476228c758329bbfe0b1759e8634694a9497afea05bcbf24e2aa0627eac4240484bb71de646a9296872a3c0eX",
@@ -0,0 +1,106 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	minSeedLength    = 16         // Minimum seed length accepted, as in BIP-32
+	maxSeedLength    = 64         // Maximum seed length accepted, as in BIP-32
+	hardenedKeyStart = 0x80000000 // Index of the first hardened child key
+	chainCodeLength  = 32         // Length of the chain code of a derived key
+)
+
+// masterKeyDomain is the domain separator used when deriving the master key
+// from a seed.
+var masterKeyDomain = []byte("ed448 seed")
+
+var (
+	errInvalidSeedLength = fmt.Errorf("seed length must be between %d and %d bytes", minSeedLength, maxSeedLength)
+	errNonHardenedIndex  = errors.New("ed448 keys only support hardened derivation")
+)
+
+// DeriveEd448 deterministically derives the private key at the given path from
+// a seed of 16 to 64 bytes, similarly to SLIP-0010. The derived key can be used
+// like any other, e.g. its address is returned by PubkeyToAddress.
+//
+// The path is of the form m/44'/654'/0'/0'/0'. As with SLIP-0010 for Ed25519,
+// there is no public parent to public child derivation, so every component has
+// to be hardened. Since Ed448 keys are 57 bytes long, the HMAC-SHA512 function of
+// SLIP-0010 is replaced by SHAKE256 producing a 57 byte key and a 32 byte chain
+// code:
+//
+//	master:    SHAKE256("ed448 seed" || seed)
+//	child(i):  SHAKE256(chain || 0x00 || key || ser32(i))
+func DeriveEd448(seed []byte, path string) (*PrivateKey, error) {
+	if len(seed) < minSeedLength || len(seed) > maxSeedLength {
+		return nil, errInvalidSeedLength
+	}
+	indices, err := parseHardenedPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chain := deriveKeyAndChain(masterKeyDomain, seed)
+	for _, index := range indices {
+		var ser [4]byte
+		binary.BigEndian.PutUint32(ser[:], index)
+
+		key, chain = deriveKeyAndChain(chain, []byte{0x00}, key, ser[:])
+	}
+	return UnmarshalPrivateKey(key)
+}
+
+// deriveKeyAndChain hashes the given data into a private key and a chain code.
+func deriveKeyAndChain(data ...[]byte) (key, chain []byte) {
+	h := sha3.NewShake256()
+	for _, b := range data {
+		h.Write(b)
+	}
+	out := make([]byte, PrivkeyLength+chainCodeLength)
+	h.Read(out)
+	return out[:PrivkeyLength], out[PrivkeyLength:]
+}
+
+// parseHardenedPath converts a derivation path of the form m/44'/654'/0' into
+// the child indices, rejecting non-hardened components.
+func parseHardenedPath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if strings.TrimSpace(components[0]) != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with 'm'", path)
+	}
+	indices := make([]uint32, 0, len(components)-1)
+	for _, component := range components[1:] {
+		component = strings.TrimSpace(component)
+		if !strings.HasSuffix(component, "'") {
+			return nil, fmt.Errorf("invalid component %q: %v", component, errNonHardenedIndex)
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(component, "'"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid component %q", component)
+		}
+		indices = append(indices, hardenedKeyStart+uint32(index))
+	}
+	return indices, nil
+}
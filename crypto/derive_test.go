@@ -0,0 +1,89 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/common"
+)
+
+// Tests that key derivation matches fixed test vectors, so derived keys stay
+// stable across versions.
+func TestDeriveEd448Vectors(t *testing.T) {
+	seed := common.Hex2Bytes("000102030405060708090a0b0c0d0e0f")
+
+	tests := []struct {
+		path    string
+		key     string
+		address string
+	}{
+		{"m", "737ce855344f2e4896f34447b66edaab2a0ab2fe4bb49480cb82934a74c29f71facbcdf80ccfa8e73b3f69441957bdc9f29215be9ff1f1564c", "cb11204558305d0b5ecd873ea46fdbf18cea0be84468"},
+		{"m/0'", "b9ae7107d6221c10971b435d7ae9d5f12177a1c7a27fecbde00be7b95dd8c4863a48fd904785e9fe047d1528acf5e0be48819c1632f4b7876c", "cb530e675499dd7475656b6c07470bc7433b4450b9c4"},
+		{"m/44'/654'/0'/0'/0'", "23d1a4fcc2c7b400c4164397598f3f485f689641e5e73c9b3f497130e3582f7ee2148bdd7c3de02b3509c23cc540ca3227dfe00f714db38da8", "cb49eaedef135b19b6942b34b564c13e347e820d1767"},
+	}
+	for _, tt := range tests {
+		key, err := DeriveEd448(seed, tt.path)
+		if err != nil {
+			t.Fatalf("%s: derivation failed: %v", tt.path, err)
+		}
+		if have := hex.EncodeToString(key.PrivateKey()); have != tt.key {
+			t.Errorf("%s: key mismatch: have %s, want %s", tt.path, have, tt.key)
+		}
+		if have := PubkeyToAddress(key.PublicKey()); have.Hex() != tt.address {
+			t.Errorf("%s: address mismatch: have %s, want %s", tt.path, have.Hex(), tt.address)
+		}
+		// Derived keys should be usable for signing
+		hash := SHA3([]byte("derived"))
+		sig, err := Sign(hash, key)
+		if err != nil {
+			t.Fatalf("%s: signing failed: %v", tt.path, err)
+		}
+		pub, err := Ecrecover(hash, sig)
+		if err != nil {
+			t.Fatalf("%s: recovery failed: %v", tt.path, err)
+		}
+		if !bytes.Equal(pub, key.PublicKey()[:]) {
+			t.Errorf("%s: recovered public key mismatch", tt.path)
+		}
+	}
+}
+
+func TestDeriveEd448Invalid(t *testing.T) {
+	seed := common.Hex2Bytes("000102030405060708090a0b0c0d0e0f")
+
+	tests := []struct {
+		seed []byte
+		path string
+	}{
+		{seed[:15], "m"},            // seed too short
+		{make([]byte, 65), "m"},     // seed too long
+		{seed, ""},                  // empty path
+		{seed, "44'/654'"},          // relative path
+		{seed, "m/44'/654'/0'/0/0"}, // non-hardened component
+		{seed, "m/44'/x'"},          // non-numeric component
+		{seed, "m/2147483648'"},     // component out of range
+		{seed, "m/-1'"},             // negative component
+	}
+	for _, tt := range tests {
+		if key, err := DeriveEd448(tt.seed, tt.path); err == nil {
+			t.Errorf("seed %x, path %q: expected error, got key %x", tt.seed, tt.path, key.PrivateKey())
+		}
+	}
+}
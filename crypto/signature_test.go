@@ -18,6 +18,8 @@ package crypto
 
 import (
 	"bytes"
+	"crypto/rand"
+	"reflect"
 	"testing"
 
 	"github.com/core-coin/go-core/v2/common"
@@ -93,3 +95,163 @@ func BenchmarkVerifySignature(b *testing.B) {
 		}
 	}
 }
+
+func makeBatch(tb testing.TB, n int) (pubkeys, messages, sigs [][]byte) {
+	for i := 0; i < n; i++ {
+		key, err := GenerateKey(rand.Reader)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		msg := SHA3([]byte{byte(i), byte(i >> 8)})
+		sig, err := Sign(msg, key)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubkeys = append(pubkeys, key.PublicKey()[:])
+		messages = append(messages, msg)
+		sigs = append(sigs, sig)
+	}
+	return pubkeys, messages, sigs
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, n := range []int{0, 1, minParallelBatch - 1, 3 * minParallelBatch} {
+		pubkeys, messages, sigs := makeBatch(t, n)
+		if ok, failed := VerifyBatch(pubkeys, messages, sigs); !ok || len(failed) != 0 {
+			t.Fatalf("batch of %d: valid signatures rejected: %v", n, failed)
+		}
+		if n < 2 {
+			continue
+		}
+		// Corrupt a mix of signatures, messages and keys
+		want := []int{0, n / 2, n - 1}
+		sigs[0] = common.CopyBytes(sigs[0])
+		sigs[0][3]++
+		messages[n/2] = SHA3([]byte("tampered"))
+		pubkeys[n-1] = pubkeys[0]
+
+		ok, failed := VerifyBatch(pubkeys, messages, sigs)
+		if ok {
+			t.Fatalf("batch of %d: invalid signatures accepted", n)
+		}
+		if !reflect.DeepEqual(failed, want) {
+			t.Fatalf("batch of %d: failed indices mismatch: have %v, want %v", n, failed, want)
+		}
+	}
+	// Entries missing a component must fail
+	pubkeys, messages, sigs := makeBatch(t, 3)
+	if ok, failed := VerifyBatch(pubkeys, messages[:2], sigs); ok || !reflect.DeepEqual(failed, []int{2}) {
+		t.Fatalf("incomplete batch: have %v %v, want false [2]", ok, failed)
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	pubkeys, messages, sigs := makeBatch(b, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, _ := VerifyBatch(pubkeys, messages, sigs); !ok {
+			b.Fatal("verify error")
+		}
+	}
+}
+
+func BenchmarkVerifySerial(b *testing.B) {
+	pubkeys, messages, sigs := makeBatch(b, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range sigs {
+			if !VerifySignature(pubkeys[j], messages[j], sigs[j]) {
+				b.Fatal("verify error")
+			}
+		}
+	}
+}
+
+func TestVerifyAndExtract(t *testing.T) {
+	key, _ := UnmarshalPrivateKeyHex(testPrivHex)
+	want, err := common.HexToAddress(testAddrHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := Sign(testmsg, key)
+	if err != nil {
+		t.Fatalf("Sign error: %s", err)
+	}
+	pub := key.PublicKey()[:]
+
+	// Extended signature, with and without the public key
+	for _, p := range [][]byte{nil, pub} {
+		addr, err := VerifyAndExtract(p, testmsg, sig)
+		if err != nil {
+			t.Fatalf("extended signature rejected: %v", err)
+		}
+		if addr != want {
+			t.Errorf("address mismatch: have %x, want %x", addr, want)
+		}
+	}
+	// Plain signature along with the public key
+	addr, err := VerifyAndExtract(pub, testmsg, sig[:SignatureLength])
+	if err != nil {
+		t.Fatalf("plain signature rejected: %v", err)
+	}
+	if addr != want {
+		t.Errorf("address mismatch: have %x, want %x", addr, want)
+	}
+	// Invalid inputs
+	if _, err := VerifyAndExtract(nil, testmsg, sig[:SignatureLength]); err != errInvalidPubkey {
+		t.Errorf("plain signature without key: have %v, want %v", err, errInvalidPubkey)
+	}
+	if _, err := VerifyAndExtract(testpubkey, testmsg, sig); err != errInvalidPubkey {
+		t.Errorf("mismatching key: have %v, want %v", err, errInvalidPubkey)
+	}
+	if _, err := VerifyAndExtract(testpubkey, testmsg, sig[:SignatureLength]); err != errInvalidSignature {
+		t.Errorf("wrong key: have %v, want %v", err, errInvalidSignature)
+	}
+	if _, err := VerifyAndExtract(nil, testmsg[1:], sig); err != errInvalidSignature {
+		t.Errorf("wrong message: have %v, want %v", err, errInvalidSignature)
+	}
+	if _, err := VerifyAndExtract(nil, testmsg, sig[1:]); err != errInvalidSignature {
+		t.Errorf("truncated signature: have %v, want %v", err, errInvalidSignature)
+	}
+}
+
+func TestVerifyWithAddress(t *testing.T) {
+	key, _ := UnmarshalPrivateKeyHex(testPrivHex)
+	addr, err := common.HexToAddress(testAddrHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := Sign(testmsg, key)
+	if err != nil {
+		t.Fatalf("Sign error: %s", err)
+	}
+	pub := key.PublicKey()[:]
+
+	if !VerifyWithAddress(addr, pub, testmsg, sig) {
+		t.Errorf("extended signature rejected")
+	}
+	if !VerifyWithAddress(addr, pub, testmsg, sig[:SignatureLength]) {
+		t.Errorf("plain signature rejected")
+	}
+	// Mismatched public key and address pairs
+	other, _ := GenerateKey(rand.Reader)
+	if VerifyWithAddress(other.Address(), pub, testmsg, sig) {
+		t.Errorf("signature accepted for foreign address")
+	}
+	if VerifyWithAddress(addr, other.PublicKey()[:], testmsg, sig[:SignatureLength]) {
+		t.Errorf("signature accepted for foreign public key")
+	}
+	if VerifyWithAddress(addr, testpubkey, testmsg, sig) {
+		t.Errorf("signature accepted for public key not embedded in it")
+	}
+	// Malformed inputs
+	if VerifyWithAddress(addr, nil, testmsg, sig) {
+		t.Errorf("signature accepted without public key")
+	}
+	if VerifyWithAddress(addr, pub, testmsg[1:], sig) {
+		t.Errorf("signature accepted for wrong message")
+	}
+	if VerifyWithAddress(addr, pub, testmsg, sig[1:]) {
+		t.Errorf("truncated signature accepted")
+	}
+}
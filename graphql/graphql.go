@@ -20,7 +20,6 @@ package graphql
 import (
 	"context"
 	"errors"
-	"math/big"
 	"time"
 
 	"github.com/core-coin/go-core/v2/internal/xcbapi"
@@ -215,7 +214,14 @@ func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account,
 	if err != nil || tx == nil {
 		return nil, err
 	}
-	signer := types.NewNucleusSigner(big.NewInt(int64(tx.NetworkID())))
+	signer := types.LatestSigner(t.backend.ChainConfig())
+	if t.block != nil {
+		header, err := t.block.resolveHeader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		signer = types.MakeBlockSigner(t.backend.ChainConfig(), header.Number)
+	}
 	from, err := types.Sender(signer, tx)
 	if err != nil {
 		return nil, err
@@ -23,6 +23,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/core-coin/go-core/v2/log"
 	"github.com/core-coin/go-core/v2/metrics"
@@ -44,6 +45,20 @@ var (
 		Usage: "Per-module verbosity: comma-separated list of <pattern>=<level> (e.g. xcb/*=5,p2p=4)",
 		Value: "",
 	}
+	logjsonFlag = cli.BoolFlag{
+		Name:  "log.json",
+		Usage: "Format logs with JSON, one object per record",
+	}
+	logjsonAllowFlag = cli.StringFlag{
+		Name:  "log.json.allow",
+		Usage: "Comma-separated list of log fields to retain in JSON logs (default = all)",
+		Value: "",
+	}
+	logjsonDenyFlag = cli.StringFlag{
+		Name:  "log.json.deny",
+		Usage: "Comma-separated list of log fields to strip from JSON logs",
+		Value: "",
+	}
 	backtraceAtFlag = cli.StringFlag{
 		Name:  "backtrace",
 		Usage: "Request a stack trace at a specific logging statement (e.g. \"block.go:271\")",
@@ -88,7 +103,8 @@ var (
 
 // Flags holds all command-line flags required for debugging.
 var Flags = []cli.Flag{
-	verbosityFlag, vmoduleFlag, backtraceAtFlag, debugFlag,
+	verbosityFlag, vmoduleFlag, logjsonFlag, logjsonAllowFlag, logjsonDenyFlag,
+	backtraceAtFlag, debugFlag,
 	pprofFlag, pprofAddrFlag, pprofPortFlag, memprofilerateFlag,
 	blockprofilerateFlag, cpuprofileFlag, traceFlag,
 }
@@ -112,6 +128,12 @@ func init() {
 // It should be called as early as possible in the program.
 func Setup(ctx *cli.Context) error {
 	// logging
+	if ctx.GlobalBool(logjsonFlag.Name) {
+		allow := splitFields(ctx.GlobalString(logjsonAllowFlag.Name))
+		deny := splitFields(ctx.GlobalString(logjsonDenyFlag.Name))
+		ostream = log.JSONHandler(os.Stderr, allow, deny)
+		glogger.SetHandler(ostream)
+	}
 	log.PrintOrigins(ctx.GlobalBool(debugFlag.Name))
 	glogger.Verbosity(log.Lvl(ctx.GlobalInt(verbosityFlag.Name)))
 	glogger.Vmodule(ctx.GlobalString(vmoduleFlag.Name))
@@ -147,6 +169,17 @@ func Setup(ctx *cli.Context) error {
 	return nil
 }
 
+// splitFields splits a comma-separated list of log fields, dropping empty ones.
+func splitFields(list string) []string {
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 func StartPProf(address string, withMetrics bool) {
 	// Hook go-metrics into expvar on any /debug/metrics request, load all vars
 	// from the registry into expvar, and execute regular expvar handler.
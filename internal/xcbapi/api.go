@@ -38,6 +38,7 @@ import (
 	"github.com/core-coin/go-core/v2/common/math"
 	"github.com/core-coin/go-core/v2/consensus/clique"
 	"github.com/core-coin/go-core/v2/core"
+	"github.com/core-coin/go-core/v2/core/state"
 	"github.com/core-coin/go-core/v2/core/types"
 	"github.com/core-coin/go-core/v2/core/vm"
 	"github.com/core-coin/go-core/v2/crypto"
@@ -116,7 +117,7 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range pending {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, s.b.ChainConfig().NetworkID)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, s.b.ChainConfig())
 		}
 		content["pending"][account.Hex()] = dump
 	}
@@ -124,7 +125,7 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range queue {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, s.b.ChainConfig().NetworkID)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, s.b.ChainConfig())
 		}
 		content["queued"][account.Hex()] = dump
 	}
@@ -567,25 +568,23 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 		codeHash = crypto.SHA3Hash(nil)
 	}
 
-	// create the proof for the storageKeys
+	// create the accountProof and the proofs for the storageKeys
+	keys := make([]common.Hash, len(storageKeys))
+	for i, key := range storageKeys {
+		keys[i] = common.HexToHash(key)
+	}
+	accountProof, proofs, proofErr := state.GetStorageProof(address, keys)
+	if proofErr != nil {
+		return nil, proofErr
+	}
 	for i, key := range storageKeys {
 		if storageTrie != nil {
-			proof, storageError := state.GetStorageProof(address, common.HexToHash(key))
-			if storageError != nil {
-				return nil, storageError
-			}
-			storageProof[i] = StorageResult{key, (*hexutil.Big)(state.GetState(address, common.HexToHash(key)).Big()), toHexSlice(proof)}
+			storageProof[i] = StorageResult{key, (*hexutil.Big)(state.GetState(address, keys[i]).Big()), toHexSlice(proofs[i])}
 		} else {
 			storageProof[i] = StorageResult{key, &hexutil.Big{}, []string{}}
 		}
 	}
 
-	// create the accountProof
-	accountProof, proofErr := state.GetProof(address)
-	if proofErr != nil {
-		return nil, proofErr
-	}
-
 	return &AccountResult{
 		Address:      address,
 		AccountProof: toHexSlice(accountProof),
@@ -775,13 +774,13 @@ func (args *CallArgs) ToMessage(globalEnergyCap uint64) types.Message {
 	return msg
 }
 
-// account indicates the overriding fields of account during the execution of
-// a message call.
+// OverrideAccount indicates the overriding fields of account during the execution
+// of a message call.
 // Note, state and stateDiff can't be specified at the same time. If state is
 // set, message execution will only use the data in the given state. Otherwise
 // if statDiff is set, all diff will be applied first and then execute the call
 // message.
-type account struct {
+type OverrideAccount struct {
 	Nonce     *hexutil.Uint64              `json:"nonce"`
 	Code      *hexutil.Bytes               `json:"code"`
 	Balance   **hexutil.Big                `json:"balance"`
@@ -789,15 +788,15 @@ type account struct {
 	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
 }
 
-func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides map[common.Address]account, vmCfg vm.Config, timeout time.Duration, globalEnergyCap uint64) (*core.ExecutionResult, error) {
-	defer func(start time.Time) { log.Debug("Executing CVM call finished", "runtime", time.Since(start)) }(time.Now())
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
 
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if state == nil || err != nil {
-		return nil, err
+// Apply overrides the fields of specified accounts into the given state.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
 	}
-	// Override the fields of specified contracts before execution.
-	for addr, account := range overrides {
+	for addr, account := range *diff {
 		// Override account nonce.
 		if account.Nonce != nil {
 			state.SetNonce(addr, uint64(*account.Nonce))
@@ -811,7 +810,7 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 			state.SetBalance(addr, (*big.Int)(*account.Balance))
 		}
 		if account.State != nil && account.StateDiff != nil {
-			return nil, fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
 		}
 		// Replace entire state if caller requires.
 		if account.State != nil {
@@ -824,6 +823,25 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 			}
 		}
 	}
+	return nil
+}
+
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalEnergyCap uint64) (*core.ExecutionResult, error) {
+	defer func(start time.Time) { log.Debug("Executing CVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	return doCall(ctx, b, args, state, header, timeout, globalEnergyCap)
+}
+
+// doCall executes the given call on top of the provided state, which will be
+// modified by the execution.
+func doCall(ctx context.Context, b Backend, args CallArgs, state *state.StateDB, header *types.Header, timeout time.Duration, globalEnergyCap uint64) (*core.ExecutionResult, error) {
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered energy, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -902,12 +920,8 @@ func (e *revertError) ErrorData() interface{} {
 //
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *map[common.Address]account) (hexutil.Bytes, error) {
-	var accounts map[common.Address]account
-	if overrides != nil {
-		accounts = *overrides
-	}
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, accounts, vm.Config{}, 5*time.Second, s.b.RPCEnergyCap())
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, vm.Config{}, 5*time.Second, s.b.RPCEnergyCap())
 	if err != nil {
 		return nil, err
 	}
@@ -918,19 +932,80 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOr
 	return result.Return(), result.Err
 }
 
+// CallResult is the outcome of a single call executed by Multicall.
+type CallResult struct {
+	Return     hexutil.Bytes  `json:"return"`
+	EnergyUsed hexutil.Uint64 `json:"energyUsed"`
+	Error      string         `json:"error,omitempty"`
+	Revert     hexutil.Bytes  `json:"revert,omitempty"` // Raw revert data, if the call reverted
+}
+
+// Multicall executes a batch of calls against the state of the given block,
+// returning the result of each call. All calls are executed on the same state
+// snapshot, but are isolated from each other: state changes made by a call are
+// discarded before running the next one.
+//
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// which is applied once, before executing any of the calls.
+//
+// A failing call doesn't abort the batch, its error is reported in its result
+// instead. The method only returns an error if the state is unavailable or the
+// batch can't be executed at all (e.g. because of a timeout).
+func (s *PublicBlockChainAPI) Multicall(ctx context.Context, calls []CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) ([]CallResult, error) {
+	defer func(start time.Time) { log.Debug("Executing CVM multicall finished", "calls", len(calls), "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	// Share a single timeout between all the calls of the batch
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	results := make([]CallResult, len(calls))
+	for i, args := range calls {
+		snapshot := state.Snapshot()
+		result, err := doCall(ctx, s.b, args, state, header, 0, s.b.RPCEnergyCap())
+		state.RevertToSnapshot(snapshot)
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", 5*time.Second)
+		}
+		if result != nil {
+			results[i].EnergyUsed = hexutil.Uint64(result.UsedEnergy)
+		}
+		switch {
+		case err != nil:
+			results[i].Error = err.Error()
+		case len(result.Revert()) > 0:
+			revert := newRevertError(result)
+			results[i].Error, results[i].Revert = revert.Error(), result.Revert()
+		case result.Err != nil:
+			results[i].Error = result.Err.Error()
+		default:
+			results[i].Return = result.Return()
+		}
+	}
+	return results, nil
+}
+
 func DoEstimateEnergy(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, energyCap uint64) (hexutil.Uint64, error) {
 	// Binary search the energy requirement, as it may be higher than the amount used
 	var (
-		lo  uint64 = params.TxEnergy - 1
-		hi  uint64
-		cap uint64
+		txEnergy = b.ChainConfig().EnergyCosts().TxEnergy
+		lo       = txEnergy - 1
+		hi       uint64
+		cap      uint64
 	)
 	// Use zero address if sender unspecified.
 	if args.From == nil {
 		args.From = new(common.Address)
 	}
 	// Determine the highest energy limit can be used during the estimation.
-	if args.Energy != nil && uint64(*args.Energy) >= params.TxEnergy {
+	if args.Energy != nil && uint64(*args.Energy) >= txEnergy {
 		hi = uint64(*args.Energy)
 	} else {
 		// Retrieve the block to act as the energy ceiling
@@ -1123,7 +1198,7 @@ func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 // RPCMarshalBlock converts the given block to the RPC output which depends on fullTx. If inclTx is true transactions are
 // returned. When fullTx is true the returned block contains full transaction details, otherwise it will only contain
 // transaction hashes.
-func RPCMarshalBlock(block *types.Block, inclTx bool, fullTx bool, networkID *big.Int) (map[string]interface{}, error) {
+func RPCMarshalBlock(block *types.Block, inclTx bool, fullTx bool, config *params.ChainConfig) (map[string]interface{}, error) {
 	fields := RPCMarshalHeader(block.Header())
 	fields["size"] = hexutil.Uint64(block.Size())
 
@@ -1133,7 +1208,7 @@ func RPCMarshalBlock(block *types.Block, inclTx bool, fullTx bool, networkID *bi
 		}
 		if fullTx {
 			formatTx = func(tx *types.Transaction) (interface{}, error) {
-				return newRPCTransactionFromBlockHash(block, tx.Hash(), networkID), nil
+				return newRPCTransactionFromBlockHash(block, tx.Hash(), config), nil
 			}
 		}
 		txs := block.Transactions()
@@ -1167,7 +1242,7 @@ func (s *PublicBlockChainAPI) rpcMarshalHeader(ctx context.Context, header *type
 // rpcMarshalBlock uses the generalized output filler, then adds the total difficulty field, which requires
 // a `PublicBlockchainAPI`.
 func (s *PublicBlockChainAPI) rpcMarshalBlock(ctx context.Context, b *types.Block, inclTx bool, fullTx bool) (map[string]interface{}, error) {
-	fields, err := RPCMarshalBlock(b, inclTx, fullTx, s.b.ChainConfig().NetworkID)
+	fields, err := RPCMarshalBlock(b, inclTx, fullTx, s.b.ChainConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -1179,25 +1254,30 @@ func (s *PublicBlockChainAPI) rpcMarshalBlock(ctx context.Context, b *types.Bloc
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        *common.Hash    `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Energy           hexutil.Uint64  `json:"energy"`
-	EnergyPrice      *hexutil.Big    `json:"energyPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	NetworkID        hexutil.Uint64  `json:"network_id"`
-	Signature        hexutil.Bytes   `json:"signature"`
-	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
+	BlockHash        *common.Hash      `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Energy           hexutil.Uint64    `json:"energy"`
+	EnergyPrice      *hexutil.Big      `json:"energyPrice"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	NetworkID        hexutil.Uint64    `json:"network_id"`
+	Signature        hexutil.Bytes     `json:"signature"`
+	TransactionIndex *hexutil.Uint64   `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	Type             hexutil.Uint64    `json:"type,omitempty"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
 // representation, with the given location metadata set (if available).
-func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, networkID *big.Int) *RPCTransaction {
-	signer := types.NewNucleusSigner(networkID)
+func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, config *params.ChainConfig) *RPCTransaction {
+	signer := types.LatestSigner(config)
+	if blockHash != (common.Hash{}) {
+		signer = types.MakeBlockSigner(config, new(big.Int).SetUint64(blockNumber))
+	}
 	from, err := types.Sender(signer, tx)
 	if err != nil {
 		log.Error("newRPCTransaction signature or recipient error", "err", err)
@@ -1213,6 +1293,11 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		NetworkID:   hexutil.Uint64(tx.NetworkID()),
 		Signature:   hexutil.Bytes(tx.Signature()),
 		Value:       (*hexutil.Big)(tx.Value()),
+		Type:        hexutil.Uint64(tx.Type()),
+	}
+	if tx.Type() == types.AccessListTxType {
+		al := tx.AccessList()
+		result.Accesses = &al
 	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = &blockHash
@@ -1223,17 +1308,17 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 }
 
 // newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
-func newRPCPendingTransaction(tx *types.Transaction, networkID *big.Int) *RPCTransaction {
-	return newRPCTransaction(tx, common.Hash{}, 0, 0, networkID)
+func newRPCPendingTransaction(tx *types.Transaction, config *params.ChainConfig) *RPCTransaction {
+	return newRPCTransaction(tx, common.Hash{}, 0, 0, config)
 }
 
 // newRPCTransactionFromBlockIndex returns a transaction that will serialize to the RPC representation.
-func newRPCTransactionFromBlockIndex(b *types.Block, index uint64, networkID *big.Int) *RPCTransaction {
+func newRPCTransactionFromBlockIndex(b *types.Block, index uint64, config *params.ChainConfig) *RPCTransaction {
 	txs := b.Transactions()
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index, networkID)
+	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index, config)
 }
 
 // newRPCRawTransactionFromBlockIndex returns the bytes of a transaction given a block and a transaction index.
@@ -1247,10 +1332,10 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.By
 }
 
 // newRPCTransactionFromBlockHash returns a transaction that will serialize to the RPC representation.
-func newRPCTransactionFromBlockHash(b *types.Block, hash common.Hash, networkID *big.Int) *RPCTransaction {
+func newRPCTransactionFromBlockHash(b *types.Block, hash common.Hash, config *params.ChainConfig) *RPCTransaction {
 	for idx, tx := range b.Transactions() {
 		if tx.Hash() == hash {
-			return newRPCTransactionFromBlockIndex(b, uint64(idx), networkID)
+			return newRPCTransactionFromBlockIndex(b, uint64(idx), config)
 		}
 	}
 	return nil
@@ -1288,7 +1373,7 @@ func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Co
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
 func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.ChainConfig().NetworkID)
+		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.ChainConfig())
 	}
 	return nil
 }
@@ -1296,7 +1381,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx conte
 // GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
 func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
 	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.ChainConfig().NetworkID)
+		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.ChainConfig())
 	}
 	return nil
 }
@@ -1344,11 +1429,11 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 		return nil, err
 	}
 	if tx != nil {
-		return newRPCTransaction(tx, blockHash, blockNumber, index, s.b.ChainConfig().NetworkID), nil
+		return newRPCTransaction(tx, blockHash, blockNumber, index, s.b.ChainConfig()), nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
-		return newRPCPendingTransaction(tx, s.b.ChainConfig().NetworkID), nil
+		return newRPCPendingTransaction(tx, s.b.ChainConfig()), nil
 	}
 
 	// Transaction unknown, return as such
@@ -1387,7 +1472,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
-	var signer types.Signer = types.NewNucleusSigner(s.b.ChainConfig().NetworkID)
+	signer := types.MakeBlockSigner(s.b.ChainConfig(), new(big.Int).SetUint64(blockNumber))
 	from, err := types.Sender(signer, tx)
 	if err != nil {
 		return nil, err
@@ -1401,16 +1486,23 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		"to":                   tx.To(),
 		"energyUsed":           hexutil.Uint64(receipt.EnergyUsed),
 		"cumulativeEnergyUsed": hexutil.Uint64(receipt.CumulativeEnergyUsed),
+		"effectiveEnergyPrice": (*hexutil.Big)(receipt.EffectiveEnergyPrice),
 		"contractAddress":      nil,
 		"logs":                 receipt.Logs,
 		"logsBloom":            receipt.Bloom,
 	}
 
-	// Assign receipt status or post state.
+	// Assign receipt status, post state and revert reason if available.
+	fields["status"] = hexutil.Uint(receipt.Status)
 	if len(receipt.PostState) > 0 {
 		fields["root"] = hexutil.Bytes(receipt.PostState)
-	} else {
-		fields["status"] = hexutil.Uint(receipt.Status)
+	}
+	if receipt.Status == types.ReceiptStatusFailed && s.b.RPCRevertReason() {
+		if block, err := s.b.BlockByHash(ctx, blockHash); err == nil && block != nil {
+			if reason := revertReason(ctx, s.b, block, index); reason != "" {
+				fields["revertReason"] = reason
+			}
+		}
 	}
 	if receipt.Logs == nil {
 		fields["logs"] = [][]*types.Log{}
@@ -1422,6 +1514,43 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	return fields, nil
 }
 
+// revertReason re-executes the transaction at the given index of the block on
+// top of its parent state to retrieve the reason it reverted with. An empty
+// string is returned if the transaction didn't revert with Error(string), if
+// the parent state is not available anymore or if the execution timed out.
+func revertReason(ctx context.Context, b Backend, block *types.Block, index uint64) string {
+	txs := block.Transactions()
+	if index >= uint64(len(txs)) {
+		return ""
+	}
+	statedb, _, err := b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(block.ParentHash(), false))
+	if statedb == nil || err != nil {
+		return ""
+	}
+	msg, err := txs[index].AsMessage(types.MakeBlockSigner(b.ChainConfig(), block.Number()))
+	if err != nil {
+		return ""
+	}
+	// Share a single timeout between all the transactions executed
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cvm, vmError, err := b.GetCVM(ctx, msg, statedb, block.Header())
+	if err != nil {
+		return ""
+	}
+	go func() {
+		<-ctx.Done()
+		cvm.Cancel()
+	}()
+	result, err := core.ReplayTransaction(cvm, statedb, block, index)
+	if err != nil || vmError() != nil || cvm.Cancelled() {
+		return ""
+	}
+	reason, _ := abi.UnpackRevert(result.Revert())
+	return reason
+}
+
 // sign is a helper function that signs a transaction with the private key of the given address.
 func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	// Look up the wallet containing the requested signer
@@ -1539,7 +1668,7 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		return common.Hash{}, err
 	}
 	if tx.To() == nil {
-		signer := types.MakeSigner(b.ChainConfig().NetworkID)
+		signer := types.LatestSigner(b.ChainConfig())
 		from, err := types.Sender(signer, tx)
 		if err != nil {
 			return common.Hash{}, err
@@ -1682,15 +1811,15 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 			accounts[account.Address] = struct{}{}
 		}
 	}
+	signer := types.LatestSigner(s.b.ChainConfig())
 	transactions := make([]*RPCTransaction, 0, len(pending))
 	for _, tx := range pending {
-		var signer types.Signer = types.NewNucleusSigner(s.b.ChainConfig().NetworkID)
 		from, err := types.Sender(signer, tx)
 		if err != nil {
 			return nil, err
 		}
 		if _, exists := accounts[from]; exists {
-			transactions = append(transactions, newRPCPendingTransaction(tx, s.b.ChainConfig().NetworkID))
+			transactions = append(transactions, newRPCPendingTransaction(tx, s.b.ChainConfig()))
 		}
 	}
 	return transactions, nil
@@ -1724,8 +1853,8 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	if err != nil {
 		return common.Hash{}, err
 	}
+	signer := types.LatestSigner(s.b.ChainConfig())
 	for _, p := range pending {
-		var signer types.Signer = types.NewNucleusSigner(s.b.ChainConfig().NetworkID)
 		wantSigHash := signer.Hash(matchTx)
 
 		if pFrom, err := types.Sender(signer, p); err == nil && pFrom == sendArgs.From && signer.Hash(p) == wantSigHash {
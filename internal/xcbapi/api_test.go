@@ -0,0 +1,314 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcbapi
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/accounts/abi"
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/common/hexutil"
+	"github.com/core-coin/go-core/v2/core"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/core/state"
+	"github.com/core-coin/go-core/v2/core/types"
+	"github.com/core-coin/go-core/v2/core/vm"
+	"github.com/core-coin/go-core/v2/crypto"
+	"github.com/core-coin/go-core/v2/params"
+	"github.com/core-coin/go-core/v2/rpc"
+	"github.com/core-coin/go-core/v2/trie"
+)
+
+var (
+	// returnCode is a contract returning the word 0x2a.
+	returnCode = []byte{
+		byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	}
+	// revertCode is a contract reverting with the data 0xdead.
+	revertCode = []byte{
+		byte(vm.PUSH2), 0xde, 0xad, byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x02, byte(vm.PUSH1), 0x1e, byte(vm.REVERT),
+	}
+	// counterCode is a contract incrementing storage slot 0 and returning the
+	// new value.
+	counterCode = []byte{
+		byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.PUSH1), 0x01, byte(vm.ADD),
+		byte(vm.DUP1), byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	}
+)
+
+// testBackend is a Backend serving calls from a fixed in-memory state. Only the
+// methods needed for executing calls are implemented.
+type testBackend struct {
+	Backend
+
+	db   state.Database
+	root common.Hash
+}
+
+// newTestBackend creates a backend with the given accounts deployed.
+func newTestBackend(t *testing.T, codes map[common.Address][]byte) *testBackend {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, db, nil)
+	for addr, code := range codes {
+		statedb.SetCode(addr, code)
+	}
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	return &testBackend{db: db, root: root}
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	statedb, err := state.New(b.root, b.db, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := &types.Header{
+		Number:      big.NewInt(1),
+		Difficulty:  big.NewInt(1),
+		EnergyLimit: params.GenesisEnergyLimit,
+		Root:        b.root,
+	}
+	return statedb, header, nil
+}
+
+func (b *testBackend) GetCVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.CVM, func() error, error) {
+	txContext := core.NewCVMTxContext(msg)
+	context := core.NewCVMBlockContext(header, nil, &common.Address{})
+	return vm.NewCVM(context, txContext, state, params.MainnetChainConfig, vm.Config{}), func() error { return nil }, nil
+}
+
+func (b *testBackend) RPCEnergyCap() uint64 { return 25000000 }
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return params.MainnetChainConfig }
+
+// Tests that a multicall executes every call independently on the same state,
+// reporting failures per call without affecting the others.
+func TestMulticall(t *testing.T) {
+	var (
+		returner = crypto.CreateAddress(common.Address{}, 0)
+		reverter = crypto.CreateAddress(common.Address{}, 1)
+		counter  = crypto.CreateAddress(common.Address{}, 2)
+	)
+	api := NewPublicBlockChainAPI(newTestBackend(t, map[common.Address][]byte{
+		returner: returnCode,
+		reverter: revertCode,
+		counter:  counterCode,
+	}))
+	energy := hexutil.Uint64(100000)
+	results, err := api.Multicall(context.Background(), []CallArgs{
+		{To: &returner},
+		{To: &counter},
+		{To: &reverter},
+		{To: &counter},
+		{To: &returner, Energy: new(hexutil.Uint64)},
+		{To: &returner, Energy: &energy},
+	}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	if err != nil {
+		t.Fatalf("multicall failed: %v", err)
+	}
+	if len(results) != 6 {
+		t.Fatalf("result count mismatch: have %d, want 6", len(results))
+	}
+	// Successful calls should return their output
+	for _, i := range []int{0, 5} {
+		if results[i].Error != "" {
+			t.Errorf("result %d: unexpected error: %v", i, results[i].Error)
+		}
+		if want := common.LeftPadBytes([]byte{0x2a}, 32); !bytes.Equal(results[i].Return, want) {
+			t.Errorf("result %d: return mismatch: have %x, want %x", i, results[i].Return, want)
+		}
+		if results[i].EnergyUsed == 0 {
+			t.Errorf("result %d: no energy used", i)
+		}
+	}
+	// State changes of a call should not leak into subsequent ones
+	for _, i := range []int{1, 3} {
+		if want := common.LeftPadBytes([]byte{0x01}, 32); !bytes.Equal(results[i].Return, want) {
+			t.Errorf("result %d: counter mismatch: have %x, want %x", i, results[i].Return, want)
+		}
+	}
+	// Failed calls should be reported without aborting the batch
+	if results[2].Error != "execution reverted" {
+		t.Errorf("revert error mismatch: have %q, want %q", results[2].Error, "execution reverted")
+	}
+	if !bytes.Equal(results[2].Revert, []byte{0xde, 0xad}) {
+		t.Errorf("revert data mismatch: have %x, want dead", results[2].Revert)
+	}
+	if results[2].Return != nil {
+		t.Errorf("reverted call returned data: %x", results[2].Return)
+	}
+	if results[4].Error == "" || results[4].Return != nil {
+		t.Errorf("out of energy call succeeded: %+v", results[4])
+	}
+}
+
+// balanceOfCode is a contract returning the value of the mapping at slot 0 for
+// the key passed in the calldata, mimicking a token's balanceOf.
+var balanceOfCode = []byte{
+	byte(vm.PUSH1), 0x00, byte(vm.CALLDATALOAD), byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+	byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x20, byte(vm.MSTORE),
+	byte(vm.PUSH1), 0x40, byte(vm.PUSH1), 0x00, byte(vm.SHA3), byte(vm.SLOAD),
+	byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+	byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+}
+
+// Tests that state overrides are applied before executing a call, and that they
+// don't outlive the call.
+func TestCallStateOverride(t *testing.T) {
+	var (
+		token  = crypto.CreateAddress(common.Address{}, 0)
+		clone  = crypto.CreateAddress(common.Address{}, 1)
+		holder = crypto.CreateAddress(common.Address{}, 2)
+
+		input = hexutil.Bytes(common.LeftPadBytes(holder.Bytes(), 32))
+		slot  = common.BytesToHash(crypto.SHA3(common.LeftPadBytes(holder.Bytes(), 32), common.Hash{}.Bytes()))
+		block = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	api := NewPublicBlockChainAPI(newTestBackend(t, map[common.Address][]byte{
+		token: balanceOfCode,
+	}))
+	code := hexutil.Bytes(balanceOfCode)
+
+	tests := []struct {
+		to        common.Address
+		overrides *StateOverride
+		want      common.Hash
+		fail      bool
+	}{
+		// No overrides, the balance is empty
+		{to: token, want: common.Hash{}},
+		// Override the balance slot of the holder
+		{
+			to:        token,
+			overrides: &StateOverride{token: OverrideAccount{StateDiff: &map[common.Hash]common.Hash{slot: common.HexToHash("0x64")}}},
+			want:      common.HexToHash("0x64"),
+		},
+		// Replace the entire storage of the token
+		{
+			to:        token,
+			overrides: &StateOverride{token: OverrideAccount{State: &map[common.Hash]common.Hash{slot: common.HexToHash("0xc8")}}},
+			want:      common.HexToHash("0xc8"),
+		},
+		// Deploy the token code onto an empty account, with storage
+		{
+			to: clone,
+			overrides: &StateOverride{clone: OverrideAccount{
+				Code:      &code,
+				StateDiff: &map[common.Hash]common.Hash{slot: common.HexToHash("0x2a")},
+			}},
+			want: common.HexToHash("0x2a"),
+		},
+		// Previous overrides should not leak into subsequent calls
+		{to: token, want: common.Hash{}},
+		{to: clone, want: common.Hash{}},
+		// Overriding both the full storage and a diff is invalid
+		{
+			to: token,
+			overrides: &StateOverride{token: OverrideAccount{
+				State:     &map[common.Hash]common.Hash{},
+				StateDiff: &map[common.Hash]common.Hash{},
+			}},
+			fail: true,
+		},
+	}
+	for i, tt := range tests {
+		to := tt.to
+		have, err := api.Call(context.Background(), CallArgs{To: &to, Data: &input}, block, tt.overrides)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected failure", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: call failed: %v", i, err)
+			continue
+		}
+		if common.BytesToHash(have) != tt.want {
+			t.Errorf("test %d: balance mismatch: have %x, want %x", i, have, tt.want)
+		}
+	}
+}
+
+// Tests that the revert reason of a failed transaction is derived by executing
+// the block up to it on top of the parent state.
+func TestRevertReason(t *testing.T) {
+	typ, _ := abi.NewType("string", "", nil)
+	packed, err := (abi.Arguments{{Type: typ}}).Pack("insufficient funds")
+	if err != nil {
+		t.Fatalf("could not pack revert reason: %v", err)
+	}
+	var (
+		reverter = crypto.CreateAddress(common.Address{}, 0)
+		counter  = crypto.CreateAddress(common.Address{}, 1)
+		reason   = append(crypto.SHA3([]byte("Error(string)"))[:4], packed...)
+	)
+	// Deploy a contract reverting with the reason next to the counter
+	var code []byte
+	for offset := 0; offset < len(reason); offset += 32 {
+		chunk := make([]byte, 32)
+		copy(chunk, reason[offset:])
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, chunk...)
+		code = append(code, byte(vm.PUSH1), byte(offset), byte(vm.MSTORE))
+	}
+	code = append(code, byte(vm.PUSH1), byte(len(reason)), byte(vm.PUSH1), 0x00, byte(vm.REVERT))
+
+	b := newTestBackend(t, map[common.Address][]byte{reverter: code, counter: counterCode})
+
+	// Fund a sender and include a succeeding and a reverting transaction
+	key, _ := crypto.GenerateKey(crand.Reader)
+	statedb, _ := state.New(b.root, b.db, nil)
+	statedb.SetBalance(key.Address(), big.NewInt(10000000000))
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := b.db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	b.root = root
+
+	signer := types.NewNucleusSigner(params.MainnetChainConfig.NetworkID)
+	passing, _ := types.SignTx(types.NewTransaction(0, counter, common.Big0, 100000, big.NewInt(1), nil), signer, key)
+	failing, _ := types.SignTx(types.NewTransaction(1, reverter, common.Big0, 100000, big.NewInt(1), nil), signer, key)
+
+	block := types.NewBlock(&types.Header{
+		Number:      big.NewInt(1),
+		Difficulty:  big.NewInt(1),
+		EnergyLimit: params.GenesisEnergyLimit,
+	}, []*types.Transaction{passing, failing}, nil, nil, new(trie.Trie))
+
+	for i, want := range []string{"", "insufficient funds", ""} { // last index is out of range
+		if have := revertReason(context.Background(), b, block, uint64(i)); have != want {
+			t.Errorf("transaction %d: revert reason mismatch: have %q, want %q", i, have, want)
+		}
+	}
+}
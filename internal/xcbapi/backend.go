@@ -47,8 +47,9 @@ type Backend interface {
 	ChainDb() xcbdb.Database
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
-	RPCEnergyCap() uint64 // global energy cap for xcb_call over rpc: DoS protection
-	RPCTxFeeCap() float64 // global tx fee cap for all transaction related APIs
+	RPCEnergyCap() uint64  // global energy cap for xcb_call over rpc: DoS protection
+	RPCTxFeeCap() float64  // global tx fee cap for all transaction related APIs
+	RPCRevertReason() bool // whether receipts carry revert reasons: re-executes blocks, off by default
 
 	// Blockchain API
 	SetHead(number uint64)
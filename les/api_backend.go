@@ -273,6 +273,10 @@ func (b *LesApiBackend) RPCTxFeeCap() float64 {
 	return b.xcb.config.RPCTxFeeCap
 }
 
+func (b *LesApiBackend) RPCRevertReason() bool {
+	return b.xcb.config.RPCRevertReason
+}
+
 func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	if b.xcb.bloomIndexer == nil {
 		return 0, 0
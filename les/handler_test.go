@@ -17,7 +17,9 @@
 package les
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -641,3 +643,31 @@ func TestStopResumeLes3(t *testing.T) {
 		}
 	}
 }
+
+// Tests that transactions too large for the transaction pool are rejected while
+// decoding the request.
+func TestSendTxDecodeOversized(t *testing.T) {
+	signer := types.NewNucleusSigner(big.NewInt(1))
+	small, _ := types.SignTx(types.NewTransaction(0, userKey1.Address(), big.NewInt(10000), params.TxEnergy, big.NewInt(1), nil), signer, bankKey)
+	large, _ := types.SignTx(types.NewTransaction(1, userKey1.Address(), big.NewInt(10000), params.TxEnergy, big.NewInt(1), make([]byte, core.TxMaxSize)), signer, bankKey)
+
+	for i, tt := range []struct {
+		txs     types.Transactions
+		wantErr error
+	}{
+		{types.Transactions{small}, nil},
+		{types.Transactions{small, large}, types.ErrTxTooLarge},
+	} {
+		enc, err := rlp.EncodeToBytes(&sendTxRequest{ReqID: 1, Txs: tt.txs})
+		if err != nil {
+			t.Fatalf("test %d: failed to encode request: %v", i, err)
+		}
+		req, err := decodeSendTx(rlp.NewStream(bytes.NewReader(enc), uint64(len(enc))))
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.wantErr)
+		}
+		if err == nil && (req.ReqID != 1 || len(req.Txs) != 1 || req.Txs[0].Hash() != small.Hash()) {
+			t.Errorf("test %d: decoded request mismatch: %v", i, req)
+		}
+	}
+}
@@ -779,11 +779,8 @@ func (h *serverHandler) handleMsg(p *clientPeer, wg *sync.WaitGroup) error {
 			miscInTxsPacketsMeter.Mark(1)
 			miscInTxsTrafficMeter.Mark(int64(msg.Size))
 		}
-		var req struct {
-			ReqID uint64
-			Txs   []*types.Transaction
-		}
-		if err := msg.Decode(&req); err != nil {
+		req, err := decodeSendTx(rlp.NewStream(msg.Payload, uint64(msg.Size)))
+		if err != nil {
 			clientErrorMeter.Mark(1)
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
@@ -1035,3 +1032,27 @@ func (b *broadcaster) sendTo(node *enode.Node) {
 		}
 	}
 }
+
+// sendTxRequest is the content of a SendTxV2Msg.
+type sendTxRequest struct {
+	ReqID uint64
+	Txs   []*types.Transaction
+}
+
+// decodeSendTx decodes a SendTxV2Msg, rejecting the transactions too large for
+// the transaction pool before reading them.
+func decodeSendTx(s *rlp.Stream) (*sendTxRequest, error) {
+	if _, err := s.List(); err != nil {
+		return nil, err
+	}
+	req := new(sendTxRequest)
+	if err := s.Decode(&req.ReqID); err != nil {
+		return nil, err
+	}
+	txs, err := types.DecodeTransactions(s, core.TxMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	req.Txs = txs
+	return req, s.ListEnd()
+}
@@ -19,6 +19,7 @@ package light
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -91,7 +92,7 @@ type TxRelayBackend interface {
 func NewTxPool(config *params.ChainConfig, chain *LightChain, relay TxRelayBackend) *TxPool {
 	pool := &TxPool{
 		config:      config,
-		signer:      types.NewNucleusSigner(config.NetworkID),
+		signer:      types.LatestSigner(config),
 		nonce:       make(map[common.Address]uint64),
 		pending:     make(map[common.Hash]*types.Transaction),
 		mined:       make(map[common.Hash][]*types.Transaction),
@@ -364,6 +365,12 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 		return core.ErrEnergyLimit
 	}
 
+	// Accept only legacy transactions until the access list fork activates
+	next := new(big.Int).Add(header.Number, big.NewInt(1))
+	if !pool.config.IsAccessList(next) && tx.Type() != types.LegacyTxType {
+		return types.ErrTxTypeNotSupported
+	}
+
 	// Transactions can't be negative. This may never happen
 	// using RLP decoded transactions but may occur if you create
 	// a transaction using the RPC for example.
@@ -378,7 +385,7 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 	}
 
 	// Should supply enough intrinsic energy
-	energy, err := core.IntrinsicEnergy(tx.Data(), tx.To() == nil)
+	energy, err := core.IntrinsicEnergyWithAccessList(tx.Data(), tx.AccessList(), tx.To() == nil, pool.config)
 	if err != nil {
 		return err
 	}
@@ -190,6 +190,45 @@ func LvlFilterHandler(maxLvl Lvl, h Handler) Handler {
 	}, h)
 }
 
+// FieldFilterHandler returns a Handler that strips context fields from the
+// records before passing them to the wrapped Handler. If allow is not empty,
+// only the listed keys are retained; keys in deny are always removed. The time,
+// level and message of the records are never filtered. For example, to drop
+// the remote addresses of peers:
+//
+//	log.FieldFilterHandler(nil, []string{"addr", "ip"}, log.StdoutHandler)
+func FieldFilterHandler(allow, deny []string, h Handler) Handler {
+	allowed := make(map[string]bool, len(allow))
+	for _, key := range allow {
+		allowed[key] = true
+	}
+	denied := make(map[string]bool, len(deny))
+	for _, key := range deny {
+		denied[key] = true
+	}
+	return FuncHandler(func(r *Record) error {
+		ctx := make([]interface{}, 0, len(r.Ctx))
+		for i := 0; i < len(r.Ctx); i += 2 {
+			key, ok := r.Ctx[i].(string)
+			if !ok || denied[key] || (len(allowed) > 0 && !allowed[key]) {
+				continue
+			}
+			ctx = append(ctx, r.Ctx[i], r.Ctx[i+1])
+		}
+		// Copy the record, it might be shared with other handlers
+		filtered := *r
+		filtered.Ctx = ctx
+		return h.Log(&filtered)
+	})
+}
+
+// JSONHandler returns a Handler that writes each record as a single JSON object
+// per line to the given writer, retaining only the context fields permitted by
+// the allow and deny lists (see FieldFilterHandler).
+func JSONHandler(wr io.Writer, allow, deny []string) Handler {
+	return FieldFilterHandler(allow, deny, StreamHandler(wr, JSONFormat()))
+}
+
 // MultiHandler dispatches any write to each of its handlers.
 // This is useful for writing different types of log information
 // to different locations. For example, to log to a file and
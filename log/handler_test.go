@@ -0,0 +1,89 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Tests that the JSON handler emits a valid JSON object per record, retaining
+// only the permitted context fields.
+func TestJSONHandler(t *testing.T) {
+	tests := []struct {
+		allow, deny []string
+		want        []string
+	}{
+		{want: []string{"peer", "addr", "count"}},
+		{allow: []string{"peer", "count"}, want: []string{"peer", "count"}},
+		{deny: []string{"addr"}, want: []string{"peer", "count"}},
+		{allow: []string{"peer", "addr"}, deny: []string{"addr"}, want: []string{"peer"}},
+	}
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		logger := New()
+		logger.SetHandler(JSONHandler(&buf, tt.allow, tt.deny))
+		logger.Info("first", "peer", "abcd", "addr", "10.0.0.1:30300", "count", 1)
+		logger.Warn("second", "addr", "10.0.0.2:30300", "peer", "ef01", "count", 2)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("test %d: record count mismatch: have %d, want 2", i, len(lines))
+		}
+		for j, line := range lines {
+			var record map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("test %d, record %d: invalid JSON %q: %v", i, j, line, err)
+			}
+			for _, key := range []string{"t", "lvl", "msg"} {
+				if _, ok := record[key]; !ok {
+					t.Errorf("test %d, record %d: missing %q field", i, j, key)
+				}
+			}
+			if len(record) != 3+len(tt.want) {
+				t.Errorf("test %d, record %d: field count mismatch: have %v, want %v", i, j, record, tt.want)
+			}
+			for _, key := range tt.want {
+				if _, ok := record[key]; !ok {
+					t.Errorf("test %d, record %d: missing %q field", i, j, key)
+				}
+			}
+		}
+	}
+}
+
+// Tests that filtering the fields of a record doesn't affect other handlers
+// processing the same record.
+func TestFieldFilterHandlerCopy(t *testing.T) {
+	var filtered, full *Record
+	h := MultiHandler(
+		FieldFilterHandler(nil, []string{"secret"}, FuncHandler(func(r *Record) error { filtered = r; return nil })),
+		FuncHandler(func(r *Record) error { full = r; return nil }),
+	)
+	logger := New()
+	logger.SetHandler(h)
+	logger.Info("msg", "secret", "hunter2", "public", true)
+
+	if len(filtered.Ctx) != 2 || filtered.Ctx[0] != "public" {
+		t.Errorf("filtered context mismatch: have %v", filtered.Ctx)
+	}
+	if len(full.Ctx) != 4 {
+		t.Errorf("full context mismatch: have %v", full.Ctx)
+	}
+}
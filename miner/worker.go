@@ -479,7 +479,7 @@ func (w *worker) mainLoop() {
 			// be automatically eliminated.
 			if !w.isRunning() && w.current != nil {
 				// If block is already full, abort
-				if gp := w.current.energyPool; gp != nil && gp.Energy() < params.TxEnergy {
+				if gp := w.current.energyPool; gp != nil && gp.Energy() < w.chainConfig.EnergyCosts().TxEnergy {
 					continue
 				}
 				w.mu.RLock()
@@ -646,7 +646,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		return err
 	}
 	env := &environment{
-		signer:    types.NewNucleusSigner(w.chainConfig.NetworkID),
+		signer:    types.MakeBlockSigner(w.chainConfig, header.Number),
 		state:     state,
 		ancestors: mapset.NewSet(),
 		family:    mapset.NewSet(),
@@ -770,8 +770,8 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			return atomic.LoadInt32(interrupt) == commitInterruptNewHead
 		}
 		// If we don't have enough energy for any further transactions then we're done
-		if w.current.energyPool.Energy() < params.TxEnergy {
-			log.Trace("Not enough energy for further transactions", "have", w.current.energyPool, "want", params.TxEnergy)
+		if txEnergy := w.chainConfig.EnergyCosts().TxEnergy; w.current.energyPool.Energy() < txEnergy {
+			log.Trace("Not enough energy for further transactions", "have", w.current.energyPool, "want", txEnergy)
 			break
 		}
 		// Retrieve the next transaction and abort if all done
@@ -780,6 +780,13 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			break
 		}
 
+		// Typed transactions may linger in the pool across the fork boundary,
+		// ignore them until the access list fork activates.
+		if tx.Type() != types.LegacyTxType && !w.chainConfig.IsAccessList(w.current.header.Number) {
+			log.Trace("Ignoring typed transaction before fork", "hash", tx.Hash(), "type", tx.Type())
+			txs.Pop()
+			continue
+		}
 		from, err := types.Sender(w.current.signer, tx)
 		if err != nil {
 			log.Error("Bad transaction recipient or signature", "err", err)
@@ -843,6 +850,15 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 	return false
 }
 
+// energyLimit computes the energy limit of the block mined on top of parent
+// using the configured energy limit policy.
+func (w *worker) energyLimit(parent *types.Block) uint64 {
+	if w.config.EnergyPolicy == nil {
+		return core.CalcEnergyLimit(parent, w.config.EnergyFloor, w.config.EnergyCeil)
+	}
+	return w.config.EnergyPolicy.EnergyLimit(parent, w.config.EnergyFloor, w.config.EnergyCeil)
+}
+
 // commitNewWork generates several new sealing tasks based on the parent block.
 func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64) {
 	w.mu.RLock()
@@ -865,7 +881,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	header := &types.Header{
 		ParentHash:  parent.Hash(),
 		Number:      num.Add(num, common.Big1),
-		EnergyLimit: core.CalcEnergyLimit(parent, w.config.EnergyFloor, w.config.EnergyCeil),
+		EnergyLimit: w.energyLimit(parent),
 		Extra:       w.extra,
 		Time:        uint64(timestamp),
 	}
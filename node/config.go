@@ -138,6 +138,16 @@ type Config struct {
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// HTTPEnableHTTP2 serves the HTTP RPC interface over HTTP/2 as well, so that
+	// concurrent calls are multiplexed over a single connection. Without TLS the
+	// cleartext variant (h2c) is used.
+	HTTPEnableHTTP2 bool `toml:",omitempty"`
+
+	// HTTPTLSCert and HTTPTLSKey are the certificate and private key files to serve
+	// the HTTP RPC interface over TLS. If empty, TLS is disabled.
+	HTTPTLSCert string `toml:",omitempty"`
+	HTTPTLSKey  string `toml:",omitempty"`
+
 	// AuthAddr is the listening address on which authenticated APIs are provided.
 	AuthAddr string `toml:",omitempty"`
 
@@ -177,6 +187,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// RPCMethodMetrics enables the collection of per-method call counts, error
+	// counts and latencies on the HTTP and websocket RPC servers.
+	RPCMethodMetrics bool `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
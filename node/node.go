@@ -150,9 +150,17 @@ func New(conf *Config) (*Node, error) {
 	if err := validatePrefix("WebSocket", conf.WSPathPrefix); err != nil {
 		return nil, err
 	}
+	if (conf.HTTPTLSCert == "") != (conf.HTTPTLSKey == "") {
+		return nil, errors.New("HTTP TLS requires both a certificate and a key")
+	}
 
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts)
+	node.http.transport = transportConfig{
+		http2:   conf.HTTPEnableHTTP2,
+		tlsCert: conf.HTTPTLSCert,
+		tlsKey:  conf.HTTPTLSKey,
+	}
 	node.httpAuth = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
 	node.wsAuth = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
@@ -421,6 +429,7 @@ func (n *Node) startRPC() error {
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			methodMetrics:      n.config.RPCMethodMetrics,
 		}); err != nil {
 			return err
 		}
@@ -434,9 +443,10 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(n.rpcAPIs, wsConfig{
-			Modules: n.config.WSModules,
-			Origins: n.config.WSOrigins,
-			prefix:  n.config.WSPathPrefix,
+			Modules:       n.config.WSModules,
+			Origins:       n.config.WSOrigins,
+			prefix:        n.config.WSPathPrefix,
+			methodMetrics: n.config.RPCMethodMetrics,
 		}); err != nil {
 			return err
 		}
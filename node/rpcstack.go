@@ -19,6 +19,7 @@ package node
 import (
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -30,11 +31,20 @@ import (
 	"sync/atomic"
 
 	"github.com/rs/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/core-coin/go-core/v2/log"
 	"github.com/core-coin/go-core/v2/rpc"
 )
 
+// transportConfig is the transport level configuration of an HTTP server.
+type transportConfig struct {
+	http2   bool   // whether to serve HTTP/2, in cleartext (h2c) without TLS
+	tlsCert string // optional TLS certificate file
+	tlsKey  string // optional TLS private key file
+}
+
 // httpConfig is the JSON-RPC/HTTP configuration.
 type httpConfig struct {
 	Modules            []string
@@ -42,14 +52,16 @@ type httpConfig struct {
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
 	jwtSecret          []byte // optional JWT secret
+	methodMetrics      bool   // whether to collect per-method metrics
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
-	Origins   []string
-	Modules   []string
-	prefix    string // path prefix on which to mount ws handler
-	jwtSecret []byte // optional JWT secret
+	Origins       []string
+	Modules       []string
+	prefix        string // path prefix on which to mount ws handler
+	jwtSecret     []byte // optional JWT secret
+	methodMetrics bool   // whether to collect per-method metrics
 }
 
 type rpcHandler struct {
@@ -58,9 +70,10 @@ type rpcHandler struct {
 }
 
 type httpServer struct {
-	log      log.Logger
-	timeouts rpc.HTTPTimeouts
-	mux      http.ServeMux // registered handlers go here
+	log       log.Logger
+	timeouts  rpc.HTTPTimeouts
+	transport transportConfig
+	mux       http.ServeMux // registered handlers go here
 
 	mu       sync.Mutex
 	server   *http.Server
@@ -132,6 +145,12 @@ func (h *httpServer) start() error {
 		h.server.WriteTimeout = h.timeouts.WriteTimeout
 		h.server.IdleTimeout = h.timeouts.IdleTimeout
 	}
+	scheme, err := h.configureTransport()
+	if err != nil {
+		h.disableRPC()
+		h.disableWS()
+		return err
+	}
 
 	// Start the server.
 	listener, err := net.Listen("tcp", h.endpoint)
@@ -143,11 +162,15 @@ func (h *httpServer) start() error {
 		return err
 	}
 	h.listener = listener
-	go h.server.Serve(listener)
+	if h.server.TLSConfig != nil {
+		go h.server.ServeTLS(listener, "", "")
+	} else {
+		go h.server.Serve(listener)
+	}
 
 	// if server is websocket only, return after logging
 	if h.wsAllowed() && !h.rpcAllowed() {
-		url := fmt.Sprintf("ws://%v", listener.Addr())
+		url := fmt.Sprintf("%s://%v", strings.Replace(scheme, "http", "ws", 1), listener.Addr())
 		if h.wsConfig.prefix != "" {
 			url += h.wsConfig.prefix
 		}
@@ -157,6 +180,7 @@ func (h *httpServer) start() error {
 	// Log http endpoint.
 	h.log.Info("HTTP server started",
 		"endpoint", listener.Addr(), "auth", (h.httpConfig.jwtSecret != nil),
+		"tls", h.server.TLSConfig != nil, "http2", h.transport.http2,
 		"cors", strings.Join(h.httpConfig.CorsAllowedOrigins, ","),
 		"prefix", h.httpConfig.prefix,
 		"vhosts", strings.Join(h.httpConfig.Vhosts, ","),
@@ -172,13 +196,39 @@ func (h *httpServer) start() error {
 	for _, path := range paths {
 		name := h.handlerNames[path]
 		if !logged[name] {
-			log.Info(name+" enabled", "url", "http://"+listener.Addr().String()+path)
+			log.Info(name+" enabled", "url", scheme+"://"+listener.Addr().String()+path)
 			logged[name] = true
 		}
 	}
 	return nil
 }
 
+// configureTransport sets up TLS and HTTP/2 on the server according to the
+// transport configuration, returning the URL scheme of the server.
+func (h *httpServer) configureTransport() (string, error) {
+	scheme := "http"
+	if h.transport.tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(h.transport.tlsCert, h.transport.tlsKey)
+		if err != nil {
+			return "", err
+		}
+		h.server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		scheme = "https"
+	}
+	switch {
+	case !h.transport.http2:
+		// HTTP/2 would be negotiated over TLS by default, keep it opt-in.
+		h.server.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	case h.server.TLSConfig != nil:
+		if err := http2.ConfigureServer(h.server, &http2.Server{IdleTimeout: h.server.IdleTimeout}); err != nil {
+			return "", err
+		}
+	default:
+		h.server.Handler = h2c.NewHandler(h, &http2.Server{IdleTimeout: h.server.IdleTimeout})
+	}
+	return scheme, nil
+}
+
 func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
@@ -282,6 +332,9 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	if config.methodMetrics {
+		srv.EnableMethodMetrics(nil)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
 		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
@@ -314,6 +367,9 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	if config.methodMetrics {
+		srv.EnableMethodMetrics(nil)
+	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
 		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
@@ -18,17 +18,31 @@ package node
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
 
 	"github.com/core-coin/go-core/v2/internal/testlog"
 	"github.com/core-coin/go-core/v2/log"
@@ -378,3 +392,137 @@ func TestJWT(t *testing.T) {
 	}
 	srv.stop()
 }
+
+// barrierService blocks every call until all the expected calls have arrived.
+type barrierService struct {
+	wg *sync.WaitGroup
+}
+
+func (s *barrierService) Wait(id int) int {
+	s.wg.Done()
+	s.wg.Wait()
+	return id
+}
+
+// TestHTTP2Multiplexing checks that concurrent calls are multiplexed over a
+// single cleartext HTTP/2 connection and complete independently.
+func TestHTTP2Multiplexing(t *testing.T) {
+	const calls = 8
+
+	wg := new(sync.WaitGroup)
+	wg.Add(calls)
+	apis := []rpc.API{{Namespace: "test", Service: &barrierService{wg}, Public: true}}
+
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+	srv.transport = transportConfig{http2: true}
+	assert.NoError(t, srv.enableRPC(apis, httpConfig{}))
+	assert.NoError(t, srv.setListenAddr("localhost", 0))
+	assert.NoError(t, srv.start())
+	defer srv.stop()
+
+	// Dial HTTP/2 with prior knowledge, counting the opened connections
+	var dials int32
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return net.Dial(network, addr)
+		},
+	}
+	client, err := rpc.DialHTTPWithClient("http://"+srv.listenAddr(), &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errc := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		go func(id int) {
+			var result int
+			if err := client.CallContext(ctx, &result, "test_wait", id); err != nil {
+				errc <- err
+			} else if result != id {
+				errc <- fmt.Errorf("result mismatch: have %d, want %d", result, id)
+			} else {
+				errc <- nil
+			}
+		}(i)
+	}
+	for i := 0; i < calls; i++ {
+		if err := <-errc; err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Errorf("connection count mismatch: have %d, want 1", n)
+	}
+}
+
+// TestHTTPTLS checks that HTTP/2 is only negotiated over TLS if enabled.
+func TestHTTPTLS(t *testing.T) {
+	cert, key := writeTestCertificate(t)
+
+	for _, enabled := range []bool{false, true} {
+		srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+		srv.transport = transportConfig{http2: enabled, tlsCert: cert, tlsKey: key}
+		assert.NoError(t, srv.enableRPC(nil, httpConfig{}))
+		assert.NoError(t, srv.setListenAddr("localhost", 0))
+		assert.NoError(t, srv.start())
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		}}
+		body := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules","params":[]}`)
+		resp, err := client.Post("https://"+srv.listenAddr(), "application/json", body)
+		if err != nil {
+			t.Fatalf("http2 %v: request failed: %v", enabled, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("http2 %v: status mismatch: have %d, want %d", enabled, resp.StatusCode, http.StatusOK)
+		}
+		if have := resp.ProtoMajor == 2; have != enabled {
+			t.Errorf("http2 %v: negotiated protocol %s", enabled, resp.Proto)
+		}
+		srv.stop()
+	}
+}
+
+// writeTestCertificate creates a self-signed certificate for localhost and
+// returns the paths of the certificate and key files.
+func writeTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
@@ -135,6 +135,7 @@ type dialConfig struct {
 	netRestrict    *netutil.Netlist // IP whitelist, disabled if nil
 	resolver       nodeResolver
 	dialer         NodeDialer
+	ranker         func([]*enode.Node) []*enode.Node // static dial ordering, random if nil
 	log            log.Logger
 	clock          mclock.Clock
 	rand           *mrand.Rand
@@ -411,6 +412,9 @@ func (d *dialScheduler) checkDial(n *enode.Node) error {
 
 // startStaticDials starts n static dial tasks.
 func (d *dialScheduler) startStaticDials(n int) (started int) {
+	if d.ranker != nil {
+		return d.startRankedStaticDials(n)
+	}
 	for started = 0; started < n && len(d.staticPool) > 0; started++ {
 		idx := d.rand.Intn(len(d.staticPool))
 		task := d.staticPool[idx]
@@ -420,6 +424,33 @@ func (d *dialScheduler) startStaticDials(n int) (started int) {
 	return started
 }
 
+// startRankedStaticDials starts n static dial tasks in the order chosen by the
+// configured ranker.
+func (d *dialScheduler) startRankedStaticDials(n int) (started int) {
+	if n == 0 || len(d.staticPool) == 0 {
+		return 0
+	}
+	candidates := make([]*enode.Node, len(d.staticPool))
+	for i, task := range d.staticPool {
+		candidates[i] = task.dest
+	}
+	for _, node := range d.ranker(candidates) {
+		if started >= n {
+			break
+		}
+		// The ranker may return nodes which are not in the pool,
+		// or the same node multiple times.
+		task, ok := d.static[node.ID()]
+		if !ok || task.staticPoolIndex < 0 {
+			continue
+		}
+		d.startDial(task)
+		d.removeFromStaticPool(task.staticPoolIndex)
+		started++
+	}
+	return started
+}
+
 // updateStaticPool attempts to move the given static dial back into staticPool.
 func (d *dialScheduler) updateStaticPool(id enode.ID) {
 	task, ok := d.static[id]
@@ -17,12 +17,14 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/rand"
 	"net"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -311,6 +313,48 @@ func TestDialSchedManyStaticNodes(t *testing.T) {
 	})
 }
 
+// This test checks that static dials follow the order of the configured ranker.
+func TestDialSchedRanker(t *testing.T) {
+	t.Parallel()
+
+	config := dialConfig{
+		maxDialPeers: 2,
+		ranker: func(nodes []*enode.Node) []*enode.Node {
+			ranked := make([]*enode.Node, len(nodes))
+			copy(ranked, nodes)
+			sort.Slice(ranked, func(i, j int) bool {
+				return bytes.Compare(ranked[i].ID().Bytes(), ranked[j].ID().Bytes()) > 0
+			})
+			return ranked
+		},
+	}
+	runDialTest(t, config, []dialTestRound{
+		{
+			peersAdded: []*conn{
+				{flags: dynDialedConn, node: newNode(uintID(0xFFFE), "")},
+				{flags: dynDialedConn, node: newNode(uintID(0xFFFF), "")},
+			},
+			update: func(d *dialScheduler) {
+				for id := uint16(0); id < 20; id++ {
+					d.addStatic(newNode(uintID(id), "127.0.0.1:30300"))
+				}
+			},
+		},
+		{
+			peersRemoved: []enode.ID{
+				uintID(0xFFFE),
+				uintID(0xFFFF),
+			},
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x13), "127.0.0.1:30300"),
+				newNode(uintID(0x12), "127.0.0.1:30300"),
+				newNode(uintID(0x11), "127.0.0.1:30300"),
+				newNode(uintID(0x10), "127.0.0.1:30300"),
+			},
+		},
+	})
+}
+
 // This test checks that past dials are not retried for some time.
 func TestDialSchedHistory(t *testing.T) {
 	t.Parallel()
@@ -47,6 +47,11 @@ type Config struct {
 	Log          log.Logger         // if set, log messages go here
 	ValidSchemes enr.IdentityScheme // allowed identity schemes
 	Clock        mclock.Clock
+
+	// NoRandomLookups disables the lookups the table performs on its own to keep
+	// its buckets filled. The table keeps answering queries and explicit lookups
+	// still work, but no discovery traffic is initiated spontaneously.
+	NoRandomLookups bool
 }
 
 func (cfg Config) withDefaults() Config {
@@ -0,0 +1,62 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+// Contains the meters and timers used by the discovery protocols.
+
+package discover
+
+import (
+	"time"
+
+	"github.com/core-coin/go-core/v2/metrics"
+)
+
+var (
+	pingMetrics     = newQueryMetrics("ping", nil)
+	findnodeMetrics = newQueryMetrics("findnode", nil)
+)
+
+// queryMetrics tracks the health of a discovery query type across the v4 and
+// v5 protocols. If the metrics system is disabled, the meters are no-ops.
+type queryMetrics struct {
+	latency  metrics.Timer // Round-trip time of the answered queries
+	failures metrics.Meter // Queries which failed for any reason, including timeouts
+	timeouts metrics.Meter // Queries which were not answered in time
+}
+
+func newQueryMetrics(query string, r metrics.Registry) *queryMetrics {
+	return &queryMetrics{
+		latency:  metrics.NewRegisteredTimer("discover/"+query+"/latency", r),
+		failures: metrics.NewRegisteredMeter("discover/"+query+"/failures", r),
+		timeouts: metrics.NewRegisteredMeter("discover/"+query+"/timeouts", r),
+	}
+}
+
+// observe records the outcome of a query sent at the given time.
+func (m *queryMetrics) observe(start time.Time, err error) {
+	if !metrics.Enabled {
+		return
+	}
+	switch err {
+	case nil:
+		m.latency.UpdateSince(start)
+	case errTimeout:
+		m.timeouts.Mark(1)
+		fallthrough
+	default:
+		m.failures.Mark(1)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"net"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/metrics"
+	"github.com/core-coin/go-core/v2/p2p/discover/v4wire"
+	"github.com/core-coin/go-core/v2/p2p/discover/v5wire"
+	"github.com/core-coin/go-core/v2/p2p/enode"
+)
+
+// enableQueryMetrics turns on the metrics system and replaces the query metrics
+// with fresh ones for the duration of the test. Tests using it must not run in
+// parallel.
+func enableQueryMetrics(t *testing.T) {
+	enabled, ping, findnode := metrics.Enabled, pingMetrics, findnodeMetrics
+	t.Cleanup(func() {
+		metrics.Enabled, pingMetrics, findnodeMetrics = enabled, ping, findnode
+	})
+	metrics.Enabled = true
+	r := metrics.NewRegistry()
+	pingMetrics, findnodeMetrics = newQueryMetrics("ping", r), newQueryMetrics("findnode", r)
+}
+
+func checkQueryMetrics(t *testing.T, name string, m *queryMetrics, answered, failures, timeouts int64) {
+	t.Helper()
+	if have := m.latency.Count(); have != answered {
+		t.Errorf("%s: answered query count mismatch: have %d, want %d", name, have, answered)
+	}
+	if have := m.failures.Count(); have != failures {
+		t.Errorf("%s: failure count mismatch: have %d, want %d", name, have, failures)
+	}
+	if have := m.timeouts.Count(); have != timeouts {
+		t.Errorf("%s: timeout count mismatch: have %d, want %d", name, have, timeouts)
+	}
+}
+
+func TestUDPv4_queryMetrics(t *testing.T) {
+	enableQueryMetrics(t)
+	test := newUDPTest(t)
+	defer test.close()
+
+	toaddr := &net.UDPAddr{IP: net.ParseIP("1.2.3.4"), Port: 2222}
+	node := enode.NewV4(newkey().PublicKey(), toaddr.IP, 0, toaddr.Port)
+	if _, err := test.udp.ping(node); err != errTimeout {
+		t.Fatal("expected timeout error, got", err)
+	}
+	if _, err := test.udp.findnode(enode.ID{1, 2, 3, 4}, toaddr, v4wire.Pubkey{4, 5, 6, 7}); err != errTimeout {
+		t.Fatal("expected timeout error, got", err)
+	}
+	checkQueryMetrics(t, "ping", pingMetrics, 0, 1, 1)
+	checkQueryMetrics(t, "findnode", findnodeMetrics, 0, 1, 1)
+}
+
+func TestUDPv5_queryMetrics(t *testing.T) {
+	enableQueryMetrics(t)
+	test := newUDPV5Test(t)
+	defer test.close()
+
+	remote := test.getNode(test.remotekey, test.remoteaddr).Node()
+	done := make(chan error, 1)
+
+	// The first ping times out, the second one is answered
+	go func() {
+		_, err := test.udp.ping(remote)
+		done <- err
+	}()
+	test.waitPacketOut(func(p *v5wire.Ping, addr *net.UDPAddr, _ v5wire.Nonce) {})
+	if err := <-done; err != errTimeout {
+		t.Fatalf("want errTimeout, got %q", err)
+	}
+	go func() {
+		_, err := test.udp.ping(remote)
+		done <- err
+	}()
+	test.waitPacketOut(func(p *v5wire.Ping, addr *net.UDPAddr, _ v5wire.Nonce) {
+		test.packetInFrom(test.remotekey, test.remoteaddr, &v5wire.Pong{ReqID: p.ReqID})
+	})
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	// The findnode is answered with an empty response
+	go func() {
+		_, err := test.udp.findnode(remote, []uint{250})
+		done <- err
+	}()
+	test.waitPacketOut(func(p *v5wire.Findnode, addr *net.UDPAddr, _ v5wire.Nonce) {
+		test.packetInFrom(test.remotekey, test.remoteaddr, &v5wire.Nodes{ReqID: p.ReqID, Total: 1})
+	})
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	checkQueryMetrics(t, "ping", pingMetrics, 1, 1, 1)
+	checkQueryMetrics(t, "findnode", findnodeMetrics, 1, 0, 0)
+}
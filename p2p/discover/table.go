@@ -79,6 +79,8 @@ type Table struct {
 	closeReq   chan struct{}
 	closed     chan struct{}
 
+	noRandomLookups bool // whether refreshes only reload the seed nodes
+
 	nodeAddedHook func(*node) // for testing
 }
 
@@ -286,6 +288,10 @@ func (tab *Table) doRefresh(done chan struct{}) {
 	// (hopefully) still alive.
 	tab.loadSeedNodes()
 
+	// Lookups are disabled, rely on the seed nodes only.
+	if tab.noRandomLookups {
+		return
+	}
 	// Run self lookup to discover new neighbor nodes.
 	tab.net.lookupSelf()
 
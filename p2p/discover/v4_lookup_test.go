@@ -63,6 +63,43 @@ func TestUDPv4_Lookup(t *testing.T) {
 	checkLookupResults(t, lookupTestnet, results)
 }
 
+// TestUDPv4_NoRandomLookups checks that the table doesn't look up nodes on its
+// own when random lookups are disabled, while explicit lookups still work.
+func TestUDPv4_NoRandomLookups(t *testing.T) {
+	t.Parallel()
+	test := newUDPTestWithConfig(t, Config{NoRandomLookups: true})
+
+	// Seed the table and force a refresh, which should not send any findnode.
+	fillTable(test.table, []*node{wrapNode(lookupTestnet.node(256, 0))})
+	<-test.table.refresh()
+
+	test.pipe.mu.Lock()
+	for _, dgram := range test.pipe.queue {
+		p, _, _, err := v4wire.Decode(dgram.data)
+		if err != nil {
+			t.Errorf("failed to decode sent packet: %v", err)
+		} else if p.Kind() == v4wire.FindnodePacket {
+			t.Errorf("findnode sent to %v during refresh", &dgram.to)
+		}
+	}
+	test.pipe.mu.Unlock()
+
+	// Explicit lookups should still be performed.
+	targetKey, _ := decodePubkey(lookupTestnet.target[:])
+	resultC := make(chan []*enode.Node, 1)
+	go func() {
+		resultC <- test.udp.LookupPubkey(targetKey)
+		test.close()
+	}()
+	serveTestnet(test, lookupTestnet)
+
+	results := <-resultC
+	if len(results) != bucketSize {
+		t.Errorf("wrong number of results: got %d, want %d", len(results), bucketSize)
+	}
+	checkLookupResults(t, lookupTestnet, results)
+}
+
 func TestUDPv4_LookupIterator(t *testing.T) {
 	t.Parallel()
 	test := newUDPTest(t)
@@ -146,6 +146,7 @@ func ListenV4(c UDPConn, ln *enode.LocalNode, cfg Config) (*UDPv4, error) {
 	if err != nil {
 		return nil, err
 	}
+	tab.noRandomLookups = cfg.NoRandomLookups
 	t.tab = tab
 	go tab.loop()
 
@@ -214,10 +215,12 @@ func (t *UDPv4) Ping(n *enode.Node) error {
 
 // ping sends a ping message to the given node and waits for a reply.
 func (t *UDPv4) ping(n *enode.Node) (seq uint64, err error) {
+	start := time.Now()
 	rm := t.sendPing(n.ID(), &net.UDPAddr{IP: n.IP(), Port: n.UDP()}, nil)
 	if err = <-rm.errc; err == nil {
 		seq = rm.reply.(*v4wire.Pong).ENRSeq()
 	}
+	pingMetrics.observe(start, err)
 	return seq, err
 }
 
@@ -319,6 +322,7 @@ func (t *UDPv4) findnode(toid enode.ID, toaddr *net.UDPAddr, target v4wire.Pubke
 		}
 		return true, nreceived >= bucketSize
 	})
+	start := time.Now()
 	t.send(toaddr, toid, &v4wire.Findnode{
 		Target:     target,
 		Expiration: uint64(time.Now().Add(expiration).Unix()),
@@ -332,6 +336,7 @@ func (t *UDPv4) findnode(toid enode.ID, toaddr *net.UDPAddr, target v4wire.Pubke
 	if err == errTimeout && rm.reply != nil {
 		err = nil
 	}
+	findnodeMetrics.observe(start, err)
 	return nodes, err
 }
 
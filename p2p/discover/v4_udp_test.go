@@ -59,6 +59,10 @@ type udpTest struct {
 }
 
 func newUDPTest(t *testing.T) *udpTest {
+	return newUDPTestWithConfig(t, Config{})
+}
+
+func newUDPTestWithConfig(t *testing.T, cfg Config) *udpTest {
 	test := &udpTest{
 		t:          t,
 		pipe:       newpipe(),
@@ -69,10 +73,9 @@ func newUDPTest(t *testing.T) *udpTest {
 
 	test.db, _ = enode.OpenDB("")
 	ln := enode.NewLocalNode(test.db, test.localkey)
-	test.udp, _ = ListenV4(test.pipe, ln, Config{
-		PrivateKey: test.localkey,
-		Log:        testlog.Logger(t, log.LvlTrace),
-	})
+	cfg.PrivateKey = test.localkey
+	cfg.Log = testlog.Logger(t, log.LvlTrace)
+	test.udp, _ = ListenV4(test.pipe, ln, cfg)
 	test.table = test.udp.tab
 	// Wait for initial refresh so the table doesn't send unexpected findnode.
 	<-test.table.initDone
@@ -165,6 +165,7 @@ func newUDPv5(conn UDPConn, ln *enode.LocalNode, cfg Config) (*UDPv5, error) {
 	if err != nil {
 		return nil, err
 	}
+	tab.noRandomLookups = cfg.NoRandomLookups
 	t.tab = tab
 	return t, nil
 }
@@ -332,14 +333,17 @@ func lookupDistances(target, dest enode.ID) (dists []uint) {
 
 // ping calls PING on a node and waits for a PONG response.
 func (t *UDPv5) ping(n *enode.Node) (uint64, error) {
+	start := time.Now()
 	req := &v5wire.Ping{ENRSeq: t.localNode.Node().Seq()}
 	resp := t.call(n, v5wire.PongMsg, req)
 	defer t.callDone(resp)
 
 	select {
 	case pong := <-resp.ch:
+		pingMetrics.observe(start, nil)
 		return pong.(*v5wire.Pong).ENRSeq, nil
 	case err := <-resp.err:
+		pingMetrics.observe(start, err)
 		return 0, err
 	}
 }
@@ -358,8 +362,11 @@ func (t *UDPv5) RequestENR(n *enode.Node) (*enode.Node, error) {
 
 // findnode calls FINDNODE on a node and waits for responses.
 func (t *UDPv5) findnode(n *enode.Node, distances []uint) ([]*enode.Node, error) {
+	start := time.Now()
 	resp := t.call(n, v5wire.NodesMsg, &v5wire.Findnode{Distances: distances})
-	return t.waitForNodes(resp, distances)
+	nodes, err := t.waitForNodes(resp, distances)
+	findnodeMetrics.observe(start, err)
+	return nodes, err
 }
 
 // waitForNodes waits for NODES responses to the given call.
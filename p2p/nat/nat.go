@@ -60,12 +60,20 @@ type Interface interface {
 //	"upnp"               uses the Universal Plug and Play protocol
 //	"pmp"                uses NAT-PMP with an auto-detected gateway address
 //	"pmp:192.168.0.1"    uses NAT-PMP with the given gateway address
+//	"stun:<host:port>,.." queries the given STUN servers for the external IP
 func Parse(spec string) (Interface, error) {
 	var (
 		parts = strings.SplitN(spec, ":", 2)
 		mech  = strings.ToLower(parts[0])
 		ip    net.IP
 	)
+	if mech == "stun" {
+		// STUN takes a server list instead of an IP address.
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, errors.New("missing STUN server")
+		}
+		return STUN(strings.Split(parts[1], ",")), nil
+	}
 	if len(parts) > 1 {
 		ip = net.ParseIP(parts[1])
 		if ip == nil {
@@ -0,0 +1,213 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/core-coin/go-core/v2/log"
+)
+
+const (
+	stunDefaultPort = "3478"
+	stunTimeout     = 3 * time.Second
+
+	stunHeaderSize     = 20
+	stunMagicCookie    = 0x2112A442
+	stunBindingRequest = 0x0001
+	stunBindingSuccess = 0x0101
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrXorMappedAddress = 0x0020
+
+	stunFamilyIPv4 = 0x01
+	stunFamilyIPv6 = 0x02
+)
+
+var (
+	errNoSTUNServer      = errors.New("no STUN server configured")
+	errSTUNShortMessage  = errors.New("STUN message too short")
+	errSTUNBadResponse   = errors.New("unexpected STUN message")
+	errSTUNNoMappedAddr  = errors.New("STUN response carries no mapped address")
+	errSTUNBadAddrFamily = errors.New("unknown STUN address family")
+)
+
+// stunClient discovers the external address of the local machine by asking
+// STUN servers (RFC 5389) which address our binding requests arrive from.
+type stunClient struct {
+	servers []string
+	timeout time.Duration
+}
+
+// STUN returns a port mapper that learns the external IP from the given STUN
+// servers, queried in order until one of them answers. Servers are given as
+// host:port, the port defaults to 3478 if omitted.
+//
+// STUN can't create port mappings, so like ExtIP the mapping operations do
+// nothing. It is useful when the NAT keeps the local ports (or they were
+// forwarded manually) but neither UPnP nor NAT-PMP is available.
+func STUN(servers []string) Interface {
+	s := &stunClient{timeout: stunTimeout}
+	for _, server := range servers {
+		if server = strings.TrimSpace(server); server == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, stunDefaultPort)
+		}
+		s.servers = append(s.servers, server)
+	}
+	return s
+}
+
+func (s *stunClient) String() string {
+	return fmt.Sprintf("STUN(%s)", strings.Join(s.servers, ","))
+}
+
+// These do nothing.
+
+func (*stunClient) AddMapping(string, int, int, string, time.Duration) error { return nil }
+func (*stunClient) DeleteMapping(string, int, int) error                     { return nil }
+
+func (s *stunClient) ExternalIP() (net.IP, error) {
+	if len(s.servers) == 0 {
+		return nil, errNoSTUNServer
+	}
+	var err error
+	for _, server := range s.servers {
+		var ip net.IP
+		if ip, err = s.query(server); err == nil {
+			return ip, nil
+		}
+		log.Debug("STUN query failed", "server", server, "err", err)
+	}
+	return nil, err
+}
+
+// query sends a binding request to a single server and returns the mapped
+// address from its response.
+func (s *stunClient) query(server string) (net.IP, error) {
+	conn, err := net.DialTimeout("udp", server, s.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(req[0:], stunBindingRequest)
+	binary.BigEndian.PutUint32(req[4:], stunMagicCookie)
+	if _, err := rand.Read(req[8:]); err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return parseSTUNResponse(buf[:n], req[8:])
+}
+
+// parseSTUNResponse extracts the mapped address from a binding success
+// response matching the given transaction ID. XOR-MAPPED-ADDRESS is preferred
+// over the legacy MAPPED-ADDRESS attribute.
+func parseSTUNResponse(msg []byte, txid []byte) (net.IP, error) {
+	if len(msg) < stunHeaderSize {
+		return nil, errSTUNShortMessage
+	}
+	if binary.BigEndian.Uint16(msg[0:]) != stunBindingSuccess ||
+		binary.BigEndian.Uint32(msg[4:]) != stunMagicCookie ||
+		!bytes.Equal(msg[8:stunHeaderSize], txid) {
+		return nil, errSTUNBadResponse
+	}
+	size := int(binary.BigEndian.Uint16(msg[2:]))
+	if len(msg) < stunHeaderSize+size {
+		return nil, errSTUNShortMessage
+	}
+	var (
+		attrs  = msg[stunHeaderSize : stunHeaderSize+size]
+		mapped net.IP
+	)
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		alen := int(binary.BigEndian.Uint16(attrs[2:]))
+		if len(attrs) < 4+alen {
+			return nil, errSTUNShortMessage
+		}
+		val := attrs[4 : 4+alen]
+
+		switch typ {
+		case stunAttrXorMappedAddress:
+			// The address is XOR-ed with the magic cookie and transaction ID.
+			return decodeSTUNAddress(val, msg[4:stunHeaderSize])
+		case stunAttrMappedAddress:
+			ip, err := decodeSTUNAddress(val, nil)
+			if err != nil {
+				return nil, err
+			}
+			mapped = ip
+		}
+		// Attribute values are padded to a multiple of four bytes.
+		if skip := 4 + (alen+3)&^3; skip < len(attrs) {
+			attrs = attrs[skip:]
+		} else {
+			attrs = nil
+		}
+	}
+	if mapped == nil {
+		return nil, errSTUNNoMappedAddr
+	}
+	return mapped, nil
+}
+
+// decodeSTUNAddress decodes the value of a (XOR-)MAPPED-ADDRESS attribute.
+// If mask is non-nil, the address bytes are XOR-ed with it.
+func decodeSTUNAddress(val []byte, mask []byte) (net.IP, error) {
+	if len(val) < 4 {
+		return nil, errSTUNShortMessage
+	}
+	var size int
+	switch val[1] {
+	case stunFamilyIPv4:
+		size = net.IPv4len
+	case stunFamilyIPv6:
+		size = net.IPv6len
+	default:
+		return nil, errSTUNBadAddrFamily
+	}
+	if len(val) < 4+size {
+		return nil, errSTUNShortMessage
+	}
+	ip := make(net.IP, size)
+	copy(ip, val[4:])
+	if mask != nil {
+		for i := range ip {
+			ip[i] ^= mask[i]
+		}
+	}
+	return ip, nil
+}
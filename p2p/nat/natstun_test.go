@@ -0,0 +1,154 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// startSTUNResponder runs a fake STUN server which answers every binding
+// request with the given mapped address.
+func startSTUNResponder(t *testing.T, mapped net.IP, xor bool) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < stunHeaderSize || binary.BigEndian.Uint16(buf) != stunBindingRequest {
+				continue
+			}
+			conn.WriteTo(makeSTUNResponse(buf[8:stunHeaderSize], mapped, 30303, xor), from)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func makeSTUNResponse(txid []byte, ip net.IP, port uint16, xor bool) []byte {
+	family := byte(stunFamilyIPv6)
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, family = ip4, stunFamilyIPv4
+	}
+	msg := make([]byte, stunHeaderSize+8+len(ip))
+	binary.BigEndian.PutUint16(msg[0:], stunBindingSuccess)
+	binary.BigEndian.PutUint16(msg[2:], uint16(8+len(ip)))
+	binary.BigEndian.PutUint32(msg[4:], stunMagicCookie)
+	copy(msg[8:], txid)
+
+	attr := msg[stunHeaderSize:]
+	binary.BigEndian.PutUint16(attr[0:], stunAttrMappedAddress)
+	binary.BigEndian.PutUint16(attr[2:], uint16(4+len(ip)))
+	attr[5] = family
+	binary.BigEndian.PutUint16(attr[6:], port)
+	copy(attr[8:], ip)
+	if xor {
+		binary.BigEndian.PutUint16(attr[0:], stunAttrXorMappedAddress)
+		binary.BigEndian.PutUint16(attr[6:], port^uint16(stunMagicCookie>>16))
+		for i := range ip {
+			attr[8+i] ^= msg[4+i]
+		}
+	}
+	return msg
+}
+
+func TestSTUNExternalIP(t *testing.T) {
+	tests := []struct {
+		ip  net.IP
+		xor bool
+	}{
+		{net.IP{203, 0, 113, 7}, true},
+		{net.IP{203, 0, 113, 8}, false},
+		{net.ParseIP("2001:db8::1"), true},
+	}
+	for _, tt := range tests {
+		server := startSTUNResponder(t, tt.ip, tt.xor)
+		ip, err := STUN([]string{server}).ExternalIP()
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.ip, err)
+		}
+		if !ip.Equal(tt.ip) {
+			t.Errorf("got IP %v, want %v", ip, tt.ip)
+		}
+	}
+}
+
+func TestSTUNFallback(t *testing.T) {
+	// The first server never answers, the second one does.
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+
+	want := net.IP{198, 51, 100, 1}
+	s := STUN([]string{silent.LocalAddr().String(), startSTUNResponder(t, want, true)}).(*stunClient)
+	s.timeout = 200 * time.Millisecond
+
+	ip, err := s.ExternalIP()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ip.Equal(want) {
+		t.Errorf("got IP %v, want %v", ip, want)
+	}
+}
+
+func TestSTUNBadResponse(t *testing.T) {
+	txid := make([]byte, 12)
+	resp := makeSTUNResponse(txid, net.IP{1, 2, 3, 4}, 1, true)
+
+	if _, err := parseSTUNResponse(resp[:10], txid); err != errSTUNShortMessage {
+		t.Errorf("short message: got error %v", err)
+	}
+	if _, err := parseSTUNResponse(resp, []byte("other txid!!")); err != errSTUNBadResponse {
+		t.Errorf("transaction mismatch: got error %v", err)
+	}
+	if _, err := parseSTUNResponse(resp[:stunHeaderSize+4], txid); err != errSTUNShortMessage {
+		t.Errorf("truncated attribute: got error %v", err)
+	}
+	empty := append([]byte{}, resp[:stunHeaderSize]...)
+	binary.BigEndian.PutUint16(empty[2:], 0)
+	if _, err := parseSTUNResponse(empty, txid); err != errSTUNNoMappedAddr {
+		t.Errorf("no attributes: got error %v", err)
+	}
+}
+
+func TestParseSTUN(t *testing.T) {
+	m, err := Parse("stun:stun1.example.org,stun2.example.org:3479")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if have, want := m.String(), "STUN(stun1.example.org:3478,stun2.example.org:3479)"; have != want {
+		t.Errorf("got %s, want %s", have, want)
+	}
+	if _, err := Parse("stun"); err == nil {
+		t.Error("expected error for missing server list")
+	}
+	if _, err := STUN(nil).ExternalIP(); err != errNoSTUNServer {
+		t.Errorf("got error %v, want %v", err, errNoSTUNServer)
+	}
+}
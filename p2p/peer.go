@@ -206,12 +206,13 @@ func (p *Peer) Log() log.Logger {
 	return p.log
 }
 
-func (p *Peer) run() (remoteRequested bool, err error) {
+// run is the main loop of the peer. It returns the reason of the disconnect,
+// which was sent to the peer or received from it if remoteRequested is set.
+func (p *Peer) run() (remoteRequested bool, reason DiscReason, err error) {
 	var (
 		writeStart = make(chan struct{}, 1)
 		writeErr   = make(chan error, 1)
 		readErr    = make(chan error, 1)
-		reason     DiscReason // sent to the peer
 	)
 	p.wg.Add(2)
 	go p.readLoop(readErr)
@@ -237,6 +238,8 @@ loop:
 			if r, ok := err.(DiscReason); ok {
 				remoteRequested = true
 				reason = r
+			} else if _, ok := err.(*peerError); ok {
+				reason = DiscProtocolError
 			} else {
 				reason = DiscNetworkError
 			}
@@ -253,7 +256,7 @@ loop:
 	close(p.closed)
 	p.rw.close(reason)
 	p.wg.Wait()
-	return remoteRequested, err
+	return remoteRequested, reason, err
 }
 
 func (p *Peer) pingLoop() {
@@ -308,7 +311,7 @@ func (p *Peer) handle(msg Msg) error {
 		// it's a subprotocol message
 		proto, err := p.getProto(msg.Code)
 		if err != nil {
-			return fmt.Errorf("msg code out of range: %v", msg.Code)
+			return err
 		}
 		if metrics.Enabled {
 			m := fmt.Sprintf("%s/%s/%d/%#02x", ingressMeterName, proto.Name, proto.Version, msg.Code-proto.offset)
@@ -108,9 +108,9 @@ func discReasonForError(err error) DiscReason {
 	if err == errProtocolReturned {
 		return DiscQuitting
 	}
-	peerError, ok := err.(*peerError)
-	if ok {
-		switch peerError.code {
+	var perr *peerError
+	if errors.As(err, &perr) {
+		switch perr.code {
 		case errInvalidMsgCode, errInvalidMsg:
 			return DiscProtocolError
 		default:
@@ -0,0 +1,118 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/core-coin/go-core/v2/common/mclock"
+	"github.com/core-coin/go-core/v2/p2p/enode"
+	"github.com/core-coin/go-core/v2/p2p/netutil"
+)
+
+var errPeerBanned = errors.New("peer is banned")
+
+// reputation scores peers on the protocol errors they caused, banning their
+// node IDs and IP addresses once the number of errors reaches the threshold.
+// Scores are forgotten, and bans lifted, after a period without new errors.
+type reputation struct {
+	clock     mclock.Clock
+	threshold int           // Number of protocol errors resulting in a ban
+	duration  time.Duration // Time after the last protocol error the score is reset
+
+	mu       sync.Mutex
+	scores   map[string]*peerScore // Scores keyed by node ID and IP address
+	expiries expHeap               // Reset times of the scores, may contain stale items
+}
+
+// peerScore is the protocol error count of a node ID or IP address.
+type peerScore struct {
+	errors int
+	expiry mclock.AbsTime
+}
+
+func newReputation(clock mclock.Clock, threshold int, duration time.Duration) *reputation {
+	return &reputation{
+		clock:     clock,
+		threshold: threshold,
+		duration:  duration,
+		scores:    make(map[string]*peerScore),
+	}
+}
+
+// penalize records a protocol error caused by the given node, connected from
+// ip. It reports whether the node is banned as a result. LAN addresses are not
+// scored, only the node ID.
+func (r *reputation) penalize(id enode.ID, ip net.IP) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire()
+	banned := r.penalizeKey(id.String())
+	if ip != nil && !netutil.IsLAN(ip) {
+		if r.penalizeKey(ip.String()) {
+			banned = true
+		}
+	}
+	return banned
+}
+
+func (r *reputation) penalizeKey(key string) bool {
+	score := r.scores[key]
+	if score == nil {
+		score = new(peerScore)
+		r.scores[key] = score
+	}
+	score.errors++
+	score.expiry = r.clock.Now().Add(r.duration)
+	r.expiries.add(key, score.expiry)
+
+	return score.errors >= r.threshold
+}
+
+// bannedNode reports whether the node ID is banned.
+func (r *reputation) bannedNode(id enode.ID) bool {
+	return r.banned(id.String())
+}
+
+// bannedIP reports whether the IP address is banned.
+func (r *reputation) bannedIP(ip net.IP) bool {
+	return ip != nil && r.banned(ip.String())
+}
+
+func (r *reputation) banned(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire()
+	score := r.scores[key]
+	return score != nil && score.errors >= r.threshold
+}
+
+// expire drops the scores which weren't raised during the ban duration.
+func (r *reputation) expire() {
+	now := r.clock.Now()
+	r.expiries.expire(now, func(key string) {
+		// Raising the score pushes a new expiry, skip the stale ones.
+		if score := r.scores[key]; score != nil && score.expiry < now {
+			delete(r.scores, key)
+		}
+	})
+}
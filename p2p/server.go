@@ -52,6 +52,7 @@ const (
 	// Connectivity defaults.
 	defaultMaxPendingPeers = 50
 	defaultDialRatio       = 3
+	defaultBanDuration     = time.Hour
 
 	// This time limits inbound connection attempts per source IP.
 	inboundThrottleTime = 30 * time.Second
@@ -85,6 +86,18 @@ type Config struct {
 	// Setting DialRatio to zero defaults it to 3.
 	DialRatio int `toml:",omitempty"`
 
+	// BanThreshold is the number of protocol errors after which a peer is banned,
+	// refusing connections from its node ID and, for inbound peers, IP address.
+	// Only malformed messages received after the handshakes count as protocol
+	// errors. Trusted peers are never banned. Setting BanThreshold to zero
+	// disables banning.
+	BanThreshold int `toml:",omitempty"`
+
+	// BanDuration is the time after the last protocol error of a peer when its
+	// errors are forgotten, lifting the ban. Setting BanDuration to zero defaults
+	// it to one hour.
+	BanDuration time.Duration `toml:",omitempty"`
+
 	// NoDiscovery can be used to disable the peer discovery mechanism.
 	// Disabling is useful for protocol debugging (manual topology).
 	NoDiscovery bool
@@ -93,6 +106,12 @@ type Config struct {
 	// protocol should be started or not.
 	DiscoveryV5 bool `toml:",omitempty"`
 
+	// NoRandomLookups keeps the discovery table running, answering queries from
+	// other nodes, but stops it from searching for random nodes on its own. The
+	// discovered nodes aren't used as dial candidates either, leaving the static
+	// and trusted nodes as the only peers dialed.
+	NoRandomLookups bool `toml:",omitempty"`
+
 	// Name sets the node name of this server.
 	// Use common.MakeName to create a name that follows existing conventions.
 	Name string `toml:"-"`
@@ -145,6 +164,12 @@ type Config struct {
 	// is used to dial outbound peer connections.
 	Dialer NodeDialer `toml:"-"`
 
+	// If DialRanker is set to a non-nil value, it is used to order the static
+	// dial candidates, dialing the nodes it returns first. Candidates left out
+	// of the returned list aren't dialed in that round. If nil, candidates are
+	// dialed in random order.
+	DialRanker func([]*enode.Node) []*enode.Node `toml:"-"`
+
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool `toml:",omitempty"`
 
@@ -197,6 +222,7 @@ type Server struct {
 
 	// State of run loop and listenLoop.
 	inboundHistory expHeap
+	reputation     *reputation
 }
 
 type peerOpFunc func(map[enode.ID]*Peer)
@@ -204,6 +230,7 @@ type peerOpFunc func(map[enode.ID]*Peer)
 type peerDrop struct {
 	*Peer
 	err       error
+	reason    DiscReason
 	requested bool // true if signaled by the peer
 }
 
@@ -469,6 +496,9 @@ func (srv *Server) Start() (err error) {
 	srv.removetrusted = make(chan *enode.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
+	if srv.BanThreshold > 0 {
+		srv.reputation = newReputation(srv.clock, srv.BanThreshold, srv.banDuration())
+	}
 
 	if err := srv.setupLocalNode(); err != nil {
 		return err
@@ -579,18 +609,21 @@ func (srv *Server) setupDiscovery() error {
 			sconn = &sharedUDPConn{conn, unhandled}
 		}
 		cfg := discover.Config{
-			PrivateKey:  srv.PrivateKey,
-			NetRestrict: srv.NetRestrict,
-			Bootnodes:   srv.BootstrapNodes,
-			Unhandled:   unhandled,
-			Log:         srv.log,
+			PrivateKey:      srv.PrivateKey,
+			NetRestrict:     srv.NetRestrict,
+			Bootnodes:       srv.BootstrapNodes,
+			Unhandled:       unhandled,
+			Log:             srv.log,
+			NoRandomLookups: srv.NoRandomLookups,
 		}
 		ntab, err := discover.ListenUDP(conn, srv.localnode, cfg)
 		if err != nil {
 			return err
 		}
 		srv.ntab = ntab
-		srv.discmix.AddSource(ntab.RandomNodes())
+		if !srv.NoRandomLookups {
+			srv.discmix.AddSource(ntab.RandomNodes())
+		}
 	}
 
 	// Discovery V5
@@ -621,6 +654,7 @@ func (srv *Server) setupDialScheduler() {
 		log:            srv.Logger,
 		netRestrict:    srv.NetRestrict,
 		dialer:         srv.Dialer,
+		ranker:         srv.DialRanker,
 		clock:          srv.clock,
 	}
 	if srv.ntab != nil {
@@ -654,6 +688,13 @@ func (srv *Server) maxDialedConns() (limit int) {
 	return limit
 }
 
+func (srv *Server) banDuration() time.Duration {
+	if srv.BanDuration == 0 {
+		return defaultBanDuration
+	}
+	return srv.BanDuration
+}
+
 func (srv *Server) setupListening() error {
 	// Launch the listener.
 	listener, err := srv.listenFunc("tcp", srv.ListenAddr)
@@ -773,6 +814,17 @@ running:
 			if pd.Inbound() {
 				inboundCount--
 			}
+			if srv.reputation != nil && !pd.requested && pd.reason == DiscProtocolError && !trusted[pd.ID()] {
+				// Only the IP addresses of inbound peers are penalized, the ones
+				// we dialed may be shared with other nodes.
+				var ip net.IP
+				if pd.Inbound() {
+					ip = netutil.AddrIP(pd.RemoteAddr())
+				}
+				if srv.reputation.penalize(pd.ID(), ip) {
+					srv.log.Debug("Banned p2p peer", "id", pd.ID(), "addr", pd.RemoteAddr(), "err", pd.err)
+				}
+			}
 		}
 	}
 
@@ -809,6 +861,8 @@ func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount in
 		return DiscAlreadyConnected
 	case c.node.ID() == srv.localnode.ID():
 		return DiscSelf
+	case srv.reputation != nil && !c.is(trustedConn) && srv.reputation.bannedNode(c.node.ID()):
+		return errPeerBanned
 	default:
 		return nil
 	}
@@ -904,6 +958,10 @@ func (srv *Server) checkInboundConn(fd net.Conn, remoteIP net.IP) error {
 	if srv.NetRestrict != nil && !srv.NetRestrict.Contains(remoteIP) {
 		return fmt.Errorf("not whitelisted in NetRestrict")
 	}
+	// Reject peers banned for protocol errors.
+	if srv.reputation != nil && srv.reputation.bannedIP(remoteIP) {
+		return errPeerBanned
+	}
 	// Reject Internet peers that try too often.
 	now := srv.clock.Now()
 	srv.inboundHistory.expire(now, nil)
@@ -1035,12 +1093,12 @@ func (srv *Server) runPeer(p *Peer) {
 	})
 
 	// Run the per-peer main loop.
-	remoteRequested, err := p.run()
+	remoteRequested, reason, err := p.run()
 
 	// Announce disconnect on the main loop to update the peer set.
 	// The main loop waits for existing peers to be sent on srv.delpeer
 	// before returning, so this send should not select on srv.quit.
-	srv.delpeer <- peerDrop{p, err, remoteRequested}
+	srv.delpeer <- peerDrop{p, err, reason, remoteRequested}
 
 	// Broadcast peer drop to external subscribers. This needs to be
 	// after the send to delpeer so subscribers have a consistent view of
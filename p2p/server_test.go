@@ -19,15 +19,18 @@ package p2p
 import (
 	crand "crypto/rand"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"net"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"golang.org/x/crypto/sha3"
 
+	"github.com/core-coin/go-core/v2/common/mclock"
 	"github.com/core-coin/go-core/v2/crypto"
 	"github.com/core-coin/go-core/v2/internal/testlog"
 	"github.com/core-coin/go-core/v2/log"
@@ -567,6 +570,165 @@ func TestServerInboundThrottle(t *testing.T) {
 	}
 }
 
+// This test checks that peers causing protocol errors are banned until the
+// ban expires, and that other errors don't count.
+func TestServerBanPeers(t *testing.T) {
+	var (
+		clock    = new(mclock.Simulated)
+		key      = newkey()
+		remoteIP = net.IP{95, 33, 21, 2}
+		protoErr error
+		broken   = Protocol{
+			Name:    "broken",
+			Version: 1,
+			Length:  1,
+			Run: func(p *Peer, rw MsgReadWriter) error {
+				return protoErr
+			},
+		}
+	)
+	srv := &Server{
+		Config: Config{
+			PrivateKey:   newkey(),
+			MaxPeers:     10,
+			NoDial:       true,
+			NoDiscovery:  true,
+			Protocols:    []Protocol{broken},
+			BanThreshold: 2,
+			BanDuration:  time.Minute,
+			Logger:       testlog.Logger(t, log.LvlTrace),
+			clock:        clock,
+		},
+		newTransport: func(fd net.Conn, dialDest *crypto.PublicKey) transport {
+			return &banTransport{pubkey: key.PublicKey(), caps: []Cap{broken.cap()}, closed: make(chan struct{})}
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal("can't start: ", err)
+	}
+	defer srv.Stop()
+
+	events := make(chan *PeerEvent, 1)
+	sub := srv.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	// connect runs a connection with the remote peer, waiting for the protocol
+	// error to drop it if the peer is accepted.
+	connect := func(flags connFlag) error {
+		fd, _ := net.Pipe()
+		var dest *enode.Node
+		if flags&dynDialedConn != 0 {
+			dest = enode.NewV4(key.PublicKey(), remoteIP, 4444, 0)
+		}
+		if err := srv.SetupConn(&fakeAddrConn{fd, &net.TCPAddr{IP: remoteIP, Port: 4444}}, flags, dest); err != nil {
+			return err
+		}
+		for {
+			select {
+			case ev := <-events:
+				if ev.Type == PeerEventTypeDrop {
+					return nil
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("peer not dropped")
+			}
+		}
+	}
+	// Errors of the subprotocol not caused by malformed messages, like failed
+	// handshakes, don't count.
+	protoErr = errors.New("network id mismatch")
+	for i := 0; i < 3; i++ {
+		if err := connect(inboundConn); err != nil {
+			t.Fatalf("connection %d rejected: %v", i, err)
+		}
+	}
+	// Malformed messages from dialed peers ban their node ID, but not their IP.
+	protoErr = fmt.Errorf("bad message: %w", newPeerError(errInvalidMsg, "malformed"))
+	for i := 0; i < 2; i++ {
+		if err := connect(dynDialedConn); err != nil {
+			t.Fatalf("dialed connection %d rejected: %v", i, err)
+		}
+	}
+	if err := connect(dynDialedConn); err != errPeerBanned {
+		t.Fatalf("banned dialed peer not rejected: %v", err)
+	}
+	if err := srv.checkInboundConn(nil, remoteIP); err != nil {
+		t.Fatalf("IP of dialed peer rejected: %v", err)
+	}
+	// Once the ban expires, the peer may connect again, its inbound connections
+	// banning the IP as well.
+	clock.Run(time.Minute + time.Second)
+	for i := 0; i < 2; i++ {
+		if err := connect(inboundConn); err != nil {
+			t.Fatalf("connection %d rejected: %v", i, err)
+		}
+	}
+	if err := connect(inboundConn); err != errPeerBanned {
+		t.Fatalf("banned peer not rejected: %v", err)
+	}
+	if err := srv.checkInboundConn(nil, remoteIP); err != errPeerBanned {
+		t.Fatalf("banned IP not rejected: %v", err)
+	}
+	clock.Run(time.Minute + time.Second)
+	if err := srv.checkInboundConn(nil, remoteIP); err != nil {
+		t.Fatalf("IP rejected after ban expiry: %v", err)
+	}
+	if err := connect(inboundConn); err != nil {
+		t.Fatalf("peer rejected after ban expiry: %v", err)
+	}
+}
+
+// This test checks that peers are not banned unless a threshold is configured.
+func TestServerBanPeersDisabled(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal("can't start: ", err)
+	}
+	defer srv.Stop()
+
+	if srv.reputation != nil {
+		t.Fatal("peer reputation tracked without ban threshold")
+	}
+}
+
+// banTransport is a transport completing the handshakes with the given
+// capabilities. It discards all written messages.
+type banTransport struct {
+	pubkey *crypto.PublicKey
+	caps   []Cap
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *banTransport) doEncHandshake(prv *crypto.PrivateKey) (*crypto.PublicKey, error) {
+	return c.pubkey, nil
+}
+
+func (c *banTransport) doProtoHandshake(our *protoHandshake) (*protoHandshake, error) {
+	return &protoHandshake{ID: c.pubkey[:], Caps: c.caps}, nil
+}
+
+func (c *banTransport) close(err error) {
+	c.once.Do(func() { close(c.closed) })
+}
+
+func (c *banTransport) WriteMsg(msg Msg) error {
+	return msg.Discard()
+}
+
+func (c *banTransport) ReadMsg() (Msg, error) {
+	<-c.closed
+	return Msg{}, io.EOF
+}
+
 func listenFakeAddr(network, laddr string, remoteAddr net.Addr) (net.Listener, error) {
 	l, err := net.Listen(network, laddr)
 	if err == nil {
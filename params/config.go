@@ -18,6 +18,7 @@ package params
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -92,9 +93,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1), nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1337), nil, new(CryptoreConfig), nil}
+	TestChainConfig = &ChainConfig{big.NewInt(1337), nil, nil, new(CryptoreConfig), nil, nil}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -147,11 +148,15 @@ type CheckpointOracleConfig struct {
 type ChainConfig struct {
 	NetworkID *big.Int `json:"networkId"` // networkId identifies the current chain and is used for replay protection
 
-	EWASMBlock *big.Int `json:"ewasmBlock,omitempty"` // EWASM switch block (nil = no fork, 0 = already activated)
+	EWASMBlock      *big.Int `json:"ewasmBlock,omitempty"`      // EWASM switch block (nil = no fork, 0 = already activated)
+	AccessListBlock *big.Int `json:"accessListBlock,omitempty"` // Access list transactions switch block (nil = no fork, 0 = already activated)
 
 	// Various consensus engines
 	Cryptore *CryptoreConfig `json:"cryptore,omitempty"`
 	Clique   *CliqueConfig   `json:"clique,omitempty"`
+
+	// EnergyTable overrides the default energy costs (nil = protocol defaults)
+	EnergyTable *EnergyTable `json:"energyTable,omitempty"`
 }
 
 // CryptoreConfig is the consensus engine configs for proof-of-work based sealing.
@@ -195,6 +200,21 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsAccessList returns whether num represents a block number after the access
+// list transactions fork.
+func (c *ChainConfig) IsAccessList(num *big.Int) bool {
+	return isForked(c.AccessListBlock, num)
+}
+
+// EnergyCosts returns the energy costs in effect on the chain, which are the
+// protocol defaults, unless overridden by the EnergyTable of the config.
+func (c *ChainConfig) EnergyCosts() EnergyCosts {
+	if c == nil || c.EnergyTable == nil {
+		return DefaultEnergyCosts
+	}
+	return c.EnergyTable.apply(DefaultEnergyCosts)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -248,6 +268,19 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.AccessListBlock, newcfg.AccessListBlock, head) {
+		return newCompatError("access list fork block", c.AccessListBlock, newcfg.AccessListBlock)
+	}
+	return nil
+}
+
+// CheckEnergyCompatible checks whether the energy costs of newcfg may replace
+// the stored ones. The costs apply from genesis, so they cannot be changed once
+// blocks on top of it have been imported.
+func (c *ChainConfig) CheckEnergyCompatible(newcfg *ChainConfig, height uint64) error {
+	if height > 0 && c.EnergyCosts() != newcfg.EnergyCosts() {
+		return ErrEnergyTableIncompatible
+	}
 	return nil
 }
 
@@ -275,6 +308,10 @@ func configNumEqual(x, y *big.Int) bool {
 	return x.Cmp(y) == 0
 }
 
+// ErrEnergyTableIncompatible is returned if the energy costs of a chain are
+// changed after blocks have been imported on top of its genesis.
+var ErrEnergyTableIncompatible = errors.New("energy table incompatible with the stored chain config")
+
 // ConfigCompatError is raised if the locally-stored blockchain is initialised with a
 // ChainConfig that would alter the past.
 type ConfigCompatError struct {
@@ -48,6 +48,17 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored: &ChainConfig{AccessListBlock: big.NewInt(30)},
+			new:    &ChainConfig{},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "access list fork block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    nil,
+				RewindTo:     29,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -57,3 +68,40 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckEnergyCompatible(t *testing.T) {
+	txEnergy, defaultTxEnergy := uint64(30000), uint64(TxEnergy)
+
+	tests := []struct {
+		stored, new *ChainConfig
+		head        uint64
+		wantErr     error
+	}{
+		{
+			stored: &ChainConfig{EnergyTable: &EnergyTable{TxEnergy: &txEnergy}},
+			new:    &ChainConfig{EnergyTable: &EnergyTable{TxEnergy: &txEnergy}},
+			head:   5,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{EnergyTable: &EnergyTable{TxEnergy: &defaultTxEnergy}},
+			head:   5,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{EnergyTable: &EnergyTable{TxEnergy: &txEnergy}},
+			head:   0,
+		},
+		{
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{EnergyTable: &EnergyTable{TxEnergy: &txEnergy}},
+			head:    5,
+			wantErr: ErrEnergyTableIncompatible,
+		},
+	}
+	for _, test := range tests {
+		if err := test.stored.CheckEnergyCompatible(test.new, test.head); err != test.wantErr {
+			t.Errorf("error mismatch:\nstored: %v\nnew: %v\nhead: %v\nerr: %v\nwant: %v", test.stored, test.new, test.head, err, test.wantErr)
+		}
+	}
+}
@@ -60,6 +60,10 @@ const (
 	MemoryEnergy             uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 	TxDataNonZeroEnergy      uint64 = 16    // Per byte of non zero data attached to a transaction
 
+	TxAccessListAddressEnergy    uint64 = 2400 // Per address specified in the access list of a transaction
+	TxAccessListStorageKeyEnergy uint64 = 1900 // Per storage key specified in the access list of a transaction
+	WarmStorageReadEnergy        uint64 = 100  // Cost of accessing an account or storage slot already accessed by the transaction or in its access list
+
 	// These have been changed during the course of the chain
 	CallEnergy         uint64 = 700  // Static portion of energy for CALL-derivates
 	BalanceEnergy      uint64 = 700  // The cost of a BALANCE operation
@@ -104,3 +108,87 @@ var (
 	MinimumDifficulty      = big.NewInt(8192) // The minimum that the difficulty may ever be.
 	DurationLimit          = big.NewInt(6)    // The decision boundary on the blocktime duration used to determine whether difficulty should go up or not.
 )
+
+// EnergyTable overrides the energy costs of transactions and of the static part
+// of CVM operations, allowing test networks to experiment with pricing. Fields
+// left nil fall back to the default protocol costs, see EnergyCosts.
+type EnergyTable struct {
+	TxEnergy                 *uint64 `json:"txEnergy,omitempty"`
+	TxEnergyContractCreation *uint64 `json:"txEnergyContractCreation,omitempty"`
+	TxDataZeroEnergy         *uint64 `json:"txDataZeroEnergy,omitempty"`
+	TxDataNonZeroEnergy      *uint64 `json:"txDataNonZeroEnergy,omitempty"`
+
+	Sha3Energy        *uint64 `json:"sha3Energy,omitempty"`
+	BalanceEnergy     *uint64 `json:"balanceEnergy,omitempty"`
+	ExtcodeSizeEnergy *uint64 `json:"extcodeSizeEnergy,omitempty"`
+	ExtcodeCopyBase   *uint64 `json:"extcodeCopyBase,omitempty"`
+	ExtcodeHashEnergy *uint64 `json:"extcodeHashEnergy,omitempty"`
+	SloadEnergy       *uint64 `json:"sloadEnergy,omitempty"`
+	JumpdestEnergy    *uint64 `json:"jumpdestEnergy,omitempty"`
+	CallEnergy        *uint64 `json:"callEnergy,omitempty"`
+	CreateEnergy      *uint64 `json:"createEnergy,omitempty"`
+	Create2Energy     *uint64 `json:"create2Energy,omitempty"`
+}
+
+// EnergyCosts are the energy costs of transactions and of the static part of
+// CVM operations in effect on a chain.
+type EnergyCosts struct {
+	TxEnergy                 uint64
+	TxEnergyContractCreation uint64
+	TxDataZeroEnergy         uint64
+	TxDataNonZeroEnergy      uint64
+
+	Sha3Energy        uint64
+	BalanceEnergy     uint64
+	ExtcodeSizeEnergy uint64
+	ExtcodeCopyBase   uint64
+	ExtcodeHashEnergy uint64
+	SloadEnergy       uint64
+	JumpdestEnergy    uint64
+	CallEnergy        uint64
+	CreateEnergy      uint64
+	Create2Energy     uint64
+}
+
+// DefaultEnergyCosts contains the default protocol energy costs.
+var DefaultEnergyCosts = EnergyCosts{
+	TxEnergy:                 TxEnergy,
+	TxEnergyContractCreation: TxEnergyContractCreation,
+	TxDataZeroEnergy:         TxDataZeroEnergy,
+	TxDataNonZeroEnergy:      TxDataNonZeroEnergy,
+
+	Sha3Energy:        Sha3Energy,
+	BalanceEnergy:     BalanceEnergy,
+	ExtcodeSizeEnergy: ExtcodeSizeEnergy,
+	ExtcodeCopyBase:   ExtcodeCopyBase,
+	ExtcodeHashEnergy: ExtcodeHashEnergy,
+	SloadEnergy:       SloadEnergy,
+	JumpdestEnergy:    JumpdestEnergy,
+	CallEnergy:        CallEnergy,
+	CreateEnergy:      CreateEnergy,
+	Create2Energy:     Create2Energy,
+}
+
+// apply returns a copy of the costs with the ones set in the table overridden.
+func (t *EnergyTable) apply(costs EnergyCosts) EnergyCosts {
+	override := func(v *uint64, set *uint64) {
+		if set != nil {
+			*v = *set
+		}
+	}
+	override(&costs.TxEnergy, t.TxEnergy)
+	override(&costs.TxEnergyContractCreation, t.TxEnergyContractCreation)
+	override(&costs.TxDataZeroEnergy, t.TxDataZeroEnergy)
+	override(&costs.TxDataNonZeroEnergy, t.TxDataNonZeroEnergy)
+	override(&costs.Sha3Energy, t.Sha3Energy)
+	override(&costs.BalanceEnergy, t.BalanceEnergy)
+	override(&costs.ExtcodeSizeEnergy, t.ExtcodeSizeEnergy)
+	override(&costs.ExtcodeCopyBase, t.ExtcodeCopyBase)
+	override(&costs.ExtcodeHashEnergy, t.ExtcodeHashEnergy)
+	override(&costs.SloadEnergy, t.SloadEnergy)
+	override(&costs.JumpdestEnergy, t.JumpdestEnergy)
+	override(&costs.CallEnergy, t.CallEnergy)
+	override(&costs.CreateEnergy, t.CreateEnergy)
+	override(&costs.Create2Energy, t.Create2Energy)
+	return costs
+}
@@ -284,6 +284,35 @@ func TestStreamRaw(t *testing.T) {
 	}
 }
 
+// eofReader returns io.EOF along with the last bytes it reads.
+type eofReader []byte
+
+func (r *eofReader) Read(buf []byte) (n int, err error) {
+	n = copy(buf, *r)
+	if *r = (*r)[n:]; len(*r) == 0 {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Tests that reading the last bytes of the input doesn't fail if the reader
+// reports EOF together with them.
+func TestStreamRawReaderEOF(t *testing.T) {
+	// The value must be larger than the read buffer of the stream, so that
+	// it's read straight from the reader
+	want, _ := EncodeToBytes(bytes.Repeat([]byte{0x01}, 10000))
+	input := eofReader(append([]byte{}, want...))
+	s := NewStream(&input, 0)
+
+	raw, err := s.Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw, want) {
+		t.Errorf("raw mismatch: got %x, want %x", raw, want)
+	}
+}
+
 func TestDecodeErrors(t *testing.T) {
 	r := bytes.NewReader(nil)
 
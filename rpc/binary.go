@@ -0,0 +1,243 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/core-coin/go-core/v2/rlp"
+)
+
+// binaryMessage is the wire representation of a jsonrpcMessage in the binary
+// codec. The envelope is RLP encoded, whereas params and results are carried as
+// opaque blobs, saving the codec from scanning and re-validating large payloads.
+type binaryMessage struct {
+	Version string
+	ID      []byte
+	Method  string
+	Params  []byte
+	Error   []byte // JSON encoded jsonError, empty if the message is not an error
+	Result  []byte
+}
+
+// binaryFrame is a single unit of transmission of the binary codec, containing
+// either a single message or a batch of them.
+type binaryFrame struct {
+	Batch    bool
+	Messages []binaryMessage
+}
+
+// binaryCodec reads and writes RPC messages to the underlying connection using a
+// compact RLP framing instead of JSON text.
+type binaryCodec struct {
+	remote  string
+	closer  sync.Once        // close closed channel once
+	closeCh chan interface{} // closed on Close
+	stream  *rlp.Stream      // decoder of the inbound frames
+	limit   uint64           // maximum size of an inbound frame, 0 = unlimited
+	encMu   sync.Mutex       // guards the writes to the connection
+	conn    Conn
+}
+
+// NewBinaryCodec creates a binary codec on the given connection. Inbound frames
+// are limited to the same size as requests on the HTTP and WebSocket transports.
+// If conn implements ConnRemoteAddr, log messages will use it to include the
+// remote address of the connection.
+func NewBinaryCodec(conn Conn) ServerCodec {
+	return newBinaryCodec(conn, maxRequestContentLength)
+}
+
+func newBinaryCodec(conn Conn, limit uint64) *binaryCodec {
+	codec := &binaryCodec{
+		closeCh: make(chan interface{}),
+		stream:  rlp.NewStream(conn, 0),
+		limit:   limit,
+		conn:    conn,
+	}
+	if ra, ok := conn.(ConnRemoteAddr); ok {
+		codec.remote = ra.RemoteAddr()
+	}
+	return codec
+}
+
+// DialBinary creates a new IPC client that connects to the given endpoint, just
+// like DialIPC, but talks to the server using the binary codec.
+//
+// The context is used for the initial connection establishment. It does not
+// affect subsequent interactions with the client.
+func DialBinary(ctx context.Context, endpoint string) (*Client, error) {
+	return newClient(ctx, func(ctx context.Context) (ServerCodec, error) {
+		conn, err := newIPCConnection(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		// Responses may legitimately be way larger than requests, don't limit them.
+		return newBinaryCodec(conn, 0), nil
+	})
+}
+
+func (c *binaryCodec) peerInfo() PeerInfo {
+	return PeerInfo{Transport: "ipc", RemoteAddr: c.remote}
+}
+
+func (c *binaryCodec) remoteAddr() string {
+	return c.remote
+}
+
+func (c *binaryCodec) readBatch() (messages []*jsonrpcMessage, batch bool, err error) {
+	// Check the frame size before decoding anything, nested items can't be
+	// larger than the enclosing list.
+	_, size, err := c.stream.Kind()
+	if err != nil {
+		return nil, false, err
+	}
+	if c.limit > 0 && size > c.limit {
+		return nil, false, fmt.Errorf("frame too large (%d>%d)", size, c.limit)
+	}
+	var frame binaryFrame
+	if err := c.stream.Decode(&frame); err != nil {
+		return nil, false, err
+	}
+	messages = make([]*jsonrpcMessage, len(frame.Messages))
+	for i, bin := range frame.Messages {
+		msg := &jsonrpcMessage{
+			Version: bin.Version,
+			ID:      nonEmptyRaw(bin.ID),
+			Method:  bin.Method,
+			Params:  nonEmptyRaw(bin.Params),
+			Result:  nonEmptyRaw(bin.Result),
+		}
+		if len(bin.Error) > 0 {
+			msg.Error = new(jsonError)
+			if err := json.Unmarshal(bin.Error, msg.Error); err != nil {
+				// Treat it like any other invalid message.
+				msg = new(jsonrpcMessage)
+			}
+		}
+		messages[i] = msg
+	}
+	return messages, frame.Batch, nil
+}
+
+func (c *binaryCodec) writeJSON(ctx context.Context, v interface{}) error {
+	var frame binaryFrame
+	switch v := v.(type) {
+	case *jsonrpcMessage:
+		frame.Messages = make([]binaryMessage, 1)
+		if err := frame.Messages[0].fill(v); err != nil {
+			return err
+		}
+	case []*jsonrpcMessage:
+		frame.Batch = true
+		frame.Messages = make([]binaryMessage, len(v))
+		for i, msg := range v {
+			if err := frame.Messages[i].fill(msg); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("can't encode %T with the binary codec", v)
+	}
+	blob, err := rlp.EncodeToBytes(&frame)
+	if err != nil {
+		return err
+	}
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultWriteTimeout)
+	}
+	c.conn.SetWriteDeadline(deadline)
+	_, err = c.conn.Write(blob)
+	return err
+}
+
+func (c *binaryCodec) close() {
+	c.closer.Do(func() {
+		close(c.closeCh)
+		c.conn.Close()
+	})
+}
+
+// Closed returns a channel which will be closed when Close is called
+func (c *binaryCodec) closed() <-chan interface{} {
+	return c.closeCh
+}
+
+// fill sets the fields of the wire message from the given RPC message.
+func (bin *binaryMessage) fill(msg *jsonrpcMessage) error {
+	*bin = binaryMessage{
+		Version: msg.Version,
+		ID:      msg.ID,
+		Method:  msg.Method,
+		Params:  msg.Params,
+		Result:  msg.Result,
+	}
+	if msg.Error != nil {
+		blob, err := json.Marshal(msg.Error)
+		if err != nil {
+			return err
+		}
+		bin.Error = blob
+	}
+	return nil
+}
+
+// nonEmptyRaw converts a decoded blob into a raw JSON value. RLP doesn't know
+// about nil, but an empty blob is never a valid JSON value so it can be mapped
+// back to a missing field.
+func nonEmptyRaw(blob []byte) json.RawMessage {
+	if len(blob) == 0 {
+		return nil
+	}
+	return blob
+}
+
+// bufferedConn is a net.Conn whose reads go through a buffered reader, allowing
+// the first bytes of the stream to be inspected without consuming them.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// negotiateCodec picks the codec to serve a stream connection with, based on the
+// first byte sent by the remote side. Binary frames always start with an RLP list
+// header, which can never be the beginning of a JSON value.
+func negotiateCodec(conn net.Conn) (ServerCodec, error) {
+	r := bufio.NewReader(conn)
+	prefix, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	buffered := &bufferedConn{Conn: conn, r: r}
+	if prefix[0] >= 0xC0 {
+		return NewBinaryCodec(buffered), nil
+	}
+	return NewCodec(buffered), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Tests that calls and subscriptions round-trip through the binary codec, and
+// that the listener keeps serving JSON clients next to binary ones.
+func TestBinaryCodecSubscription(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	endpoint := fmt.Sprintf("go-core-test-ipc-%d-%d", os.Getpid(), rand.Int63())
+	if runtime.GOOS == "windows" {
+		endpoint = `\\.\pipe\` + endpoint
+	} else {
+		endpoint = os.TempDir() + "/" + endpoint
+	}
+	l, err := ipcListen(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go server.ServeListener(l)
+
+	client, err := DialBinary(context.Background(), endpoint)
+	if err != nil {
+		t.Fatal("can't dial:", err)
+	}
+	defer client.Close()
+
+	// Check plain calls, including error responses.
+	var resp echoResult
+	if err := client.Call(&resp, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (echoResult{"hello", 10, &echoArgs{"world"}}); !reflect.DeepEqual(resp, want) {
+		t.Errorf("incorrect result %#v, want %#v", resp, want)
+	}
+	err = client.Call(nil, "test_returnError")
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != 444 {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Check that notifications arrive in order.
+	nc := make(chan int)
+	count := 10
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "someSubscription", count, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	for i := 0; i < count; i++ {
+		select {
+		case val := <-nc:
+			if val != i {
+				t.Fatalf("value mismatch: got %d, want %d", val, i)
+			}
+		case err := <-sub.Err():
+			t.Fatal("subscription failed:", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notification", i)
+		}
+	}
+	sub.Unsubscribe()
+
+	// Check that JSON clients are still served on the same listener.
+	jsonClient, err := DialIPC(context.Background(), endpoint)
+	if err != nil {
+		t.Fatal("can't dial:", err)
+	}
+	defer jsonClient.Close()
+
+	var result int
+	if err := jsonClient.Call(&result, "nftest_echo", 42); err != nil {
+		t.Fatal(err)
+	}
+	if result != 42 {
+		t.Errorf("incorrect result %d, want 42", result)
+	}
+}
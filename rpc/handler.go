@@ -198,6 +198,7 @@ func (h *handler) addSubscriptions(nn []*Notifier) {
 
 	for _, n := range nn {
 		if sub := n.takeSubscription(); sub != nil {
+			h.reg.beginServe()
 			h.serverSubs[sub.ID] = sub
 		}
 	}
@@ -211,16 +212,36 @@ func (h *handler) cancelServerSubscriptions(err error) {
 	for id, s := range h.serverSubs {
 		s.err <- err
 		close(s.err)
+		close(s.quit)
 		delete(h.serverSubs, id)
+		h.reg.endServe()
+	}
+}
+
+// dropSubscription terminates a single server subscription, delivering err to
+// the subscriber through its error channel.
+func (h *handler) dropSubscription(id ID, err error) {
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	if s := h.serverSubs[id]; s != nil {
+		s.err <- err
+		close(s.err)
+		close(s.quit)
+		delete(h.serverSubs, id)
+		h.reg.endServe()
 	}
 }
 
 // startCallProc runs fn in a new goroutine and starts tracking it in the h.calls wait group.
+// The goroutine is also tracked by the registry, so that draining waits for it.
 func (h *handler) startCallProc(fn func(*callProc)) {
 	h.callWG.Add(1)
+	h.reg.beginServe()
 	go func() {
 		ctx, cancel := context.WithCancel(h.rootCtx)
 		defer h.callWG.Done()
+		defer h.reg.endServe()
 		defer cancel()
 		fn(&callProc{ctx: ctx})
 	}()
@@ -316,6 +337,11 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	// Unsubscribing is always allowed, so that subscriptions can be finished
+	// while the server is draining.
+	if !msg.isUnsubscribe() && !h.reg.accepting() {
+		return msg.errorResponse(errServerShuttingDown)
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -346,6 +372,10 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		}
 		rpcServingTimer.UpdateSince(start)
 		newRPCServingTimer(msg.Method, answer.Error == nil).UpdateSince(start)
+
+		if r := h.reg.methodMetrics(); r != nil {
+			recordMethodCall(r, msg.Method, time.Since(start), answer.Error != nil)
+		}
 	}
 	return answer
 }
@@ -402,7 +432,9 @@ func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 		return false, ErrSubscriptionNotFound
 	}
 	close(s.err)
+	close(s.quit)
 	delete(h.serverSubs, id)
+	h.reg.endServe()
 	return true, nil
 }
 
@@ -24,7 +24,8 @@ import (
 	"github.com/core-coin/go-core/v2/p2p/netutil"
 )
 
-// ServeListener accepts connections on l, serving JSON-RPC on them.
+// ServeListener accepts connections on l, serving JSON-RPC on them. Clients
+// speaking the binary codec are detected automatically.
 func (s *Server) ServeListener(l net.Listener) error {
 	for {
 		conn, err := l.Accept()
@@ -35,7 +36,14 @@ func (s *Server) ServeListener(l net.Listener) error {
 			return err
 		}
 		log.Trace("Accepted RPC connection", "conn", conn.RemoteAddr())
-		go s.ServeCodec(NewCodec(conn), 0)
+		go func(conn net.Conn) {
+			codec, err := negotiateCodec(conn)
+			if err != nil {
+				conn.Close()
+				return
+			}
+			s.ServeCodec(codec, 0)
+		}(conn)
 	}
 }
 
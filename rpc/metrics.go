@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/core-coin/go-core/v2/metrics"
 )
@@ -37,3 +38,19 @@ func newRPCServingTimer(method string, valid bool) metrics.Timer {
 	m := fmt.Sprintf("rpc/duration/%s/%s", method, flag)
 	return metrics.GetOrRegisterTimer(m, nil)
 }
+
+// recordMethodCall updates the call count, error count and latency histogram of
+// a single method in the given registry. The counters are always collected, but
+// the latency histogram honours the global metrics switch like all the others.
+func recordMethodCall(r metrics.Registry, method string, elapsed time.Duration, failed bool) {
+	prefix := fmt.Sprintf("rpc/methods/%s/", method)
+
+	metrics.GetOrRegisterCounterForced(prefix+"calls", r).Inc(1)
+	if failed {
+		metrics.GetOrRegisterCounterForced(prefix+"errors", r).Inc(1)
+	}
+	latency := r.GetOrRegister(prefix+"latency", func() metrics.Histogram {
+		return metrics.NewHistogram(metrics.NewExpDecaySample(1028, 0.015))
+	}).(metrics.Histogram)
+	latency.Update(int64(elapsed))
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/core-coin/go-core/v2/log"
+)
+
+// reconnectDelay is the time to wait between two failed attempts of restoring a
+// dropped subscription.
+var reconnectDelay = time.Second
+
+// ReconnectClient is a websocket RPC client whose subscriptions survive dropped
+// connections. Whenever the connection breaks, the client redials the endpoint
+// and re-establishes all active subscriptions with their original arguments.
+//
+// Notifications sent by the server while the connection was down are lost, the
+// Resubscribed channel of a subscription can be used to detect such gaps.
+type ReconnectClient struct {
+	*Client
+}
+
+// DialWebsocketReconnect creates a new websocket RPC client which automatically
+// reconnects and resubscribes when the connection to the server is lost.
+func DialWebsocketReconnect(ctx context.Context, endpoint, origin string) (*ReconnectClient, error) {
+	client, err := DialWebsocket(ctx, endpoint, origin)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconnectClient{Client: client}, nil
+}
+
+// XcbSubscribe registers a reconnecting subscripion under the "xcb" namespace.
+func (c *ReconnectClient) XcbSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*ReconnectSubscription, error) {
+	return c.Subscribe(ctx, "xcb", channel, args...)
+}
+
+// Subscribe calls the "<namespace>_subscribe" method with the given arguments,
+// registering a subscription which is restored every time the connection to the
+// server is re-established. Server notifications are sent to the given channel,
+// for the lifetime of the subscription across all reconnects.
+func (c *ReconnectClient) Subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ReconnectSubscription, error) {
+	sub, err := c.Client.Subscribe(ctx, namespace, channel, args...)
+	if err != nil {
+		return nil, err
+	}
+	rsub := &ReconnectSubscription{
+		client:       c.Client,
+		namespace:    namespace,
+		channel:      channel,
+		args:         args,
+		sub:          sub,
+		resubscribed: make(chan struct{}, 1),
+		err:          make(chan error, 1),
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go rsub.loop()
+	return rsub, nil
+}
+
+// ReconnectSubscription is a subscription established through the Subscribe or
+// XcbSubscribe methods of a ReconnectClient.
+type ReconnectSubscription struct {
+	client    *Client
+	namespace string
+	channel   interface{}
+	args      []interface{}
+	sub       *ClientSubscription // Currently live subscription on the server
+
+	resubscribed chan struct{} // Signals each restored subscription
+	err          chan error    // Reports the error terminating the subscription
+
+	quitOnce sync.Once     // ensures quit is closed once
+	quit     chan struct{} // quit is closed when the subscription is unsubscribed
+	done     chan struct{} // done is closed when the resubscription loop exits
+	errOnce  sync.Once     // ensures err is closed once
+}
+
+// Err returns the subscription error channel. Connection failures are handled
+// internally, so the channel only receives a value if the subscription ends for
+// good. The received error is nil if Close has been called on the underlying
+// client.
+//
+// The error channel is closed when Unsubscribe is called on the subscription.
+func (sub *ReconnectSubscription) Err() <-chan error {
+	return sub.err
+}
+
+// Resubscribed returns a channel which is signalled each time the subscription
+// is re-established after a dropped connection. Signals are coalesced if they
+// are not consumed in time.
+func (sub *ReconnectSubscription) Resubscribed() <-chan struct{} {
+	return sub.resubscribed
+}
+
+// Unsubscribe unsubscribes the notification and closes the error channel.
+// It can safely be called more than once.
+func (sub *ReconnectSubscription) Unsubscribe() {
+	sub.quitOnce.Do(func() { close(sub.quit) })
+	<-sub.done
+	sub.errOnce.Do(func() { close(sub.err) })
+}
+
+// loop watches the live subscription, restoring it whenever it fails due to a
+// connection error.
+func (sub *ReconnectSubscription) loop() {
+	defer close(sub.done)
+
+	for {
+		select {
+		case <-sub.quit:
+			sub.sub.Unsubscribe()
+			return
+
+		case err := <-sub.sub.Err():
+			// Client shutdown and slow consumers are final, anything else is
+			// assumed to be a connection failure
+			if err == nil || err == ErrSubscriptionQueueOverflow {
+				sub.err <- err
+				return
+			}
+			log.Debug("RPC subscription dropped, resubscribing", "namespace", sub.namespace, "err", err)
+			if !sub.resubscribe() {
+				return
+			}
+			select {
+			case sub.resubscribed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// resubscribe keeps trying to restore the subscription until it succeeds or the
+// subscription is terminated, reporting whether it succeeded.
+func (sub *ReconnectSubscription) resubscribe() bool {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultDialTimeout)
+		s, err := sub.client.Subscribe(ctx, sub.namespace, sub.channel, sub.args...)
+		cancel()
+
+		switch {
+		case err == nil:
+			sub.sub = s
+			return true
+		case err == ErrClientQuit:
+			sub.err <- nil
+			return false
+		}
+		log.Trace("RPC resubscription failed", "namespace", sub.namespace, "err", err)
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-sub.quit:
+			return false
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// dropListener is a net.Listener which can forcibly close all the connections
+// it accepted.
+type dropListener struct {
+	net.Listener
+
+	conns []net.Conn
+	lock  sync.Mutex
+}
+
+func (l *dropListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.lock.Lock()
+		l.conns = append(l.conns, conn)
+		l.lock.Unlock()
+	}
+	return conn, err
+}
+
+// drop closes every connection accepted so far.
+func (l *dropListener) drop() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+	l.conns = nil
+}
+
+// Tests that subscriptions of a reconnecting client are restored after the
+// server drops the connection mid-stream.
+func TestReconnectSubscription(t *testing.T) {
+	defer func(delay time.Duration) { reconnectDelay = delay }(reconnectDelay)
+	reconnectDelay = 10 * time.Millisecond
+
+	var (
+		srv     = newTestServer()
+		httpsrv = httptest.NewUnstartedServer(srv.WebsocketHandler([]string{"*"}))
+		ln      = &dropListener{Listener: httpsrv.Listener}
+	)
+	httpsrv.Listener = ln
+	httpsrv.Start()
+	defer srv.Stop()
+	defer httpsrv.Close()
+
+	wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	client, err := DialWebsocketReconnect(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+
+	nc := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "someSubscription", 2, 0)
+	if err != nil {
+		t.Fatalf("can't subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	receive := func(want int) {
+		select {
+		case have := <-nc:
+			if have != want {
+				t.Fatalf("notification mismatch: have %d, want %d", have, want)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for notification %d", want)
+		}
+	}
+	receive(0)
+	receive(1)
+
+	// Drop the connection and wait for the subscription to be restored
+	ln.drop()
+	select {
+	case <-sub.Resubscribed():
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for resubscription")
+	}
+	receive(0)
+	receive(1)
+
+	// Closing the client should terminate the subscription cleanly
+	client.Close()
+	select {
+	case err := <-sub.Err():
+		if err != nil {
+			t.Fatalf("subscription error after close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not terminated after close")
+	}
+}
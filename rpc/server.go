@@ -18,17 +18,22 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"io"
 	"sync/atomic"
 
 	mapset "github.com/deckarep/golang-set"
 
 	"github.com/core-coin/go-core/v2/log"
+	"github.com/core-coin/go-core/v2/metrics"
 )
 
 const MetadataApi = "rpc"
 const EngineApi = "engine"
 
+// errServerShuttingDown is returned for calls arriving while the server drains.
+var errServerShuttingDown = errors.New("server is shutting down")
+
 // CodecOption specifies which type of messages a codec supports.
 //
 // Deprecated: this option is no longer honored by Server.
@@ -68,6 +73,31 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// RegisterVersionedName registers receiver as the given version of the name
+// namespace, allowing several incompatible versions of an API to be served side
+// by side. Clients select a version by qualifying the namespace with it, as in
+// "name@2.0_method" or a "name@2.0" subscription namespace. The unqualified
+// namespace is served by the first version registered, so that existing clients
+// keep working when a new version is added, unless a service is registered
+// under it with RegisterName.
+//
+// The versions available are listed by the rpc_modules method.
+func (s *Server) RegisterVersionedName(name, version string, receiver interface{}) error {
+	return s.services.registerVersionedName(name, version, receiver)
+}
+
+// EnableMethodMetrics turns on the collection of per-method metrics into the
+// given registry, or into the default one if nil. For every served method, the
+// number of calls and errors are counted under rpc/methods/<method>/calls and
+// rpc/methods/<method>/errors, while its latency is tracked in the histogram
+// rpc/methods/<method>/latency.
+func (s *Server) EnableMethodMetrics(r metrics.Registry) {
+	if r == nil {
+		r = metrics.DefaultRegistry
+	}
+	s.services.setMethodMetrics(r)
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -123,11 +153,38 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 func (s *Server) Stop() {
 	if atomic.CompareAndSwapInt32(&s.run, 1, 0) {
 		log.Debug("RPC server shutting down")
-		s.codecs.Each(func(c interface{}) bool {
-			c.(ServerCodec).close()
-			return true
-		})
+		s.closeCodecs()
+	}
+}
+
+// Shutdown gracefully stops the server. It stops accepting new connections and
+// calls, then waits for the calls in flight and the active subscriptions to finish
+// before closing all codecs. If ctx expires first, the remaining calls and
+// subscriptions are canceled by closing the codecs and the context error is
+// returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&s.run, 1, 0) {
+		return nil
+	}
+	log.Debug("RPC server draining")
+
+	var err error
+	select {
+	case <-s.services.drain():
+	case <-ctx.Done():
+		err = ctx.Err()
 	}
+	s.closeCodecs()
+	return err
+}
+
+// closeCodecs closes all the codecs being served, which cancels their pending
+// requests and subscriptions.
+func (s *Server) closeCodecs() {
+	s.codecs.Each(func(c interface{}) bool {
+		c.(ServerCodec).close()
+		return true
+	})
 }
 
 // RPCService gives meta information about the server.
@@ -138,14 +195,7 @@ type RPCService struct {
 
 // Modules returns the list of RPC services with their version number
 func (s *RPCService) Modules() map[string]string {
-	s.server.services.mu.Lock()
-	defer s.server.services.mu.Unlock()
-
-	modules := make(map[string]string)
-	for name := range s.server.services.services {
-		modules[name] = "1.0"
-	}
-	return modules
+	return s.server.services.modules()
 }
 
 // PeerInfo contains information about the remote end of the network connection.
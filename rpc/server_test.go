@@ -19,6 +19,7 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"net"
@@ -26,6 +27,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/core-coin/go-core/v2/metrics"
 )
 
 func TestServerRegisterName(t *testing.T) {
@@ -150,3 +153,217 @@ func TestServerShortLivedConn(t *testing.T) {
 		}
 	}
 }
+
+// Tests that per-method metrics are only collected once enabled, counting the
+// calls and errors of every served method.
+func TestServerMethodMetrics(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Calls are not tracked while method metrics are disabled
+	registry := metrics.NewRegistry()
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if m := registry.Get("rpc/methods/test_noArgsRets/calls"); m != nil {
+		t.Fatalf("metrics collected while disabled: %v", m)
+	}
+	// Enable method metrics and ensure successful and failed calls are counted
+	server.EnableMethodMetrics(registry)
+	for i := 0; i < 3; i++ {
+		if err := client.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+	}
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected call to fail")
+	}
+	tests := []struct {
+		name string
+		want int64
+	}{
+		{"rpc/methods/test_noArgsRets/calls", 3},
+		{"rpc/methods/test_noArgsRets/errors", 0},
+		{"rpc/methods/test_returnError/calls", 1},
+		{"rpc/methods/test_returnError/errors", 1},
+	}
+	for _, tt := range tests {
+		var have int64
+		if counter, ok := registry.Get(tt.name).(metrics.Counter); ok {
+			have = counter.Count()
+		}
+		if have != tt.want {
+			t.Errorf("%s mismatch: have %d, want %d", tt.name, have, tt.want)
+		}
+	}
+	if _, ok := registry.Get("rpc/methods/test_noArgsRets/latency").(metrics.Histogram); !ok {
+		t.Errorf("latency histogram missing")
+	}
+}
+
+// waitServing waits until the server is serving at least n calls or subscriptions.
+func waitServing(t *testing.T, server *Server, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		server.services.mu.Lock()
+		active := server.services.active
+		server.services.mu.Unlock()
+		if active >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server not serving %d calls", n)
+}
+
+// Tests that shutting down the server waits for the calls in flight to finish,
+// while rejecting new ones.
+func TestServerShutdown(t *testing.T) {
+	server := newTestServer()
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Start a slow call and wait until the server is processing it
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Call(nil, "test_sleep", 300*time.Millisecond)
+	}()
+	waitServing(t, server, 1)
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(context.Background())
+	}()
+	// New calls must be rejected while draining
+	for server.services.accepting() {
+		time.Sleep(time.Millisecond)
+	}
+	if err := client.Call(nil, "test_noArgsRets"); err == nil || err.Error() != errServerShuttingDown.Error() {
+		t.Fatalf("call during shutdown error mismatch: have %v, want %v", err, errServerShuttingDown)
+	}
+	// The shutdown must wait for the slow call, which must not be interrupted
+	select {
+	case err := <-shutdown:
+		if err != nil {
+			t.Fatalf("shutdown failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown timed out")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("call in flight failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("call in flight not finished after shutdown")
+	}
+}
+
+// Tests that shutting down the server gives up on the calls in flight once the
+// context expires, canceling them.
+func TestServerShutdownTimeout(t *testing.T) {
+	server := newTestServer()
+	client := DialInProc(server)
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Call(nil, "test_block")
+	}()
+	waitServing(t, server, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("shutdown error mismatch: have %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("canceled call succeeded")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("call in flight not canceled")
+	}
+}
+
+type versionedService struct{ version string }
+
+func (s *versionedService) Version() string { return s.version }
+
+type versionedServiceV2 struct{ versionedService }
+
+func (s *versionedServiceV2) Added() bool { return true }
+
+func TestServerRegisterVersionedName(t *testing.T) {
+	server := NewServer()
+	defer server.Stop()
+
+	if err := server.RegisterVersionedName("ver", "1.0", &versionedService{"1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterVersionedName("ver", "2.0", &versionedServiceV2{versionedService{"2.0"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterVersionedName("nftest", "2.0", new(notificationTestService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterVersionedName("ver", "", &versionedService{}); err == nil {
+		t.Error("empty version accepted")
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Calls are routed to the selected version, or the first one if unqualified
+	for method, want := range map[string]string{
+		"ver_version":     "1.0",
+		"ver@1.0_version": "1.0",
+		"ver@2.0_version": "2.0",
+	} {
+		var have string
+		if err := client.Call(&have, method); err != nil {
+			t.Fatalf("%s failed: %v", method, err)
+		}
+		if have != want {
+			t.Errorf("%s: have version %s, want %s", method, have, want)
+		}
+	}
+	var added bool
+	if err := client.Call(&added, "ver@2.0_added"); err != nil || !added {
+		t.Errorf("ver@2.0_added: have %v, %v", added, err)
+	}
+	for _, method := range []string{"ver_added", "ver@1.0_added", "ver@3.0_version"} {
+		if err := client.Call(nil, method); err == nil {
+			t.Errorf("%s: expected method not found error", method)
+		}
+	}
+	// Subscriptions are routed as well
+	sub, err := client.Subscribe(context.Background(), "nftest@2.0", make(chan int), "someSubscription", 1, 0)
+	if err != nil {
+		t.Fatalf("versioned subscription failed: %v", err)
+	}
+	sub.Unsubscribe()
+
+	// The versions are listed in the modules
+	var modules map[string]string
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatal(err)
+	}
+	for name, version := range map[string]string{"ver": "1.0", "ver@1.0": "1.0", "ver@2.0": "2.0", "nftest": "2.0", "rpc": "1.0"} {
+		if modules[name] != version {
+			t.Errorf("module %s: have version %q, want %q", name, modules[name], version)
+		}
+	}
+	// A plain service takes over the unqualified namespace
+	if err := server.RegisterName("ver", &versionedService{"plain"}); err != nil {
+		t.Fatal(err)
+	}
+	var have string
+	if err := client.Call(&have, "ver_version"); err != nil || have != "plain" {
+		t.Errorf("ver_version after plain registration: have %q, %v", have, err)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"unicode"
 
 	"github.com/core-coin/go-core/v2/log"
+	"github.com/core-coin/go-core/v2/metrics"
 )
 
 var (
@@ -36,9 +37,19 @@ var (
 	stringType       = reflect.TypeOf("")
 )
 
+// versionSeparator separates a namespace from the version of the service in the
+// qualified namespace of a versioned service, e.g. "ns@2.0_method".
+const versionSeparator = "@"
+
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	defaults map[string]string // Default version of the versioned namespaces
+	metrics  metrics.Registry  // Registry collecting per-method metrics, nil if disabled
+
+	draining bool          // Whether new calls are rejected as the server is shutting down
+	active   int           // Number of calls and subscriptions currently being served
+	idle     chan struct{} // Closed when nothing is served any more while draining
 }
 
 // service represents a registered object.
@@ -92,6 +103,43 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	return nil
 }
 
+// registerVersionedName registers the methods of rcvr as the given version of
+// the namespace. The first version registered becomes the default one, serving
+// the unqualified namespace unless a plain service is registered under it.
+func (r *serviceRegistry) registerVersionedName(name, version string, rcvr interface{}) error {
+	if strings.Contains(name, versionSeparator) || strings.Contains(name, serviceMethodSeparator) {
+		return fmt.Errorf("invalid service name %q", name)
+	}
+	if version == "" || strings.Contains(version, serviceMethodSeparator) {
+		return fmt.Errorf("invalid version %q for service %s", version, name)
+	}
+	if err := r.registerName(name+versionSeparator+version, rcvr); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.defaults == nil {
+		r.defaults = make(map[string]string)
+	}
+	if _, ok := r.defaults[name]; !ok {
+		r.defaults[name] = version
+	}
+	return nil
+}
+
+// service returns the service registered under the given namespace, resolving
+// unqualified versioned namespaces to their default version. It has to be called
+// with the lock held.
+func (r *serviceRegistry) service(namespace string) service {
+	if svc, ok := r.services[namespace]; ok {
+		return svc
+	}
+	if version, ok := r.defaults[namespace]; ok {
+		return r.services[namespace+versionSeparator+version]
+	}
+	return service{}
+}
+
 // callback returns the callback corresponding to the given RPC method name.
 func (r *serviceRegistry) callback(method string) *callback {
 	elem := strings.SplitN(method, serviceMethodSeparator, 2)
@@ -100,14 +148,96 @@ func (r *serviceRegistry) callback(method string) *callback {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.services[elem[0]].callbacks[elem[1]]
+	return r.service(elem[0]).callbacks[elem[1]]
 }
 
 // subscription returns a subscription callback in the given service.
 func (r *serviceRegistry) subscription(service, name string) *callback {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.services[service].subscriptions[name]
+	return r.service(service).subscriptions[name]
+}
+
+// modules returns the available namespaces along with their versions. Versioned
+// services are listed under their qualified namespace, and under the plain one
+// for their default version.
+func (r *serviceRegistry) modules() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modules := make(map[string]string)
+	for name := range r.services {
+		if i := strings.Index(name, versionSeparator); i >= 0 {
+			modules[name] = name[i+len(versionSeparator):]
+		} else {
+			modules[name] = "1.0"
+		}
+	}
+	for name, version := range r.defaults {
+		if _, ok := r.services[name]; !ok {
+			modules[name] = version
+		}
+	}
+	return modules
+}
+
+// setMethodMetrics sets the registry collecting per-method metrics.
+func (r *serviceRegistry) setMethodMetrics(registry metrics.Registry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = registry
+}
+
+// methodMetrics returns the registry collecting per-method metrics, or nil if
+// their collection is disabled.
+func (r *serviceRegistry) methodMetrics() metrics.Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// beginServe starts tracking a call or subscription being served.
+func (r *serviceRegistry) beginServe() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active++
+}
+
+// accepting returns whether new calls can be served, i.e. the registry is not
+// draining.
+func (r *serviceRegistry) accepting() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.draining
+}
+
+// endServe stops tracking a call or subscription, signalling the drainer if it
+// was the last one being served.
+func (r *serviceRegistry) endServe() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active--
+	if r.active == 0 && r.idle != nil {
+		close(r.idle)
+		r.idle = nil
+	}
+}
+
+// drain stops accepting new calls and returns a channel which is closed once all
+// the calls and subscriptions being served are finished.
+func (r *serviceRegistry) drain() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.draining = true
+	idle := make(chan struct{})
+	if r.active == 0 {
+		close(idle)
+	} else {
+		r.idle = idle
+	}
+	return idle
 }
 
 // suitableCallbacks iterates over the methods of the given type. It determines if a method
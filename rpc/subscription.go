@@ -36,8 +36,48 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionClosed is returned when notifying a buffered subscription which
+	// is not delivering notifications anymore
+	ErrSubscriptionClosed = errors.New("subscription closed")
 )
 
+// OverflowPolicy defines what happens when a notification is sent to a buffered
+// subscription whose queue is full.
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = iota // Notify waits until the client catches up
+	OverflowDropOldest                       // The oldest queued notification is discarded
+	OverflowClose                            // The subscription is terminated with ErrSubscriptionQueueOverflow
+)
+
+// SubscriptionOption is a configuration option for server side subscriptions.
+type SubscriptionOption interface {
+	applySubscriptionOption(*subscriptionConfig)
+}
+
+type subscriptionConfig struct {
+	bufferSize int
+	overflow   OverflowPolicy
+}
+
+type subscriptionOptionFunc func(*subscriptionConfig)
+
+func (fn subscriptionOptionFunc) applySubscriptionOption(cfg *subscriptionConfig) {
+	fn(cfg)
+}
+
+// WithNotificationBuffer configures the subscription to queue up to size notifications
+// and deliver them to the client from a dedicated goroutine, so that Notify doesn't wait
+// for the connection. The policy decides what happens when a slow client lets the queue
+// fill up.
+func WithNotificationBuffer(size int, policy OverflowPolicy) SubscriptionOption {
+	return subscriptionOptionFunc(func(cfg *subscriptionConfig) {
+		cfg.bufferSize = size
+		cfg.overflow = policy
+	})
+}
+
 var globalGen = randomIDGenerator()
 
 // ID defines a pseudo random number that is used to identify RPC subscriptions.
@@ -99,13 +139,23 @@ type Notifier struct {
 	buffer       []json.RawMessage
 	callReturned bool
 	activated    bool
+
+	config     subscriptionConfig
+	queue      []json.RawMessage // notifications waiting for delivery (buffered subscriptions)
+	space      *sync.Cond        // signalled when the queue shrinks or delivery stops
+	wake       chan struct{}     // signals the delivery loop about queued notifications
+	stopped    bool              // whether the delivery loop exited
+	overflowed bool              // whether the queue overflowed with the OverflowClose policy
 }
 
 // CreateSubscription returns a new subscription that is coupled to the
 // RPC connection. By default subscriptions are inactive and notifications
 // are dropped until the subscription is marked as active. This is done
 // by the RPC server after the subscription ID is send to the client.
-func (n *Notifier) CreateSubscription() *Subscription {
+//
+// Without options notifications are written to the connection by Notify
+// itself, see WithNotificationBuffer for decoupling the two.
+func (n *Notifier) CreateSubscription(options ...SubscriptionOption) *Subscription {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
@@ -114,7 +164,14 @@ func (n *Notifier) CreateSubscription() *Subscription {
 	} else if n.callReturned {
 		panic("can't create subscription after subscribe call has returned")
 	}
-	n.sub = &Subscription{ID: n.h.idgen(), namespace: n.namespace, err: make(chan error, 1)}
+	for _, opt := range options {
+		opt.applySubscriptionOption(&n.config)
+	}
+	if n.config.bufferSize > 0 {
+		n.space = sync.NewCond(&n.mu)
+		n.wake = make(chan struct{}, 1)
+	}
+	n.sub = &Subscription{ID: n.h.idgen(), namespace: n.namespace, err: make(chan error, 1), quit: make(chan struct{})}
 	return n.sub
 }
 
@@ -134,6 +191,9 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	} else if n.sub.ID != id {
 		panic("Notify with wrong ID")
 	}
+	if n.config.bufferSize > 0 {
+		return n.enqueue(enc)
+	}
 	if n.activated {
 		return n.send(n.sub, enc)
 	}
@@ -163,6 +223,16 @@ func (n *Notifier) activate() error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
+	if n.config.bufferSize > 0 && n.sub != nil {
+		n.activated = true
+		if n.overflowed {
+			go n.h.dropSubscription(n.sub.ID, ErrSubscriptionQueueOverflow)
+			return nil
+		}
+		go n.deliver()
+		n.signal()
+		return nil
+	}
 	for _, data := range n.buffer {
 		if err := n.send(n.sub, data); err != nil {
 			return err
@@ -182,12 +252,101 @@ func (n *Notifier) send(sub *Subscription, data json.RawMessage) error {
 	})
 }
 
+// enqueue adds a notification to the queue of a buffered subscription, applying
+// the overflow policy if the queue is full. The lock must be held.
+func (n *Notifier) enqueue(data json.RawMessage) error {
+	if n.overflowed {
+		return ErrSubscriptionQueueOverflow
+	}
+	if n.stopped {
+		return ErrSubscriptionClosed
+	}
+	if len(n.queue) >= n.config.bufferSize {
+		switch n.config.overflow {
+		case OverflowDropOldest:
+			n.queue = n.queue[1:]
+
+		case OverflowClose:
+			n.overflowed = true
+			n.queue = nil
+			if n.activated {
+				go n.h.dropSubscription(n.sub.ID, ErrSubscriptionQueueOverflow)
+			}
+			return ErrSubscriptionQueueOverflow
+
+		default:
+			// Notifications sent before activation can't wait for the delivery
+			// loop, the subscribe call itself might be the one notifying.
+			for n.activated && !n.stopped && len(n.queue) >= n.config.bufferSize {
+				n.space.Wait()
+			}
+			if n.stopped {
+				return ErrSubscriptionClosed
+			}
+		}
+	}
+	n.queue = append(n.queue, data)
+	if n.activated {
+		n.signal()
+	}
+	return nil
+}
+
+// signal wakes up the delivery loop if it's idle.
+func (n *Notifier) signal() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver is the delivery loop of buffered subscriptions, writing the queued
+// notifications to the connection until the subscription or the connection ends.
+func (n *Notifier) deliver() {
+	defer func() {
+		n.mu.Lock()
+		n.stopped = true
+		n.queue = nil
+		n.space.Broadcast()
+		n.mu.Unlock()
+	}()
+	for {
+		select {
+		case <-n.wake:
+		case <-n.sub.quit:
+			return
+		case <-n.h.conn.closed():
+			return
+		}
+		for {
+			n.mu.Lock()
+			if n.overflowed {
+				n.mu.Unlock()
+				return
+			}
+			if len(n.queue) == 0 {
+				n.mu.Unlock()
+				break
+			}
+			data := n.queue[0]
+			n.queue = n.queue[1:]
+			n.space.Broadcast()
+			n.mu.Unlock()
+
+			if err := n.send(n.sub, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
 // A Subscription is created by a notifier and tied to that notifier. The client can use
 // this subscription to wait for an unsubscribe request for the client, see Err().
 type Subscription struct {
 	ID        ID
 	namespace string
-	err       chan error // closed on unsubscribe
+	err       chan error    // closed on unsubscribe
+	quit      chan struct{} // closed when the subscription ends
 }
 
 // Err returns a channel that is closed when the client send an unsubscribe request.
@@ -17,6 +17,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -171,6 +172,136 @@ func TestServerUnsubscribe(t *testing.T) {
 	}
 }
 
+// floodTestService sends a burst of notifications through a buffered subscription
+// once the test allows it to.
+type floodTestService struct {
+	start chan struct{} // closed by the test to start flooding
+	done  chan error    // result of the notification burst
+	ended chan error    // error delivered on the subscription's error channel
+}
+
+func (s *floodTestService) Flood(ctx context.Context, count, size int, policy OverflowPolicy) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription(WithNotificationBuffer(size, policy))
+	go func() {
+		<-s.start
+		var err error
+		for i := 0; i < count && err == nil; i++ {
+			err = notifier.Notify(sub.ID, i)
+		}
+		s.done <- err
+		s.ended <- <-sub.Err()
+	}()
+	return sub, nil
+}
+
+// This test checks the overflow policies of buffered subscriptions against a client
+// which doesn't read its notifications.
+func TestSubscriptionOverflowPolicy(t *testing.T) {
+	const count, size = 20, 4
+
+	subscribe := func(t *testing.T, policy OverflowPolicy) (*json.Decoder, *floodTestService, func()) {
+		p1, p2 := net.Pipe()
+		server := NewServer()
+		service := &floodTestService{start: make(chan struct{}), done: make(chan error, 1), ended: make(chan error, 1)}
+		server.RegisterName("flood", service)
+		go server.ServeCodec(NewCodec(p1), 0)
+
+		p2.SetDeadline(time.Now().Add(10 * time.Second))
+		fmt.Fprintf(p2, `{"jsonrpc":"2.0","id":1,"method":"flood_subscribe","params":["flood",%d,%d,%d]}`, count, size, policy)
+
+		in := json.NewDecoder(p2)
+		if resp, _, err := readAndValidateMessage(in); err != nil || resp == nil {
+			t.Fatalf("subscription failed: %v", err)
+		}
+		close(service.start)
+		return in, service, func() { p2.Close(); server.Stop() }
+	}
+	readValue := func(t *testing.T, in *json.Decoder) int {
+		_, notification, err := readAndValidateMessage(in)
+		if err != nil || notification == nil {
+			t.Fatalf("expected notification, got error %v", err)
+		}
+		var val int
+		if err := json.Unmarshal(notification.Result, &val); err != nil {
+			t.Fatalf("invalid notification: %v", err)
+		}
+		return val
+	}
+
+	t.Run("block", func(t *testing.T) {
+		in, service, stop := subscribe(t, OverflowBlock)
+		defer stop()
+
+		// The burst can't complete while the client doesn't read.
+		select {
+		case err := <-service.done:
+			t.Fatalf("notifications completed without client reading: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		// Reading unblocks it and all notifications arrive in order.
+		for i := 0; i < count; i++ {
+			if val := readValue(t, in); val != i {
+				t.Fatalf("value mismatch: got %d, want %d", val, i)
+			}
+		}
+		if err := <-service.done; err != nil {
+			t.Fatalf("unexpected notify error: %v", err)
+		}
+	})
+	t.Run("drop-oldest", func(t *testing.T) {
+		in, service, stop := subscribe(t, OverflowDropOldest)
+		defer stop()
+
+		// The burst completes even though the client doesn't read.
+		select {
+		case err := <-service.done:
+			if err != nil {
+				t.Fatalf("unexpected notify error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("notifications blocked on slow client")
+		}
+		// Only the newest notifications (and possibly the one being written
+		// when the queue overflowed) are delivered.
+		var vals []int
+		for len(vals) == 0 || vals[len(vals)-1] != count-1 {
+			val := readValue(t, in)
+			if len(vals) > 0 && val <= vals[len(vals)-1] {
+				t.Fatalf("notifications out of order: %v, %d", vals, val)
+			}
+			vals = append(vals, val)
+		}
+		if len(vals) > size+1 {
+			t.Fatalf("too many notifications delivered: %v", vals)
+		}
+	})
+	t.Run("close", func(t *testing.T) {
+		_, service, stop := subscribe(t, OverflowClose)
+		defer stop()
+
+		select {
+		case err := <-service.done:
+			if err != ErrSubscriptionQueueOverflow {
+				t.Fatalf("wrong notify error: got %v, want %v", err, ErrSubscriptionQueueOverflow)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("notifications blocked on slow client")
+		}
+		select {
+		case err := <-service.ended:
+			if err != ErrSubscriptionQueueOverflow {
+				t.Fatalf("wrong subscription error: got %v, want %v", err, ErrSubscriptionQueueOverflow)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription not terminated")
+		}
+	})
+}
+
 type subConfirmation struct {
 	reqid int
 	subid ID
@@ -49,7 +49,7 @@ func (tt *TransactionTest) Run(config *params.ChainConfig) error {
 			return nil, nil, err
 		}
 		// Intrinsic energy
-		requiredEnergy, err := core.IntrinsicEnergy(tx.Data(), tx.To() == nil)
+		requiredEnergy, err := core.IntrinsicEnergyWithAccessList(tx.Data(), tx.AccessList(), tx.To() == nil, config)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/VictoriaMetrics/fastcache"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/core-coin/go-core/v2/common"
 	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/crypto"
 	"github.com/core-coin/go-core/v2/log"
 	"github.com/core-coin/go-core/v2/metrics"
 	"github.com/core-coin/go-core/v2/rlp"
@@ -69,6 +71,9 @@ var (
 // behind this split design is to provide read access to RPC handlers and sync
 // servers even while the trie is executing expensive garbage collection.
 type Database struct {
+	cleanHits   uint64 // Number of node lookups served by the clean cache (atomic, 64-bit aligned)
+	cleanMisses uint64 // Number of node lookups loaded from disk into the clean cache (atomic)
+
 	diskdb xcbdb.KeyValueStore // Persistent storage for matured trie nodes
 
 	cleans  *fastcache.Cache            // GC friendly memory cache of clean node RLPs
@@ -77,6 +82,8 @@ type Database struct {
 	newest  common.Hash                 // Newest tracked node, flush-list tail
 
 	preimages map[common.Hash][]byte // Preimages of nodes from the secure trie
+	hashers   *sync.Pool             // Pool of hashers used to hash the trie nodes
+	journaled bool                   // Whether commits are journaled to survive crashes
 
 	gctime  time.Duration      // Time spent on garbage collection since last commit
 	gcnodes uint64             // Nodes garbage collected since last commit
@@ -279,6 +286,18 @@ type Config struct {
 	Cache     int    // Memory allowance (MB) to use for caching trie nodes in memory
 	Journal   string // Journal of clean cache to survive node restarts
 	Preimages bool   // Flag whether the preimage of trie key is recorded
+
+	// CommitJournal makes commits atomic across crashes: the node set of a commit
+	// is journaled in a single write before being flushed, and an interrupted
+	// commit is finished when the database is opened again. As the nodes are
+	// written twice, it's off by default.
+	CommitJournal bool
+
+	// Hasher creates the hash function used to hash the trie nodes (nil = SHA3-256).
+	// It's meant for experimentation only: tries hashed with anything else than the
+	// default are incompatible with the rest of the system (e.g. the empty root,
+	// proofs, the state sync).
+	Hasher func() crypto.SHA3State
 }
 
 // NewDatabase creates a new trie database to store ephemeral trie content before
@@ -288,6 +307,14 @@ func NewDatabase(diskdb xcbdb.KeyValueStore) *Database {
 	return NewDatabaseWithConfig(diskdb, nil)
 }
 
+// NewDatabaseWithCache creates a new trie database to store ephemeral trie content
+// before its written out to disk or garbage collected. It also acts as a read cache
+// for nodes loaded from disk, using at most cache megabytes of memory. Preimages
+// are recorded the same way as by NewDatabase.
+func NewDatabaseWithCache(diskdb xcbdb.KeyValueStore, cache int) *Database {
+	return NewDatabaseWithConfig(diskdb, &Config{Cache: cache, Preimages: true})
+}
+
 // NewDatabaseWithConfig creates a new trie database to store ephemeral trie content
 // before its written out to disk or garbage collected. It also acts as a read cache
 // for nodes loaded from disk.
@@ -306,10 +333,20 @@ func NewDatabaseWithConfig(diskdb xcbdb.KeyValueStore, config *Config) *Database
 		dirties: map[common.Hash]*cachedNode{{}: {
 			children: make(map[common.Hash]uint16),
 		}},
+		hashers: hasherPool,
+	}
+	if config != nil && config.Hasher != nil {
+		db.hashers = newHasherPool(config.Hasher)
 	}
 	if config == nil || config.Preimages { // TODO(raisty): Flip to default off in the future
 		db.preimages = make(map[common.Hash][]byte)
 	}
+	if config != nil && config.CommitJournal {
+		db.journaled = true
+		if _, err := db.RecoverJournal(); err != nil {
+			log.Error("Failed to recover trie commit journal", "err", err)
+		}
+	}
 	return db
 }
 
@@ -375,6 +412,7 @@ func (db *Database) node(hash common.Hash) node {
 	// Retrieve the node from the clean cache if available
 	if db.cleans != nil {
 		if enc := db.cleans.Get(nil, hash[:]); enc != nil {
+			atomic.AddUint64(&db.cleanHits, 1)
 			memcacheCleanHitMeter.Mark(1)
 			memcacheCleanReadMeter.Mark(int64(len(enc)))
 			return mustDecodeNode(hash[:], enc)
@@ -399,6 +437,7 @@ func (db *Database) node(hash common.Hash) node {
 	}
 	if db.cleans != nil {
 		db.cleans.Set(hash[:], enc)
+		atomic.AddUint64(&db.cleanMisses, 1)
 		memcacheCleanMissMeter.Mark(1)
 		memcacheCleanWriteMeter.Mark(int64(len(enc)))
 	}
@@ -415,6 +454,7 @@ func (db *Database) Node(hash common.Hash) ([]byte, error) {
 	// Retrieve the node from the clean cache if available
 	if db.cleans != nil {
 		if enc := db.cleans.Get(nil, hash[:]); enc != nil {
+			atomic.AddUint64(&db.cleanHits, 1)
 			memcacheCleanHitMeter.Mark(1)
 			memcacheCleanReadMeter.Mark(int64(len(enc)))
 			return enc, nil
@@ -437,6 +477,7 @@ func (db *Database) Node(hash common.Hash) ([]byte, error) {
 	if len(enc) != 0 {
 		if db.cleans != nil {
 			db.cleans.Set(hash[:], enc)
+			atomic.AddUint64(&db.cleanMisses, 1)
 			memcacheCleanMissMeter.Mark(1)
 			memcacheCleanWriteMeter.Mark(int64(len(enc)))
 		}
@@ -718,6 +759,17 @@ func (db *Database) Commit(node common.Hash, report bool, callback func(common.H
 		}
 		batch.Reset()
 	}
+	// Journal the trie in a single write, so that an interrupted commit can be
+	// finished on restart instead of leaving a partial trie on disk
+	if db.journaled {
+		db.lock.RLock()
+		journal, err := rlp.EncodeToBytes(&commitJournal{Root: node, Nodes: db.journalNodes(node, nil)})
+		db.lock.RUnlock()
+		if err != nil {
+			return err
+		}
+		rawdb.WriteTrieCommitJournal(db.diskdb, journal)
+	}
 	// Move the trie itself into the batch, flushing if enough data is accumulated
 	nodes, storage := len(db.dirties), db.dirtiesSize
 
@@ -731,6 +783,9 @@ func (db *Database) Commit(node common.Hash, report bool, callback func(common.H
 		log.Error("Failed to write trie to disk", "err", err)
 		return err
 	}
+	if db.journaled {
+		rawdb.DeleteTrieCommitJournal(db.diskdb)
+	}
 	// Uncache any leftovers in the last batch
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -793,6 +848,65 @@ func (db *Database) commit(hash common.Hash, batch xcbdb.Batch, uncacher *cleane
 	return nil
 }
 
+// commitJournal is the set of trie nodes written by a commit.
+type commitJournal struct {
+	Root  common.Hash
+	Nodes []journalNode
+}
+
+// journalNode is a trie node in the commit journal.
+type journalNode struct {
+	Hash common.Hash
+	Blob []byte
+}
+
+// journalNodes appends the dirty nodes of the trie rooted at hash to the list,
+// children first like commit writes them.
+func (db *Database) journalNodes(hash common.Hash, nodes []journalNode) []journalNode {
+	node, ok := db.dirties[hash]
+	if !ok {
+		return nodes
+	}
+	node.forChilds(func(child common.Hash) {
+		nodes = db.journalNodes(child, nodes)
+	})
+	return append(nodes, journalNode{Hash: hash, Blob: node.rlp()})
+}
+
+// RecoverJournal finishes a journaled commit that was interrupted, by writing
+// out the whole node set of it. It returns the root of the recovered trie, or
+// the zero hash if there was no commit to finish.
+//
+// It's run when the database is opened with CommitJournal enabled, and must be
+// called before the tries of the disk database are accessed.
+func (db *Database) RecoverJournal() (common.Hash, error) {
+	blob := rawdb.ReadTrieCommitJournal(db.diskdb)
+	if len(blob) == 0 {
+		return common.Hash{}, nil
+	}
+	var journal commitJournal
+	if err := rlp.DecodeBytes(blob, &journal); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid trie commit journal: %v", err)
+	}
+	batch := db.diskdb.NewBatch()
+	for _, node := range journal.Nodes {
+		rawdb.WriteTrieNode(batch, node.Hash, node.Blob)
+		if batch.ValueSize() >= xcbdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return common.Hash{}, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return common.Hash{}, err
+	}
+	rawdb.DeleteTrieCommitJournal(db.diskdb)
+
+	log.Info("Recovered interrupted trie commit", "root", journal.Root, "nodes", len(journal.Nodes))
+	return journal.Root, nil
+}
+
 // cleaner is a database batch replayer that takes a batch of write operations
 // and cleans up the trie database from anything written to disk.
 type cleaner struct {
@@ -856,6 +970,47 @@ func (db *Database) Size() (common.StorageSize, common.StorageSize) {
 	return db.dirtiesSize + db.childrenSize + metadataSize - metarootRefs, db.preimagesSize
 }
 
+// CleanCacheStats returns the number of node lookups served from the clean cache
+// and the number of nodes which had to be loaded from disk into it.
+func (db *Database) CleanCacheStats() (hits uint64, misses uint64) {
+	return atomic.LoadUint64(&db.cleanHits), atomic.LoadUint64(&db.cleanMisses)
+}
+
+// NodeStats iterates over all the nodes reachable from the given root and
+// returns their count and total encoded size. Nodes embedded into their parents
+// are accounted for as part of the parent. The nodes are resolved one by one
+// while iterating, so arbitrarily large tries can be measured.
+func (db *Database) NodeStats(root common.Hash) (uint64, common.StorageSize, error) {
+	if root == emptyRoot || root == (common.Hash{}) {
+		return 0, 0, nil
+	}
+	tr, err := New(root, db)
+	if err != nil {
+		return 0, 0, err
+	}
+	var (
+		nodes uint64
+		size  common.StorageSize
+	)
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
+			continue
+		}
+		blob, err := db.Node(hash)
+		if err != nil {
+			return 0, 0, err
+		}
+		nodes++
+		size += common.StorageSize(len(blob))
+	}
+	if err := it.Error(); err != nil {
+		return 0, 0, err
+	}
+	return nodes, size, nil
+}
+
 // saveCache saves clean state cache to given directory path
 // using specified CPU cores.
 func (db *Database) saveCache(dir string, threads int) error {
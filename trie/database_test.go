@@ -17,11 +17,20 @@
 package trie
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"testing"
 
+	"golang.org/x/crypto/sha3"
+
+	"github.com/core-coin/go-core/v2/xcbdb"
 	"github.com/core-coin/go-core/v2/xcbdb/memorydb"
 
 	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/core/rawdb"
+	"github.com/core-coin/go-core/v2/crypto"
 )
 
 // Tests that the trie database returns a missing trie node error if attempting
@@ -32,3 +41,335 @@ func TestDatabaseMetarootFetch(t *testing.T) {
 		t.Fatalf("metaroot retrieval succeeded")
 	}
 }
+
+// Tests that the node statistics of a trie account for all the nodes reachable
+// from the root, both from the dirty cache and from disk.
+func TestDatabaseNodeStats(t *testing.T) {
+	diskdb := memorydb.New()
+	db := NewDatabase(diskdb)
+
+	if nodes, size, err := db.NodeStats(emptyRoot); err != nil || nodes != 0 || size != 0 {
+		t.Fatalf("empty trie stats mismatch: have %d nodes, %v size (%v), want none", nodes, size, err)
+	}
+	trie, _ := New(common.Hash{}, db)
+	for _, kv := range []struct{ k, v string }{
+		{"do", "verb"},
+		{"dog", "puppy"},
+		{"doge", "coin"},
+		{"horse", "stallion"},
+		{"somethingveryoddindeedthis is", "myothernodedata"},
+	} {
+		trie.Update([]byte(kv.k), []byte(kv.v))
+	}
+	root, err := trie.Commit(nil)
+	if err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	dirtyNodes, dirtySize, err := db.NodeStats(root)
+	if err != nil {
+		t.Fatalf("failed to retrieve dirty stats: %v", err)
+	}
+	if err := db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit database: %v", err)
+	}
+	// Every node persisted to disk should be accounted for
+	var (
+		diskNodes uint64
+		diskSize  common.StorageSize
+	)
+	it := diskdb.NewIterator(nil, nil)
+	for it.Next() {
+		diskNodes++
+		diskSize += common.StorageSize(len(it.Value()))
+	}
+	it.Release()
+
+	nodes, size, err := NewDatabase(diskdb).NodeStats(root)
+	if err != nil {
+		t.Fatalf("failed to retrieve disk stats: %v", err)
+	}
+	if nodes != diskNodes || size != diskSize {
+		t.Errorf("disk stats mismatch: have %d nodes, %v size, want %d nodes, %v size", nodes, size, diskNodes, diskSize)
+	}
+	if dirtyNodes != diskNodes || dirtySize != diskSize {
+		t.Errorf("dirty stats mismatch: have %d nodes, %v size, want %d nodes, %v size", dirtyNodes, dirtySize, diskNodes, diskSize)
+	}
+	if nodes != 5 {
+		t.Errorf("node count mismatch: have %d, want 5", nodes)
+	}
+}
+
+// Tests that a custom node hasher can be configured, producing different but
+// stable roots, without affecting tries using the default hashing.
+func TestDatabaseCustomHasher(t *testing.T) {
+	keccak := func() crypto.SHA3State { return sha3.NewLegacyKeccak256().(crypto.SHA3State) }
+
+	build := func(db *Database) (common.Hash, *Database) {
+		trie, _ := New(common.Hash{}, db)
+		for i := byte(0); i < 200; i++ {
+			trie.Update([]byte{i, i >> 4}, bytes.Repeat([]byte{i}, int(i%40)+1))
+		}
+		root, err := trie.Commit(nil)
+		if err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		if err := db.Commit(root, false, nil); err != nil {
+			t.Fatalf("failed to commit database: %v", err)
+		}
+		return root, db
+	}
+	defaultRoot, _ := build(NewDatabase(memorydb.New()))
+	customRoot, customDb := build(NewDatabaseWithConfig(memorydb.New(), &Config{Hasher: keccak}))
+	if customRoot == defaultRoot {
+		t.Fatalf("custom hasher produced the default root %x", customRoot)
+	}
+	if root, _ := build(NewDatabaseWithConfig(memorydb.New(), &Config{Hasher: keccak})); root != customRoot {
+		t.Errorf("custom root unstable: have %x, want %x", root, customRoot)
+	}
+	if root, _ := build(NewDatabase(memorydb.New())); root != defaultRoot {
+		t.Errorf("default root changed: have %x, want %x", root, defaultRoot)
+	}
+	// The nodes should be stored and resolvable by their custom hashes
+	if _, err := customDb.Node(customRoot); err != nil {
+		t.Fatalf("custom root node missing: %v", err)
+	}
+	trie, err := New(customRoot, NewDatabaseWithConfig(customDb.DiskDB(), &Config{Hasher: keccak}))
+	if err != nil {
+		t.Fatalf("failed to open custom trie: %v", err)
+	}
+	for i := byte(0); i < 200; i++ {
+		if have, want := trie.Get([]byte{i, i >> 4}), bytes.Repeat([]byte{i}, int(i%40)+1); !bytes.Equal(have, want) {
+			t.Fatalf("value %d mismatch: have %x, want %x", i, have, want)
+		}
+	}
+	if trie.Hash() != customRoot {
+		t.Errorf("reopened root mismatch: have %x, want %x", trie.Hash(), customRoot)
+	}
+}
+
+// makeDiskTrie creates a trie with the given number of entries and flushes it
+// to a fresh disk database, returning its root hash and the disk database.
+func makeDiskTrie(entries int) (common.Hash, *memorydb.Database) {
+	diskdb := memorydb.New()
+	db := NewDatabase(diskdb)
+	trie, _ := New(common.Hash{}, db)
+
+	for i := 0; i < entries; i++ {
+		k := make([]byte, 32)
+		binary.BigEndian.PutUint64(k, uint64(i))
+		trie.Update(k, k)
+	}
+	root, _ := trie.Commit(nil)
+	db.Commit(root, false, nil)
+	return root, diskdb
+}
+
+// Tests that nodes loaded from disk are served from the clean cache on subsequent
+// lookups, and that the cache statistics are tracked accordingly.
+func TestDatabaseCleanCache(t *testing.T) {
+	root, diskdb := makeDiskTrie(100)
+	db := NewDatabaseWithCache(diskdb, 1)
+
+	lookup := func() {
+		trie, err := New(root, db)
+		if err != nil {
+			t.Fatalf("failed to open trie: %v", err)
+		}
+		k := make([]byte, 32)
+		for i := 0; i < 100; i++ {
+			binary.BigEndian.PutUint64(k, uint64(i))
+			if v, err := trie.TryGet(k); err != nil || !bytes.Equal(v, k) {
+				t.Fatalf("value mismatch for %x: have %x (%v)", k, v, err)
+			}
+		}
+	}
+	// The first round of lookups must load everything from disk
+	lookup()
+	hits, misses := db.CleanCacheStats()
+	if hits != 0 || misses == 0 {
+		t.Fatalf("cold cache stats mismatch: have %d hits, %d misses", hits, misses)
+	}
+	// The second round must be served from the cache alone
+	lookup()
+	if newHits, newMisses := db.CleanCacheStats(); newHits < misses || newMisses != misses {
+		t.Fatalf("warm cache stats mismatch: have %d hits, %d misses, want >= %d hits, %d misses", newHits, newMisses, misses, misses)
+	}
+	// Without a cache nothing should be tracked
+	nocache := NewDatabase(diskdb)
+	if _, err := New(root, nocache); err != nil {
+		t.Fatalf("failed to open trie: %v", err)
+	}
+	if hits, misses := nocache.CleanCacheStats(); hits != 0 || misses != 0 {
+		t.Fatalf("uncached stats mismatch: have %d hits, %d misses, want none", hits, misses)
+	}
+}
+
+// Tests that a trie database with a clean cache records preimages the same way
+// as one without.
+func TestDatabaseCleanCachePreimages(t *testing.T) {
+	for _, db := range []*Database{NewDatabase(memorydb.New()), NewDatabaseWithCache(memorydb.New(), 1)} {
+		trie, err := NewSecure(common.Hash{}, db)
+		if err != nil {
+			t.Fatalf("failed to create trie: %v", err)
+		}
+		key := []byte("preimage")
+		trie.Update(key, key)
+		if _, err := trie.Commit(nil); err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		if have := db.preimage(crypto.SHA3Hash(key)); !bytes.Equal(have, key) {
+			t.Errorf("preimage mismatch: have %x, want %x", have, key)
+		}
+	}
+}
+
+func BenchmarkDatabaseGetNoCache(b *testing.B) { benchDatabaseGet(b, 0) }
+func BenchmarkDatabaseGetCache(b *testing.B)   { benchDatabaseGet(b, 16) }
+
+// benchDatabaseGet repeatedly retrieves the same keys through freshly opened
+// tries, so that every lookup needs to resolve the nodes from the database.
+func benchDatabaseGet(b *testing.B, cache int) {
+	root, diskdb := makeDiskTrie(benchElemCount)
+	db := NewDatabaseWithCache(diskdb, cache)
+
+	k := make([]byte, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie, _ := New(root, db)
+		binary.BigEndian.PutUint64(k, uint64(i%benchElemCount))
+		trie.TryGet(k)
+	}
+	b.StopTimer()
+
+	if hits, misses := db.CleanCacheStats(); cache > 0 {
+		b.ReportMetric(float64(hits)/float64(hits+misses), "hitrate")
+	} else if hits != 0 || misses != 0 {
+		b.Fatalf("uncached stats mismatch: have %d hits, %d misses", hits, misses)
+	}
+}
+
+var errCrash = errors.New("simulated crash")
+
+// crashingStore is a key-value store whose batches stop writing, as if the
+// process crashed, once the given number of batched writes landed.
+type crashingStore struct {
+	xcbdb.KeyValueStore
+	writes int
+}
+
+func (s *crashingStore) NewBatch() xcbdb.Batch {
+	return &crashingBatch{store: s}
+}
+
+type crashingBatch struct {
+	store *crashingStore
+	keys  [][]byte
+	vals  [][]byte
+	size  int
+}
+
+func (b *crashingBatch) Put(key, value []byte) error {
+	b.keys = append(b.keys, common.CopyBytes(key))
+	b.vals = append(b.vals, common.CopyBytes(value))
+	b.size += len(value)
+	return nil
+}
+
+func (b *crashingBatch) Delete(key []byte) error { panic("not implemented") }
+func (b *crashingBatch) ValueSize() int          { return b.size }
+
+func (b *crashingBatch) Write() error {
+	for i := range b.keys {
+		if b.store.writes == 0 {
+			return errCrash
+		}
+		b.store.writes--
+		b.store.Put(b.keys[i], b.vals[i])
+	}
+	return nil
+}
+
+func (b *crashingBatch) Reset() {
+	b.keys, b.vals, b.size = nil, nil, 0
+}
+
+func (b *crashingBatch) Replay(w xcbdb.KeyValueWriter) error {
+	for i := range b.keys {
+		if err := w.Put(b.keys[i], b.vals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tests that a journaled commit interrupted by a crash is finished when the
+// database is opened again.
+func TestDatabaseCommitJournal(t *testing.T) {
+	diskdb := memorydb.New()
+	db := NewDatabaseWithConfig(&crashingStore{KeyValueStore: diskdb, writes: 5}, &Config{CommitJournal: true})
+
+	trie, _ := New(common.Hash{}, db)
+	for i := 0; i < 100; i++ {
+		trie.Update([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	root, err := trie.Commit(nil)
+	if err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	if err := db.Commit(root, false, nil); err != errCrash {
+		t.Fatalf("commit error mismatch: have %v, want %v", err, errCrash)
+	}
+	// Only part of the trie made it to disk
+	if _, err := New(root, NewDatabase(diskdb)); err == nil {
+		t.Fatalf("interrupted commit persisted the root")
+	}
+	// Reopen the database and make sure the whole trie is recovered
+	db = NewDatabase(diskdb)
+	recovered, err := db.RecoverJournal()
+	if err != nil {
+		t.Fatalf("failed to recover journal: %v", err)
+	}
+	if recovered != root {
+		t.Fatalf("recovered root mismatch: have %x, want %x", recovered, root)
+	}
+	if journal := rawdb.ReadTrieCommitJournal(diskdb); len(journal) != 0 {
+		t.Fatalf("journal not deleted after recovery")
+	}
+	if again, err := db.RecoverJournal(); again != (common.Hash{}) || err != nil {
+		t.Fatalf("repeated recovery: have %x, %v", again, err)
+	}
+	trie, err = New(root, db)
+	if err != nil {
+		t.Fatalf("failed to open recovered trie: %v", err)
+	}
+	it := NewIterator(trie.NodeIterator(nil))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err != nil {
+		t.Fatalf("recovered trie inconsistent: %v", it.Err)
+	}
+	if count != 100 {
+		t.Fatalf("recovered entry count mismatch: have %d, want %d", count, 100)
+	}
+}
+
+// Tests that a successful journaled commit doesn't leave a journal behind.
+func TestDatabaseCommitJournalCleanup(t *testing.T) {
+	diskdb := memorydb.New()
+	db := NewDatabaseWithConfig(diskdb, &Config{CommitJournal: true})
+
+	trie, _ := New(common.Hash{}, db)
+	trie.Update([]byte("key"), []byte("value"))
+	root, _ := trie.Commit(nil)
+	if err := db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit database: %v", err)
+	}
+	if journal := rawdb.ReadTrieCommitJournal(diskdb); len(journal) != 0 {
+		t.Fatalf("journal left behind after commit")
+	}
+	if _, err := New(root, NewDatabase(diskdb)); err != nil {
+		t.Fatalf("failed to open committed trie: %v", err)
+	}
+}
@@ -41,27 +41,44 @@ func (b *sliceBuffer) Reset() {
 type hasher struct {
 	sha      crypto.SHA3State
 	tmp      sliceBuffer
-	parallel bool // Whether to use paralallel threads when hashing
+	parallel bool       // Whether to use paralallel threads when hashing
+	pool     *sync.Pool // Pool the hasher was taken from, to return it to
 }
 
-// hasherPool holds pureHashers
-var hasherPool = sync.Pool{
-	New: func() interface{} {
+// hasherPool holds pureHashers using the default SHA3 node hashing
+var hasherPool = newHasherPool(nil)
+
+// newHasherPool creates a pool of hashers using the given hash function for
+// hashing nodes, or SHA3-256 if nil.
+func newHasherPool(hashFn func() crypto.SHA3State) *sync.Pool {
+	if hashFn == nil {
+		hashFn = func() crypto.SHA3State { return sha3.New256().(crypto.SHA3State) }
+	}
+	pool := new(sync.Pool)
+	pool.New = func() interface{} {
 		return &hasher{
-			tmp: make(sliceBuffer, 0, 550), // cap is as large as a full fullNode.
-			sha: sha3.New256().(crypto.SHA3State),
+			tmp:  make(sliceBuffer, 0, 550), // cap is as large as a full fullNode.
+			sha:  hashFn(),
+			pool: pool,
 		}
-	},
+	}
+	return pool
 }
 
+// newHasher retrieves a hasher using the default SHA3 node hashing.
 func newHasher(parallel bool) *hasher {
-	h := hasherPool.Get().(*hasher)
+	return newHasherFromPool(hasherPool, parallel)
+}
+
+// newHasherFromPool retrieves a hasher from the given pool.
+func newHasherFromPool(pool *sync.Pool, parallel bool) *hasher {
+	h := pool.Get().(*hasher)
 	h.parallel = parallel
 	return h
 }
 
 func returnHasherToPool(h *hasher) {
-	hasherPool.Put(h)
+	h.pool.Put(h)
 }
 
 // hash collapses a node down into a hash node, also returning a copy of the
@@ -126,7 +143,7 @@ func (h *hasher) hashFullNodeChildren(n *fullNode) (collapsed *fullNode, cached
 		wg.Add(16)
 		for i := 0; i < 16; i++ {
 			go func(i int) {
-				hasher := newHasher(false)
+				hasher := newHasherFromPool(h.pool, false)
 				if child := n.Children[i]; child != nil {
 					collapsed.Children[i], cached.Children[i] = hasher.hash(child, false)
 				} else {
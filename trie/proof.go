@@ -67,7 +67,7 @@ func (t *Trie) Prove(key []byte, fromLevel uint, proofDb xcbdb.KeyValueWriter) e
 			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
 		}
 	}
-	hasher := newHasher(false)
+	hasher := newHasherFromPool(t.hashers(), false)
 	defer returnHasherToPool(hasher)
 
 	for i, n := range nodes {
@@ -0,0 +1,69 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"sync"
+
+	"github.com/core-coin/go-core/v2/common"
+)
+
+// Reader is a read-only view of a trie which, unlike Trie, is safe for concurrent
+// use by multiple goroutines.
+//
+// Lookups never modify the nodes of the trie, they resolve the missing ones from
+// the database into copies along the accessed path. The most recently expanded
+// root is cached, so subsequent lookups don't need to hit the database again.
+type Reader struct {
+	trie *Trie // Backing trie, used only for resolving nodes from the database
+
+	root    node         // Root node of the trie with all the resolved nodes cached
+	version uint64       // Counter of the root replacements to detect stale expansions
+	lock    sync.RWMutex // Lock protecting the cached root node
+}
+
+// NewReader creates a concurrent reader of the trie with the given root node
+// from db. Similarly to New, it panics if db is nil and returns a
+// MissingNodeError if root does not exist in the database.
+func NewReader(root common.Hash, db *Database) (*Reader, error) {
+	trie, err := New(root, db)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{trie: trie, root: trie.root}, nil
+}
+
+// Get returns the value for key stored in the trie. The value bytes must not be
+// modified by the caller. If a node was not found in the database, a
+// MissingNodeError is returned.
+func (r *Reader) Get(key []byte) ([]byte, error) {
+	r.lock.RLock()
+	root, version := r.root, r.version
+	r.lock.RUnlock()
+
+	value, newroot, didResolve, err := r.trie.tryGet(root, keybytesToHex(key), 0)
+	if err == nil && didResolve {
+		// Only cache the expanded root if nobody replaced it in the meantime, as
+		// that would drop the nodes resolved by the concurrent lookup
+		r.lock.Lock()
+		if r.version == version {
+			r.root, r.version = newroot, version+1
+		}
+		r.lock.Unlock()
+	}
+	return value, err
+}
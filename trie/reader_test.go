@@ -0,0 +1,122 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/common"
+	"github.com/core-coin/go-core/v2/xcbdb/memorydb"
+)
+
+// makeReaderTrie creates a committed trie with the given number of entries,
+// returning its root hash and database.
+func makeReaderTrie(entries int) (common.Hash, *Database) {
+	db := NewDatabase(memorydb.New())
+	trie, _ := New(common.Hash{}, db)
+
+	for i := 0; i < entries; i++ {
+		k := make([]byte, 32)
+		binary.BigEndian.PutUint64(k, uint64(i))
+		trie.Update(k, k)
+	}
+	root, _ := trie.Commit(nil)
+	return root, db
+}
+
+// Tests that a reader can be hammered from multiple goroutines concurrently,
+// always returning the correct values. Run with -race to detect data races.
+func TestReaderConcurrentGet(t *testing.T) {
+	const entries = 1000
+
+	root, db := makeReaderTrie(entries)
+	reader, err := NewReader(root, db)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+	var (
+		pend sync.WaitGroup
+		errc = make(chan error, 8)
+	)
+	for i := 0; i < 8; i++ {
+		pend.Add(1)
+		go func(offset int) {
+			defer pend.Done()
+
+			k := make([]byte, 32)
+			for j := 0; j < entries; j++ {
+				binary.BigEndian.PutUint64(k, uint64((offset*entries/8+j)%entries))
+				value, err := reader.Get(k)
+				if err != nil {
+					errc <- err
+					return
+				}
+				if !bytes.Equal(value, k) {
+					errc <- fmt.Errorf("value mismatch for %x: have %x", k, value)
+					return
+				}
+			}
+		}(i)
+	}
+	pend.Wait()
+	close(errc)
+
+	for err := range errc {
+		t.Errorf("concurrent lookup failed: %v", err)
+	}
+	// Non-existent keys should be reported as such
+	if value, err := reader.Get([]byte("missing")); value != nil || err != nil {
+		t.Errorf("missing key lookup mismatch: have %x (%v), want nil", value, err)
+	}
+}
+
+// Tests that a reader reports nodes missing from the database.
+func TestReaderMissingNode(t *testing.T) {
+	root, db := makeReaderTrie(100)
+	reader, err := NewReader(root, db)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+	// Drop every node apart from the root from the database
+	fresh := NewDatabase(memorydb.New())
+	fresh.dirties[root] = db.dirties[root]
+
+	reader.trie.db = fresh
+	if _, err := reader.Get(make([]byte, 32)); err == nil {
+		t.Fatal("lookup with missing nodes succeeded")
+	} else if _, ok := err.(*MissingNodeError); !ok {
+		t.Fatalf("error type mismatch: have %T, want *MissingNodeError", err)
+	}
+}
+
+func BenchmarkReaderConcurrentGet(b *testing.B) {
+	root, db := makeReaderTrie(benchElemCount)
+	reader, _ := NewReader(root, db)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		k := make([]byte, 32)
+		for i := 0; pb.Next(); i++ {
+			binary.BigEndian.PutUint64(k, uint64(i%benchElemCount))
+			reader.Get(k)
+		}
+	})
+}
@@ -544,13 +544,22 @@ func (t *Trie) Commit(onleaf LeafCallback) (root common.Hash, err error) {
 	return rootHash, nil
 }
 
+// hashers returns the hasher pool of the trie's database, falling back to the
+// default one for tries created without a database.
+func (t *Trie) hashers() *sync.Pool {
+	if t.db == nil {
+		return hasherPool
+	}
+	return t.db.hashers
+}
+
 // hashRoot calculates the root hash of the given trie
 func (t *Trie) hashRoot(db *Database) (node, node, error) {
 	if t.root == nil {
 		return hashNode(emptyRoot.Bytes()), nil, nil
 	}
 	// If the number of changes is below 100, we let one thread handle it
-	h := newHasher(t.unhashed >= 100)
+	h := newHasherFromPool(t.hashers(), t.unhashed >= 100)
 	defer returnHasherToPool(h)
 	hashed, cached := h.hash(t.root, true)
 	t.unhashed = 0
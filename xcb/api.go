@@ -344,7 +344,7 @@ func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs,
 		} else {
 			results[i].RLP = fmt.Sprintf("0x%x", rlpBytes)
 		}
-		if results[i].Block, err = xcbapi.RPCMarshalBlock(block, true, true, big.NewInt(int64(api.xcb.config.NetworkId))); err != nil {
+		if results[i].Block, err = xcbapi.RPCMarshalBlock(block, true, true, api.xcb.blockchain.Config()); err != nil {
 			results[i].Block = map[string]interface{}{"error": err.Error()}
 		}
 	}
@@ -306,6 +306,10 @@ func (b *XcbAPIBackend) RPCTxFeeCap() float64 {
 	return b.xcb.config.RPCTxFeeCap
 }
 
+func (b *XcbAPIBackend) RPCRevertReason() bool {
+	return b.xcb.config.RPCRevertReason
+}
+
 func (b *XcbAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.xcb.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
@@ -202,7 +202,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 			// Fetch and execute the next block trace tasks
 			for task := range tasks {
-				signer := types.MakeSigner(api.xcb.blockchain.Config().NetworkID)
+				signer := types.MakeBlockSigner(api.xcb.blockchain.Config(), task.block.Number())
 				blockCtx := core.NewCVMBlockContext(task.block.Header(), api.xcb.blockchain, nil)
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
@@ -458,7 +458,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	}
 	// Execute all the transaction contained within the block concurrently
 	var (
-		signer = types.MakeSigner(api.xcb.blockchain.Config().NetworkID)
+		signer = types.MakeBlockSigner(api.xcb.blockchain.Config(), block.Number())
 
 		txs     = block.Transactions()
 		results = make([]*txTraceResult, len(txs))
@@ -555,7 +555,7 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 
 	// Execute transaction, either tracing all or just the requested one
 	var (
-		signer = types.MakeSigner(api.xcb.blockchain.Config().NetworkID)
+		signer = types.MakeBlockSigner(api.xcb.blockchain.Config(), block.Number())
 		dumps  []string
 		vmctx  = core.NewCVMBlockContext(block.Header(), api.xcb.blockchain, nil)
 	)
@@ -835,7 +835,7 @@ func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec
 	}
 
 	// Recompute transactions up to the target index.
-	signer := types.MakeSigner(api.xcb.blockchain.Config().NetworkID)
+	signer := types.MakeBlockSigner(api.xcb.blockchain.Config(), block.Number())
 
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
@@ -165,6 +165,11 @@ type Config struct {
 	// send-transction variants. The unit is core.
 	RPCTxFeeCap float64 `toml:",omitempty"`
 
+	// RPCRevertReason enables deriving the revert reason of failed transactions
+	// in the receipts served over RPC. It re-executes the block up to the
+	// transaction, so it's off by default.
+	RPCRevertReason bool `toml:",omitempty"`
+
 	// Checkpoint is a hardcoded checkpoint which can be nil.
 	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
 
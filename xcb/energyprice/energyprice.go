@@ -123,7 +123,7 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 		txPrices  []*big.Int
 	)
 	for sent < gpo.checkBlocks && number > 0 {
-		go gpo.getBlockPrices(ctx, types.MakeSigner(gpo.backend.ChainConfig().NetworkID), number, sampleNumber, result, quit)
+		go gpo.getBlockPrices(ctx, types.MakeBlockSigner(gpo.backend.ChainConfig(), new(big.Int).SetUint64(number)), number, sampleNumber, result, quit)
 		sent++
 		exp++
 		number--
@@ -146,7 +146,7 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 		// meaningful returned, try to query more blocks. But the maximum
 		// is 2*checkBlocks.
 		if len(res.prices) == 1 && len(txPrices)+1+exp < gpo.checkBlocks*2 && number > 0 {
-			go gpo.getBlockPrices(ctx, types.MakeSigner(gpo.backend.ChainConfig().NetworkID), number, sampleNumber, result, quit)
+			go gpo.getBlockPrices(ctx, types.MakeBlockSigner(gpo.backend.ChainConfig(), new(big.Int).SetUint64(number)), number, sampleNumber, result, quit)
 			sent++
 			exp++
 			number--
@@ -56,6 +56,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrustedPeersBroadcasting bool
 		RPCEnergyCap             uint64                         `toml:",omitempty"`
 		RPCTxFeeCap              float64                        `toml:",omitempty"`
+		RPCRevertReason          bool                           `toml:",omitempty"`
 		Checkpoint               *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle         *params.CheckpointOracleConfig `toml:",omitempty"`
 	}
@@ -99,6 +100,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrustedPeersBroadcasting = c.TrustedPeersBroadcasting
 	enc.RPCEnergyCap = c.RPCEnergyCap
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCRevertReason = c.RPCRevertReason
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
 	return &enc, nil
@@ -146,6 +148,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrustedPeersBroadcasting *bool
 		RPCEnergyCap             *uint64                        `toml:",omitempty"`
 		RPCTxFeeCap              *float64                       `toml:",omitempty"`
+		RPCRevertReason          *bool                          `toml:",omitempty"`
 		Checkpoint               *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle         *params.CheckpointOracleConfig `toml:",omitempty"`
 	}
@@ -270,6 +273,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCRevertReason != nil {
+		c.RPCRevertReason = *dec.RPCRevertReason
+	}
 	if dec.Checkpoint != nil {
 		c.Checkpoint = dec.Checkpoint
 	}
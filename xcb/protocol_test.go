@@ -17,6 +17,7 @@
 package xcb
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -271,6 +272,32 @@ func testRecvTransactions(t *testing.T, protocol int) {
 	}
 }
 
+// This test checks that transactions too large for the local pool are rejected
+// while decoding the message, dropping the peer.
+func TestRecvOversizedTransactions(t *testing.T) {
+	txAdded := make(chan []*types.Transaction)
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, txAdded)
+	pm.acceptTxs = 1 // mark synced to accept transactions
+	p, errc := newTestPeer("peer", xcb65, pm, true)
+	defer pm.Stop()
+	defer p.close()
+
+	tx := newTestTransaction(testAccount, 0, core.TxMaxSize)
+	if err := p2p.Send(p.app, TransactionMsg, []interface{}{tx}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, types.ErrTxTooLarge) {
+			t.Errorf("error mismatch: have %v, want %v", err, types.ErrTxTooLarge)
+		}
+	case added := <-txAdded:
+		t.Errorf("oversized transactions added: %v", added)
+	case <-time.After(2 * time.Second):
+		t.Errorf("peer not dropped within 2 seconds")
+	}
+}
+
 // This test checks that pending transactions are sent.
 func TestSendTransactions63(t *testing.T) { testSendTransactions(t, 63) }
 func TestSendTransactions64(t *testing.T) { testSendTransactions(t, 64) }
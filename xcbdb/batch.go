@@ -44,3 +44,72 @@ type Batcher interface {
 	// until a final write is called.
 	NewBatch() Batch
 }
+
+// autoBatch is a batch that writes its contents to the host database whenever
+// the amount of queued data reaches a threshold, bounding its memory usage.
+type autoBatch struct {
+	batch    Batch
+	maxBytes int
+}
+
+// NewAutoBatch creates a batch over db which is flushed automatically each time
+// its size reaches maxBytes. Write needs to be called at the end to flush the
+// remaining changes.
+//
+// Since flushed data is already persisted, the changes of an auto batch are not
+// written atomically, and Reset and Replay only cover the not yet flushed part.
+func NewAutoBatch(db Batcher, maxBytes int) Batch {
+	return &autoBatch{
+		batch:    db.NewBatch(),
+		maxBytes: maxBytes,
+	}
+}
+
+// Put inserts the given value into the batch, flushing it if it grew too large.
+func (b *autoBatch) Put(key []byte, value []byte) error {
+	if err := b.batch.Put(key, value); err != nil {
+		return err
+	}
+	return b.flush()
+}
+
+// Delete inserts a key removal into the batch, flushing it if it grew too large.
+func (b *autoBatch) Delete(key []byte) error {
+	if err := b.batch.Delete(key); err != nil {
+		return err
+	}
+	return b.flush()
+}
+
+// flush writes the batch to the host database if its size reached the limit.
+func (b *autoBatch) flush() error {
+	if b.batch.ValueSize() < b.maxBytes {
+		return nil
+	}
+	if err := b.batch.Write(); err != nil {
+		return err
+	}
+	b.batch.Reset()
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing since the last
+// flush.
+func (b *autoBatch) ValueSize() int {
+	return b.batch.ValueSize()
+}
+
+// Write flushes any remaining data to disk.
+func (b *autoBatch) Write() error {
+	return b.batch.Write()
+}
+
+// Reset discards the data queued up since the last flush.
+func (b *autoBatch) Reset() {
+	b.batch.Reset()
+}
+
+// Replay replays the batch contents queued up since the last flush.
+func (b *autoBatch) Replay(w KeyValueWriter) error {
+	return b.batch.Replay(w)
+}
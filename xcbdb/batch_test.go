@@ -0,0 +1,80 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcbdb_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/xcbdb"
+	"github.com/core-coin/go-core/v2/xcbdb/memorydb"
+)
+
+// Tests that an auto batch flushes its contents whenever it grows too large,
+// and that all data ends up in the database after the final write.
+func TestAutoBatch(t *testing.T) {
+	var (
+		db      = memorydb.New()
+		batch   = xcbdb.NewAutoBatch(db, 1024)
+		entries = 1000
+		value   = bytes.Repeat([]byte{0x01}, 32)
+		flushes int
+	)
+	for i := 0; i < entries; i++ {
+		key := make([]byte, 4)
+		binary.BigEndian.PutUint32(key, uint32(i))
+
+		before := db.Len()
+		if err := batch.Put(key, value); err != nil {
+			t.Fatalf("failed to insert entry %d: %v", i, err)
+		}
+		if db.Len() != before {
+			flushes++
+		}
+		if size := batch.ValueSize(); size >= 1024 {
+			t.Fatalf("batch size above limit after entry %d: %d", i, size)
+		}
+	}
+	// Batches may or may not account for the keys, so only check a lower bound
+	if min := entries * len(value) / (1024 + len(value)); flushes < min {
+		t.Errorf("too few flushes: have %d, want at least %d", flushes, min)
+	}
+	if db.Len() == entries {
+		t.Errorf("all entries flushed before final write")
+	}
+	// Delete a flushed entry and write out the remainder
+	if err := batch.Delete([]byte{0, 0, 0, 0}); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+	if db.Len() != entries-1 {
+		t.Errorf("entry count mismatch: have %d, want %d", db.Len(), entries-1)
+	}
+	for i := 1; i < entries; i++ {
+		key := make([]byte, 4)
+		binary.BigEndian.PutUint32(key, uint32(i))
+		if have, err := db.Get(key); err != nil || !bytes.Equal(have, value) {
+			t.Fatalf("entry %d mismatch: have %x, %v", i, have, err)
+		}
+	}
+	if has, _ := db.Has([]byte{0, 0, 0, 0}); has {
+		t.Errorf("deleted entry still present")
+	}
+}
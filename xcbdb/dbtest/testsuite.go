@@ -128,6 +128,67 @@ func TestDatabaseSuite(t *testing.T, New func() xcbdb.KeyValueStore) {
 		}
 	})
 
+	t.Run("ReverseIterator", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		rdb, ok := db.(xcbdb.ReverseIteratee)
+		if !ok {
+			t.Skip("reverse iteration not supported")
+		}
+		keys := []string{"", "1", "10", "11", "12", "2", "20", "21", "3", "a", "a1", "a\xff", "b"}
+		for _, k := range keys {
+			if err := db.Put([]byte(k), []byte("v"+k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tests := []struct {
+			prefix string
+			start  string
+		}{
+			{"", ""},
+			{"1", ""},
+			{"2", ""},
+			{"a", ""},
+			{"c", ""},
+			{"", "2"},
+			{"", "15"},
+			{"1", "1"},
+			{"1", "0"},
+			{"1", "9"},
+			{"a", "\xff"},
+			{"a", "2"},
+		}
+		for i, tt := range tests {
+			// Collect the expected keys by reversing a forward iteration
+			var want []string
+			it := db.NewIterator([]byte(tt.prefix), nil)
+			for it.Next() {
+				if tt.start == "" || string(it.Key()) <= tt.prefix+tt.start {
+					want = append([]string{string(it.Key())}, want...)
+				}
+			}
+			it.Release()
+
+			var have []string
+			it = rdb.NewReverseIterator([]byte(tt.prefix), []byte(tt.start))
+			for it.Next() {
+				if !bytes.Equal(it.Value(), []byte("v"+string(it.Key()))) {
+					t.Errorf("test %d: key %q: value mismatch: have %q", i, it.Key(), it.Value())
+				}
+				have = append(have, string(it.Key()))
+			}
+			if err := it.Error(); err != nil {
+				t.Errorf("test %d: iteration failed: %v", i, err)
+			}
+			it.Release()
+
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("test %d: prefix=%q start=%q: order mismatch: have %q, want %q", i, tt.prefix, tt.start, have, want)
+			}
+		}
+	})
+
 	t.Run("IteratorWith", func(t *testing.T) {
 		db := New()
 		defer db.Close()
@@ -59,3 +59,16 @@ type Iteratee interface {
 	// no need for the caller to prepend the prefix to the start
 	NewIterator(prefix []byte, start []byte) Iterator
 }
+
+// ReverseIteratee wraps the NewReverseIterator method of a backing data store
+// capable of iterating its content in descending key order.
+type ReverseIteratee interface {
+	// NewReverseIterator creates a binary-alphabetical iterator over a subset of
+	// database content with a particular key prefix, in descending key order. The
+	// iteration begins at a particular initial key (or before, if it does not
+	// exist); an empty start begins at the last key with the given prefix.
+	//
+	// Note: This method assumes that the prefix is NOT part of the start, so there's
+	// no need for the caller to prepend the prefix to the start
+	NewReverseIterator(prefix []byte, start []byte) Iterator
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
@@ -193,11 +194,105 @@ func (db *Database) NewIterator(prefix []byte, start []byte) xcbdb.Iterator {
 	return db.db.NewIterator(bytesPrefixRange(prefix, start), nil)
 }
 
+// NewReverseIterator creates a binary-alphabetical iterator over a subset of
+// database content with a particular key prefix, in descending key order,
+// starting at a particular initial key (or before, if it does not exist).
+//
+// Like forward iterators, the returned iterator operates on an implicit
+// snapshot of the database, so concurrent writes are not reflected.
+func (db *Database) NewReverseIterator(prefix []byte, start []byte) xcbdb.Iterator {
+	r := util.BytesPrefix(prefix)
+	if len(start) > 0 {
+		// Limit is exclusive, use the first key after the start to include it
+		r.Limit = append(append(common.CopyBytes(prefix), start...), 0x00)
+	}
+	return &reverseIterator{iter: db.db.NewIterator(r, nil)}
+}
+
 // Stat returns a particular internal stat of the database.
 func (db *Database) Stat(property string) (string, error) {
 	return db.db.GetProperty(property)
 }
 
+// LevelStats contains the compaction statistics of a single database level.
+type LevelStats struct {
+	Tables int           // Number of tables in the level
+	Size   int64         // Total size of the tables in bytes
+	Time   time.Duration // Time spent compacting into the level
+	Read   int64         // Bytes read by the compactions into the level
+	Write  int64         // Bytes written by the compactions into the level
+}
+
+// Stats contains the internal statistics of the database, as a structured
+// alternative to the text based properties of Stat.
+type Stats struct {
+	Levels []LevelStats // Compaction statistics of each level, level 0 first
+
+	MemComp       uint32 // Number of memory table flushes
+	Level0Comp    uint32 // Number of table compactions of level 0
+	NonLevel0Comp uint32 // Number of table compactions of the other levels
+	SeekComp      uint32 // Number of table compactions triggered by reads
+
+	IORead  uint64 // Total bytes read from disk
+	IOWrite uint64 // Total bytes written to disk
+
+	WriteDelayCount    int32         // Number of writes delayed by compaction
+	WriteDelayDuration time.Duration // Total time writes were delayed by compaction
+	WritePaused        bool          // Whether writes are currently paused by compaction
+
+	// ReadAmplification is the number of tables a read may need to check in
+	// the worst case: every table of level 0, and one table per other level.
+	ReadAmplification int
+
+	// WriteAmplification is the ratio of the bytes written to disk to the bytes
+	// written by the user (the journal), zero if nothing was written yet.
+	WriteAmplification float64
+}
+
+// Stats returns the compaction, disk and write delay statistics of the database.
+func (db *Database) Stats() (*Stats, error) {
+	var raw leveldb.DBStats
+	if err := db.db.Stats(&raw); err != nil {
+		return nil, err
+	}
+	stats := &Stats{
+		Levels:             make([]LevelStats, len(raw.LevelSizes)),
+		MemComp:            raw.MemComp,
+		Level0Comp:         raw.Level0Comp,
+		NonLevel0Comp:      raw.NonLevel0Comp,
+		SeekComp:           raw.SeekComp,
+		IORead:             raw.IORead,
+		IOWrite:            raw.IOWrite,
+		WriteDelayCount:    raw.WriteDelayCount,
+		WriteDelayDuration: raw.WriteDelayDuration,
+		WritePaused:        raw.WritePaused,
+	}
+	var compWrite uint64
+	for i := range stats.Levels {
+		stats.Levels[i] = LevelStats{
+			Tables: raw.LevelTablesCounts[i],
+			Size:   raw.LevelSizes[i],
+			Time:   raw.LevelDurations[i],
+			Read:   raw.LevelRead[i],
+			Write:  raw.LevelWrite[i],
+		}
+		compWrite += uint64(raw.LevelWrite[i])
+
+		switch {
+		case i == 0:
+			stats.ReadAmplification += raw.LevelTablesCounts[i]
+		case raw.LevelTablesCounts[i] > 0:
+			stats.ReadAmplification++
+		}
+	}
+	// Everything not written by compactions (including memory table flushes,
+	// accounted to level 0) was written to the journal.
+	if raw.IOWrite > compWrite {
+		stats.WriteAmplification = float64(raw.IOWrite) / float64(raw.IOWrite-compWrite)
+	}
+	return stats, nil
+}
+
 // Compact flattens the underlying data store for the given key range. In essence,
 // deleted and overwritten versions are discarded, and the data is rearranged to
 // reduce the cost of operations needed to access them.
@@ -493,3 +588,46 @@ func bytesPrefixRange(prefix, start []byte) *util.Range {
 	r.Start = append(r.Start, start...)
 	return r
 }
+
+// reverseIterator is a wrapper around a leveldb iterator that walks it from the
+// last key towards the first one.
+type reverseIterator struct {
+	iter   iterator.Iterator
+	inited bool
+}
+
+// Next moves the iterator to the previous key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *reverseIterator) Next() bool {
+	if !it.inited {
+		it.inited = true
+		return it.iter.Last()
+	}
+	return it.iter.Prev()
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *reverseIterator) Error() error {
+	return it.iter.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *reverseIterator) Key() []byte {
+	return it.iter.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *reverseIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *reverseIterator) Release() {
+	it.iter.Release()
+}
@@ -17,9 +17,11 @@
 package leveldb
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/storage"
 
 	"github.com/core-coin/go-core/v2/xcbdb"
@@ -39,3 +41,46 @@ func TestLevelDB(t *testing.T) {
 		})
 	})
 }
+
+func TestLevelDBStats(t *testing.T) {
+	// Use a tiny write buffer, so memory tables get flushed often
+	ldb, err := leveldb.Open(storage.NewMemStorage(), &opt.Options{WriteBuffer: 64 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := &Database{db: ldb}
+	defer db.Close()
+
+	value := make([]byte, 1024)
+	for i := 0; i < 2048; i++ {
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, uint64(i))
+		if err := db.Put(key, value); err != nil {
+			t.Fatalf("failed to write key %d: %v", i, err)
+		}
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("failed to compact database: %v", err)
+	}
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("failed to retrieve stats: %v", err)
+	}
+	if len(stats.Levels) == 0 {
+		t.Fatal("no level stats")
+	}
+	var size, written int64
+	for _, level := range stats.Levels {
+		size += level.Size
+		written += level.Write
+	}
+	if size == 0 || written == 0 {
+		t.Errorf("empty level stats: size %d, compaction writes %d", size, written)
+	}
+	if stats.MemComp == 0 || stats.Level0Comp+stats.NonLevel0Comp == 0 {
+		t.Errorf("compactions not counted: %+v", stats)
+	}
+	if stats.IOWrite == 0 || stats.ReadAmplification == 0 || stats.WriteAmplification <= 1 {
+		t.Errorf("bad io stats: write %d, read amplification %d, write amplification %f", stats.IOWrite, stats.ReadAmplification, stats.WriteAmplification)
+	}
+}
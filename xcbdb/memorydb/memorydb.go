@@ -164,6 +164,40 @@ func (db *Database) NewIterator(prefix []byte, start []byte) xcbdb.Iterator {
 	}
 }
 
+// NewReverseIterator creates a binary-alphabetical iterator over a subset of
+// database content with a particular key prefix, in descending key order,
+// starting at a particular initial key (or before, if it does not exist).
+func (db *Database) NewReverseIterator(prefix []byte, start []byte) xcbdb.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var (
+		pr     = string(prefix)
+		st     = pr + string(start)
+		keys   = make([]string, 0, len(db.db))
+		values = make([][]byte, 0, len(db.db))
+	)
+	// Collect the keys from the memory database corresponding to the given prefix
+	// and start
+	for key := range db.db {
+		if !strings.HasPrefix(key, pr) {
+			continue
+		}
+		if len(start) == 0 || key <= st {
+			keys = append(keys, key)
+		}
+	}
+	// Sort the items in reverse and retrieve the associated values
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	for _, key := range keys {
+		values = append(values, db.db[key])
+	}
+	return &iterator{
+		keys:   keys,
+		values: values,
+	}
+}
+
 // Stat returns a particular internal stat of the database.
 func (db *Database) Stat(property string) (string, error) {
 	return "", errors.New("unknown property")
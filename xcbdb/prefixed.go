@@ -0,0 +1,222 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcbdb
+
+// prefixed is a wrapper around a key-value store that transparently prefixes
+// every key, allowing multiple subsystems to share a single physical database
+// without their keys colliding.
+type prefixed struct {
+	db     KeyValueStore
+	prefix []byte
+}
+
+// NewPrefixed returns a key-value store that prefixes all keys with the given
+// prefix before delegating to db. Iteration and compaction are confined to the
+// keys of the prefixed namespace.
+//
+// Prefixes sharing the same database should not be prefixes of each other, or
+// the namespaces will overlap. Closing the returned store is a noop, the owner
+// of db remains responsible for closing it.
+func NewPrefixed(db KeyValueStore, prefix []byte) KeyValueStore {
+	return &prefixed{
+		db:     db,
+		prefix: append([]byte{}, prefix...),
+	}
+}
+
+// key returns the prefixed version of the given key.
+func (p *prefixed) key(key []byte) []byte {
+	buf := make([]byte, len(p.prefix)+len(key))
+	copy(buf, p.prefix)
+	copy(buf[len(p.prefix):], key)
+	return buf
+}
+
+// Close is a noop to implement the KeyValueStore interface.
+func (p *prefixed) Close() error {
+	return nil
+}
+
+// Has retrieves if a prefixed version of a key is present in the database.
+func (p *prefixed) Has(key []byte) (bool, error) {
+	return p.db.Has(p.key(key))
+}
+
+// Get retrieves the given prefixed key if it's present in the database.
+func (p *prefixed) Get(key []byte) ([]byte, error) {
+	return p.db.Get(p.key(key))
+}
+
+// Put inserts the given value into the database at a prefixed version of the
+// provided key.
+func (p *prefixed) Put(key []byte, value []byte) error {
+	return p.db.Put(p.key(key), value)
+}
+
+// Delete removes the given prefixed key from the database.
+func (p *prefixed) Delete(key []byte) error {
+	return p.db.Delete(p.key(key))
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of the
+// namespace content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist). The returned keys are stripped
+// of the namespace prefix.
+func (p *prefixed) NewIterator(prefix []byte, start []byte) Iterator {
+	return &prefixedIterator{
+		iter:   p.db.NewIterator(p.key(prefix), start),
+		prefix: len(p.prefix),
+	}
+}
+
+// Stat returns a particular internal stat of the database.
+func (p *prefixed) Stat(property string) (string, error) {
+	return p.db.Stat(property)
+}
+
+// Compact flattens the underlying data store for the given key range, limited
+// to the namespace of the prefix.
+//
+// A nil start is treated as the first key of the namespace; a nil limit is
+// treated as a key after all keys of the namespace.
+func (p *prefixed) Compact(start []byte, limit []byte) error {
+	start = p.key(start)
+	if limit != nil {
+		limit = p.key(limit)
+	} else {
+		limit = upperBound(p.prefix)
+	}
+	return p.db.Compact(start, limit)
+}
+
+// upperBound returns the first key after all keys starting with prefix, or nil
+// if there is no such key (i.e. the prefix is empty or consists of 0xff bytes).
+func upperBound(prefix []byte) []byte {
+	limit := append([]byte{}, prefix...)
+	for i := len(limit) - 1; i >= 0; i-- {
+		// Bump the current byte, stopping if it doesn't overflow
+		limit[i]++
+		if limit[i] > 0 {
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
+// NewBatch creates a write-only database that buffers changes to its host db
+// until a final write is called, each operation prefixing all keys with the
+// namespace prefix.
+func (p *prefixed) NewBatch() Batch {
+	return &prefixedBatch{batch: p.db.NewBatch(), db: p}
+}
+
+// prefixedBatch is a wrapper around a database batch that prefixes each key
+// access with the namespace prefix.
+type prefixedBatch struct {
+	batch Batch
+	db    *prefixed
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *prefixedBatch) Put(key, value []byte) error {
+	return b.batch.Put(b.db.key(key), value)
+}
+
+// Delete inserts a key removal into the batch for later committing.
+func (b *prefixedBatch) Delete(key []byte) error {
+	return b.batch.Delete(b.db.key(key))
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *prefixedBatch) ValueSize() int {
+	return b.batch.ValueSize()
+}
+
+// Write flushes any accumulated data to disk.
+func (b *prefixedBatch) Write() error {
+	return b.batch.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *prefixedBatch) Reset() {
+	b.batch.Reset()
+}
+
+// Replay replays the batch contents, stripping the namespace prefix.
+func (b *prefixedBatch) Replay(w KeyValueWriter) error {
+	return b.batch.Replay(&prefixedReplayer{w: w, prefix: len(b.db.prefix)})
+}
+
+// prefixedReplayer is a wrapper around a batch replayer which truncates the
+// added prefix.
+type prefixedReplayer struct {
+	w      KeyValueWriter
+	prefix int
+}
+
+// Put implements the interface KeyValueWriter.
+func (r *prefixedReplayer) Put(key []byte, value []byte) error {
+	return r.w.Put(key[r.prefix:], value)
+}
+
+// Delete implements the interface KeyValueWriter.
+func (r *prefixedReplayer) Delete(key []byte) error {
+	return r.w.Delete(key[r.prefix:])
+}
+
+// prefixedIterator is a wrapper around a database iterator that strips the
+// namespace prefix from the returned keys.
+type prefixedIterator struct {
+	iter   Iterator
+	prefix int
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *prefixedIterator) Next() bool {
+	return it.iter.Next()
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *prefixedIterator) Error() error {
+	return it.iter.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *prefixedIterator) Key() []byte {
+	key := it.iter.Key()
+	if key == nil {
+		return nil
+	}
+	return key[it.prefix:]
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *prefixedIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *prefixedIterator) Release() {
+	it.iter.Release()
+}
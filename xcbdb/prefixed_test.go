@@ -0,0 +1,144 @@
+// Copyright 2024 by the Authors
+// This file is part of the go-core library.
+//
+// The go-core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-core library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcbdb_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/core-coin/go-core/v2/xcbdb"
+	"github.com/core-coin/go-core/v2/xcbdb/dbtest"
+	"github.com/core-coin/go-core/v2/xcbdb/memorydb"
+)
+
+func TestPrefixedDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() xcbdb.KeyValueStore {
+			return xcbdb.NewPrefixed(memorydb.New(), []byte("ns-"))
+		})
+	})
+}
+
+// Tests that prefixed views over the same database are isolated from each other
+// and from the unprefixed keys.
+func TestPrefixedIsolation(t *testing.T) {
+	var (
+		db = memorydb.New()
+		a  = xcbdb.NewPrefixed(db, []byte{0x01})
+		b  = xcbdb.NewPrefixed(db, []byte{0x02})
+	)
+	db.Put([]byte{0x00}, []byte("raw-before"))
+	db.Put([]byte{0x03}, []byte("raw-after"))
+
+	for _, key := range []string{"1", "2", "3"} {
+		a.Put([]byte(key), []byte("a"+key))
+	}
+	for _, key := range []string{"2", "4"} {
+		b.Put([]byte(key), []byte("b"+key))
+	}
+	batch := b.NewBatch()
+	batch.Put([]byte("5"), []byte("b5"))
+	batch.Delete([]byte("2"))
+	if err := batch.Write(); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+	if has, _ := a.Has([]byte("4")); has {
+		t.Errorf("key of view b visible in view a")
+	}
+	if value, _ := a.Get([]byte("2")); !bytes.Equal(value, []byte("a2")) {
+		t.Errorf("view a value mismatch: have %q, want %q", value, "a2")
+	}
+	if has, _ := b.Has([]byte("2")); has {
+		t.Errorf("deleted key still present in view b")
+	}
+	if value, _ := db.Get([]byte{0x02, '5'}); !bytes.Equal(value, []byte("b5")) {
+		t.Errorf("underlying value mismatch: have %q, want %q", value, "b5")
+	}
+	iterate := func(db xcbdb.KeyValueStore, prefix, start []byte) []string {
+		it := db.NewIterator(prefix, start)
+		defer it.Release()
+
+		var keys []string
+		for it.Next() {
+			keys = append(keys, string(it.Key())+"="+string(it.Value()))
+		}
+		return keys
+	}
+	tests := []struct {
+		db     xcbdb.KeyValueStore
+		prefix []byte
+		start  []byte
+		want   []string
+	}{
+		{a, nil, nil, []string{"1=a1", "2=a2", "3=a3"}},
+		{a, nil, []byte("2"), []string{"2=a2", "3=a3"}},
+		{a, []byte("3"), nil, []string{"3=a3"}},
+		{b, nil, nil, []string{"4=b4", "5=b5"}},
+		{b, nil, []byte("6"), nil},
+	}
+	for i, tt := range tests {
+		if have := iterate(tt.db, tt.prefix, tt.start); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: iteration mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+	if err := a.Compact(nil, nil); err != nil {
+		t.Errorf("failed to compact view: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("failed to close view: %v", err)
+	}
+	if value, _ := db.Get([]byte{0x03}); !bytes.Equal(value, []byte("raw-after")) {
+		t.Errorf("underlying database affected by view close: have %q", value)
+	}
+}
+
+// recordingStore is a key-value store remembering the last compacted range.
+type recordingStore struct {
+	xcbdb.KeyValueStore
+	start, limit []byte
+}
+
+func (s *recordingStore) Compact(start []byte, limit []byte) error {
+	s.start, s.limit = start, limit
+	return nil
+}
+
+// Tests that compaction ranges are translated into the namespace of the prefix.
+func TestPrefixedCompactRange(t *testing.T) {
+	tests := []struct {
+		prefix       []byte
+		start, limit []byte
+		wantStart    []byte
+		wantLimit    []byte
+	}{
+		{[]byte{0x01}, nil, nil, []byte{0x01}, []byte{0x02}},
+		{[]byte{0x01, 0xff}, nil, nil, []byte{0x01, 0xff}, []byte{0x02}},
+		{[]byte{0xff, 0xff}, nil, nil, []byte{0xff, 0xff}, nil},
+		{[]byte{0x01}, []byte{0x05}, []byte{0x07}, []byte{0x01, 0x05}, []byte{0x01, 0x07}},
+		{[]byte{0x01}, []byte{0x05}, nil, []byte{0x01, 0x05}, []byte{0x02}},
+	}
+	for i, tt := range tests {
+		store := &recordingStore{KeyValueStore: memorydb.New()}
+		if err := xcbdb.NewPrefixed(store, tt.prefix).Compact(tt.start, tt.limit); err != nil {
+			t.Fatalf("test %d: compaction failed: %v", i, err)
+		}
+		if !bytes.Equal(store.start, tt.wantStart) || !bytes.Equal(store.limit, tt.wantLimit) || (tt.wantLimit == nil) != (store.limit == nil) {
+			t.Errorf("test %d: range mismatch: have [%x, %x), want [%x, %x)", i, store.start, store.limit, tt.wantStart, tt.wantLimit)
+		}
+	}
+}